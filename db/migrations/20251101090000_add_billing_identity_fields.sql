@@ -0,0 +1,18 @@
+-- migrate:up
+-- Seller identity used on e-invoices (Factur-X / UBL)
+ALTER TABLE users ADD COLUMN business_name VARCHAR(255);
+ALTER TABLE users ADD COLUMN business_address TEXT;
+ALTER TABLE users ADD COLUMN country_code VARCHAR(2);
+ALTER TABLE users ADD COLUMN tax_id VARCHAR(50);
+
+-- Buyer identity used on e-invoices
+ALTER TABLE clients ADD COLUMN country_code VARCHAR(2);
+ALTER TABLE clients ADD COLUMN tax_id VARCHAR(50);
+
+-- migrate:down
+ALTER TABLE clients DROP COLUMN tax_id;
+ALTER TABLE clients DROP COLUMN country_code;
+ALTER TABLE users DROP COLUMN tax_id;
+ALTER TABLE users DROP COLUMN country_code;
+ALTER TABLE users DROP COLUMN business_address;
+ALTER TABLE users DROP COLUMN business_name;
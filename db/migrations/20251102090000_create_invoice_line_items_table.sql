@@ -0,0 +1,19 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS invoice_line_items (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL DEFAULT 1.00,
+    unit VARCHAR(20) NOT NULL DEFAULT 'unit' CHECK (unit IN ('unit', 'hour', 'day', 'month')),
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0.00 CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_line_items_invoice_id ON invoice_line_items(invoice_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_invoice_line_items_invoice_id;
+DROP TABLE IF EXISTS invoice_line_items;
@@ -0,0 +1,38 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(5, 2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    compound BOOLEAN NOT NULL DEFAULT FALSE,
+    withholding BOOLEAN NOT NULL DEFAULT FALSE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tax_rates_user_id ON tax_rates(user_id);
+
+-- Taxes are copied onto the invoice when it is created so later changes to a
+-- user's tax rates don't alter issued invoices
+CREATE TABLE IF NOT EXISTS invoice_taxes (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(5, 2) NOT NULL,
+    compound BOOLEAN NOT NULL DEFAULT FALSE,
+    withholding BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_invoice_taxes_invoice_id ON invoice_taxes(invoice_id);
+
+ALTER TABLE invoices ADD COLUMN reverse_charge BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- migrate:down
+ALTER TABLE invoices DROP COLUMN reverse_charge;
+DROP INDEX IF EXISTS idx_invoice_taxes_invoice_id;
+DROP TABLE IF EXISTS invoice_taxes;
+DROP INDEX IF EXISTS idx_tax_rates_user_id;
+DROP TABLE IF EXISTS tax_rates;
@@ -0,0 +1,9 @@
+-- migrate:up
+-- Time at which the invoice was last moved to overdue
+ALTER TABLE invoices ADD COLUMN overdue_at TIMESTAMP;
+
+CREATE INDEX idx_invoices_due_date ON invoices(due_date);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_invoices_due_date;
+ALTER TABLE invoices DROP COLUMN overdue_at;
@@ -0,0 +1,37 @@
+-- migrate:up
+ALTER TABLE clients ADD COLUMN reminders_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- A reminder rule sends a payment reminder offset_days after an invoice's due
+-- date; negative offsets remind before the due date
+CREATE TABLE IF NOT EXISTS reminder_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offset_days INTEGER NOT NULL CHECK (offset_days >= -365 AND offset_days <= 365),
+    subject VARCHAR(255),
+    message TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT reminder_rules_user_offset_unique UNIQUE (user_id, offset_days)
+);
+
+-- Every reminder sent, or attempted, for an invoice
+CREATE TABLE IF NOT EXISTS invoice_reminders (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    reminder_rule_id INTEGER REFERENCES reminder_rules(id) ON DELETE SET NULL,
+    offset_days INTEGER NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('sent', 'failed')),
+    error TEXT,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_reminders_invoice_id ON invoice_reminders(invoice_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_invoice_reminders_invoice_id;
+DROP TABLE IF EXISTS invoice_reminders;
+DROP TABLE IF EXISTS reminder_rules;
+ALTER TABLE clients DROP COLUMN reminders_opt_out;
@@ -0,0 +1,18 @@
+-- migrate:up
+-- Every time an invoice is emailed to its client
+CREATE TABLE IF NOT EXISTS invoice_deliveries (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    recipient VARCHAR(255) NOT NULL,
+    cc TEXT,
+    subject VARCHAR(255) NOT NULL,
+    message TEXT,
+    message_id VARCHAR(255) NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_deliveries_invoice_id ON invoice_deliveries(invoice_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_invoice_deliveries_invoice_id;
+DROP TABLE IF EXISTS invoice_deliveries;
@@ -0,0 +1,46 @@
+-- migrate:up
+-- A recurring invoice is a template from which the scheduler issues a real
+-- invoice on every occurrence of its schedule
+CREATE TABLE IF NOT EXISTS recurring_invoices (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('weekly', 'monthly', 'quarterly', 'yearly')),
+    -- ISO weekday (1 = Monday) for weekly schedules, day of the month otherwise
+    anchor_day INTEGER NOT NULL CHECK (anchor_day >= 1 AND anchor_day <= 31),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    payment_terms_days INTEGER NOT NULL DEFAULT 30 CHECK (payment_terms_days >= 0 AND payment_terms_days <= 365),
+    notes TEXT,
+    auto_send BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'completed')),
+    next_run_date DATE,
+    last_run_date DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX idx_recurring_invoices_user_id ON recurring_invoices(user_id);
+CREATE INDEX idx_recurring_invoices_next_run_date ON recurring_invoices(next_run_date) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS recurring_invoice_line_items (
+    id SERIAL PRIMARY KEY,
+    recurring_invoice_id INTEGER NOT NULL REFERENCES recurring_invoices(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL DEFAULT 1.00,
+    unit VARCHAR(20) NOT NULL DEFAULT 'unit' CHECK (unit IN ('unit', 'hour', 'day', 'month')),
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0.00 CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_recurring_invoice_line_items_recurring_invoice_id ON recurring_invoice_line_items(recurring_invoice_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_recurring_invoice_line_items_recurring_invoice_id;
+DROP TABLE IF EXISTS recurring_invoice_line_items;
+DROP INDEX IF EXISTS idx_recurring_invoices_next_run_date;
+DROP INDEX IF EXISTS idx_recurring_invoices_user_id;
+DROP TABLE IF EXISTS recurring_invoices;
@@ -0,0 +1,23 @@
+-- migrate:up
+-- Pattern used to number invoices created without an explicit number, e.g.
+-- INV-{YYYY}-{SEQ:4}
+ALTER TABLE users ADD COLUMN invoice_number_pattern VARCHAR(100) NOT NULL DEFAULT 'INV-{YYYY}-{SEQ:4}';
+
+-- Replaces {PREFIX} in the invoice number pattern for the client's invoices
+ALTER TABLE clients ADD COLUMN invoice_prefix VARCHAR(20);
+
+-- Last number allocated per user and scope. The scope is the invoice number
+-- rendered without its sequence, so sequences restart whenever the year,
+-- month or client prefix in the number changes.
+CREATE TABLE IF NOT EXISTS invoice_number_sequences (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scope VARCHAR(255) NOT NULL,
+    last_value INTEGER NOT NULL CHECK (last_value >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, scope)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS invoice_number_sequences;
+ALTER TABLE clients DROP COLUMN invoice_prefix;
+ALTER TABLE users DROP COLUMN invoice_number_pattern;
@@ -0,0 +1,53 @@
+-- migrate:up
+-- Credit notes are numbered from their own pattern and sequences
+ALTER TABLE users ADD COLUMN credit_note_number_pattern VARCHAR(100) NOT NULL DEFAULT 'CN-{YYYY}-{SEQ:4}';
+
+ALTER TABLE invoice_number_sequences ADD COLUMN document_type VARCHAR(20) NOT NULL DEFAULT 'invoice' CHECK (document_type IN ('invoice', 'credit_note'));
+ALTER TABLE invoice_number_sequences DROP CONSTRAINT invoice_number_sequences_pkey;
+ALTER TABLE invoice_number_sequences ADD PRIMARY KEY (user_id, document_type, scope);
+
+-- A credit note cancels all or part of an issued invoice. Its totals are
+-- computed with the invoice's taxes when it is issued and never change.
+CREATE TABLE IF NOT EXISTS credit_notes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    credit_note_number VARCHAR(50) NOT NULL,
+    issue_date DATE NOT NULL,
+    reason TEXT,
+    subtotal DECIMAL(12, 2) NOT NULL,
+    tax_amount DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    withholding_amount DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    total DECIMAL(12, 2) NOT NULL CHECK (total > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT credit_notes_user_credit_note_number_unique UNIQUE (user_id, credit_note_number)
+);
+
+CREATE INDEX idx_credit_notes_invoice_id ON credit_notes(invoice_id);
+
+CREATE TABLE IF NOT EXISTS credit_note_line_items (
+    id SERIAL PRIMARY KEY,
+    credit_note_id INTEGER NOT NULL REFERENCES credit_notes(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL DEFAULT 1.00,
+    unit VARCHAR(20) NOT NULL DEFAULT 'unit' CHECK (unit IN ('unit', 'hour', 'day', 'month')),
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0.00 CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_credit_note_line_items_credit_note_id ON credit_note_line_items(credit_note_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_credit_note_line_items_credit_note_id;
+DROP TABLE IF EXISTS credit_note_line_items;
+DROP INDEX IF EXISTS idx_credit_notes_invoice_id;
+DROP TABLE IF EXISTS credit_notes;
+
+DELETE FROM invoice_number_sequences WHERE document_type <> 'invoice';
+ALTER TABLE invoice_number_sequences DROP CONSTRAINT invoice_number_sequences_pkey;
+ALTER TABLE invoice_number_sequences DROP COLUMN document_type;
+ALTER TABLE invoice_number_sequences ADD PRIMARY KEY (user_id, scope);
+
+ALTER TABLE users DROP COLUMN credit_note_number_pattern;
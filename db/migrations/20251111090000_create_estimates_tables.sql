@@ -0,0 +1,53 @@
+-- migrate:up
+-- Estimates are numbered from their own pattern and sequences
+ALTER TABLE users ADD COLUMN estimate_number_pattern VARCHAR(100) NOT NULL DEFAULT 'EST-{YYYY}-{SEQ:4}';
+
+ALTER TABLE invoice_number_sequences DROP CONSTRAINT invoice_number_sequences_document_type_check;
+ALTER TABLE invoice_number_sequences ADD CONSTRAINT invoice_number_sequences_document_type_check CHECK (document_type IN ('invoice', 'credit_note', 'estimate'));
+
+-- An estimate quotes work to a client before it starts. Once accepted it is
+-- converted into an invoice, which it keeps a reference to.
+CREATE TABLE IF NOT EXISTS estimates (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    estimate_number VARCHAR(50) NOT NULL,
+    issue_date DATE NOT NULL,
+    valid_until DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'accepted', 'declined', 'expired')),
+    notes TEXT,
+    invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT estimates_user_estimate_number_unique UNIQUE (user_id, estimate_number),
+    CHECK (valid_until >= issue_date)
+);
+
+CREATE INDEX idx_estimates_user_id ON estimates(user_id);
+CREATE INDEX idx_estimates_valid_until ON estimates(valid_until) WHERE status = 'sent';
+
+CREATE TABLE IF NOT EXISTS estimate_line_items (
+    id SERIAL PRIMARY KEY,
+    estimate_id INTEGER NOT NULL REFERENCES estimates(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL DEFAULT 1.00,
+    unit VARCHAR(20) NOT NULL DEFAULT 'unit' CHECK (unit IN ('unit', 'hour', 'day', 'month')),
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0.00 CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_estimate_line_items_estimate_id ON estimate_line_items(estimate_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_estimate_line_items_estimate_id;
+DROP TABLE IF EXISTS estimate_line_items;
+DROP INDEX IF EXISTS idx_estimates_valid_until;
+DROP INDEX IF EXISTS idx_estimates_user_id;
+DROP TABLE IF EXISTS estimates;
+
+DELETE FROM invoice_number_sequences WHERE document_type = 'estimate';
+ALTER TABLE invoice_number_sequences DROP CONSTRAINT invoice_number_sequences_document_type_check;
+ALTER TABLE invoice_number_sequences ADD CONSTRAINT invoice_number_sequences_document_type_check CHECK (document_type IN ('invoice', 'credit_note'));
+
+ALTER TABLE users DROP COLUMN estimate_number_pattern;
@@ -0,0 +1,15 @@
+-- migrate:up
+-- Once an invoice leaves draft it can only be changed by revising it. Each
+-- revision keeps a JSON snapshot of the invoice as it was before the change.
+CREATE TABLE IF NOT EXISTS invoice_revisions (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL CHECK (revision > 0),
+    reason TEXT,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT invoice_revisions_invoice_revision_unique UNIQUE (invoice_id, revision)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS invoice_revisions;
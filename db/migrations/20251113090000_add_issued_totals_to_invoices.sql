@@ -0,0 +1,92 @@
+-- migrate:up
+-- Totals are frozen when an invoice leaves draft, in the client's currency
+-- and converted to the user's currency at the exchange rate of that day, so
+-- reports do not drift as rates and rates of pay change
+ALTER TABLE invoices ADD COLUMN currency VARCHAR(3);
+ALTER TABLE invoices ADD COLUMN subtotal DECIMAL(12, 2);
+ALTER TABLE invoices ADD COLUMN tax_amount DECIMAL(12, 2);
+ALTER TABLE invoices ADD COLUMN withholding_amount DECIMAL(12, 2);
+ALTER TABLE invoices ADD COLUMN total DECIMAL(12, 2);
+ALTER TABLE invoices ADD COLUMN reporting_currency VARCHAR(3);
+ALTER TABLE invoices ADD COLUMN exchange_rate DECIMAL(20, 10) CHECK (exchange_rate > 0);
+
+-- Invoices issued before totals were frozen are frozen at their current
+-- totals, computed like billing.Summarize: lines and taxes are rounded half up
+-- to the currency's minor unit, compound taxes are charged on the subtotal
+-- plus the taxes before them, withholding is deducted and reverse-charged
+-- invoices carry withholding only. They get no exchange rate, so reports keep
+-- converting them at the current rate.
+DO $$
+DECLARE
+    inv RECORD;
+    tax RECORD;
+    places INTEGER;
+    inv_subtotal NUMERIC;
+    inv_tax NUMERIC;
+    inv_withholding NUMERIC;
+    amount NUMERIC;
+BEGIN
+    FOR inv IN
+        SELECT i.id, i.reverse_charge, c.currency, COALESCE(u.currency, 'USD') AS reporting_currency
+        FROM invoices i
+        INNER JOIN clients c ON c.id = i.client_id
+        INNER JOIN users u ON u.id = i.user_id
+        WHERE i.status <> 'draft'
+    LOOP
+        places := CASE WHEN inv.currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'IDR', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF', 'UGX', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 0 ELSE 2 END;
+
+        SELECT COALESCE(SUM(ROUND(te.hours * COALESCE(te.hourly_rate, 0), places)), 0)
+        INTO inv_subtotal
+        FROM time_entries te
+        INNER JOIN invoice_time_entries ite ON ite.time_entry_id = te.id
+        WHERE ite.invoice_id = inv.id;
+
+        SELECT inv_subtotal + COALESCE(SUM(ROUND(li.quantity * li.unit_price, places)), 0),
+               COALESCE(SUM(ROUND(li.quantity * li.unit_price * li.tax_rate / 100, places)), 0)
+        INTO inv_subtotal, inv_tax
+        FROM invoice_line_items li
+        WHERE li.invoice_id = inv.id;
+
+        IF inv.reverse_charge THEN
+            inv_tax := 0;
+        END IF;
+        inv_withholding := 0;
+
+        FOR tax IN
+            SELECT it.rate, it.compound, it.withholding
+            FROM invoice_taxes it
+            WHERE it.invoice_id = inv.id
+            ORDER BY it.position, it.id
+        LOOP
+            CONTINUE WHEN inv.reverse_charge AND NOT tax.withholding;
+            IF tax.compound AND NOT tax.withholding THEN
+                amount := ROUND((inv_subtotal + inv_tax) * tax.rate / 100, places);
+            ELSE
+                amount := ROUND(inv_subtotal * tax.rate / 100, places);
+            END IF;
+            IF tax.withholding THEN
+                inv_withholding := inv_withholding + amount;
+            ELSE
+                inv_tax := inv_tax + amount;
+            END IF;
+        END LOOP;
+
+        UPDATE invoices
+        SET currency = inv.currency,
+            subtotal = inv_subtotal,
+            tax_amount = inv_tax,
+            withholding_amount = inv_withholding,
+            total = inv_subtotal + inv_tax - inv_withholding,
+            reporting_currency = inv.reporting_currency
+        WHERE id = inv.id;
+    END LOOP;
+END $$;
+
+-- migrate:down
+ALTER TABLE invoices DROP COLUMN exchange_rate;
+ALTER TABLE invoices DROP COLUMN reporting_currency;
+ALTER TABLE invoices DROP COLUMN total;
+ALTER TABLE invoices DROP COLUMN withholding_amount;
+ALTER TABLE invoices DROP COLUMN tax_amount;
+ALTER TABLE invoices DROP COLUMN subtotal;
+ALTER TABLE invoices DROP COLUMN currency;
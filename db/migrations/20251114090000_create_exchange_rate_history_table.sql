@@ -0,0 +1,22 @@
+-- migrate:up
+-- exchange_rates only holds the latest rate of each currency pair. The
+-- history keeps one rate per pair and day so amounts can be converted at the
+-- rate of a past date.
+CREATE TABLE IF NOT EXISTS exchange_rate_history (
+    id SERIAL PRIMARY KEY,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    target_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL,
+    rate_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT exchange_rate_history_pair_date_unique UNIQUE (base_currency, target_currency, rate_date)
+);
+
+-- Seed the history with the rates already fetched
+INSERT INTO exchange_rate_history (base_currency, target_currency, rate, rate_date)
+SELECT base_currency, target_currency, rate, COALESCE(updated_at, CURRENT_TIMESTAMP)::date
+FROM exchange_rates
+ON CONFLICT DO NOTHING;
+
+-- migrate:down
+DROP TABLE IF EXISTS exchange_rate_history;
@@ -0,0 +1,29 @@
+-- migrate:up
+-- Rates a user has fixed for a currency pair, e.g. by contract, used instead
+-- of market rates while they are valid. A missing bound leaves that side of
+-- the validity period open.
+CREATE TABLE IF NOT EXISTS exchange_rate_overrides (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    base_currency VARCHAR(3) NOT NULL,
+    target_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL CHECK (rate > 0),
+    valid_from DATE,
+    valid_to DATE,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (base_currency <> target_currency),
+    CHECK (valid_from IS NULL OR valid_to IS NULL OR valid_from <= valid_to)
+);
+
+CREATE INDEX idx_exchange_rate_overrides_user_id ON exchange_rate_overrides(user_id);
+
+-- Where the exchange rate frozen on an issued invoice came from: the user's
+-- override or the market
+ALTER TABLE invoices ADD COLUMN exchange_rate_source VARCHAR(20);
+
+-- migrate:down
+ALTER TABLE invoices DROP COLUMN exchange_rate_source;
+DROP INDEX IF EXISTS idx_exchange_rate_overrides_user_id;
+DROP TABLE IF EXISTS exchange_rate_overrides;
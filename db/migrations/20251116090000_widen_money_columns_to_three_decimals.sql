@@ -0,0 +1,40 @@
+-- migrate:up
+-- Currencies such as BHD, KWD and TND have three minor units, so prices and
+-- amounts are stored with three decimals. Amounts are still rounded to the
+-- minor unit of their currency before they are saved.
+ALTER TABLE clients ALTER COLUMN hourly_rate TYPE DECIMAL(12, 3);
+ALTER TABLE time_entries ALTER COLUMN hourly_rate TYPE DECIMAL(12, 3);
+ALTER TABLE invoice_line_items ALTER COLUMN unit_price TYPE DECIMAL(12, 3);
+ALTER TABLE recurring_invoice_line_items ALTER COLUMN unit_price TYPE DECIMAL(12, 3);
+ALTER TABLE estimate_line_items ALTER COLUMN unit_price TYPE DECIMAL(12, 3);
+ALTER TABLE credit_note_line_items ALTER COLUMN unit_price TYPE DECIMAL(12, 3);
+ALTER TABLE payments ALTER COLUMN amount TYPE DECIMAL(12, 3);
+
+ALTER TABLE invoices ALTER COLUMN subtotal TYPE DECIMAL(14, 3);
+ALTER TABLE invoices ALTER COLUMN tax_amount TYPE DECIMAL(14, 3);
+ALTER TABLE invoices ALTER COLUMN withholding_amount TYPE DECIMAL(14, 3);
+ALTER TABLE invoices ALTER COLUMN total TYPE DECIMAL(14, 3);
+
+ALTER TABLE credit_notes ALTER COLUMN subtotal TYPE DECIMAL(14, 3);
+ALTER TABLE credit_notes ALTER COLUMN tax_amount TYPE DECIMAL(14, 3);
+ALTER TABLE credit_notes ALTER COLUMN withholding_amount TYPE DECIMAL(14, 3);
+ALTER TABLE credit_notes ALTER COLUMN total TYPE DECIMAL(14, 3);
+
+-- migrate:down
+ALTER TABLE credit_notes ALTER COLUMN total TYPE DECIMAL(12, 2);
+ALTER TABLE credit_notes ALTER COLUMN withholding_amount TYPE DECIMAL(12, 2);
+ALTER TABLE credit_notes ALTER COLUMN tax_amount TYPE DECIMAL(12, 2);
+ALTER TABLE credit_notes ALTER COLUMN subtotal TYPE DECIMAL(12, 2);
+
+ALTER TABLE invoices ALTER COLUMN total TYPE DECIMAL(12, 2);
+ALTER TABLE invoices ALTER COLUMN withholding_amount TYPE DECIMAL(12, 2);
+ALTER TABLE invoices ALTER COLUMN tax_amount TYPE DECIMAL(12, 2);
+ALTER TABLE invoices ALTER COLUMN subtotal TYPE DECIMAL(12, 2);
+
+ALTER TABLE payments ALTER COLUMN amount TYPE DECIMAL(10, 2);
+ALTER TABLE credit_note_line_items ALTER COLUMN unit_price TYPE DECIMAL(10, 2);
+ALTER TABLE estimate_line_items ALTER COLUMN unit_price TYPE DECIMAL(10, 2);
+ALTER TABLE recurring_invoice_line_items ALTER COLUMN unit_price TYPE DECIMAL(10, 2);
+ALTER TABLE invoice_line_items ALTER COLUMN unit_price TYPE DECIMAL(10, 2);
+ALTER TABLE time_entries ALTER COLUMN hourly_rate TYPE DECIMAL(10, 2);
+ALTER TABLE clients ALTER COLUMN hourly_rate TYPE DECIMAL(10, 2);
@@ -0,0 +1,29 @@
+-- migrate:up
+-- Live timers. Time of earlier runs is kept in elapsed_seconds and the current
+-- run started at resumed_at, so a timer keeps counting across server restarts.
+-- A stopped timer is recorded as the time entry it was converted into.
+CREATE TABLE IF NOT EXISTS timers (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'paused', 'stopped')),
+    started_at TIMESTAMP NOT NULL,
+    resumed_at TIMESTAMP,
+    elapsed_seconds INTEGER NOT NULL DEFAULT 0 CHECK (elapsed_seconds >= 0),
+    stopped_at TIMESTAMP,
+    time_entry_id INTEGER REFERENCES time_entries(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((status = 'running') = (resumed_at IS NOT NULL)),
+    CHECK ((status = 'stopped') = (stopped_at IS NOT NULL))
+);
+
+-- A user has at most one timer that is running or paused
+CREATE UNIQUE INDEX timers_user_active_unique ON timers(user_id) WHERE status <> 'stopped';
+CREATE INDEX idx_timers_client_id ON timers(client_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_timers_client_id;
+DROP INDEX IF EXISTS timers_user_active_unique;
+DROP TABLE IF EXISTS timers;
@@ -0,0 +1,15 @@
+-- migrate:up
+-- When the work of a time entry was done. Both are set or neither: entries
+-- recorded as a number of hours on a date have no period.
+ALTER TABLE time_entries ADD COLUMN started_at TIMESTAMPTZ;
+ALTER TABLE time_entries ADD COLUMN ended_at TIMESTAMPTZ;
+ALTER TABLE time_entries ADD CONSTRAINT time_entries_period_check
+    CHECK ((started_at IS NULL) = (ended_at IS NULL) AND (started_at IS NULL OR ended_at > started_at));
+
+CREATE INDEX idx_time_entries_user_id_started_at ON time_entries(user_id, started_at) WHERE started_at IS NOT NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_time_entries_user_id_started_at;
+ALTER TABLE time_entries DROP CONSTRAINT IF EXISTS time_entries_period_check;
+ALTER TABLE time_entries DROP COLUMN ended_at;
+ALTER TABLE time_entries DROP COLUMN started_at;
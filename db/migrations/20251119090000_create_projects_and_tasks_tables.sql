@@ -0,0 +1,55 @@
+-- migrate:up
+-- Projects group a client's work. A project's hourly rate, when set, replaces
+-- the client's rate, and a task's rate replaces the project's. Rates are
+-- captured on time entries when they are recorded, like the client's rate.
+CREATE TABLE IF NOT EXISTS projects (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'archived')),
+    hourly_rate DECIMAL(12, 3) CHECK (hourly_rate >= 0),
+    budget_hours DECIMAL(10, 2) CHECK (budget_hours > 0),
+    budget_amount DECIMAL(14, 3) CHECK (budget_amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_projects_user_id ON projects(user_id);
+CREATE INDEX idx_projects_client_id ON projects(client_id);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id SERIAL PRIMARY KEY,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    hourly_rate DECIMAL(12, 3) CHECK (hourly_rate >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tasks_project_id ON tasks(project_id);
+
+-- Time entries and timers keep their client when their project or task is
+-- deleted, and time entries keep the rate they were recorded at
+ALTER TABLE time_entries ADD COLUMN project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL;
+ALTER TABLE time_entries ADD COLUMN task_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL;
+CREATE INDEX idx_time_entries_project_id ON time_entries(project_id);
+
+ALTER TABLE timers ADD COLUMN project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL;
+ALTER TABLE timers ADD COLUMN task_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL;
+
+-- migrate:down
+ALTER TABLE timers DROP COLUMN task_id;
+ALTER TABLE timers DROP COLUMN project_id;
+
+DROP INDEX IF EXISTS idx_time_entries_project_id;
+ALTER TABLE time_entries DROP COLUMN task_id;
+ALTER TABLE time_entries DROP COLUMN project_id;
+
+DROP INDEX IF EXISTS idx_tasks_project_id;
+DROP TABLE IF EXISTS tasks;
+
+DROP INDEX IF EXISTS idx_projects_client_id;
+DROP INDEX IF EXISTS idx_projects_user_id;
+DROP TABLE IF EXISTS projects;
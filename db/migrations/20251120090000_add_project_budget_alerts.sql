@@ -0,0 +1,38 @@
+-- migrate:up
+-- Percentages of a project's hours and amount budgets at which the user is
+-- alerted. An empty list turns alerts off for the project.
+ALTER TABLE projects ADD COLUMN budget_alert_thresholds INTEGER[] NOT NULL DEFAULT '{75,90,100}';
+
+-- The thresholds a project's budgets have crossed, so each alert is only sent
+-- once. An alert is cleared when a later check finds usage back under its
+-- threshold, e.g. after the budget was raised, so it is sent again the next
+-- time the threshold is crossed.
+CREATE TABLE IF NOT EXISTS project_budget_alerts (
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    budget VARCHAR(10) NOT NULL CHECK (budget IN ('hours', 'amount')),
+    threshold INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, budget, threshold)
+);
+
+-- In-app notifications shown to the user
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    project_id INTEGER REFERENCES projects(id) ON DELETE CASCADE,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at DESC);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_notifications_user_id;
+DROP TABLE IF EXISTS notifications;
+
+DROP TABLE IF EXISTS project_budget_alerts;
+
+ALTER TABLE projects DROP COLUMN budget_alert_thresholds;
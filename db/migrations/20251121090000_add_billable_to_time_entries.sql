@@ -0,0 +1,13 @@
+-- migrate:up
+-- Whether time recorded for a client is billable unless its project says
+-- otherwise. A project without a value follows its client.
+ALTER TABLE clients ADD COLUMN billable BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE projects ADD COLUMN billable BOOLEAN;
+
+-- Non-billable time is tracked but never invoiced or counted as revenue
+ALTER TABLE time_entries ADD COLUMN billable BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- migrate:down
+ALTER TABLE time_entries DROP COLUMN billable;
+ALTER TABLE projects DROP COLUMN billable;
+ALTER TABLE clients DROP COLUMN billable;
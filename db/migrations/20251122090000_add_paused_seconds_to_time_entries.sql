@@ -0,0 +1,10 @@
+-- migrate:up
+-- How long the work was paused within the period of a time entry, e.g. while
+-- its timer was paused. Its hours are the period less the paused time.
+ALTER TABLE time_entries ADD COLUMN paused_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE time_entries ADD CONSTRAINT time_entries_paused_seconds_check
+    CHECK (paused_seconds >= 0 AND (started_at IS NOT NULL OR paused_seconds = 0));
+
+-- migrate:down
+ALTER TABLE time_entries DROP CONSTRAINT IF EXISTS time_entries_paused_seconds_check;
+ALTER TABLE time_entries DROP COLUMN paused_seconds;
@@ -0,0 +1,7 @@
+-- migrate:up
+-- The IANA timezone the user works in, e.g. Europe/Paris, in which the time
+-- their timers record is dated
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN timezone;
@@ -0,0 +1,10 @@
+-- migrate:up
+-- Invoices are numbered when they leave draft, so that deleting a draft never
+-- leaves a gap in the sequence. Drafts have an empty number until then, which
+-- the uniqueness of numbers per user must allow.
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_user_invoice_number_unique;
+CREATE UNIQUE INDEX invoices_user_invoice_number_unique ON invoices (user_id, invoice_number) WHERE invoice_number <> '';
+
+-- migrate:down
+DROP INDEX IF EXISTS invoices_user_invoice_number_unique;
+ALTER TABLE invoices ADD CONSTRAINT invoices_user_invoice_number_unique UNIQUE (user_id, invoice_number);
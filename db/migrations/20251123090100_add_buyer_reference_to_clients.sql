@@ -0,0 +1,7 @@
+-- migrate:up
+-- The reference the client asks to be quoted on its invoices to route them,
+-- e.g. a German Leitweg-ID, sent as the buyer reference of e-invoices
+ALTER TABLE clients ADD COLUMN buyer_reference VARCHAR(100);
+
+-- migrate:down
+ALTER TABLE clients DROP COLUMN buyer_reference;
@@ -0,0 +1,37 @@
+-- name: CreateCreditNote :one
+INSERT INTO credit_notes (user_id, invoice_id, client_id, credit_note_number, issue_date, reason, subtotal, tax_amount, withholding_amount, total)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, user_id, invoice_id, client_id, credit_note_number, issue_date, reason, subtotal, tax_amount, withholding_amount, total, created_at;
+
+-- name: GetCreditNoteByID :one
+SELECT id, user_id, invoice_id, client_id, credit_note_number, issue_date, reason, subtotal, tax_amount, withholding_amount, total, created_at
+FROM credit_notes
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetCreditNotesByUserID :many
+SELECT id, user_id, invoice_id, client_id, credit_note_number, issue_date, reason, subtotal, tax_amount, withholding_amount, total, created_at
+FROM credit_notes
+WHERE user_id = $1
+ORDER BY issue_date DESC, id DESC;
+
+-- name: GetCreditNotesByInvoiceID :many
+SELECT id, user_id, invoice_id, client_id, credit_note_number, issue_date, reason, subtotal, tax_amount, withholding_amount, total, created_at
+FROM credit_notes
+WHERE invoice_id = $1
+ORDER BY issue_date, id;
+
+-- name: CreditNoteNumberExists :one
+SELECT EXISTS (
+    SELECT 1 FROM credit_notes WHERE user_id = $1 AND credit_note_number = $2
+);
+
+-- name: CreateCreditNoteLineItem :one
+INSERT INTO credit_note_line_items (credit_note_id, description, quantity, unit, unit_price, tax_rate, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, credit_note_id, description, quantity, unit, unit_price, tax_rate, position;
+
+-- name: GetCreditNoteLineItems :many
+SELECT id, credit_note_id, description, quantity, unit, unit_price, tax_rate, position
+FROM credit_note_line_items
+WHERE credit_note_id = $1
+ORDER BY position, id;
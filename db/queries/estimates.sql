@@ -0,0 +1,65 @@
+-- name: CreateEstimate :one
+INSERT INTO estimates (user_id, client_id, estimate_number, issue_date, valid_until, status, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at;
+
+-- name: GetEstimateByID :one
+SELECT id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at
+FROM estimates
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetEstimatesByUserID :many
+SELECT id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at
+FROM estimates
+WHERE user_id = $1
+ORDER BY issue_date DESC, id DESC;
+
+-- name: UpdateEstimate :one
+UPDATE estimates
+SET client_id = $3, estimate_number = $4, issue_date = $5, valid_until = $6, status = $7, notes = $8, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at;
+
+-- name: UpdateEstimateStatus :one
+UPDATE estimates
+SET status = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at;
+
+-- name: MarkEstimateConverted :one
+UPDATE estimates
+SET status = 'accepted', invoice_id = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at;
+
+-- name: ExpireEstimates :many
+-- Sent estimates that were neither accepted nor declined expire after their
+-- validity date
+UPDATE estimates
+SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+WHERE status = 'sent' AND invoice_id IS NULL AND valid_until < $1
+RETURNING id, user_id, client_id, estimate_number, issue_date, valid_until, status, notes, invoice_id, created_at, updated_at;
+
+-- name: DeleteEstimate :exec
+DELETE FROM estimates
+WHERE id = $1 AND user_id = $2;
+
+-- name: EstimateNumberExists :one
+SELECT EXISTS (
+    SELECT 1 FROM estimates WHERE user_id = $1 AND estimate_number = $2
+);
+
+-- name: CreateEstimateLineItem :one
+INSERT INTO estimate_line_items (estimate_id, description, quantity, unit, unit_price, tax_rate, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, estimate_id, description, quantity, unit, unit_price, tax_rate, position;
+
+-- name: GetEstimateLineItems :many
+SELECT id, estimate_id, description, quantity, unit, unit_price, tax_rate, position
+FROM estimate_line_items
+WHERE estimate_id = $1
+ORDER BY position, id;
+
+-- name: DeleteEstimateLineItems :exec
+DELETE FROM estimate_line_items
+WHERE estimate_id = $1;
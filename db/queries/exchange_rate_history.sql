@@ -0,0 +1,19 @@
+-- name: UpsertExchangeRateHistory :exec
+INSERT INTO exchange_rate_history (base_currency, target_currency, rate, rate_date)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (base_currency, target_currency, rate_date)
+DO UPDATE SET rate = $3;
+
+-- name: GetExchangeRateAt :one
+-- Returns the latest rate of the pair on or before the date
+SELECT id, base_currency, target_currency, rate, rate_date, created_at
+FROM exchange_rate_history
+WHERE base_currency = $1 AND target_currency = $2 AND rate_date <= $3
+ORDER BY rate_date DESC
+LIMIT 1;
+
+-- name: GetExchangeRateHistory :many
+SELECT id, base_currency, target_currency, rate, rate_date, created_at
+FROM exchange_rate_history
+WHERE base_currency = $1 AND target_currency = $2 AND rate_date >= sqlc.arg(from_date) AND rate_date <= sqlc.arg(to_date)
+ORDER BY rate_date ASC;
@@ -0,0 +1,32 @@
+-- name: CreateExchangeRateOverride :one
+INSERT INTO exchange_rate_overrides (user_id, base_currency, target_currency, rate, valid_from, valid_to, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, base_currency, target_currency, rate, valid_from, valid_to, note, created_at, updated_at;
+
+-- name: GetExchangeRateOverridesByUserID :many
+SELECT id, user_id, base_currency, target_currency, rate, valid_from, valid_to, note, created_at, updated_at
+FROM exchange_rate_overrides
+WHERE user_id = $1
+ORDER BY base_currency, target_currency, valid_from NULLS FIRST;
+
+-- name: UpdateExchangeRateOverride :one
+UPDATE exchange_rate_overrides
+SET base_currency = $3, target_currency = $4, rate = $5, valid_from = $6, valid_to = $7, note = $8, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, base_currency, target_currency, rate, valid_from, valid_to, note, created_at, updated_at;
+
+-- name: DeleteExchangeRateOverride :exec
+DELETE FROM exchange_rate_overrides
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetApplicableExchangeRateOverride :one
+-- Returns the user's override for the currency pair, in either direction, valid
+-- on the date. Overrides with a later start win over open-ended ones.
+SELECT id, user_id, base_currency, target_currency, rate, valid_from, valid_to, note, created_at, updated_at
+FROM exchange_rate_overrides
+WHERE user_id = $1
+  AND ((base_currency = $2 AND target_currency = $3) OR (base_currency = $3 AND target_currency = $2))
+  AND (valid_from IS NULL OR valid_from <= CAST(sqlc.arg(on_date) AS DATE))
+  AND (valid_to IS NULL OR valid_to >= CAST(sqlc.arg(on_date) AS DATE))
+ORDER BY valid_from DESC NULLS LAST, id DESC
+LIMIT 1;
@@ -14,6 +14,12 @@ SELECT id, base_currency, target_currency, rate, updated_at
 FROM exchange_rates
 ORDER BY base_currency, target_currency;
 
+-- name: GetCurrenciesInUse :many
+SELECT currency FROM clients
+UNION
+SELECT currency FROM users WHERE currency IS NOT NULL
+ORDER BY currency;
+
 -- name: DeleteOldExchangeRates :exec
 DELETE FROM exchange_rates
 WHERE updated_at < $1;
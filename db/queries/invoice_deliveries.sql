@@ -0,0 +1,10 @@
+-- name: CreateInvoiceDelivery :one
+INSERT INTO invoice_deliveries (invoice_id, recipient, cc, subject, message, message_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, invoice_id, recipient, cc, subject, message, message_id, sent_at;
+
+-- name: GetInvoiceDeliveries :many
+SELECT id, invoice_id, recipient, cc, subject, message, message_id, sent_at
+FROM invoice_deliveries
+WHERE invoice_id = $1
+ORDER BY sent_at DESC, id DESC;
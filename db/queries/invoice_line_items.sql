@@ -0,0 +1,14 @@
+-- name: CreateInvoiceLineItem :one
+INSERT INTO invoice_line_items (invoice_id, description, quantity, unit, unit_price, tax_rate, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, invoice_id, description, quantity, unit, unit_price, tax_rate, position, created_at, updated_at;
+
+-- name: GetInvoiceLineItems :many
+SELECT id, invoice_id, description, quantity, unit, unit_price, tax_rate, position, created_at, updated_at
+FROM invoice_line_items
+WHERE invoice_id = $1
+ORDER BY position, id;
+
+-- name: DeleteInvoiceLineItems :exec
+DELETE FROM invoice_line_items
+WHERE invoice_id = $1;
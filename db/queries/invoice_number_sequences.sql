@@ -0,0 +1,20 @@
+-- name: IncrementInvoiceNumberSequence :one
+-- The row stays locked until the transaction ends, so concurrent documents in
+-- the same scope are numbered one after the other and a rolled back document
+-- gives its number back
+INSERT INTO invoice_number_sequences (user_id, document_type, scope, last_value)
+VALUES ($1, $2, $3, 1)
+ON CONFLICT (user_id, document_type, scope) DO UPDATE
+SET last_value = invoice_number_sequences.last_value + 1,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING last_value;
+
+-- name: GetInvoiceNumberSequence :one
+SELECT last_value
+FROM invoice_number_sequences
+WHERE user_id = $1 AND document_type = $2 AND scope = $3;
+
+-- name: InvoiceNumberExists :one
+SELECT EXISTS (
+    SELECT 1 FROM invoices WHERE user_id = $1 AND invoice_number = $2
+);
@@ -0,0 +1,21 @@
+-- name: CreateInvoiceRevision :one
+INSERT INTO invoice_revisions (invoice_id, revision, reason, snapshot)
+VALUES ($1, $2, $3, $4)
+RETURNING id, invoice_id, revision, reason, snapshot, created_at;
+
+-- name: GetInvoiceRevisions :many
+SELECT id, invoice_id, revision, reason, snapshot, created_at
+FROM invoice_revisions
+WHERE invoice_id = $1
+ORDER BY revision ASC;
+
+-- name: GetInvoiceRevision :one
+SELECT id, invoice_id, revision, reason, snapshot, created_at
+FROM invoice_revisions
+WHERE invoice_id = $1 AND revision = $2;
+
+-- name: GetLatestInvoiceRevision :one
+-- Returns 0 when the invoice has never been revised
+SELECT CAST(COALESCE(MAX(revision), 0) AS INTEGER) AS revision
+FROM invoice_revisions
+WHERE invoice_id = $1;
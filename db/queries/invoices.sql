@@ -1,50 +1,107 @@
 -- name: CreateInvoice :one
-INSERT INTO invoices (user_id, client_id, invoice_number, issue_date, due_date, status, notes)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at;
+INSERT INTO invoices (user_id, client_id, invoice_number, issue_date, due_date, status, notes, reverse_charge)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
 
 -- name: GetInvoiceByID :one
-SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 FROM invoices
 WHERE id = $1 AND user_id = $2;
 
+-- name: GetInvoiceCurrency :one
+-- Returns the currency of an invoice: the one frozen when it was issued, or
+-- the client's currency for drafts
+SELECT CAST(COALESCE(i.currency, c.currency) AS VARCHAR) AS currency
+FROM invoices i
+INNER JOIN clients c ON c.id = i.client_id
+WHERE i.id = $1;
+
 -- name: GetInvoicesByUserID :many
-SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 FROM invoices
 WHERE user_id = $1
 ORDER BY created_at DESC;
 
+-- name: LockInvoice :one
+-- Returns an invoice and locks it until the end of the transaction, so that
+-- payments, credit notes and revisions are checked against its balance one
+-- at a time
+SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
+FROM invoices
+WHERE id = $1 AND user_id = $2
+FOR UPDATE;
+
 -- name: UpdateInvoice :one
+-- Updates a draft invoice. No row is returned once the invoice has left
+-- draft, even if it did so after it was read.
 UPDATE invoices
-SET client_id = $3, invoice_number = $4, issue_date = $5, due_date = $6, status = $7, notes = $8, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at;
+SET client_id = $3, invoice_number = $4, issue_date = $5, due_date = $6, status = $7, notes = $8, reverse_charge = $9, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status = 'draft'
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
+
+-- name: UpdateIssuedInvoice :one
+-- Updates the details of an invoice that has left draft when it is revised,
+-- keeping its status
+UPDATE invoices
+SET client_id = $3, invoice_number = $4, issue_date = $5, due_date = $6, notes = $7, reverse_charge = $8, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status <> 'draft'
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
 
 -- name: UpdateInvoiceStatus :one
 UPDATE invoices
-SET status = $3, updated_at = CURRENT_TIMESTAMP
+SET status = $3,
+    overdue_at = CASE WHEN $3 = 'overdue' AND status <> 'overdue' THEN CURRENT_TIMESTAMP ELSE overdue_at END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
+
+-- name: MarkInvoicesOverdue :many
+UPDATE invoices
+SET status = 'overdue', overdue_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('sent', 'partially_paid') AND due_date < $1
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
+
+-- name: SetInvoiceNumber :one
+-- Gives an invoice the number allocated to it when it leaves draft
+UPDATE invoices
+SET invoice_number = $3, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at;
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
 
--- name: DeleteInvoice :exec
+-- name: SetInvoiceTotals :one
+-- Freezes the totals of an invoice that has left draft, in its own currency
+-- and converted to the user's currency at the rate used when it was issued
+UPDATE invoices
+SET currency = $3, subtotal = $4, tax_amount = $5, withholding_amount = $6, total = $7, reporting_currency = $8, exchange_rate = $9, exchange_rate_source = $10
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source;
+
+-- name: DeleteInvoice :execrows
+-- Deletes an invoice while it is still a draft
 DELETE FROM invoices
-WHERE id = $1 AND user_id = $2;
+WHERE id = $1 AND user_id = $2 AND status = 'draft';
 
 -- name: AddTimeEntryToInvoice :exec
 INSERT INTO invoice_time_entries (invoice_id, time_entry_id)
 VALUES ($1, $2);
 
 -- name: GetInvoiceTimeEntries :many
-SELECT te.id, te.user_id, te.client_id, te.date, te.hours, te.description, te.hourly_rate, te.created_at, te.updated_at
+-- Returns the time entries billed on an invoice grouped by project, with
+-- the entries without a project last
+SELECT te.id, te.user_id, te.client_id, te.project_id, te.task_id, te.date, te.hours, te.description, te.hourly_rate, te.billable, te.created_at, te.updated_at, p.name AS project_name
 FROM time_entries te
 INNER JOIN invoice_time_entries ite ON te.id = ite.time_entry_id
-WHERE ite.invoice_id = $1;
+LEFT JOIN projects p ON p.id = te.project_id
+WHERE ite.invoice_id = $1
+ORDER BY p.name NULLS LAST, te.project_id, te.date, te.id;
 
 -- name: GetAvailableTimeEntriesForClient :many
-SELECT te.id, te.user_id, te.client_id, te.date, te.hours, te.description, te.hourly_rate, te.created_at, te.updated_at
+-- Returns the client's billable time entries not billed on any invoice yet
+SELECT te.id, te.user_id, te.client_id, te.project_id, te.task_id, te.date, te.hours, te.description, te.hourly_rate, te.billable, te.created_at, te.updated_at
 FROM time_entries te
 WHERE te.client_id = $1
   AND te.user_id = $2
+  AND te.billable
   AND NOT EXISTS (
     SELECT 1 FROM invoice_time_entries ite WHERE ite.time_entry_id = te.id
   )
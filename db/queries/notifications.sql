@@ -0,0 +1,28 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, type, title, message, project_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, type, title, message, project_id, read_at, created_at;
+
+-- name: GetNotificationsByUserID :many
+-- Returns the user's most recent notifications, newest first
+SELECT id, user_id, type, title, message, project_id, read_at, created_at
+FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, type, title, message, project_id, read_at, created_at;
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND read_at IS NULL;
@@ -0,0 +1,25 @@
+-- name: CreatePayment :one
+INSERT INTO payments (invoice_id, amount, currency, payment_date, method, reference)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, invoice_id, amount, currency, payment_date, method, reference, created_at, updated_at;
+
+-- name: GetPaymentByID :one
+SELECT id, invoice_id, amount, currency, payment_date, method, reference, created_at, updated_at
+FROM payments
+WHERE id = $1 AND invoice_id = $2;
+
+-- name: GetPaymentsByInvoiceID :many
+SELECT id, invoice_id, amount, currency, payment_date, method, reference, created_at, updated_at
+FROM payments
+WHERE invoice_id = $1
+ORDER BY payment_date, id;
+
+-- name: UpdatePayment :one
+UPDATE payments
+SET amount = $3, currency = $4, payment_date = $5, method = $6, reference = $7, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND invoice_id = $2
+RETURNING id, invoice_id, amount, currency, payment_date, method, reference, created_at, updated_at;
+
+-- name: DeletePayment :execrows
+DELETE FROM payments
+WHERE id = $1 AND invoice_id = $2;
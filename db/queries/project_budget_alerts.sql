@@ -0,0 +1,13 @@
+-- name: CreateProjectBudgetAlert :one
+-- Records that a project's budget crossed a threshold. Returns no rows when it
+-- already had, so each alert is only sent once.
+INSERT INTO project_budget_alerts (project_id, budget, threshold)
+VALUES ($1, $2, $3)
+ON CONFLICT (project_id, budget, threshold) DO NOTHING
+RETURNING project_id, budget, threshold, created_at;
+
+-- name: DeleteProjectBudgetAlertsAbove :exec
+-- Clears the alerts of a project's budget whose threshold is above the
+-- percentage used, so they are sent again when it is crossed again
+DELETE FROM project_budget_alerts
+WHERE project_id = $1 AND budget = $2 AND threshold > $3;
@@ -0,0 +1,41 @@
+-- name: CreateProject :one
+INSERT INTO projects (user_id, client_id, name, description, status, hourly_rate, budget_hours, budget_amount, budget_alert_thresholds, billable)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, user_id, client_id, name, description, status, hourly_rate, budget_hours, budget_amount, created_at, updated_at, budget_alert_thresholds, billable;
+
+-- name: GetProjectByID :one
+SELECT id, user_id, client_id, name, description, status, hourly_rate, budget_hours, budget_amount, created_at, updated_at, budget_alert_thresholds, billable
+FROM projects
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetProjectsByUserID :many
+SELECT id, user_id, client_id, name, description, status, hourly_rate, budget_hours, budget_amount, created_at, updated_at, budget_alert_thresholds, billable
+FROM projects
+WHERE user_id = $1
+ORDER BY name, id;
+
+-- name: GetProjectsByClientID :many
+SELECT id, user_id, client_id, name, description, status, hourly_rate, budget_hours, budget_amount, created_at, updated_at, budget_alert_thresholds, billable
+FROM projects
+WHERE client_id = $1 AND user_id = $2
+ORDER BY name, id;
+
+-- name: UpdateProject :one
+UPDATE projects
+SET name = $3, description = $4, status = $5, hourly_rate = $6, budget_hours = $7, budget_amount = $8, budget_alert_thresholds = $9, billable = $10, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, name, description, status, hourly_rate, budget_hours, budget_amount, created_at, updated_at, budget_alert_thresholds, billable;
+
+-- name: DeleteProject :exec
+DELETE FROM projects
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetProjectDailyUsage :many
+-- Returns the hours and amount recorded against a project per day. The hours
+-- count all time, the amount only billable time at the rate each entry was
+-- recorded at.
+SELECT date, CAST(SUM(hours) AS TEXT) AS hours, CAST(SUM(CASE WHEN billable THEN hours * COALESCE(hourly_rate, 0) ELSE 0 END) AS TEXT) AS amount
+FROM time_entries
+WHERE project_id = $1
+GROUP BY date
+ORDER BY date ASC;
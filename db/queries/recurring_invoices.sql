@@ -0,0 +1,59 @@
+-- name: CreateRecurringInvoice :one
+INSERT INTO recurring_invoices (user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date, last_run_date, created_at, updated_at;
+
+-- name: GetRecurringInvoiceByID :one
+SELECT id, user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date, last_run_date, created_at, updated_at
+FROM recurring_invoices
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetRecurringInvoicesByUserID :many
+SELECT id, user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date, last_run_date, created_at, updated_at
+FROM recurring_invoices
+WHERE user_id = $1
+ORDER BY name, id;
+
+-- name: UpdateRecurringInvoice :one
+UPDATE recurring_invoices
+SET client_id = $3, name = $4, frequency = $5, anchor_day = $6, start_date = $7, end_date = $8,
+    payment_terms_days = $9, notes = $10, auto_send = $11, status = $12, next_run_date = $13,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date, last_run_date, created_at, updated_at;
+
+-- name: UpdateRecurringInvoiceSchedule :one
+UPDATE recurring_invoices
+SET status = $3, next_run_date = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date, last_run_date, created_at, updated_at;
+
+-- name: AdvanceRecurringInvoice :exec
+UPDATE recurring_invoices
+SET status = $2, next_run_date = $3, last_run_date = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteRecurringInvoice :exec
+DELETE FROM recurring_invoices
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetDueRecurringInvoices :many
+SELECT id, user_id, client_id, name, frequency, anchor_day, start_date, end_date, payment_terms_days, notes, auto_send, status, next_run_date, last_run_date, created_at, updated_at
+FROM recurring_invoices
+WHERE status = 'active' AND next_run_date <= sqlc.arg(today)::date
+ORDER BY next_run_date, id;
+
+-- name: CreateRecurringInvoiceLineItem :one
+INSERT INTO recurring_invoice_line_items (recurring_invoice_id, description, quantity, unit, unit_price, tax_rate, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, recurring_invoice_id, description, quantity, unit, unit_price, tax_rate, position;
+
+-- name: GetRecurringInvoiceLineItems :many
+SELECT id, recurring_invoice_id, description, quantity, unit, unit_price, tax_rate, position
+FROM recurring_invoice_line_items
+WHERE recurring_invoice_id = $1
+ORDER BY position, id;
+
+-- name: DeleteRecurringInvoiceLineItems :exec
+DELETE FROM recurring_invoice_line_items
+WHERE recurring_invoice_id = $1;
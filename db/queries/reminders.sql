@@ -0,0 +1,49 @@
+-- name: CreateReminderRule :one
+INSERT INTO reminder_rules (user_id, offset_days, subject, message, enabled)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, offset_days, subject, message, enabled, created_at, updated_at;
+
+-- name: GetReminderRulesByUserID :many
+SELECT id, user_id, offset_days, subject, message, enabled, created_at, updated_at
+FROM reminder_rules
+WHERE user_id = $1
+ORDER BY offset_days;
+
+-- name: UpdateReminderRule :one
+UPDATE reminder_rules
+SET offset_days = $3, subject = $4, message = $5, enabled = $6, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, offset_days, subject, message, enabled, created_at, updated_at;
+
+-- name: DeleteReminderRule :exec
+DELETE FROM reminder_rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetDueReminders :many
+-- Returns the latest reminder of each unpaid invoice that has fallen due by
+-- today, unless it or a later one was already sent. Reminders missed on the
+-- days the job did not run are caught up, but only the latest of them is sent.
+SELECT DISTINCT ON (i.id) i.id AS invoice_id, i.user_id, r.id AS reminder_rule_id, r.offset_days, r.subject, r.message
+FROM invoices i
+INNER JOIN clients c ON c.id = i.client_id
+INNER JOIN reminder_rules r ON r.user_id = i.user_id
+WHERE r.enabled = TRUE
+  AND c.reminders_opt_out = FALSE
+  AND i.status IN ('sent', 'partially_paid', 'overdue')
+  AND i.due_date + r.offset_days <= sqlc.arg(today)::date
+  AND NOT EXISTS (
+    SELECT 1 FROM invoice_reminders ir
+    WHERE ir.invoice_id = i.id AND ir.offset_days >= r.offset_days AND ir.status = 'sent'
+  )
+ORDER BY i.id, r.offset_days DESC;
+
+-- name: CreateInvoiceReminder :one
+INSERT INTO invoice_reminders (invoice_id, reminder_rule_id, offset_days, recipient, subject, status, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, invoice_id, reminder_rule_id, offset_days, recipient, subject, status, error, sent_at;
+
+-- name: GetInvoiceReminders :many
+SELECT id, invoice_id, reminder_rule_id, offset_days, recipient, subject, status, error, sent_at
+FROM invoice_reminders
+WHERE invoice_id = $1
+ORDER BY sent_at DESC, id DESC;
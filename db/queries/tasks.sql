@@ -0,0 +1,25 @@
+-- name: CreateTask :one
+INSERT INTO tasks (project_id, name, hourly_rate)
+VALUES ($1, $2, $3)
+RETURNING id, project_id, name, hourly_rate, created_at, updated_at;
+
+-- name: GetTaskByID :one
+SELECT id, project_id, name, hourly_rate, created_at, updated_at
+FROM tasks
+WHERE id = $1 AND project_id = $2;
+
+-- name: GetTasksByProjectID :many
+SELECT id, project_id, name, hourly_rate, created_at, updated_at
+FROM tasks
+WHERE project_id = $1
+ORDER BY name, id;
+
+-- name: UpdateTask :one
+UPDATE tasks
+SET name = $3, hourly_rate = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND project_id = $2
+RETURNING id, project_id, name, hourly_rate, created_at, updated_at;
+
+-- name: DeleteTask :exec
+DELETE FROM tasks
+WHERE id = $1 AND project_id = $2;
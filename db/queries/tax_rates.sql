@@ -0,0 +1,46 @@
+-- name: CreateTaxRate :one
+INSERT INTO tax_rates (user_id, name, rate, compound, withholding, is_default)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, name, rate, compound, withholding, is_default, created_at, updated_at;
+
+-- name: GetTaxRateByID :one
+SELECT id, user_id, name, rate, compound, withholding, is_default, created_at, updated_at
+FROM tax_rates
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetTaxRatesByUserID :many
+SELECT id, user_id, name, rate, compound, withholding, is_default, created_at, updated_at
+FROM tax_rates
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: GetDefaultTaxRatesByUserID :many
+SELECT id, user_id, name, rate, compound, withholding, is_default, created_at, updated_at
+FROM tax_rates
+WHERE user_id = $1 AND is_default = TRUE
+ORDER BY id;
+
+-- name: UpdateTaxRate :one
+UPDATE tax_rates
+SET name = $3, rate = $4, compound = $5, withholding = $6, is_default = $7, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, rate, compound, withholding, is_default, created_at, updated_at;
+
+-- name: DeleteTaxRate :exec
+DELETE FROM tax_rates
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateInvoiceTax :one
+INSERT INTO invoice_taxes (invoice_id, tax_rate_id, name, rate, compound, withholding, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, invoice_id, tax_rate_id, name, rate, compound, withholding, position;
+
+-- name: GetInvoiceTaxes :many
+SELECT id, invoice_id, tax_rate_id, name, rate, compound, withholding, position
+FROM invoice_taxes
+WHERE invoice_id = $1
+ORDER BY position, id;
+
+-- name: DeleteInvoiceTaxes :exec
+DELETE FROM invoice_taxes
+WHERE invoice_id = $1;
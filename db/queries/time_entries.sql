@@ -1,44 +1,74 @@
 -- name: CreateTimeEntry :one
-INSERT INTO time_entries (user_id, client_id, date, hours, description, hourly_rate)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at;
+INSERT INTO time_entries (user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at;
 
 -- name: GetTimeEntryByID :one
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE id = $1 AND user_id = $2;
 
 -- name: GetTimeEntriesByUserID :many
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE user_id = $1
-ORDER BY date DESC, created_at DESC;
+ORDER BY date DESC, started_at DESC NULLS LAST, created_at DESC;
 
 -- name: GetTimeEntriesByClientID :many
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE client_id = $1 AND user_id = $2
-ORDER BY date DESC, created_at DESC;
+ORDER BY date DESC, started_at DESC NULLS LAST, created_at DESC;
 
 -- name: UpdateTimeEntry :one
 UPDATE time_entries
-SET client_id = $3, date = $4, hours = $5, description = $6, hourly_rate = $7, updated_at = CURRENT_TIMESTAMP
+SET client_id = $3, project_id = $4, task_id = $5, date = $6, hours = $7, description = $8, hourly_rate = $9, billable = $10, started_at = $11, ended_at = $12, paused_seconds = $13, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at;
+RETURNING id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at;
 
--- name: DeleteTimeEntry :exec
+-- name: IsTimeEntryInvoiced :one
+-- Time entries billed on an invoice, draft or not, stay billable
+SELECT EXISTS (
+    SELECT 1
+    FROM invoice_time_entries ite
+    WHERE ite.time_entry_id = $1
+) AS invoiced;
+
+-- name: IsTimeEntryLocked :one
+-- Time entries billed on an invoice that has left draft can no longer change
+SELECT EXISTS (
+    SELECT 1
+    FROM invoice_time_entries ite
+    INNER JOIN invoices i ON i.id = ite.invoice_id
+    WHERE ite.time_entry_id = $1 AND i.status <> 'draft'
+) AS locked;
+
+-- name: DeleteTimeEntry :one
+-- Returns the project the deleted time entry was recorded against
 DELETE FROM time_entries
-WHERE id = $1 AND user_id = $2;
+WHERE id = $1 AND user_id = $2
+RETURNING project_id;
 
 -- name: GetTimeEntriesByDateRange :many
-SELECT date, CAST(SUM(CAST(hours AS DECIMAL)) AS TEXT) as total_hours
+SELECT date, CAST(SUM(CAST(hours AS DECIMAL)) AS TEXT) as total_hours, CAST(SUM(CASE WHEN billable THEN hours ELSE 0 END) AS TEXT) as billable_hours
 FROM time_entries
 WHERE user_id = $1 AND date >= $2 AND date <= $3
 GROUP BY date
 ORDER BY date ASC;
 
 -- name: GetDetailedTimeEntriesByDateRange :many
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE user_id = $1 AND date >= $2 AND date <= $3
-ORDER BY date DESC, created_at DESC;
+ORDER BY date DESC, started_at DESC NULLS LAST, created_at DESC;
+
+-- name: GetOverlappingTimeEntries :many
+-- Returns the user's time entries, other than exclude_id, whose period
+-- overlaps the given one. Periods that only touch do not overlap.
+SELECT id, user_id, client_id, date, hours, description, created_at, updated_at, hourly_rate, started_at, ended_at, project_id, task_id, billable, paused_seconds
+FROM time_entries
+WHERE user_id = sqlc.arg(user_id)
+  AND id <> sqlc.arg(exclude_id)
+  AND started_at < sqlc.arg(ended_at)
+  AND ended_at > sqlc.arg(started_at)
+ORDER BY started_at;
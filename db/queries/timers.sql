@@ -0,0 +1,34 @@
+-- name: CreateTimer :one
+INSERT INTO timers (user_id, client_id, project_id, task_id, description, status, started_at, resumed_at)
+VALUES ($1, $2, $3, $4, $5, 'running', $6, $6)
+RETURNING id, user_id, client_id, description, status, started_at, resumed_at, elapsed_seconds, stopped_at, time_entry_id, created_at, updated_at, project_id, task_id;
+
+-- name: GetActiveTimer :one
+-- Returns the user's running or paused timer
+SELECT id, user_id, client_id, description, status, started_at, resumed_at, elapsed_seconds, stopped_at, time_entry_id, created_at, updated_at, project_id, task_id
+FROM timers
+WHERE user_id = $1 AND status <> 'stopped';
+
+-- name: PauseTimer :one
+UPDATE timers
+SET status = 'paused', elapsed_seconds = $3, resumed_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status = 'running'
+RETURNING id, user_id, client_id, description, status, started_at, resumed_at, elapsed_seconds, stopped_at, time_entry_id, created_at, updated_at, project_id, task_id;
+
+-- name: ResumeTimer :one
+UPDATE timers
+SET status = 'running', resumed_at = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status = 'paused'
+RETURNING id, user_id, client_id, description, status, started_at, resumed_at, elapsed_seconds, stopped_at, time_entry_id, created_at, updated_at, project_id, task_id;
+
+-- name: StopTimer :one
+UPDATE timers
+SET status = 'stopped', elapsed_seconds = $3, resumed_at = NULL, stopped_at = $4, time_entry_id = $5, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status <> 'stopped'
+RETURNING id, user_id, client_id, description, status, started_at, resumed_at, elapsed_seconds, stopped_at, time_entry_id, created_at, updated_at, project_id, task_id;
+
+-- name: DeleteActiveTimer :one
+-- Discards the user's running or paused timer without recording its time
+DELETE FROM timers
+WHERE user_id = $1 AND status <> 'stopped'
+RETURNING id;
@@ -86,3 +86,44 @@ SET currency = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING id, email, name, email_verified, onboarding_completed, tour_completed, currency, date_format, created_at, updated_at;
+
+-- name: GetUserBillingProfile :one
+SELECT id, email, name, business_name, business_address, country_code, tax_id
+FROM users
+WHERE id = $1;
+
+-- name: UpdateUserBillingProfile :one
+UPDATE users
+SET business_name = $2,
+    business_address = $3,
+    country_code = $4,
+    tax_id = $5,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, email, name, business_name, business_address, country_code, tax_id;
+
+-- name: GetUserNumberPatterns :one
+SELECT invoice_number_pattern, credit_note_number_pattern, estimate_number_pattern
+FROM users
+WHERE id = $1;
+
+-- name: UpdateUserNumberPatterns :one
+UPDATE users
+SET invoice_number_pattern = $2,
+    credit_note_number_pattern = $3,
+    estimate_number_pattern = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING invoice_number_pattern, credit_note_number_pattern, estimate_number_pattern;
+
+-- name: GetUserTimezone :one
+SELECT timezone
+FROM users
+WHERE id = $1;
+
+-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING timezone;
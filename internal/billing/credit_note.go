@@ -0,0 +1,94 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreditNoteDocument is a fully loaded credit note ready to be rendered as PDF
+type CreditNoteDocument struct {
+	Parties
+	CreditNote db.CreditNote
+	LineItems  []db.CreditNoteLineItem
+	// Invoice is the invoice the credit note cancels all or part of
+	Invoice db.Invoice
+}
+
+// LoadCreditNoteDocument loads a credit note owned by userID together with
+// its seller, client, lines and the invoice it credits. It returns
+// sql.ErrNoRows (wrapped) when the credit note does not exist.
+func LoadCreditNoteDocument(ctx context.Context, queries *db.Queries, userID, creditNoteID int32) (*CreditNoteDocument, error) {
+	creditNote, err := queries.GetCreditNoteByID(ctx, db.GetCreditNoteByIDParams{
+		ID:     creditNoteID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch credit note: %w", err)
+	}
+
+	invoice, err := queries.GetInvoiceByID(ctx, db.GetInvoiceByIDParams{
+		ID:     creditNote.InvoiceID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
+	}
+
+	parties, err := loadParties(ctx, queries, userID, creditNote.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineItems, err := queries.GetCreditNoteLineItems(ctx, creditNote.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch line items: %w", err)
+	}
+
+	return &CreditNoteDocument{
+		Parties:    parties,
+		CreditNote: creditNote,
+		LineItems:  lineItems,
+		Invoice:    invoice,
+	}, nil
+}
+
+// Lines returns the credited lines of the credit note
+func (d *CreditNoteDocument) Lines() []Line {
+	return CreditNoteLines(d.LineItems)
+}
+
+// CreditNoteLines converts the line items of a credit note into lines
+func CreditNoteLines(lineItems []db.CreditNoteLineItem) []Line {
+	lines := make([]Line, len(lineItems))
+	for i, item := range lineItems {
+		lines[i] = itemLine(item.Description, item.Quantity, item.Unit, item.UnitPrice, item.TaxRate)
+	}
+	return lines
+}
+
+// AmountCredited returns the sum of the totals of creditNotes
+func AmountCredited(creditNotes []db.CreditNote) decimal.Decimal {
+	var credited decimal.Decimal
+	for _, creditNote := range creditNotes {
+		credited = credited.Add(money.Parse(creditNote.Total))
+	}
+	return credited
+}
+
+// CreditedTotals returns the subtotal, tax, withholding and total reversed by
+// creditNotes
+func CreditedTotals(creditNotes []db.CreditNote) Totals {
+	var totals Totals
+	for _, creditNote := range creditNotes {
+		totals.Subtotal = totals.Subtotal.Add(money.Parse(creditNote.Subtotal))
+		totals.Tax = totals.Tax.Add(money.Parse(creditNote.TaxAmount))
+		totals.Withholding = totals.Withholding.Add(money.Parse(creditNote.WithholdingAmount))
+		totals.Total = totals.Total.Add(money.Parse(creditNote.Total))
+	}
+	return totals
+}
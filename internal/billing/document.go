@@ -0,0 +1,389 @@
+// Package billing assembles everything needed to render or export an invoice
+// or credit note: the document row, its seller and buyer, and its billable
+// lines.
+package billing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/iso4217"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// Document is a fully loaded invoice ready to be rendered as PDF or serialized
+// as an e-invoice
+type Document struct {
+	Contents
+	Parties
+	Invoice db.Invoice
+}
+
+// Parties are the seller and buyer of an invoice or credit note
+type Parties struct {
+	Seller db.GetUserBillingProfileRow
+	Client db.GetClientByIDRow
+}
+
+// Contents is what an invoice bills: its time entries, line items and the
+// taxes applied to it, along with the payments received against it and the
+// credit notes cancelling part of it. Currency is the currency the invoice is
+// billed in, which its amounts are rounded to.
+type Contents struct {
+	Currency    string
+	TimeEntries []db.GetInvoiceTimeEntriesRow
+	LineItems   []db.InvoiceLineItem
+	Taxes       []db.InvoiceTax
+	Payments    []db.Payment
+	CreditNotes []db.CreditNote
+}
+
+// Line is a single billable line of a document. Amount is the exact product
+// of quantity and unit price, before rounding to the document currency.
+type Line struct {
+	Date        time.Time
+	Description string
+	Quantity    decimal.Decimal
+	Unit        string
+	UnitPrice   decimal.Decimal
+	Amount      decimal.Decimal
+	TaxRate     decimal.Decimal
+	// ProjectID and Project are the ID and name of the project a time entry
+	// was recorded against, zero and empty for other lines
+	ProjectID int32
+	Project   string
+}
+
+// TaxAmount returns the tax charged on the line, before rounding
+func (l Line) TaxAmount() decimal.Decimal {
+	return money.Percent(l.Amount, l.TaxRate)
+}
+
+// ProjectGroup is a run of consecutive lines of the same project
+type ProjectGroup struct {
+	ProjectID int32
+	Name      string
+	Lines     []Line
+}
+
+// Hours returns the hours billed by the lines of the group
+func (g ProjectGroup) Hours() decimal.Decimal {
+	hours := decimal.Zero
+	for _, line := range g.Lines {
+		if line.Unit == UnitHour {
+			hours = hours.Add(line.Quantity)
+		}
+	}
+	return hours
+}
+
+// Amount returns the amount of the lines of the group, before rounding
+func (g ProjectGroup) Amount() decimal.Decimal {
+	amount := decimal.Zero
+	for _, line := range g.Lines {
+		amount = amount.Add(line.Amount)
+	}
+	return amount
+}
+
+// GroupByProject splits lines into runs of consecutive lines of the same
+// project. Invoice time entries are loaded grouped by project, so the lines
+// of an invoice make one group per project followed by a group of the lines
+// without a project.
+func GroupByProject(lines []Line) []ProjectGroup {
+	var groups []ProjectGroup
+	for _, line := range lines {
+		if n := len(groups); n > 0 && groups[n-1].ProjectID == line.ProjectID {
+			groups[n-1].Lines = append(groups[n-1].Lines, line)
+			continue
+		}
+		groups = append(groups, ProjectGroup{
+			ProjectID: line.ProjectID,
+			Name:      line.Project,
+			Lines:     []Line{line},
+		})
+	}
+	return groups
+}
+
+// Unit codes from UN/ECE Recommendation 20
+const (
+	UnitHour  = "HUR"
+	UnitDay   = "DAY"
+	UnitMonth = "MON"
+	UnitPiece = "C62"
+)
+
+// lineItemUnits maps the units accepted on invoice line items to their
+// UN/ECE codes
+var lineItemUnits = map[string]string{
+	"unit":  UnitPiece,
+	"hour":  UnitHour,
+	"day":   UnitDay,
+	"month": UnitMonth,
+}
+
+// FormatPrice formats a unit price or hourly rate as it is stored, with
+// enough decimals for the minor unit of any currency. Prices are only rounded
+// to the document currency once multiplied into line amounts.
+func FormatPrice(price decimal.Decimal) string {
+	return money.FormatTo(price, iso4217.MaxMinorUnits)
+}
+
+// IsValidLineItemUnit reports whether unit can be stored on an invoice line item
+func IsValidLineItemUnit(unit string) bool {
+	_, ok := lineItemUnits[unit]
+	return ok
+}
+
+// Totals summarizes the lines and taxes of an invoice. Tax includes both
+// per-line taxes (LineTax) and the charged invoice-level taxes. Balance is
+// what remains to be paid of Total once payments and credit notes are
+// deducted.
+type Totals struct {
+	Hours       decimal.Decimal
+	Subtotal    decimal.Decimal
+	LineTax     decimal.Decimal
+	Taxes       []AppliedTax
+	Tax         decimal.Decimal
+	Withholding decimal.Decimal
+	Total       decimal.Decimal
+	Paid        decimal.Decimal
+	Credited    decimal.Decimal
+	Balance     decimal.Decimal
+}
+
+// Net returns the total still billed once credit notes are deducted
+func (t Totals) Net() decimal.Decimal {
+	return t.Total.Sub(t.Credited)
+}
+
+// LoadDocument loads an invoice owned by userID together with its seller,
+// client and contents. It returns sql.ErrNoRows (wrapped) when the
+// invoice does not exist.
+func LoadDocument(ctx context.Context, queries *db.Queries, userID, invoiceID int32) (*Document, error) {
+	invoice, err := queries.GetInvoiceByID(ctx, db.GetInvoiceByIDParams{
+		ID:     invoiceID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
+	}
+
+	parties, err := loadParties(ctx, queries, userID, invoice.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := LoadContents(ctx, queries, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		Contents: contents,
+		Parties:  parties,
+		Invoice:  invoice,
+	}, nil
+}
+
+// loadParties loads the user's billing profile and their client
+func loadParties(ctx context.Context, queries *db.Queries, userID, clientID int32) (Parties, error) {
+	seller, err := queries.GetUserBillingProfile(ctx, userID)
+	if err != nil {
+		return Parties{}, fmt.Errorf("failed to fetch seller: %w", err)
+	}
+
+	client, err := queries.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     clientID,
+		UserID: userID,
+	})
+	if err != nil {
+		return Parties{}, fmt.Errorf("failed to fetch client: %w", err)
+	}
+
+	return Parties{Seller: seller, Client: client}, nil
+}
+
+// LoadContents loads the currency, time entries, line items, taxes, payments
+// and credit notes of an invoice
+func LoadContents(ctx context.Context, queries *db.Queries, invoiceID int32) (Contents, error) {
+	currency, err := queries.GetInvoiceCurrency(ctx, invoiceID)
+	if err != nil {
+		return Contents{}, fmt.Errorf("failed to fetch currency: %w", err)
+	}
+
+	timeEntries, err := queries.GetInvoiceTimeEntries(ctx, invoiceID)
+	if err != nil {
+		return Contents{}, fmt.Errorf("failed to fetch time entries: %w", err)
+	}
+
+	lineItems, err := queries.GetInvoiceLineItems(ctx, invoiceID)
+	if err != nil {
+		return Contents{}, fmt.Errorf("failed to fetch line items: %w", err)
+	}
+
+	taxes, err := queries.GetInvoiceTaxes(ctx, invoiceID)
+	if err != nil {
+		return Contents{}, fmt.Errorf("failed to fetch taxes: %w", err)
+	}
+
+	payments, err := queries.GetPaymentsByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return Contents{}, fmt.Errorf("failed to fetch payments: %w", err)
+	}
+
+	creditNotes, err := queries.GetCreditNotesByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return Contents{}, fmt.Errorf("failed to fetch credit notes: %w", err)
+	}
+
+	return Contents{
+		Currency:    currency,
+		TimeEntries: timeEntries,
+		LineItems:   lineItems,
+		Taxes:       taxes,
+		Payments:    payments,
+		CreditNotes: creditNotes,
+	}, nil
+}
+
+// Currency returns the document currency, which is the client's currency
+func (p Parties) Currency() string {
+	if p.Client.Currency == "" {
+		return "USD" // Default fallback
+	}
+	return p.Client.Currency
+}
+
+// SellerName returns the business name if set, otherwise the user's name
+func (p Parties) SellerName() string {
+	if p.Seller.BusinessName.Valid && p.Seller.BusinessName.String != "" {
+		return p.Seller.BusinessName.String
+	}
+	return p.Seller.Name
+}
+
+// BuyerName returns the client's company if set, otherwise the client's name
+func (p Parties) BuyerName() string {
+	if p.Client.Company.Valid && p.Client.Company.String != "" {
+		return p.Client.Company.String
+	}
+	return p.Client.Name
+}
+
+// Lines returns the billable lines of the invoice
+func (c Contents) Lines() []Line {
+	return BuildLines(c.TimeEntries, c.LineItems)
+}
+
+// Totals returns the hours, subtotal, taxes, total and balance of the invoice
+func (c Contents) Totals(reverseCharge bool) Totals {
+	totals := Summarize(c.Lines(), c.Taxes, reverseCharge, c.Currency)
+	totals.Paid = AmountPaid(c.Payments)
+	totals.Credited = AmountCredited(c.CreditNotes)
+	totals.Balance = totals.Total.Sub(totals.Paid).Sub(totals.Credited)
+	return totals
+}
+
+// Currency returns the document currency: the currency the invoice was issued
+// in, or the client's currency while it is a draft
+func (d *Document) Currency() string {
+	return d.Contents.Currency
+}
+
+// Totals returns the hours, subtotal, taxes and total of the document
+func (d *Document) Totals() Totals {
+	return d.Contents.Totals(d.Invoice.ReverseCharge)
+}
+
+// IssuedTotals returns the totals of the invoice, using the subtotal, tax,
+// withholding and total frozen when it was issued when it has them. Paid,
+// credited and the balance always follow the invoice's payments and credit
+// notes.
+func IssuedTotals(invoice db.Invoice, contents Contents) Totals {
+	totals := contents.Totals(invoice.ReverseCharge)
+	if !invoice.Total.Valid {
+		return totals
+	}
+	totals.Subtotal = money.ParseNull(invoice.Subtotal)
+	totals.Tax = money.ParseNull(invoice.TaxAmount)
+	totals.Withholding = money.ParseNull(invoice.WithholdingAmount)
+	totals.Total = money.ParseNull(invoice.Total)
+	totals.Balance = totals.Total.Sub(totals.Paid).Sub(totals.Credited)
+	return totals
+}
+
+// IssuedRate returns the exchange rate from the invoice's currency to
+// reportingCurrency frozen when the invoice was issued. It reports false when
+// the invoice has no frozen rate to that currency, e.g. because it is still a
+// draft or the user has since changed currency.
+func IssuedRate(invoice db.Invoice, reportingCurrency string) (float64, bool) {
+	if !invoice.ExchangeRate.Valid || invoice.ReportingCurrency.String != reportingCurrency {
+		return 0, false
+	}
+	rate, err := strconv.ParseFloat(invoice.ExchangeRate.String, 64)
+	if err != nil {
+		return 0, false
+	}
+	return rate, true
+}
+
+// BuildLines merges time entries and line items into billable lines. Time
+// entries come first, in the order given, and are billed at the hourly rate
+// captured on the entry, without tax; line items follow in their stored
+// order.
+func BuildLines(timeEntries []db.GetInvoiceTimeEntriesRow, lineItems []db.InvoiceLineItem) []Line {
+	lines := make([]Line, 0, len(timeEntries)+len(lineItems))
+	for _, entry := range timeEntries {
+		hours := money.Parse(entry.Hours)
+		hourlyRate := money.ParseNull(entry.HourlyRate)
+
+		description := entry.Description.String
+		if description == "" {
+			description = "No description"
+		}
+
+		lines = append(lines, Line{
+			Date:        entry.Date,
+			Description: description,
+			Quantity:    hours,
+			Unit:        UnitHour,
+			UnitPrice:   hourlyRate,
+			Amount:      hours.Mul(hourlyRate),
+			ProjectID:   entry.ProjectID.Int32,
+			Project:     entry.ProjectName.String,
+		})
+	}
+
+	for _, item := range lineItems {
+		lines = append(lines, itemLine(item.Description, item.Quantity, item.Unit, item.UnitPrice, item.TaxRate))
+	}
+	return lines
+}
+
+// itemLine converts a stored line item, whose amounts are decimal strings,
+// into a line
+func itemLine(description, quantity, unit, unitPrice, taxRate string) Line {
+	q := money.Parse(quantity)
+	price := money.Parse(unitPrice)
+
+	code, ok := lineItemUnits[unit]
+	if !ok {
+		code = UnitPiece
+	}
+
+	return Line{
+		Description: description,
+		Quantity:    q,
+		Unit:        code,
+		UnitPrice:   price,
+		Amount:      q.Mul(price),
+		TaxRate:     money.Parse(taxRate),
+	}
+}
@@ -0,0 +1,64 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+
+	"worklio-api/internal/db"
+)
+
+// EstimateDocument is a fully loaded estimate ready to be rendered as PDF
+type EstimateDocument struct {
+	Parties
+	Estimate  db.Estimate
+	LineItems []db.EstimateLineItem
+}
+
+// LoadEstimateDocument loads an estimate owned by userID together with its
+// seller, client and lines. It returns sql.ErrNoRows (wrapped) when the
+// estimate does not exist.
+func LoadEstimateDocument(ctx context.Context, queries *db.Queries, userID, estimateID int32) (*EstimateDocument, error) {
+	estimate, err := queries.GetEstimateByID(ctx, db.GetEstimateByIDParams{
+		ID:     estimateID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch estimate: %w", err)
+	}
+
+	parties, err := loadParties(ctx, queries, userID, estimate.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineItems, err := queries.GetEstimateLineItems(ctx, estimate.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch line items: %w", err)
+	}
+
+	return &EstimateDocument{
+		Parties:   parties,
+		Estimate:  estimate,
+		LineItems: lineItems,
+	}, nil
+}
+
+// Lines returns the quoted lines of the estimate
+func (d *EstimateDocument) Lines() []Line {
+	return EstimateLines(d.LineItems)
+}
+
+// Totals returns the subtotal, line taxes and total of the estimate.
+// Invoice-level taxes are only applied once the estimate is converted.
+func (d *EstimateDocument) Totals() Totals {
+	return Summarize(d.Lines(), nil, false, d.Currency())
+}
+
+// EstimateLines converts the line items of an estimate into lines
+func EstimateLines(lineItems []db.EstimateLineItem) []Line {
+	lines := make([]Line, len(lineItems))
+	for i, item := range lineItems {
+		lines[i] = itemLine(item.Description, item.Quantity, item.Unit, item.UnitPrice, item.TaxRate)
+	}
+	return lines
+}
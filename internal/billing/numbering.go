@@ -0,0 +1,110 @@
+package billing
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultNumberPattern numbers invoices per year, e.g. INV-2025-0001
+const DefaultNumberPattern = "INV-{YYYY}-{SEQ:4}"
+
+// maxSequenceWidth is the widest zero padding a {SEQ:n} token may ask for
+const maxSequenceWidth = 10
+
+// numberToken matches the placeholders of an invoice number pattern
+var numberToken = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+
+// NumberPattern is a user-defined invoice number pattern. It is made of
+// literal text and the placeholders:
+//
+//	{YYYY}   four-digit year of the issue date
+//	{YY}     two-digit year of the issue date
+//	{MM}     two-digit month of the issue date
+//	{PREFIX} the client's invoice prefix, empty when it has none
+//	{SEQ:n}  the sequence number, zero-padded to n digits ({SEQ} is unpadded)
+//
+// Every other part of the number scopes the sequence, so a pattern with
+// {YYYY} restarts numbering each year and one with {PREFIX} numbers each
+// prefix separately.
+type NumberPattern string
+
+// Validate returns an error describing why the pattern cannot number
+// invoices, or nil when it can
+func (p NumberPattern) Validate() error {
+	pattern := string(p)
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if len(pattern) > 100 {
+		return fmt.Errorf("pattern must be at most 100 characters")
+	}
+
+	sequences := 0
+	for _, match := range numberToken.FindAllStringSubmatch(pattern, -1) {
+		switch match[1] {
+		case "YYYY", "YY", "MM", "PREFIX":
+			if match[2] != "" {
+				return fmt.Errorf("{%s} does not take a width", match[1])
+			}
+		case "SEQ":
+			sequences++
+			if match[2] != "" {
+				width, _ := strconv.Atoi(match[2])
+				if width < 1 || width > maxSequenceWidth {
+					return fmt.Errorf("{SEQ} width must be between 1 and %d", maxSequenceWidth)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown placeholder {%s}", match[1])
+		}
+	}
+	if sequences != 1 {
+		return fmt.Errorf("pattern must contain {SEQ} exactly once")
+	}
+
+	literal := numberToken.ReplaceAllString(pattern, "")
+	if strings.ContainsAny(literal, "{}") {
+		return fmt.Errorf("pattern contains an unterminated placeholder")
+	}
+	return nil
+}
+
+// Scope returns the pattern rendered for issueDate and prefix with the
+// sequence left out. Invoices with the same scope share a sequence.
+func (p NumberPattern) Scope(issueDate time.Time, prefix string) string {
+	return p.render(issueDate, prefix, func(string) string { return "{SEQ}" })
+}
+
+// Format returns the invoice number with sequence seq, issued on issueDate to
+// a client with the given prefix
+func (p NumberPattern) Format(issueDate time.Time, prefix string, seq int) string {
+	return p.render(issueDate, prefix, func(width string) string {
+		if width == "" {
+			return strconv.Itoa(seq)
+		}
+		n, _ := strconv.Atoi(width)
+		return fmt.Sprintf("%0*d", n, seq)
+	})
+}
+
+func (p NumberPattern) render(issueDate time.Time, prefix string, sequence func(width string) string) string {
+	return numberToken.ReplaceAllStringFunc(string(p), func(token string) string {
+		match := numberToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "YYYY":
+			return issueDate.Format("2006")
+		case "YY":
+			return issueDate.Format("06")
+		case "MM":
+			return issueDate.Format("01")
+		case "PREFIX":
+			return prefix
+		case "SEQ":
+			return sequence(match[2])
+		}
+		return token
+	})
+}
@@ -0,0 +1,82 @@
+package billing
+
+import (
+	"context"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// paymentMethods lists the accepted ways an invoice can be paid
+var paymentMethods = map[string]bool{
+	"bank_transfer": true,
+	"card":          true,
+	"cash":          true,
+	"check":         true,
+	"paypal":        true,
+	"other":         true,
+}
+
+// IsValidPaymentMethod reports whether method can be stored on a payment
+func IsValidPaymentMethod(method string) bool {
+	return paymentMethods[method]
+}
+
+// AmountPaid returns the sum of payments
+func AmountPaid(payments []db.Payment) decimal.Decimal {
+	var paid decimal.Decimal
+	for _, payment := range payments {
+		paid = paid.Add(money.Parse(payment.Amount))
+	}
+	return paid
+}
+
+// IsSettled reports whether paid covers total, to the minor unit of currency
+func IsSettled(total, paid decimal.Decimal, currency string) bool {
+	return money.Round(total.Sub(paid), currency).Sign() <= 0
+}
+
+// PaymentStatus returns the status an invoice in status current should move to
+// once paid out of total has been received, where total is net of credit
+// notes. Fully paid invoices, and those cancelled entirely by credit notes,
+// become paid and partially paid ones partially_paid, except overdue invoices,
+// which stay overdue until settled. An invoice whose payments were all removed
+// goes back to sent.
+func PaymentStatus(current string, total, paid decimal.Decimal, currency string) string {
+	switch {
+	case paid.IsPositive() && IsSettled(total, paid, currency), IsSettled(total, decimal.Zero, currency):
+		return "paid"
+	case paid.IsPositive() && current == "overdue":
+		return "overdue"
+	case paid.IsPositive():
+		return "partially_paid"
+	case current == "paid" || current == "partially_paid":
+		return "sent"
+	default:
+		return current
+	}
+}
+
+// SyncInvoiceStatus moves the invoice to the status implied by its payments
+// and credit notes
+func SyncInvoiceStatus(ctx context.Context, queries *db.Queries, invoice db.Invoice) error {
+	contents, err := LoadContents(ctx, queries, invoice.ID)
+	if err != nil {
+		return err
+	}
+
+	totals := IssuedTotals(invoice, contents)
+	status := PaymentStatus(invoice.Status, totals.Net(), totals.Paid, contents.Currency)
+	if status == invoice.Status {
+		return nil
+	}
+
+	_, err = queries.UpdateInvoiceStatus(ctx, db.UpdateInvoiceStatusParams{
+		ID:     invoice.ID,
+		UserID: invoice.UserID,
+		Status: status,
+	})
+	return err
+}
@@ -0,0 +1,128 @@
+package billing
+
+import (
+	"time"
+
+	"worklio-api/internal/db"
+)
+
+// Recurring invoice frequencies
+const (
+	FrequencyWeekly    = "weekly"
+	FrequencyMonthly   = "monthly"
+	FrequencyQuarterly = "quarterly"
+	FrequencyYearly    = "yearly"
+)
+
+// frequencyMonths is the number of months between occurrences of the
+// month-based frequencies
+var frequencyMonths = map[string]int{
+	FrequencyMonthly:   1,
+	FrequencyQuarterly: 3,
+	FrequencyYearly:    12,
+}
+
+// IsValidFrequency reports whether frequency can be stored on a recurring
+// invoice
+func IsValidFrequency(frequency string) bool {
+	return frequency == FrequencyWeekly || frequencyMonths[frequency] > 0
+}
+
+// IsValidAnchorDay reports whether anchorDay is valid for frequency: an ISO
+// weekday (1 = Monday, 7 = Sunday) for weekly schedules, otherwise a day of
+// the month between 1 and 31
+func IsValidAnchorDay(frequency string, anchorDay int) bool {
+	if frequency == FrequencyWeekly {
+		return anchorDay >= 1 && anchorDay <= 7
+	}
+	return anchorDay >= 1 && anchorDay <= 31
+}
+
+// Schedule is the recurrence of a recurring invoice. Weekly schedules fall on
+// the anchor weekday. Monthly, quarterly and yearly schedules fall on the
+// anchor day of every first, third or twelfth month counted from the start
+// date's month, or on the last day of shorter months.
+type Schedule struct {
+	Frequency string
+	AnchorDay int
+	StartDate time.Time
+	// EndDate is the last day an occurrence may fall on; zero means the
+	// schedule never ends
+	EndDate time.Time
+}
+
+// Next returns the first occurrence on or after date, or false when the
+// schedule ends before one
+func (s Schedule) Next(date time.Time) (time.Time, bool) {
+	from := dateOnly(date)
+	start := dateOnly(s.StartDate)
+	if from.Before(start) {
+		from = start
+	}
+
+	var next time.Time
+	if s.Frequency == FrequencyWeekly {
+		days := (s.AnchorDay%7 - int(from.Weekday()) + 7) % 7
+		next = from.AddDate(0, 0, days)
+	} else {
+		months := frequencyMonths[s.Frequency]
+		if months == 0 {
+			return time.Time{}, false
+		}
+		step := ((from.Year()-start.Year())*12 + int(from.Month()-start.Month())) / months
+		for {
+			next = monthDay(start.Year(), start.Month()+time.Month(step*months), s.AnchorDay)
+			if !next.Before(from) {
+				break
+			}
+			step++
+		}
+	}
+
+	if !s.EndDate.IsZero() && next.After(dateOnly(s.EndDate)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Occurrences returns up to n occurrences on or after date
+func (s Schedule) Occurrences(date time.Time, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	for len(occurrences) < n {
+		next, ok := s.Next(date)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		date = next.AddDate(0, 0, 1)
+	}
+	return occurrences
+}
+
+// monthDay returns the given day of a month, or the month's last day when it
+// is shorter. month may overflow into following years.
+func monthDay(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// dateOnly truncates t to midnight UTC on the same calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RecurringSchedule returns the schedule of a recurring invoice
+func RecurringSchedule(rec db.RecurringInvoice) Schedule {
+	schedule := Schedule{
+		Frequency: rec.Frequency,
+		AnchorDay: int(rec.AnchorDay),
+		StartDate: rec.StartDate,
+	}
+	if rec.EndDate.Valid {
+		schedule.EndDate = rec.EndDate.Time
+	}
+	return schedule
+}
@@ -0,0 +1,205 @@
+package billing
+
+import (
+	"fmt"
+	"strconv"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// Snapshot is the state of an invoice at one revision: its details, what it
+// bills and the totals that resulted. Snapshots are stored as JSON when an
+// issued invoice is revised.
+type Snapshot struct {
+	ClientID      int32               `json:"client_id"`
+	InvoiceNumber string              `json:"invoice_number"`
+	IssueDate     string              `json:"issue_date"`
+	DueDate       string              `json:"due_date"`
+	Status        string              `json:"status"`
+	Notes         string              `json:"notes"`
+	ReverseCharge bool                `json:"reverse_charge"`
+	Currency      string              `json:"currency"`
+	TimeEntries   []SnapshotTimeEntry `json:"time_entries"`
+	LineItems     []SnapshotLineItem  `json:"line_items"`
+	Taxes         []SnapshotTax       `json:"taxes"`
+	Subtotal      decimal.Decimal     `json:"subtotal"`
+	Tax           decimal.Decimal     `json:"tax_amount"`
+	Withholding   decimal.Decimal     `json:"withholding_amount"`
+	Total         decimal.Decimal     `json:"total_amount"`
+}
+
+// SnapshotTimeEntry is a time entry billed on a snapshotted invoice
+type SnapshotTimeEntry struct {
+	ID          int32  `json:"id"`
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	Hours       string `json:"hours"`
+	HourlyRate  string `json:"hourly_rate"`
+}
+
+// SnapshotLineItem is a line item of a snapshotted invoice
+type SnapshotLineItem struct {
+	Description string `json:"description"`
+	Quantity    string `json:"quantity"`
+	Unit        string `json:"unit"`
+	UnitPrice   string `json:"unit_price"`
+	TaxRate     string `json:"tax_rate"`
+}
+
+// SnapshotTax is a tax applied to a snapshotted invoice
+type SnapshotTax struct {
+	Name        string `json:"name"`
+	Rate        string `json:"rate"`
+	Compound    bool   `json:"compound"`
+	Withholding bool   `json:"withholding"`
+}
+
+// Change is a field that differs between two snapshots. Fields of time
+// entries, line items and taxes are addressed by position, e.g.
+// "line_items[0].quantity"; a field missing from one side is empty.
+type Change struct {
+	Field string
+	From  string
+	To    string
+}
+
+// NewSnapshot captures the current state of invoice
+func NewSnapshot(invoice db.Invoice, contents Contents) Snapshot {
+	totals := contents.Totals(invoice.ReverseCharge)
+
+	snapshot := Snapshot{
+		ClientID:      invoice.ClientID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		IssueDate:     invoice.IssueDate.Format("2006-01-02"),
+		DueDate:       invoice.DueDate.Format("2006-01-02"),
+		Status:        invoice.Status,
+		Notes:         invoice.Notes.String,
+		ReverseCharge: invoice.ReverseCharge,
+		Currency:      contents.Currency,
+		TimeEntries:   make([]SnapshotTimeEntry, len(contents.TimeEntries)),
+		LineItems:     make([]SnapshotLineItem, len(contents.LineItems)),
+		Taxes:         make([]SnapshotTax, len(contents.Taxes)),
+		Subtotal:      totals.Subtotal,
+		Tax:           totals.Tax,
+		Withholding:   totals.Withholding,
+		Total:         totals.Total,
+	}
+
+	for i, entry := range contents.TimeEntries {
+		snapshot.TimeEntries[i] = SnapshotTimeEntry{
+			ID:          entry.ID,
+			Date:        entry.Date.Format("2006-01-02"),
+			Description: entry.Description.String,
+			Hours:       entry.Hours,
+			HourlyRate:  entry.HourlyRate.String,
+		}
+	}
+	for i, item := range contents.LineItems {
+		snapshot.LineItems[i] = SnapshotLineItem{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			Unit:        item.Unit,
+			UnitPrice:   item.UnitPrice,
+			TaxRate:     item.TaxRate,
+		}
+	}
+	for i, tax := range contents.Taxes {
+		snapshot.Taxes[i] = SnapshotTax{
+			Name:        tax.Name,
+			Rate:        tax.Rate,
+			Compound:    tax.Compound,
+			Withholding: tax.Withholding,
+		}
+	}
+
+	return snapshot
+}
+
+// Diff lists the fields that changed from one snapshot to the next, in the
+// order they appear on the invoice
+func Diff(from, to Snapshot) []Change {
+	before := from.fields()
+	after := to.fields()
+
+	values := make(map[string]string, len(after))
+	for _, f := range after {
+		values[f.name] = f.value
+	}
+
+	changes := []Change{}
+	seen := make(map[string]bool, len(before))
+	for _, f := range before {
+		seen[f.name] = true
+		if value := values[f.name]; value != f.value {
+			changes = append(changes, Change{Field: f.name, From: f.value, To: value})
+		}
+	}
+	for _, f := range after {
+		if !seen[f.name] {
+			changes = append(changes, Change{Field: f.name, To: f.value})
+		}
+	}
+	return changes
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+// fields flattens the snapshot into named values so two snapshots can be
+// compared field by field
+func (s Snapshot) fields() []field {
+	amount := func(value decimal.Decimal) string {
+		return money.Format(value, s.Currency)
+	}
+
+	fields := []field{
+		{"client_id", strconv.Itoa(int(s.ClientID))},
+		{"invoice_number", s.InvoiceNumber},
+		{"issue_date", s.IssueDate},
+		{"due_date", s.DueDate},
+		{"status", s.Status},
+		{"notes", s.Notes},
+		{"reverse_charge", strconv.FormatBool(s.ReverseCharge)},
+		{"currency", s.Currency},
+	}
+	for i, entry := range s.TimeEntries {
+		prefix := fmt.Sprintf("time_entries[%d].", i)
+		fields = append(fields,
+			field{prefix + "id", strconv.Itoa(int(entry.ID))},
+			field{prefix + "date", entry.Date},
+			field{prefix + "description", entry.Description},
+			field{prefix + "hours", entry.Hours},
+			field{prefix + "hourly_rate", entry.HourlyRate},
+		)
+	}
+	for i, item := range s.LineItems {
+		prefix := fmt.Sprintf("line_items[%d].", i)
+		fields = append(fields,
+			field{prefix + "description", item.Description},
+			field{prefix + "quantity", item.Quantity},
+			field{prefix + "unit", item.Unit},
+			field{prefix + "unit_price", item.UnitPrice},
+			field{prefix + "tax_rate", item.TaxRate},
+		)
+	}
+	for i, tax := range s.Taxes {
+		prefix := fmt.Sprintf("taxes[%d].", i)
+		fields = append(fields,
+			field{prefix + "name", tax.Name},
+			field{prefix + "rate", tax.Rate},
+			field{prefix + "compound", strconv.FormatBool(tax.Compound)},
+			field{prefix + "withholding", strconv.FormatBool(tax.Withholding)},
+		)
+	}
+	return append(fields,
+		field{"subtotal", amount(s.Subtotal)},
+		field{"tax_amount", amount(s.Tax)},
+		field{"withholding_amount", amount(s.Withholding)},
+		field{"total_amount", amount(s.Total)},
+	)
+}
@@ -0,0 +1,107 @@
+package billing
+
+import (
+	"strings"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// AppliedTax is an invoice-level tax and the amount it adds (or, for
+// withholding taxes, deducts)
+type AppliedTax struct {
+	Name        string
+	Rate        decimal.Decimal
+	Compound    bool
+	Withholding bool
+	Basis       decimal.Decimal
+	Amount      decimal.Decimal
+}
+
+// euMemberStates lists the ISO 3166-1 codes of EU member states. Greece is
+// listed under GR; its VAT prefix EL is handled in IsReverseCharge.
+var euMemberStates = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "CY": true, "CZ": true, "DE": true,
+	"DK": true, "EE": true, "ES": true, "FI": true, "FR": true, "GR": true,
+	"HR": true, "HU": true, "IE": true, "IT": true, "LT": true, "LU": true,
+	"LV": true, "MT": true, "NL": true, "PL": true, "PT": true, "RO": true,
+	"SE": true, "SI": true, "SK": true,
+}
+
+// IsReverseCharge reports whether an invoice falls under the EU B2B reverse
+// charge mechanism: seller and client are VAT registered in two different
+// member states, so the client accounts for the VAT instead of the seller.
+func IsReverseCharge(sellerCountry, sellerTaxID, clientCountry, clientTaxID string) bool {
+	sellerCountry = normalizeCountry(sellerCountry)
+	clientCountry = normalizeCountry(clientCountry)
+
+	if sellerTaxID == "" || clientTaxID == "" {
+		return false
+	}
+	if !euMemberStates[sellerCountry] || !euMemberStates[clientCountry] {
+		return false
+	}
+	return sellerCountry != clientCountry
+}
+
+func normalizeCountry(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "EL" {
+		return "GR"
+	}
+	return country
+}
+
+// Summarize adds up lines and applies the invoice-level taxes in order.
+// Regular taxes are charged on the subtotal; compound taxes on the subtotal
+// plus every tax charged before them. Withholding taxes are computed on the
+// subtotal and deducted from the total. Under reverse charge no tax is
+// charged, but withholding still applies. Line amounts and taxes are rounded
+// to the minor unit of currency before they are added up, so the totals are
+// exactly the sum of the amounts printed on the document.
+func Summarize(lines []Line, taxes []db.InvoiceTax, reverseCharge bool, currency string) Totals {
+	var totals Totals
+	for _, line := range lines {
+		if line.Unit == UnitHour {
+			totals.Hours = totals.Hours.Add(line.Quantity)
+		}
+		totals.Subtotal = totals.Subtotal.Add(money.Round(line.Amount, currency))
+		if !reverseCharge {
+			totals.LineTax = totals.LineTax.Add(money.Round(line.TaxAmount(), currency))
+		}
+	}
+	totals.Tax = totals.LineTax
+
+	for _, tax := range taxes {
+		if reverseCharge && !tax.Withholding {
+			continue
+		}
+
+		rate := money.Parse(tax.Rate)
+		basis := totals.Subtotal
+		if tax.Compound && !tax.Withholding {
+			basis = basis.Add(totals.Tax)
+		}
+		amount := money.Round(money.Percent(basis, rate), currency)
+
+		if tax.Withholding {
+			totals.Withholding = totals.Withholding.Add(amount)
+		} else {
+			totals.Tax = totals.Tax.Add(amount)
+		}
+
+		totals.Taxes = append(totals.Taxes, AppliedTax{
+			Name:        tax.Name,
+			Rate:        rate,
+			Compound:    tax.Compound,
+			Withholding: tax.Withholding,
+			Basis:       basis,
+			Amount:      amount,
+		})
+	}
+
+	totals.Total = totals.Subtotal.Add(totals.Tax).Sub(totals.Withholding)
+	return totals
+}
@@ -11,34 +11,46 @@ import (
 )
 
 const createClient = `-- name: CreateClient :one
-INSERT INTO clients (user_id, name, email, phone, company, address, hourly_rate, currency)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, user_id, name, email, phone, company, address, hourly_rate, currency, created_at, updated_at
+INSERT INTO clients (user_id, name, email, phone, company, address, hourly_rate, currency, country_code, tax_id, reminders_opt_out, invoice_prefix, billable, buyer_reference)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, user_id, name, email, phone, company, address, hourly_rate, currency, country_code, tax_id, reminders_opt_out, invoice_prefix, billable, buyer_reference, created_at, updated_at
 `
 
 type CreateClientParams struct {
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
 }
 
 type CreateClientRow struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
-	UpdatedAt  sql.NullTime   `json:"updated_at"`
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (CreateClientRow, error) {
@@ -51,6 +63,12 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cre
 		arg.Address,
 		arg.HourlyRate,
 		arg.Currency,
+		arg.CountryCode,
+		arg.TaxID,
+		arg.RemindersOptOut,
+		arg.InvoicePrefix,
+		arg.Billable,
+		arg.BuyerReference,
 	)
 	var i CreateClientRow
 	err := row.Scan(
@@ -63,6 +81,12 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cre
 		&i.Address,
 		&i.HourlyRate,
 		&i.Currency,
+		&i.CountryCode,
+		&i.TaxID,
+		&i.RemindersOptOut,
+		&i.InvoicePrefix,
+		&i.Billable,
+		&i.BuyerReference,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -95,7 +119,7 @@ func (q *Queries) DeleteDemoClients(ctx context.Context, userID int32) error {
 }
 
 const getClientByID = `-- name: GetClientByID :one
-SELECT id, user_id, name, email, phone, company, address, hourly_rate, currency, created_at, updated_at
+SELECT id, user_id, name, email, phone, company, address, hourly_rate, currency, country_code, tax_id, reminders_opt_out, invoice_prefix, billable, buyer_reference, created_at, updated_at
 FROM clients
 WHERE id = $1 AND user_id = $2
 `
@@ -106,17 +130,23 @@ type GetClientByIDParams struct {
 }
 
 type GetClientByIDRow struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
-	UpdatedAt  sql.NullTime   `json:"updated_at"`
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) GetClientByID(ctx context.Context, arg GetClientByIDParams) (GetClientByIDRow, error) {
@@ -132,6 +162,12 @@ func (q *Queries) GetClientByID(ctx context.Context, arg GetClientByIDParams) (G
 		&i.Address,
 		&i.HourlyRate,
 		&i.Currency,
+		&i.CountryCode,
+		&i.TaxID,
+		&i.RemindersOptOut,
+		&i.InvoicePrefix,
+		&i.Billable,
+		&i.BuyerReference,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -139,24 +175,30 @@ func (q *Queries) GetClientByID(ctx context.Context, arg GetClientByIDParams) (G
 }
 
 const getClientsByUserID = `-- name: GetClientsByUserID :many
-SELECT id, user_id, name, email, phone, company, address, hourly_rate, currency, created_at, updated_at
+SELECT id, user_id, name, email, phone, company, address, hourly_rate, currency, country_code, tax_id, reminders_opt_out, invoice_prefix, billable, buyer_reference, created_at, updated_at
 FROM clients
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
 
 type GetClientsByUserIDRow struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
-	UpdatedAt  sql.NullTime   `json:"updated_at"`
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) GetClientsByUserID(ctx context.Context, userID int32) ([]GetClientsByUserIDRow, error) {
@@ -178,6 +220,12 @@ func (q *Queries) GetClientsByUserID(ctx context.Context, userID int32) ([]GetCl
 			&i.Address,
 			&i.HourlyRate,
 			&i.Currency,
+			&i.CountryCode,
+			&i.TaxID,
+			&i.RemindersOptOut,
+			&i.InvoicePrefix,
+			&i.Billable,
+			&i.BuyerReference,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -196,35 +244,47 @@ func (q *Queries) GetClientsByUserID(ctx context.Context, userID int32) ([]GetCl
 
 const updateClient = `-- name: UpdateClient :one
 UPDATE clients
-SET name = $3, email = $4, phone = $5, company = $6, address = $7, hourly_rate = $8, currency = $9, updated_at = CURRENT_TIMESTAMP
+SET name = $3, email = $4, phone = $5, company = $6, address = $7, hourly_rate = $8, currency = $9, country_code = $10, tax_id = $11, reminders_opt_out = $12, invoice_prefix = $13, billable = $14, buyer_reference = $15, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, name, email, phone, company, address, hourly_rate, currency, created_at, updated_at
+RETURNING id, user_id, name, email, phone, company, address, hourly_rate, currency, country_code, tax_id, reminders_opt_out, invoice_prefix, billable, buyer_reference, created_at, updated_at
 `
 
 type UpdateClientParams struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
 }
 
 type UpdateClientRow struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
-	UpdatedAt  sql.NullTime   `json:"updated_at"`
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (UpdateClientRow, error) {
@@ -238,6 +298,12 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Upd
 		arg.Address,
 		arg.HourlyRate,
 		arg.Currency,
+		arg.CountryCode,
+		arg.TaxID,
+		arg.RemindersOptOut,
+		arg.InvoicePrefix,
+		arg.Billable,
+		arg.BuyerReference,
 	)
 	var i UpdateClientRow
 	err := row.Scan(
@@ -250,6 +316,12 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Upd
 		&i.Address,
 		&i.HourlyRate,
 		&i.Currency,
+		&i.CountryCode,
+		&i.TaxID,
+		&i.RemindersOptOut,
+		&i.InvoicePrefix,
+		&i.Billable,
+		&i.BuyerReference,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
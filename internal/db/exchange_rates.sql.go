@@ -55,6 +55,36 @@ func (q *Queries) GetAllExchangeRates(ctx context.Context) ([]ExchangeRate, erro
 	return items, nil
 }
 
+const getCurrenciesInUse = `-- name: GetCurrenciesInUse :many
+SELECT currency FROM clients
+UNION
+SELECT currency FROM users WHERE currency IS NOT NULL
+ORDER BY currency
+`
+
+func (q *Queries) GetCurrenciesInUse(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getCurrenciesInUse)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		items = append(items, currency)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getExchangeRate = `-- name: GetExchangeRate :one
 SELECT id, base_currency, target_currency, rate, updated_at
 FROM exchange_rates
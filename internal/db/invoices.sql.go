@@ -27,9 +27,9 @@ func (q *Queries) AddTimeEntryToInvoice(ctx context.Context, arg AddTimeEntryToI
 }
 
 const createInvoice = `-- name: CreateInvoice :one
-INSERT INTO invoices (user_id, client_id, invoice_number, issue_date, due_date, status, notes)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+INSERT INTO invoices (user_id, client_id, invoice_number, issue_date, due_date, status, notes, reverse_charge)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 `
 
 type CreateInvoiceParams struct {
@@ -40,6 +40,7 @@ type CreateInvoiceParams struct {
 	DueDate       time.Time      `json:"due_date"`
 	Status        string         `json:"status"`
 	Notes         sql.NullString `json:"notes"`
+	ReverseCharge bool           `json:"reverse_charge"`
 }
 
 func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
@@ -51,6 +52,7 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		arg.DueDate,
 		arg.Status,
 		arg.Notes,
+		arg.ReverseCharge,
 	)
 	var i Invoice
 	err := row.Scan(
@@ -64,13 +66,23 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
 	)
 	return i, err
 }
 
-const deleteInvoice = `-- name: DeleteInvoice :exec
+const deleteInvoice = `-- name: DeleteInvoice :execrows
 DELETE FROM invoices
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND user_id = $2 AND status = 'draft'
 `
 
 type DeleteInvoiceParams struct {
@@ -78,16 +90,21 @@ type DeleteInvoiceParams struct {
 	UserID int32 `json:"user_id"`
 }
 
-func (q *Queries) DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) error {
-	_, err := q.db.ExecContext(ctx, deleteInvoice, arg.ID, arg.UserID)
-	return err
+// Deletes an invoice while it is still a draft
+func (q *Queries) DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteInvoice, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getAvailableTimeEntriesForClient = `-- name: GetAvailableTimeEntriesForClient :many
-SELECT te.id, te.user_id, te.client_id, te.date, te.hours, te.description, te.hourly_rate, te.created_at, te.updated_at
+SELECT te.id, te.user_id, te.client_id, te.project_id, te.task_id, te.date, te.hours, te.description, te.hourly_rate, te.billable, te.created_at, te.updated_at
 FROM time_entries te
 WHERE te.client_id = $1
   AND te.user_id = $2
+  AND te.billable
   AND NOT EXISTS (
     SELECT 1 FROM invoice_time_entries ite WHERE ite.time_entry_id = te.id
   )
@@ -103,14 +120,18 @@ type GetAvailableTimeEntriesForClientRow struct {
 	ID          int32          `json:"id"`
 	UserID      int32          `json:"user_id"`
 	ClientID    int32          `json:"client_id"`
+	ProjectID   sql.NullInt32  `json:"project_id"`
+	TaskID      sql.NullInt32  `json:"task_id"`
 	Date        time.Time      `json:"date"`
 	Hours       string         `json:"hours"`
 	Description sql.NullString `json:"description"`
 	HourlyRate  sql.NullString `json:"hourly_rate"`
+	Billable    bool           `json:"billable"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
 }
 
+// Returns the client's billable time entries not billed on any invoice yet
 func (q *Queries) GetAvailableTimeEntriesForClient(ctx context.Context, arg GetAvailableTimeEntriesForClientParams) ([]GetAvailableTimeEntriesForClientRow, error) {
 	rows, err := q.db.QueryContext(ctx, getAvailableTimeEntriesForClient, arg.ClientID, arg.UserID)
 	if err != nil {
@@ -124,10 +145,13 @@ func (q *Queries) GetAvailableTimeEntriesForClient(ctx context.Context, arg GetA
 			&i.ID,
 			&i.UserID,
 			&i.ClientID,
+			&i.ProjectID,
+			&i.TaskID,
 			&i.Date,
 			&i.Hours,
 			&i.Description,
 			&i.HourlyRate,
+			&i.Billable,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -145,7 +169,7 @@ func (q *Queries) GetAvailableTimeEntriesForClient(ctx context.Context, arg GetA
 }
 
 const getInvoiceByID = `-- name: GetInvoiceByID :one
-SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 FROM invoices
 WHERE id = $1 AND user_id = $2
 `
@@ -169,29 +193,63 @@ func (q *Queries) GetInvoiceByID(ctx context.Context, arg GetInvoiceByIDParams)
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
 	)
 	return i, err
 }
 
+const getInvoiceCurrency = `-- name: GetInvoiceCurrency :one
+SELECT CAST(COALESCE(i.currency, c.currency) AS VARCHAR) AS currency
+FROM invoices i
+INNER JOIN clients c ON c.id = i.client_id
+WHERE i.id = $1
+`
+
+// Returns the currency of an invoice: the one frozen when it was issued, or
+// the client's currency for drafts
+func (q *Queries) GetInvoiceCurrency(ctx context.Context, id int32) (string, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceCurrency, id)
+	var currency string
+	err := row.Scan(&currency)
+	return currency, err
+}
+
 const getInvoiceTimeEntries = `-- name: GetInvoiceTimeEntries :many
-SELECT te.id, te.user_id, te.client_id, te.date, te.hours, te.description, te.hourly_rate, te.created_at, te.updated_at
+SELECT te.id, te.user_id, te.client_id, te.project_id, te.task_id, te.date, te.hours, te.description, te.hourly_rate, te.billable, te.created_at, te.updated_at, p.name AS project_name
 FROM time_entries te
 INNER JOIN invoice_time_entries ite ON te.id = ite.time_entry_id
+LEFT JOIN projects p ON p.id = te.project_id
 WHERE ite.invoice_id = $1
+ORDER BY p.name NULLS LAST, te.project_id, te.date, te.id
 `
 
 type GetInvoiceTimeEntriesRow struct {
 	ID          int32          `json:"id"`
 	UserID      int32          `json:"user_id"`
 	ClientID    int32          `json:"client_id"`
+	ProjectID   sql.NullInt32  `json:"project_id"`
+	TaskID      sql.NullInt32  `json:"task_id"`
 	Date        time.Time      `json:"date"`
 	Hours       string         `json:"hours"`
 	Description sql.NullString `json:"description"`
 	HourlyRate  sql.NullString `json:"hourly_rate"`
+	Billable    bool           `json:"billable"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ProjectName sql.NullString `json:"project_name"`
 }
 
+// Returns the time entries billed on an invoice grouped by project, with
+// the entries without a project last
 func (q *Queries) GetInvoiceTimeEntries(ctx context.Context, invoiceID int32) ([]GetInvoiceTimeEntriesRow, error) {
 	rows, err := q.db.QueryContext(ctx, getInvoiceTimeEntries, invoiceID)
 	if err != nil {
@@ -205,12 +263,16 @@ func (q *Queries) GetInvoiceTimeEntries(ctx context.Context, invoiceID int32) ([
 			&i.ID,
 			&i.UserID,
 			&i.ClientID,
+			&i.ProjectID,
+			&i.TaskID,
 			&i.Date,
 			&i.Hours,
 			&i.Description,
 			&i.HourlyRate,
+			&i.Billable,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ProjectName,
 		); err != nil {
 			return nil, err
 		}
@@ -226,7 +288,7 @@ func (q *Queries) GetInvoiceTimeEntries(ctx context.Context, invoiceID int32) ([
 }
 
 const getInvoicesByUserID = `-- name: GetInvoicesByUserID :many
-SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 FROM invoices
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -252,6 +314,16 @@ func (q *Queries) GetInvoicesByUserID(ctx context.Context, userID int32) ([]Invo
 			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ReverseCharge,
+			&i.OverdueAt,
+			&i.Currency,
+			&i.Subtotal,
+			&i.TaxAmount,
+			&i.WithholdingAmount,
+			&i.Total,
+			&i.ReportingCurrency,
+			&i.ExchangeRate,
+			&i.ExchangeRateSource,
 		); err != nil {
 			return nil, err
 		}
@@ -266,11 +338,208 @@ func (q *Queries) GetInvoicesByUserID(ctx context.Context, userID int32) ([]Invo
 	return items, nil
 }
 
-const updateInvoice = `-- name: UpdateInvoice :one
+const lockInvoice = `-- name: LockInvoice :one
+SELECT id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
+FROM invoices
+WHERE id = $1 AND user_id = $2
+FOR UPDATE
+`
+
+type LockInvoiceParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+// Returns an invoice and locks it until the end of the transaction, so that
+// payments, credit notes and revisions are checked against its balance one
+// at a time
+func (q *Queries) LockInvoice(ctx context.Context, arg LockInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, lockInvoice, arg.ID, arg.UserID)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ClientID,
+		&i.InvoiceNumber,
+		&i.IssueDate,
+		&i.DueDate,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
+	)
+	return i, err
+}
+
+const markInvoicesOverdue = `-- name: MarkInvoicesOverdue :many
+UPDATE invoices
+SET status = 'overdue', overdue_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('sent', 'partially_paid') AND due_date < $1
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
+`
+
+func (q *Queries) MarkInvoicesOverdue(ctx context.Context, dueDate time.Time) ([]Invoice, error) {
+	rows, err := q.db.QueryContext(ctx, markInvoicesOverdue, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Invoice
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ClientID,
+			&i.InvoiceNumber,
+			&i.IssueDate,
+			&i.DueDate,
+			&i.Status,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ReverseCharge,
+			&i.OverdueAt,
+			&i.Currency,
+			&i.Subtotal,
+			&i.TaxAmount,
+			&i.WithholdingAmount,
+			&i.Total,
+			&i.ReportingCurrency,
+			&i.ExchangeRate,
+			&i.ExchangeRateSource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setInvoiceNumber = `-- name: SetInvoiceNumber :one
 UPDATE invoices
-SET client_id = $3, invoice_number = $4, issue_date = $5, due_date = $6, status = $7, notes = $8, updated_at = CURRENT_TIMESTAMP
+SET invoice_number = $3, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
+`
+
+type SetInvoiceNumberParams struct {
+	ID            int32  `json:"id"`
+	UserID        int32  `json:"user_id"`
+	InvoiceNumber string `json:"invoice_number"`
+}
+
+// Gives an invoice the number allocated to it when it leaves draft
+func (q *Queries) SetInvoiceNumber(ctx context.Context, arg SetInvoiceNumberParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, setInvoiceNumber, arg.ID, arg.UserID, arg.InvoiceNumber)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ClientID,
+		&i.InvoiceNumber,
+		&i.IssueDate,
+		&i.DueDate,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
+	)
+	return i, err
+}
+
+const setInvoiceTotals = `-- name: SetInvoiceTotals :one
+UPDATE invoices
+SET currency = $3, subtotal = $4, tax_amount = $5, withholding_amount = $6, total = $7, reporting_currency = $8, exchange_rate = $9, exchange_rate_source = $10
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
+`
+
+type SetInvoiceTotalsParams struct {
+	ID                 int32          `json:"id"`
+	UserID             int32          `json:"user_id"`
+	Currency           sql.NullString `json:"currency"`
+	Subtotal           sql.NullString `json:"subtotal"`
+	TaxAmount          sql.NullString `json:"tax_amount"`
+	WithholdingAmount  sql.NullString `json:"withholding_amount"`
+	Total              sql.NullString `json:"total"`
+	ReportingCurrency  sql.NullString `json:"reporting_currency"`
+	ExchangeRate       sql.NullString `json:"exchange_rate"`
+	ExchangeRateSource sql.NullString `json:"exchange_rate_source"`
+}
+
+// Freezes the totals of an invoice that has left draft, in its own currency
+// and converted to the user's currency at the rate used when it was issued
+func (q *Queries) SetInvoiceTotals(ctx context.Context, arg SetInvoiceTotalsParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, setInvoiceTotals,
+		arg.ID,
+		arg.UserID,
+		arg.Currency,
+		arg.Subtotal,
+		arg.TaxAmount,
+		arg.WithholdingAmount,
+		arg.Total,
+		arg.ReportingCurrency,
+		arg.ExchangeRate,
+		arg.ExchangeRateSource,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ClientID,
+		&i.InvoiceNumber,
+		&i.IssueDate,
+		&i.DueDate,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
+	)
+	return i, err
+}
+
+const updateInvoice = `-- name: UpdateInvoice :one
+UPDATE invoices
+SET client_id = $3, invoice_number = $4, issue_date = $5, due_date = $6, status = $7, notes = $8, reverse_charge = $9, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status = 'draft'
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 `
 
 type UpdateInvoiceParams struct {
@@ -282,8 +551,11 @@ type UpdateInvoiceParams struct {
 	DueDate       time.Time      `json:"due_date"`
 	Status        string         `json:"status"`
 	Notes         sql.NullString `json:"notes"`
+	ReverseCharge bool           `json:"reverse_charge"`
 }
 
+// Updates a draft invoice. No row is returned once the invoice has left
+// draft, even if it did so after it was read.
 func (q *Queries) UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) (Invoice, error) {
 	row := q.db.QueryRowContext(ctx, updateInvoice,
 		arg.ID,
@@ -294,6 +566,7 @@ func (q *Queries) UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) (I
 		arg.DueDate,
 		arg.Status,
 		arg.Notes,
+		arg.ReverseCharge,
 	)
 	var i Invoice
 	err := row.Scan(
@@ -307,15 +580,27 @@ func (q *Queries) UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) (I
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
 	)
 	return i, err
 }
 
 const updateInvoiceStatus = `-- name: UpdateInvoiceStatus :one
 UPDATE invoices
-SET status = $3, updated_at = CURRENT_TIMESTAMP
+SET status = $3,
+    overdue_at = CASE WHEN $3 = 'overdue' AND status <> 'overdue' THEN CURRENT_TIMESTAMP ELSE overdue_at END,
+    updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
 `
 
 type UpdateInvoiceStatusParams struct {
@@ -338,6 +623,73 @@ func (q *Queries) UpdateInvoiceStatus(ctx context.Context, arg UpdateInvoiceStat
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
+	)
+	return i, err
+}
+
+const updateIssuedInvoice = `-- name: UpdateIssuedInvoice :one
+UPDATE invoices
+SET client_id = $3, invoice_number = $4, issue_date = $5, due_date = $6, notes = $7, reverse_charge = $8, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND status <> 'draft'
+RETURNING id, user_id, client_id, invoice_number, issue_date, due_date, status, notes, created_at, updated_at, reverse_charge, overdue_at, currency, subtotal, tax_amount, withholding_amount, total, reporting_currency, exchange_rate, exchange_rate_source
+`
+
+type UpdateIssuedInvoiceParams struct {
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	InvoiceNumber string         `json:"invoice_number"`
+	IssueDate     time.Time      `json:"issue_date"`
+	DueDate       time.Time      `json:"due_date"`
+	Notes         sql.NullString `json:"notes"`
+	ReverseCharge bool           `json:"reverse_charge"`
+}
+
+// Updates the details of an invoice that has left draft when it is revised,
+// keeping its status
+func (q *Queries) UpdateIssuedInvoice(ctx context.Context, arg UpdateIssuedInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, updateIssuedInvoice,
+		arg.ID,
+		arg.UserID,
+		arg.ClientID,
+		arg.InvoiceNumber,
+		arg.IssueDate,
+		arg.DueDate,
+		arg.Notes,
+		arg.ReverseCharge,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ClientID,
+		&i.InvoiceNumber,
+		&i.IssueDate,
+		&i.DueDate,
+		&i.Status,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReverseCharge,
+		&i.OverdueAt,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.WithholdingAmount,
+		&i.Total,
+		&i.ReportingCurrency,
+		&i.ExchangeRate,
+		&i.ExchangeRateSource,
 	)
 	return i, err
 }
@@ -6,21 +6,79 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
 type Client struct {
-	ID         int32          `json:"id"`
-	UserID     int32          `json:"user_id"`
-	Name       string         `json:"name"`
-	Email      string         `json:"email"`
-	Phone      sql.NullString `json:"phone"`
-	Company    sql.NullString `json:"company"`
-	Address    sql.NullString `json:"address"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
-	UpdatedAt  sql.NullTime   `json:"updated_at"`
-	HourlyRate sql.NullString `json:"hourly_rate"`
-	Currency   string         `json:"currency"`
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Company         sql.NullString `json:"company"`
+	Address         sql.NullString `json:"address"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	HourlyRate      sql.NullString `json:"hourly_rate"`
+	Currency        string         `json:"currency"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+	RemindersOptOut bool           `json:"reminders_opt_out"`
+	InvoicePrefix   sql.NullString `json:"invoice_prefix"`
+	Billable        bool           `json:"billable"`
+	BuyerReference  sql.NullString `json:"buyer_reference"`
+}
+
+type CreditNote struct {
+	ID                int32          `json:"id"`
+	UserID            int32          `json:"user_id"`
+	InvoiceID         int32          `json:"invoice_id"`
+	ClientID          int32          `json:"client_id"`
+	CreditNoteNumber  string         `json:"credit_note_number"`
+	IssueDate         time.Time      `json:"issue_date"`
+	Reason            sql.NullString `json:"reason"`
+	Subtotal          string         `json:"subtotal"`
+	TaxAmount         string         `json:"tax_amount"`
+	WithholdingAmount string         `json:"withholding_amount"`
+	Total             string         `json:"total"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+}
+
+type CreditNoteLineItem struct {
+	ID           int32  `json:"id"`
+	CreditNoteID int32  `json:"credit_note_id"`
+	Description  string `json:"description"`
+	Quantity     string `json:"quantity"`
+	Unit         string `json:"unit"`
+	UnitPrice    string `json:"unit_price"`
+	TaxRate      string `json:"tax_rate"`
+	Position     int32  `json:"position"`
+}
+
+type Estimate struct {
+	ID             int32          `json:"id"`
+	UserID         int32          `json:"user_id"`
+	ClientID       int32          `json:"client_id"`
+	EstimateNumber string         `json:"estimate_number"`
+	IssueDate      time.Time      `json:"issue_date"`
+	ValidUntil     time.Time      `json:"valid_until"`
+	Status         string         `json:"status"`
+	Notes          sql.NullString `json:"notes"`
+	InvoiceID      sql.NullInt32  `json:"invoice_id"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+}
+
+type EstimateLineItem struct {
+	ID          int32  `json:"id"`
+	EstimateID  int32  `json:"estimate_id"`
+	Description string `json:"description"`
+	Quantity    string `json:"quantity"`
+	Unit        string `json:"unit"`
+	UnitPrice   string `json:"unit_price"`
+	TaxRate     string `json:"tax_rate"`
+	Position    int32  `json:"position"`
 }
 
 type ExchangeRate struct {
@@ -31,17 +89,113 @@ type ExchangeRate struct {
 	UpdatedAt      sql.NullTime `json:"updated_at"`
 }
 
+type ExchangeRateHistory struct {
+	ID             int32        `json:"id"`
+	BaseCurrency   string       `json:"base_currency"`
+	TargetCurrency string       `json:"target_currency"`
+	Rate           string       `json:"rate"`
+	RateDate       time.Time    `json:"rate_date"`
+	CreatedAt      sql.NullTime `json:"created_at"`
+}
+
+type ExchangeRateOverride struct {
+	ID             int32          `json:"id"`
+	UserID         int32          `json:"user_id"`
+	BaseCurrency   string         `json:"base_currency"`
+	TargetCurrency string         `json:"target_currency"`
+	Rate           string         `json:"rate"`
+	ValidFrom      sql.NullTime   `json:"valid_from"`
+	ValidTo        sql.NullTime   `json:"valid_to"`
+	Note           sql.NullString `json:"note"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+}
+
 type Invoice struct {
-	ID            int32          `json:"id"`
-	UserID        int32          `json:"user_id"`
-	ClientID      int32          `json:"client_id"`
-	InvoiceNumber string         `json:"invoice_number"`
-	IssueDate     time.Time      `json:"issue_date"`
-	DueDate       time.Time      `json:"due_date"`
-	Status        string         `json:"status"`
-	Notes         sql.NullString `json:"notes"`
-	CreatedAt     sql.NullTime   `json:"created_at"`
-	UpdatedAt     sql.NullTime   `json:"updated_at"`
+	ID                 int32          `json:"id"`
+	UserID             int32          `json:"user_id"`
+	ClientID           int32          `json:"client_id"`
+	InvoiceNumber      string         `json:"invoice_number"`
+	IssueDate          time.Time      `json:"issue_date"`
+	DueDate            time.Time      `json:"due_date"`
+	Status             string         `json:"status"`
+	Notes              sql.NullString `json:"notes"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	UpdatedAt          sql.NullTime   `json:"updated_at"`
+	ReverseCharge      bool           `json:"reverse_charge"`
+	OverdueAt          sql.NullTime   `json:"overdue_at"`
+	Currency           sql.NullString `json:"currency"`
+	Subtotal           sql.NullString `json:"subtotal"`
+	TaxAmount          sql.NullString `json:"tax_amount"`
+	WithholdingAmount  sql.NullString `json:"withholding_amount"`
+	Total              sql.NullString `json:"total"`
+	ReportingCurrency  sql.NullString `json:"reporting_currency"`
+	ExchangeRate       sql.NullString `json:"exchange_rate"`
+	ExchangeRateSource sql.NullString `json:"exchange_rate_source"`
+}
+
+type InvoiceDelivery struct {
+	ID        int32          `json:"id"`
+	InvoiceID int32          `json:"invoice_id"`
+	Recipient string         `json:"recipient"`
+	Cc        sql.NullString `json:"cc"`
+	Subject   string         `json:"subject"`
+	Message   sql.NullString `json:"message"`
+	MessageID string         `json:"message_id"`
+	SentAt    sql.NullTime   `json:"sent_at"`
+}
+
+type InvoiceLineItem struct {
+	ID          int32        `json:"id"`
+	InvoiceID   int32        `json:"invoice_id"`
+	Description string       `json:"description"`
+	Quantity    string       `json:"quantity"`
+	Unit        string       `json:"unit"`
+	UnitPrice   string       `json:"unit_price"`
+	TaxRate     string       `json:"tax_rate"`
+	Position    int32        `json:"position"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type InvoiceNumberSequence struct {
+	UserID       int32        `json:"user_id"`
+	Scope        string       `json:"scope"`
+	LastValue    int32        `json:"last_value"`
+	UpdatedAt    sql.NullTime `json:"updated_at"`
+	DocumentType string       `json:"document_type"`
+}
+
+type InvoiceReminder struct {
+	ID             int32          `json:"id"`
+	InvoiceID      int32          `json:"invoice_id"`
+	ReminderRuleID sql.NullInt32  `json:"reminder_rule_id"`
+	OffsetDays     int32          `json:"offset_days"`
+	Recipient      string         `json:"recipient"`
+	Subject        string         `json:"subject"`
+	Status         string         `json:"status"`
+	Error          sql.NullString `json:"error"`
+	SentAt         sql.NullTime   `json:"sent_at"`
+}
+
+type InvoiceRevision struct {
+	ID        int32           `json:"id"`
+	InvoiceID int32           `json:"invoice_id"`
+	Revision  int32           `json:"revision"`
+	Reason    sql.NullString  `json:"reason"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+	CreatedAt sql.NullTime    `json:"created_at"`
+}
+
+type InvoiceTax struct {
+	ID          int32         `json:"id"`
+	InvoiceID   int32         `json:"invoice_id"`
+	TaxRateID   sql.NullInt32 `json:"tax_rate_id"`
+	Name        string        `json:"name"`
+	Rate        string        `json:"rate"`
+	Compound    bool          `json:"compound"`
+	Withholding bool          `json:"withholding"`
+	Position    int32         `json:"position"`
 }
 
 type InvoiceTimeEntry struct {
@@ -49,16 +203,147 @@ type InvoiceTimeEntry struct {
 	TimeEntryID int32 `json:"time_entry_id"`
 }
 
-type TimeEntry struct {
+type Notification struct {
+	ID        int32         `json:"id"`
+	UserID    int32         `json:"user_id"`
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	Message   string        `json:"message"`
+	ProjectID sql.NullInt32 `json:"project_id"`
+	ReadAt    sql.NullTime  `json:"read_at"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+}
+
+type Payment struct {
 	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
+	InvoiceID   int32          `json:"invoice_id"`
+	Amount      string         `json:"amount"`
+	Currency    string         `json:"currency"`
+	PaymentDate time.Time      `json:"payment_date"`
+	Method      string         `json:"method"`
+	Reference   sql.NullString `json:"reference"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
+}
+
+type Project struct {
+	ID                    int32          `json:"id"`
+	UserID                int32          `json:"user_id"`
+	ClientID              int32          `json:"client_id"`
+	Name                  string         `json:"name"`
+	Description           sql.NullString `json:"description"`
+	Status                string         `json:"status"`
+	HourlyRate            sql.NullString `json:"hourly_rate"`
+	BudgetHours           sql.NullString `json:"budget_hours"`
+	BudgetAmount          sql.NullString `json:"budget_amount"`
+	CreatedAt             sql.NullTime   `json:"created_at"`
+	UpdatedAt             sql.NullTime   `json:"updated_at"`
+	BudgetAlertThresholds []int32        `json:"budget_alert_thresholds"`
+	Billable              sql.NullBool   `json:"billable"`
+}
+
+type ProjectBudgetAlert struct {
+	ProjectID int32        `json:"project_id"`
+	Budget    string       `json:"budget"`
+	Threshold int32        `json:"threshold"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type RecurringInvoice struct {
+	ID               int32          `json:"id"`
+	UserID           int32          `json:"user_id"`
+	ClientID         int32          `json:"client_id"`
+	Name             string         `json:"name"`
+	Frequency        string         `json:"frequency"`
+	AnchorDay        int32          `json:"anchor_day"`
+	StartDate        time.Time      `json:"start_date"`
+	EndDate          sql.NullTime   `json:"end_date"`
+	PaymentTermsDays int32          `json:"payment_terms_days"`
+	Notes            sql.NullString `json:"notes"`
+	AutoSend         bool           `json:"auto_send"`
+	Status           string         `json:"status"`
+	NextRunDate      sql.NullTime   `json:"next_run_date"`
+	LastRunDate      sql.NullTime   `json:"last_run_date"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+}
+
+type RecurringInvoiceLineItem struct {
+	ID                 int32  `json:"id"`
+	RecurringInvoiceID int32  `json:"recurring_invoice_id"`
+	Description        string `json:"description"`
+	Quantity           string `json:"quantity"`
+	Unit               string `json:"unit"`
+	UnitPrice          string `json:"unit_price"`
+	TaxRate            string `json:"tax_rate"`
+	Position           int32  `json:"position"`
+}
+
+type ReminderRule struct {
+	ID         int32          `json:"id"`
+	UserID     int32          `json:"user_id"`
+	OffsetDays int32          `json:"offset_days"`
+	Subject    sql.NullString `json:"subject"`
+	Message    sql.NullString `json:"message"`
+	Enabled    bool           `json:"enabled"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
+}
+
+type Task struct {
+	ID         int32          `json:"id"`
+	ProjectID  int32          `json:"project_id"`
+	Name       string         `json:"name"`
+	HourlyRate sql.NullString `json:"hourly_rate"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
+}
+
+type TaxRate struct {
+	ID          int32        `json:"id"`
+	UserID      int32        `json:"user_id"`
+	Name        string       `json:"name"`
+	Rate        string       `json:"rate"`
+	Compound    bool         `json:"compound"`
+	Withholding bool         `json:"withholding"`
+	IsDefault   bool         `json:"is_default"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type TimeEntry struct {
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Billable      bool           `json:"billable"`
+	PausedSeconds int32          `json:"paused_seconds"`
+}
+
+type Timer struct {
+	ID             int32          `json:"id"`
+	UserID         int32          `json:"user_id"`
+	ClientID       int32          `json:"client_id"`
+	Description    sql.NullString `json:"description"`
+	Status         string         `json:"status"`
+	StartedAt      time.Time      `json:"started_at"`
+	ResumedAt      sql.NullTime   `json:"resumed_at"`
+	ElapsedSeconds int32          `json:"elapsed_seconds"`
+	StoppedAt      sql.NullTime   `json:"stopped_at"`
+	TimeEntryID    sql.NullInt32  `json:"time_entry_id"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	ProjectID      sql.NullInt32  `json:"project_id"`
+	TaskID         sql.NullInt32  `json:"task_id"`
 }
 
 type User struct {
@@ -83,4 +368,12 @@ type User struct {
 	OauthProvider             sql.NullString `json:"oauth_provider"`
 	AvatarUrl                 sql.NullString `json:"avatar_url"`
 	StripeCustomerID          sql.NullString `json:"stripe_customer_id"`
+	BusinessName              sql.NullString `json:"business_name"`
+	BusinessAddress           sql.NullString `json:"business_address"`
+	CountryCode               sql.NullString `json:"country_code"`
+	TaxID                     sql.NullString `json:"tax_id"`
+	InvoiceNumberPattern      string         `json:"invoice_number_pattern"`
+	CreditNoteNumberPattern   string         `json:"credit_note_number_pattern"`
+	EstimateNumberPattern     string         `json:"estimate_number_pattern"`
+	Timezone                  string         `json:"timezone"`
 }
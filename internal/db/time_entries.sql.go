@@ -12,59 +12,84 @@ import (
 )
 
 const createTimeEntry = `-- name: CreateTimeEntry :one
-INSERT INTO time_entries (user_id, client_id, date, hours, description, hourly_rate)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+INSERT INTO time_entries (user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 `
 
 type CreateTimeEntryParams struct {
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
 }
 
 type CreateTimeEntryRow struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) CreateTimeEntry(ctx context.Context, arg CreateTimeEntryParams) (CreateTimeEntryRow, error) {
 	row := q.db.QueryRowContext(ctx, createTimeEntry,
 		arg.UserID,
 		arg.ClientID,
+		arg.ProjectID,
+		arg.TaskID,
 		arg.Date,
 		arg.Hours,
 		arg.Description,
 		arg.HourlyRate,
+		arg.Billable,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.PausedSeconds,
 	)
 	var i CreateTimeEntryRow
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.ClientID,
+		&i.ProjectID,
+		&i.TaskID,
 		&i.Date,
 		&i.Hours,
 		&i.Description,
 		&i.HourlyRate,
+		&i.Billable,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.PausedSeconds,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const deleteTimeEntry = `-- name: DeleteTimeEntry :exec
+const deleteTimeEntry = `-- name: DeleteTimeEntry :one
 DELETE FROM time_entries
 WHERE id = $1 AND user_id = $2
+RETURNING project_id
 `
 
 type DeleteTimeEntryParams struct {
@@ -72,16 +97,19 @@ type DeleteTimeEntryParams struct {
 	UserID int32 `json:"user_id"`
 }
 
-func (q *Queries) DeleteTimeEntry(ctx context.Context, arg DeleteTimeEntryParams) error {
-	_, err := q.db.ExecContext(ctx, deleteTimeEntry, arg.ID, arg.UserID)
-	return err
+// Returns the project the deleted time entry was recorded against
+func (q *Queries) DeleteTimeEntry(ctx context.Context, arg DeleteTimeEntryParams) (sql.NullInt32, error) {
+	row := q.db.QueryRowContext(ctx, deleteTimeEntry, arg.ID, arg.UserID)
+	var project_id sql.NullInt32
+	err := row.Scan(&project_id)
+	return project_id, err
 }
 
 const getDetailedTimeEntriesByDateRange = `-- name: GetDetailedTimeEntriesByDateRange :many
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE user_id = $1 AND date >= $2 AND date <= $3
-ORDER BY date DESC, created_at DESC
+ORDER BY date DESC, started_at DESC NULLS LAST, created_at DESC
 `
 
 type GetDetailedTimeEntriesByDateRangeParams struct {
@@ -91,15 +119,21 @@ type GetDetailedTimeEntriesByDateRangeParams struct {
 }
 
 type GetDetailedTimeEntriesByDateRangeRow struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) GetDetailedTimeEntriesByDateRange(ctx context.Context, arg GetDetailedTimeEntriesByDateRangeParams) ([]GetDetailedTimeEntriesByDateRangeRow, error) {
@@ -115,10 +149,16 @@ func (q *Queries) GetDetailedTimeEntriesByDateRange(ctx context.Context, arg Get
 			&i.ID,
 			&i.UserID,
 			&i.ClientID,
+			&i.ProjectID,
+			&i.TaskID,
 			&i.Date,
 			&i.Hours,
 			&i.Description,
 			&i.HourlyRate,
+			&i.Billable,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.PausedSeconds,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -135,11 +175,74 @@ func (q *Queries) GetDetailedTimeEntriesByDateRange(ctx context.Context, arg Get
 	return items, nil
 }
 
+const getOverlappingTimeEntries = `-- name: GetOverlappingTimeEntries :many
+SELECT id, user_id, client_id, date, hours, description, created_at, updated_at, hourly_rate, started_at, ended_at, project_id, task_id, billable, paused_seconds
+FROM time_entries
+WHERE user_id = $1
+  AND id <> $2
+  AND started_at < $3
+  AND ended_at > $4
+ORDER BY started_at
+`
+
+type GetOverlappingTimeEntriesParams struct {
+	UserID    int32        `json:"user_id"`
+	ExcludeID int32        `json:"exclude_id"`
+	EndedAt   sql.NullTime `json:"ended_at"`
+	StartedAt sql.NullTime `json:"started_at"`
+}
+
+// Returns the user's time entries, other than exclude_id, whose period
+// overlaps the given one. Periods that only touch do not overlap.
+func (q *Queries) GetOverlappingTimeEntries(ctx context.Context, arg GetOverlappingTimeEntriesParams) ([]TimeEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getOverlappingTimeEntries,
+		arg.UserID,
+		arg.ExcludeID,
+		arg.EndedAt,
+		arg.StartedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TimeEntry
+	for rows.Next() {
+		var i TimeEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ClientID,
+			&i.Date,
+			&i.Hours,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HourlyRate,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ProjectID,
+			&i.TaskID,
+			&i.Billable,
+			&i.PausedSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTimeEntriesByClientID = `-- name: GetTimeEntriesByClientID :many
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE client_id = $1 AND user_id = $2
-ORDER BY date DESC, created_at DESC
+ORDER BY date DESC, started_at DESC NULLS LAST, created_at DESC
 `
 
 type GetTimeEntriesByClientIDParams struct {
@@ -148,15 +251,21 @@ type GetTimeEntriesByClientIDParams struct {
 }
 
 type GetTimeEntriesByClientIDRow struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) GetTimeEntriesByClientID(ctx context.Context, arg GetTimeEntriesByClientIDParams) ([]GetTimeEntriesByClientIDRow, error) {
@@ -172,10 +281,16 @@ func (q *Queries) GetTimeEntriesByClientID(ctx context.Context, arg GetTimeEntri
 			&i.ID,
 			&i.UserID,
 			&i.ClientID,
+			&i.ProjectID,
+			&i.TaskID,
 			&i.Date,
 			&i.Hours,
 			&i.Description,
 			&i.HourlyRate,
+			&i.Billable,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.PausedSeconds,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -193,7 +308,7 @@ func (q *Queries) GetTimeEntriesByClientID(ctx context.Context, arg GetTimeEntri
 }
 
 const getTimeEntriesByDateRange = `-- name: GetTimeEntriesByDateRange :many
-SELECT date, CAST(SUM(CAST(hours AS DECIMAL)) AS TEXT) as total_hours
+SELECT date, CAST(SUM(CAST(hours AS DECIMAL)) AS TEXT) as total_hours, CAST(SUM(CASE WHEN billable THEN hours ELSE 0 END) AS TEXT) as billable_hours
 FROM time_entries
 WHERE user_id = $1 AND date >= $2 AND date <= $3
 GROUP BY date
@@ -207,8 +322,9 @@ type GetTimeEntriesByDateRangeParams struct {
 }
 
 type GetTimeEntriesByDateRangeRow struct {
-	Date       time.Time `json:"date"`
-	TotalHours string    `json:"total_hours"`
+	Date          time.Time `json:"date"`
+	TotalHours    string    `json:"total_hours"`
+	BillableHours string    `json:"billable_hours"`
 }
 
 func (q *Queries) GetTimeEntriesByDateRange(ctx context.Context, arg GetTimeEntriesByDateRangeParams) ([]GetTimeEntriesByDateRangeRow, error) {
@@ -220,7 +336,7 @@ func (q *Queries) GetTimeEntriesByDateRange(ctx context.Context, arg GetTimeEntr
 	var items []GetTimeEntriesByDateRangeRow
 	for rows.Next() {
 		var i GetTimeEntriesByDateRangeRow
-		if err := rows.Scan(&i.Date, &i.TotalHours); err != nil {
+		if err := rows.Scan(&i.Date, &i.TotalHours, &i.BillableHours); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -235,22 +351,28 @@ func (q *Queries) GetTimeEntriesByDateRange(ctx context.Context, arg GetTimeEntr
 }
 
 const getTimeEntriesByUserID = `-- name: GetTimeEntriesByUserID :many
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE user_id = $1
-ORDER BY date DESC, created_at DESC
+ORDER BY date DESC, started_at DESC NULLS LAST, created_at DESC
 `
 
 type GetTimeEntriesByUserIDRow struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) GetTimeEntriesByUserID(ctx context.Context, userID int32) ([]GetTimeEntriesByUserIDRow, error) {
@@ -266,10 +388,16 @@ func (q *Queries) GetTimeEntriesByUserID(ctx context.Context, userID int32) ([]G
 			&i.ID,
 			&i.UserID,
 			&i.ClientID,
+			&i.ProjectID,
+			&i.TaskID,
 			&i.Date,
 			&i.Hours,
 			&i.Description,
 			&i.HourlyRate,
+			&i.Billable,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.PausedSeconds,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -287,7 +415,7 @@ func (q *Queries) GetTimeEntriesByUserID(ctx context.Context, userID int32) ([]G
 }
 
 const getTimeEntryByID = `-- name: GetTimeEntryByID :one
-SELECT id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+SELECT id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 FROM time_entries
 WHERE id = $1 AND user_id = $2
 `
@@ -298,15 +426,21 @@ type GetTimeEntryByIDParams struct {
 }
 
 type GetTimeEntryByIDRow struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) GetTimeEntryByID(ctx context.Context, arg GetTimeEntryByIDParams) (GetTimeEntryByIDRow, error) {
@@ -316,43 +450,94 @@ func (q *Queries) GetTimeEntryByID(ctx context.Context, arg GetTimeEntryByIDPara
 		&i.ID,
 		&i.UserID,
 		&i.ClientID,
+		&i.ProjectID,
+		&i.TaskID,
 		&i.Date,
 		&i.Hours,
 		&i.Description,
 		&i.HourlyRate,
+		&i.Billable,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.PausedSeconds,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const isTimeEntryInvoiced = `-- name: IsTimeEntryInvoiced :one
+SELECT EXISTS (
+    SELECT 1
+    FROM invoice_time_entries ite
+    WHERE ite.time_entry_id = $1
+) AS invoiced
+`
+
+// Time entries billed on an invoice, draft or not, stay billable
+func (q *Queries) IsTimeEntryInvoiced(ctx context.Context, timeEntryID int32) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTimeEntryInvoiced, timeEntryID)
+	var invoiced bool
+	err := row.Scan(&invoiced)
+	return invoiced, err
+}
+
+const isTimeEntryLocked = `-- name: IsTimeEntryLocked :one
+SELECT EXISTS (
+    SELECT 1
+    FROM invoice_time_entries ite
+    INNER JOIN invoices i ON i.id = ite.invoice_id
+    WHERE ite.time_entry_id = $1 AND i.status <> 'draft'
+) AS locked
+`
+
+// Time entries billed on an invoice that has left draft can no longer change
+func (q *Queries) IsTimeEntryLocked(ctx context.Context, timeEntryID int32) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTimeEntryLocked, timeEntryID)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
+
 const updateTimeEntry = `-- name: UpdateTimeEntry :one
 UPDATE time_entries
-SET client_id = $3, date = $4, hours = $5, description = $6, hourly_rate = $7, updated_at = CURRENT_TIMESTAMP
+SET client_id = $3, project_id = $4, task_id = $5, date = $6, hours = $7, description = $8, hourly_rate = $9, billable = $10, started_at = $11, ended_at = $12, paused_seconds = $13, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, client_id, date, hours, description, hourly_rate, created_at, updated_at
+RETURNING id, user_id, client_id, project_id, task_id, date, hours, description, hourly_rate, billable, started_at, ended_at, paused_seconds, created_at, updated_at
 `
 
 type UpdateTimeEntryParams struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
 }
 
 type UpdateTimeEntryRow struct {
-	ID          int32          `json:"id"`
-	UserID      int32          `json:"user_id"`
-	ClientID    int32          `json:"client_id"`
-	Date        time.Time      `json:"date"`
-	Hours       string         `json:"hours"`
-	Description sql.NullString `json:"description"`
-	HourlyRate  sql.NullString `json:"hourly_rate"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID            int32          `json:"id"`
+	UserID        int32          `json:"user_id"`
+	ClientID      int32          `json:"client_id"`
+	ProjectID     sql.NullInt32  `json:"project_id"`
+	TaskID        sql.NullInt32  `json:"task_id"`
+	Date          time.Time      `json:"date"`
+	Hours         string         `json:"hours"`
+	Description   sql.NullString `json:"description"`
+	HourlyRate    sql.NullString `json:"hourly_rate"`
+	Billable      bool           `json:"billable"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+	EndedAt       sql.NullTime   `json:"ended_at"`
+	PausedSeconds int32          `json:"paused_seconds"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) UpdateTimeEntry(ctx context.Context, arg UpdateTimeEntryParams) (UpdateTimeEntryRow, error) {
@@ -360,20 +545,32 @@ func (q *Queries) UpdateTimeEntry(ctx context.Context, arg UpdateTimeEntryParams
 		arg.ID,
 		arg.UserID,
 		arg.ClientID,
+		arg.ProjectID,
+		arg.TaskID,
 		arg.Date,
 		arg.Hours,
 		arg.Description,
 		arg.HourlyRate,
+		arg.Billable,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.PausedSeconds,
 	)
 	var i UpdateTimeEntryRow
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.ClientID,
+		&i.ProjectID,
+		&i.TaskID,
 		&i.Date,
 		&i.Hours,
 		&i.Description,
 		&i.HourlyRate,
+		&i.Billable,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.PausedSeconds,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
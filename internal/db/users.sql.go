@@ -191,6 +191,37 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 	return i, err
 }
 
+const getUserBillingProfile = `-- name: GetUserBillingProfile :one
+SELECT id, email, name, business_name, business_address, country_code, tax_id
+FROM users
+WHERE id = $1
+`
+
+type GetUserBillingProfileRow struct {
+	ID              int32          `json:"id"`
+	Email           string         `json:"email"`
+	Name            string         `json:"name"`
+	BusinessName    sql.NullString `json:"business_name"`
+	BusinessAddress sql.NullString `json:"business_address"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+}
+
+func (q *Queries) GetUserBillingProfile(ctx context.Context, id int32) (GetUserBillingProfileRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserBillingProfile, id)
+	var i GetUserBillingProfileRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.BusinessName,
+		&i.BusinessAddress,
+		&i.CountryCode,
+		&i.TaxID,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT id, email, password_hash, name, email_verified, onboarding_completed, tour_completed, currency, date_format, created_at, updated_at
 FROM users
@@ -331,6 +362,38 @@ func (q *Queries) GetUserByVerificationToken(ctx context.Context, verificationTo
 	return i, err
 }
 
+const getUserNumberPatterns = `-- name: GetUserNumberPatterns :one
+SELECT invoice_number_pattern, credit_note_number_pattern, estimate_number_pattern
+FROM users
+WHERE id = $1
+`
+
+type GetUserNumberPatternsRow struct {
+	InvoiceNumberPattern    string `json:"invoice_number_pattern"`
+	CreditNoteNumberPattern string `json:"credit_note_number_pattern"`
+	EstimateNumberPattern   string `json:"estimate_number_pattern"`
+}
+
+func (q *Queries) GetUserNumberPatterns(ctx context.Context, id int32) (GetUserNumberPatternsRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserNumberPatterns, id)
+	var i GetUserNumberPatternsRow
+	err := row.Scan(&i.InvoiceNumberPattern, &i.CreditNoteNumberPattern, &i.EstimateNumberPattern)
+	return i, err
+}
+
+const getUserTimezone = `-- name: GetUserTimezone :one
+SELECT timezone
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserTimezone(ctx context.Context, id int32) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserTimezone, id)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
 const resetPassword = `-- name: ResetPassword :one
 UPDATE users
 SET password_hash = $2,
@@ -405,6 +468,56 @@ func (q *Queries) UpdatePasswordResetToken(ctx context.Context, arg UpdatePasswo
 	return i, err
 }
 
+const updateUserBillingProfile = `-- name: UpdateUserBillingProfile :one
+UPDATE users
+SET business_name = $2,
+    business_address = $3,
+    country_code = $4,
+    tax_id = $5,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, email, name, business_name, business_address, country_code, tax_id
+`
+
+type UpdateUserBillingProfileParams struct {
+	ID              int32          `json:"id"`
+	BusinessName    sql.NullString `json:"business_name"`
+	BusinessAddress sql.NullString `json:"business_address"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+}
+
+type UpdateUserBillingProfileRow struct {
+	ID              int32          `json:"id"`
+	Email           string         `json:"email"`
+	Name            string         `json:"name"`
+	BusinessName    sql.NullString `json:"business_name"`
+	BusinessAddress sql.NullString `json:"business_address"`
+	CountryCode     sql.NullString `json:"country_code"`
+	TaxID           sql.NullString `json:"tax_id"`
+}
+
+func (q *Queries) UpdateUserBillingProfile(ctx context.Context, arg UpdateUserBillingProfileParams) (UpdateUserBillingProfileRow, error) {
+	row := q.db.QueryRowContext(ctx, updateUserBillingProfile,
+		arg.ID,
+		arg.BusinessName,
+		arg.BusinessAddress,
+		arg.CountryCode,
+		arg.TaxID,
+	)
+	var i UpdateUserBillingProfileRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.BusinessName,
+		&i.BusinessAddress,
+		&i.CountryCode,
+		&i.TaxID,
+	)
+	return i, err
+}
+
 const updateUserCurrency = `-- name: UpdateUserCurrency :one
 UPDATE users
 SET currency = $2,
@@ -449,6 +562,61 @@ func (q *Queries) UpdateUserCurrency(ctx context.Context, arg UpdateUserCurrency
 	return i, err
 }
 
+const updateUserNumberPatterns = `-- name: UpdateUserNumberPatterns :one
+UPDATE users
+SET invoice_number_pattern = $2,
+    credit_note_number_pattern = $3,
+    estimate_number_pattern = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING invoice_number_pattern, credit_note_number_pattern, estimate_number_pattern
+`
+
+type UpdateUserNumberPatternsParams struct {
+	ID                      int32  `json:"id"`
+	InvoiceNumberPattern    string `json:"invoice_number_pattern"`
+	CreditNoteNumberPattern string `json:"credit_note_number_pattern"`
+	EstimateNumberPattern   string `json:"estimate_number_pattern"`
+}
+
+type UpdateUserNumberPatternsRow struct {
+	InvoiceNumberPattern    string `json:"invoice_number_pattern"`
+	CreditNoteNumberPattern string `json:"credit_note_number_pattern"`
+	EstimateNumberPattern   string `json:"estimate_number_pattern"`
+}
+
+func (q *Queries) UpdateUserNumberPatterns(ctx context.Context, arg UpdateUserNumberPatternsParams) (UpdateUserNumberPatternsRow, error) {
+	row := q.db.QueryRowContext(ctx, updateUserNumberPatterns,
+		arg.ID,
+		arg.InvoiceNumberPattern,
+		arg.CreditNoteNumberPattern,
+		arg.EstimateNumberPattern,
+	)
+	var i UpdateUserNumberPatternsRow
+	err := row.Scan(&i.InvoiceNumberPattern, &i.CreditNoteNumberPattern, &i.EstimateNumberPattern)
+	return i, err
+}
+
+const updateUserTimezone = `-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING timezone
+`
+
+type UpdateUserTimezoneParams struct {
+	ID       int32  `json:"id"`
+	Timezone string `json:"timezone"`
+}
+
+func (q *Queries) UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTimezone, arg.ID, arg.Timezone)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
 const updateVerificationToken = `-- name: UpdateVerificationToken :one
 UPDATE users
 SET verification_token = $2,
@@ -0,0 +1,299 @@
+package einvoice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"worklio-api/internal/billing"
+
+	"github.com/shopspring/decimal"
+)
+
+// FacturXProfileEN16931 is the guideline identifier for the EN 16931 (COMFORT) profile
+const FacturXProfileEN16931 = "urn:cen.eu:en16931:2017"
+
+// FacturXFilename is the name the Factur-X specification mandates for the embedded XML
+const FacturXFilename = "factur-x.xml"
+
+// Document type codes from UNTDID 1001
+const (
+	TypeCodeCommercialInvoice = "380"
+)
+
+// CII namespaces
+const (
+	nsRSM = "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100"
+	nsRAM = "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100"
+	nsUDT = "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100"
+	nsQDT = "urn:un:unece:uncefact:data:standard:QualifiedDataType:100"
+)
+
+// Element order in the structs below follows the CII D16B XSD sequence, which
+// schema validation enforces.
+
+type ciiInvoice struct {
+	XMLName     xml.Name           `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRSM    string             `xml:"xmlns:rsm,attr"`
+	XmlnsRAM    string             `xml:"xmlns:ram,attr"`
+	XmlnsUDT    string             `xml:"xmlns:udt,attr"`
+	XmlnsQDT    string             `xml:"xmlns:qdt,attr"`
+	Context     ciiDocumentContext `xml:"rsm:ExchangedDocumentContext"`
+	Document    ciiDocument        `xml:"rsm:ExchangedDocument"`
+	Transaction ciiTransaction     `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiDocumentContext struct {
+	GuidelineID string `xml:"ram:GuidelineSpecifiedDocumentContextParameter>ram:ID"`
+}
+
+type ciiDocument struct {
+	ID            string      `xml:"ram:ID"`
+	TypeCode      string      `xml:"ram:TypeCode"`
+	IssueDateTime ciiDateTime `xml:"ram:IssueDateTime"`
+	Notes         []ciiNote   `xml:"ram:IncludedNote,omitempty"`
+}
+
+type ciiNote struct {
+	Content string `xml:"ram:Content"`
+}
+
+type ciiDateTime struct {
+	Value ciiDateString `xml:"udt:DateTimeString"`
+}
+
+type ciiDateString struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiTransaction struct {
+	Lines      []ciiLineItem       `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  ciiHeaderAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   ciiHeaderDelivery   `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiHeaderSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLineItem struct {
+	LineID     string            `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Product    ciiProduct        `xml:"ram:SpecifiedTradeProduct"`
+	Agreement  ciiLineAgreement  `xml:"ram:SpecifiedLineTradeAgreement"`
+	Delivery   ciiLineDelivery   `xml:"ram:SpecifiedLineTradeDelivery"`
+	Settlement ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiProduct struct {
+	Name string `xml:"ram:Name"`
+}
+
+type ciiLineAgreement struct {
+	NetPrice string `xml:"ram:NetPriceProductTradePrice>ram:ChargeAmount"`
+}
+
+type ciiLineDelivery struct {
+	BilledQuantity ciiQuantity `xml:"ram:BilledQuantity"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiLineSettlement struct {
+	Tax       ciiLineTax `xml:"ram:ApplicableTradeTax"`
+	LineTotal string     `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation>ram:LineTotalAmount"`
+}
+
+type ciiLineTax struct {
+	TypeCode     string `xml:"ram:TypeCode"`
+	CategoryCode string `xml:"ram:CategoryCode"`
+	RatePercent  string `xml:"ram:RateApplicablePercent,omitempty"`
+}
+
+type ciiHeaderAgreement struct {
+	BuyerReference string        `xml:"ram:BuyerReference,omitempty"`
+	Seller         ciiTradeParty `xml:"ram:SellerTradeParty"`
+	Buyer          ciiTradeParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiTradeParty struct {
+	Name            string              `xml:"ram:Name"`
+	Address         ciiAddress          `xml:"ram:PostalTradeAddress"`
+	Email           *ciiURIID           `xml:"ram:URIUniversalCommunication,omitempty"`
+	TaxRegistration *ciiTaxRegistration `xml:"ram:SpecifiedTaxRegistration,omitempty"`
+}
+
+type ciiAddress struct {
+	LineOne   string `xml:"ram:LineOne,omitempty"`
+	CountryID string `xml:"ram:CountryID"`
+}
+
+type ciiURIID struct {
+	URIID ciiSchemedID `xml:"ram:URIID"`
+}
+
+type ciiTaxRegistration struct {
+	ID ciiSchemedID `xml:"ram:ID"`
+}
+
+type ciiSchemedID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiHeaderDelivery struct{}
+
+type ciiHeaderSettlement struct {
+	Currency     string             `xml:"ram:InvoiceCurrencyCode"`
+	Taxes        []ciiHeaderTax     `xml:"ram:ApplicableTradeTax"`
+	PaymentTerms ciiPaymentTerms    `xml:"ram:SpecifiedTradePaymentTerms"`
+	Summation    ciiHeaderSummation `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+type ciiHeaderTax struct {
+	CalculatedAmount string `xml:"ram:CalculatedAmount"`
+	TypeCode         string `xml:"ram:TypeCode"`
+	ExemptionReason  string `xml:"ram:ExemptionReason,omitempty"`
+	BasisAmount      string `xml:"ram:BasisAmount"`
+	CategoryCode     string `xml:"ram:CategoryCode"`
+	RatePercent      string `xml:"ram:RateApplicablePercent,omitempty"`
+}
+
+type ciiPaymentTerms struct {
+	DueDate ciiDateTime `xml:"ram:DueDateDateTime"`
+}
+
+type ciiHeaderSummation struct {
+	LineTotal     string    `xml:"ram:LineTotalAmount"`
+	TaxBasisTotal string    `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal      ciiAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotal    string    `xml:"ram:GrandTotalAmount"`
+	DuePayable    string    `xml:"ram:DuePayableAmount"`
+}
+
+type ciiAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+// BuildCII serializes doc as an EN 16931 Cross Industry Invoice
+func BuildCII(doc *billing.Document) ([]byte, error) {
+	if err := Validate(doc); err != nil {
+		return nil, err
+	}
+
+	currency := doc.Currency()
+	lines := doc.Lines()
+	breakdowns := vatBreakdowns(doc, lines)
+
+	ciiLines := make([]ciiLineItem, len(lines))
+	var lineTotal decimal.Decimal
+	for i, line := range lines {
+		lineTotal = lineTotal.Add(roundAmount(line.Amount, currency))
+		tax := lineVAT(doc, line)
+		ciiLines[i] = ciiLineItem{
+			LineID:  fmt.Sprintf("%d", i+1),
+			Product: ciiProduct{Name: line.Description},
+			Agreement: ciiLineAgreement{
+				NetPrice: formatAmount(line.UnitPrice, currency),
+			},
+			Delivery: ciiLineDelivery{
+				BilledQuantity: ciiQuantity{UnitCode: line.Unit, Value: formatQuantity(line.Quantity)},
+			},
+			Settlement: ciiLineSettlement{
+				Tax: ciiLineTax{
+					TypeCode:     "VAT",
+					CategoryCode: tax.CategoryCode,
+					RatePercent:  tax.ratePercent(),
+				},
+				LineTotal: formatAmount(line.Amount, currency),
+			},
+		}
+	}
+
+	var taxTotal decimal.Decimal
+	ciiTaxes := make([]ciiHeaderTax, len(breakdowns))
+	for i, breakdown := range breakdowns {
+		taxTotal = taxTotal.Add(breakdown.Tax)
+		ciiTaxes[i] = ciiHeaderTax{
+			CalculatedAmount: formatAmount(breakdown.Tax, currency),
+			TypeCode:         "VAT",
+			ExemptionReason:  breakdown.ExemptionReason,
+			BasisAmount:      formatAmount(breakdown.Basis, currency),
+			CategoryCode:     breakdown.CategoryCode,
+			RatePercent:      breakdown.ratePercent(),
+		}
+	}
+	grandTotal := lineTotal.Add(taxTotal)
+
+	invoice := doc.Invoice
+	var notes []ciiNote
+	if invoice.Notes.Valid && invoice.Notes.String != "" {
+		notes = append(notes, ciiNote{Content: invoice.Notes.String})
+	}
+
+	out := ciiInvoice{
+		XmlnsRSM: nsRSM,
+		XmlnsRAM: nsRAM,
+		XmlnsUDT: nsUDT,
+		XmlnsQDT: nsQDT,
+		Context:  ciiDocumentContext{GuidelineID: FacturXProfileEN16931},
+		Document: ciiDocument{
+			ID:            invoice.InvoiceNumber,
+			TypeCode:      TypeCodeCommercialInvoice,
+			IssueDateTime: ciiDate(invoice.IssueDate),
+			Notes:         notes,
+		},
+		Transaction: ciiTransaction{
+			Lines: ciiLines,
+			Agreement: ciiHeaderAgreement{
+				BuyerReference: doc.Client.BuyerReference.String,
+				Seller: ciiTradeParty{
+					Name:            doc.SellerName(),
+					Address:         ciiAddress{LineOne: doc.Seller.BusinessAddress.String, CountryID: doc.Seller.CountryCode.String},
+					Email:           &ciiURIID{URIID: ciiSchemedID{SchemeID: "EM", Value: doc.Seller.Email}},
+					TaxRegistration: ciiVATRegistration(doc.Seller.TaxID.String),
+				},
+				Buyer: ciiTradeParty{
+					Name:            doc.BuyerName(),
+					Address:         ciiAddress{LineOne: doc.Client.Address.String, CountryID: doc.Client.CountryCode.String},
+					Email:           &ciiURIID{URIID: ciiSchemedID{SchemeID: "EM", Value: doc.Client.Email}},
+					TaxRegistration: ciiVATRegistration(buyerVATID(doc, breakdowns)),
+				},
+			},
+			Settlement: ciiHeaderSettlement{
+				Currency:     currency,
+				Taxes:        ciiTaxes,
+				PaymentTerms: ciiPaymentTerms{DueDate: ciiDate(invoice.DueDate)},
+				Summation: ciiHeaderSummation{
+					LineTotal:     formatAmount(lineTotal, currency),
+					TaxBasisTotal: formatAmount(lineTotal, currency),
+					TaxTotal:      ciiAmount{CurrencyID: currency, Value: formatAmount(taxTotal, currency)},
+					GrandTotal:    formatAmount(grandTotal, currency),
+					DuePayable:    formatAmount(grandTotal, currency),
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return nil, fmt.Errorf("failed to encode CII invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func ciiVATRegistration(taxID string) *ciiTaxRegistration {
+	if taxID == "" {
+		return nil
+	}
+	return &ciiTaxRegistration{ID: ciiSchemedID{SchemeID: "VA", Value: taxID}}
+}
+
+func ciiDate(t time.Time) ciiDateTime {
+	return ciiDateTime{Value: ciiDateString{Format: "102", Value: t.Format("20060102")}}
+}
@@ -0,0 +1,668 @@
+package einvoice
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// ciiElement is one child of an element in the Factur-X EN 16931 schema
+type ciiElement struct {
+	name     string
+	min, max int
+}
+
+// ciiSchema is the content model of the Factur-X EN 16931 XSD (CII D16B) for
+// every element BuildCII writes: each element's children in the order the
+// schema's xs:sequence requires them, with their cardinality. Elements the
+// schema allows but BuildCII never writes are listed too, so the order
+// around them is checked. Leaf elements are absent.
+var ciiSchema = map[string][]ciiElement{
+	"rsm:CrossIndustryInvoice": {
+		{"rsm:ExchangedDocumentContext", 1, 1},
+		{"rsm:ExchangedDocument", 1, 1},
+		{"rsm:SupplyChainTradeTransaction", 1, 1},
+	},
+	"rsm:ExchangedDocumentContext": {
+		{"ram:TestIndicator", 0, 1},
+		{"ram:BusinessProcessSpecifiedDocumentContextParameter", 0, 1},
+		{"ram:GuidelineSpecifiedDocumentContextParameter", 1, 1},
+	},
+	"ram:GuidelineSpecifiedDocumentContextParameter": {
+		{"ram:ID", 1, 1},
+	},
+	"rsm:ExchangedDocument": {
+		{"ram:ID", 1, 1},
+		{"ram:TypeCode", 1, 1},
+		{"ram:IssueDateTime", 1, 1},
+		{"ram:IncludedNote", 0, -1},
+	},
+	"ram:IssueDateTime": {
+		{"udt:DateTimeString", 1, 1},
+	},
+	"ram:IncludedNote": {
+		{"ram:Content", 1, 1},
+		{"ram:SubjectCode", 0, 1},
+	},
+	"rsm:SupplyChainTradeTransaction": {
+		{"ram:IncludedSupplyChainTradeLineItem", 1, -1},
+		{"ram:ApplicableHeaderTradeAgreement", 1, 1},
+		{"ram:ApplicableHeaderTradeDelivery", 1, 1},
+		{"ram:ApplicableHeaderTradeSettlement", 1, 1},
+	},
+	"ram:IncludedSupplyChainTradeLineItem": {
+		{"ram:AssociatedDocumentLineDocument", 1, 1},
+		{"ram:SpecifiedTradeProduct", 1, 1},
+		{"ram:SpecifiedLineTradeAgreement", 1, 1},
+		{"ram:SpecifiedLineTradeDelivery", 1, 1},
+		{"ram:SpecifiedLineTradeSettlement", 1, 1},
+	},
+	"ram:AssociatedDocumentLineDocument": {
+		{"ram:LineID", 1, 1},
+		{"ram:IncludedNote", 0, 1},
+	},
+	"ram:SpecifiedTradeProduct": {
+		{"ram:GlobalID", 0, 1},
+		{"ram:SellerAssignedID", 0, 1},
+		{"ram:BuyerAssignedID", 0, 1},
+		{"ram:Name", 1, 1},
+		{"ram:Description", 0, 1},
+	},
+	"ram:SpecifiedLineTradeAgreement": {
+		{"ram:BuyerOrderReferencedDocument", 0, 1},
+		{"ram:GrossPriceProductTradePrice", 0, 1},
+		{"ram:NetPriceProductTradePrice", 1, 1},
+	},
+	"ram:NetPriceProductTradePrice": {
+		{"ram:ChargeAmount", 1, 1},
+		{"ram:BasisQuantity", 0, 1},
+	},
+	"ram:SpecifiedLineTradeDelivery": {
+		{"ram:BilledQuantity", 1, 1},
+	},
+	"ram:SpecifiedLineTradeSettlement": {
+		{"ram:ApplicableTradeTax", 1, 1},
+		{"ram:BillingSpecifiedPeriod", 0, 1},
+		{"ram:SpecifiedTradeAllowanceCharge", 0, -1},
+		{"ram:SpecifiedTradeSettlementLineMonetarySummation", 1, 1},
+		{"ram:AdditionalReferencedDocument", 0, 1},
+		{"ram:ReceivableSpecifiedTradeAccountingAccount", 0, 1},
+	},
+	"ram:ApplicableTradeTax": {
+		{"ram:CalculatedAmount", 0, 1},
+		{"ram:TypeCode", 1, 1},
+		{"ram:ExemptionReason", 0, 1},
+		{"ram:BasisAmount", 0, 1},
+		{"ram:CategoryCode", 1, 1},
+		{"ram:ExemptionReasonCode", 0, 1},
+		{"ram:DueDateTypeCode", 0, 1},
+		{"ram:RateApplicablePercent", 0, 1},
+	},
+	"ram:SpecifiedTradeSettlementLineMonetarySummation": {
+		{"ram:LineTotalAmount", 1, 1},
+	},
+	"ram:ApplicableHeaderTradeAgreement": {
+		{"ram:BuyerReference", 0, 1},
+		{"ram:SellerTradeParty", 1, 1},
+		{"ram:BuyerTradeParty", 1, 1},
+		{"ram:SellerTaxRepresentativeTradeParty", 0, 1},
+		{"ram:BuyerOrderReferencedDocument", 0, 1},
+		{"ram:ContractReferencedDocument", 0, 1},
+	},
+	"ram:SellerTradeParty": ciiTradePartyModel,
+	"ram:BuyerTradeParty":  ciiTradePartyModel,
+	"ram:PostalTradeAddress": {
+		{"ram:PostcodeCode", 0, 1},
+		{"ram:LineOne", 0, 1},
+		{"ram:LineTwo", 0, 1},
+		{"ram:LineThree", 0, 1},
+		{"ram:CityName", 0, 1},
+		{"ram:CountryID", 1, 1},
+		{"ram:CountrySubDivisionName", 0, 1},
+	},
+	"ram:URIUniversalCommunication": {
+		{"ram:URIID", 1, 1},
+	},
+	"ram:SpecifiedTaxRegistration": {
+		{"ram:ID", 1, 1},
+	},
+	"ram:ApplicableHeaderTradeDelivery": {
+		{"ram:ShipToTradeParty", 0, 1},
+		{"ram:ActualDeliverySupplyChainEvent", 0, 1},
+		{"ram:DespatchAdviceReferencedDocument", 0, 1},
+		{"ram:ReceivingAdviceReferencedDocument", 0, 1},
+	},
+	"ram:ApplicableHeaderTradeSettlement": {
+		{"ram:CreditorReferenceID", 0, 1},
+		{"ram:PaymentReference", 0, 1},
+		{"ram:TaxCurrencyCode", 0, 1},
+		{"ram:InvoiceCurrencyCode", 1, 1},
+		{"ram:PayeeTradeParty", 0, 1},
+		{"ram:SpecifiedTradeSettlementPaymentMeans", 0, -1},
+		{"ram:ApplicableTradeTax", 1, -1},
+		{"ram:BillingSpecifiedPeriod", 0, 1},
+		{"ram:SpecifiedTradeAllowanceCharge", 0, -1},
+		{"ram:SpecifiedTradePaymentTerms", 0, 1},
+		{"ram:SpecifiedTradeSettlementHeaderMonetarySummation", 1, 1},
+		{"ram:InvoiceReferencedDocument", 0, 1},
+		{"ram:ReceivableSpecifiedTradeAccountingAccount", 0, 1},
+	},
+	"ram:SpecifiedTradePaymentTerms": {
+		{"ram:Description", 0, 1},
+		{"ram:DueDateDateTime", 0, 1},
+		{"ram:DirectDebitMandateID", 0, 1},
+	},
+	"ram:DueDateDateTime": {
+		{"udt:DateTimeString", 1, 1},
+	},
+	"ram:SpecifiedTradeSettlementHeaderMonetarySummation": {
+		{"ram:LineTotalAmount", 1, 1},
+		{"ram:ChargeTotalAmount", 0, 1},
+		{"ram:AllowanceTotalAmount", 0, 1},
+		{"ram:TaxBasisTotalAmount", 1, 1},
+		{"ram:TaxTotalAmount", 0, 2},
+		{"ram:RoundingAmount", 0, 1},
+		{"ram:GrandTotalAmount", 1, 1},
+		{"ram:TotalPrepaidAmount", 0, 1},
+		{"ram:DuePayableAmount", 1, 1},
+	},
+}
+
+var ciiTradePartyModel = []ciiElement{
+	{"ram:ID", 0, -1},
+	{"ram:GlobalID", 0, -1},
+	{"ram:Name", 1, 1},
+	{"ram:Description", 0, 1},
+	{"ram:SpecifiedLegalOrganization", 0, 1},
+	{"ram:DefinedTradeContact", 0, 1},
+	{"ram:PostalTradeAddress", 1, 1},
+	{"ram:URIUniversalCommunication", 0, 1},
+	{"ram:SpecifiedTaxRegistration", 0, 2},
+}
+
+// ciiLeafPatterns are the lexical spaces of the schema's simple types for
+// the leaves BuildCII writes
+var ciiLeafPatterns = map[string]*regexp.Regexp{
+	"udt:DateTimeString":        regexp.MustCompile(`^\d{8}$`),
+	"ram:TypeCode":              regexp.MustCompile(`^(380|VAT)$`),
+	"ram:CategoryCode":          regexp.MustCompile(`^(S|Z|E|AE|K|G|O|L|M)$`),
+	"ram:CountryID":             regexp.MustCompile(`^[A-Z]{2}$`),
+	"ram:InvoiceCurrencyCode":   regexp.MustCompile(`^[A-Z]{3}$`),
+	"ram:RateApplicablePercent": regexp.MustCompile(`^\d+(\.\d+)?$`),
+	"ram:ChargeAmount":          regexp.MustCompile(`^-?\d+(\.\d+)?$`),
+	"ram:BilledQuantity":        regexp.MustCompile(`^-?\d+(\.\d+)?$`),
+	"ram:LineTotalAmount":       regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+	"ram:CalculatedAmount":      regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+	"ram:BasisAmount":           regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+	"ram:TaxBasisTotalAmount":   regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+	"ram:TaxTotalAmount":        regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+	"ram:GrandTotalAmount":      regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+	"ram:DuePayableAmount":      regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`),
+}
+
+// ciiRequiredAttributes are the attributes the schema requires on leaves
+var ciiRequiredAttributes = map[string]string{
+	"udt:DateTimeString": "format",
+	"ram:BilledQuantity": "unitCode",
+	"ram:TaxTotalAmount": "currencyID",
+	"ram:URIID":          "schemeID",
+}
+
+var ciiPrefixes = map[string]string{
+	nsRSM: "rsm",
+	nsRAM: "ram",
+	nsUDT: "udt",
+	nsQDT: "qdt",
+}
+
+type ciiNode struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*ciiNode
+}
+
+func parseCII(t *testing.T, data []byte) *ciiNode {
+	t.Helper()
+	return parseXML(t, data, ciiPrefixes)
+}
+
+// parseXML parses data into a tree of nodes named with the prefixes of their
+// namespaces
+func parseXML(t *testing.T, data []byte, prefixes map[string]string) *ciiNode {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*ciiNode
+	var root *ciiNode
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if root == nil {
+				t.Fatalf("failed to parse XML: %v", err)
+			}
+			return root
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			prefix, ok := prefixes[token.Name.Space]
+			if !ok {
+				t.Fatalf("element %s is in unexpected namespace %q", token.Name.Local, token.Name.Space)
+			}
+			node := &ciiNode{name: prefix + ":" + token.Name.Local, attrs: make(map[string]string)}
+			for _, attr := range token.Attr {
+				if attr.Name.Space == "" {
+					node.attrs[attr.Name.Local] = attr.Value
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(token)
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// checkCIISchema reports every place node does not follow ciiSchema
+func checkCIISchema(t *testing.T, node *ciiNode, path string) {
+	t.Helper()
+	path += "/" + node.name
+
+	model, ok := ciiSchema[node.name]
+	if !ok {
+		if len(node.children) > 0 {
+			t.Errorf("%s: element has children but is a leaf in the schema", path)
+		}
+		text := strings.TrimSpace(node.text)
+		if text == "" {
+			t.Errorf("%s: empty value", path)
+		}
+		if pattern, ok := ciiLeafPatterns[node.name]; ok && !pattern.MatchString(text) {
+			t.Errorf("%s: value %q does not match %s", path, text, pattern)
+		}
+		if attr, ok := ciiRequiredAttributes[node.name]; ok && node.attrs[attr] == "" {
+			t.Errorf("%s: missing %s attribute", path, attr)
+		}
+		return
+	}
+
+	// Walk the sequence, consuming the children that match each particle
+	i := 0
+	for _, particle := range model {
+		count := 0
+		for i < len(node.children) && node.children[i].name == particle.name {
+			count++
+			i++
+		}
+		if count < particle.min {
+			t.Errorf("%s: expected at least %d %s, found %d", path, particle.min, particle.name, count)
+		}
+		if particle.max >= 0 && count > particle.max {
+			t.Errorf("%s: expected at most %d %s, found %d", path, particle.max, particle.name, count)
+		}
+	}
+	if i < len(node.children) {
+		t.Errorf("%s: unexpected or misplaced element %s", path, node.children[i].name)
+	}
+
+	for _, child := range node.children {
+		checkCIISchema(t, child, path)
+	}
+}
+
+// find returns the descendants of node at path, e.g.
+// "rsm:SupplyChainTradeTransaction/ram:IncludedSupplyChainTradeLineItem"
+func (n *ciiNode) find(path string) []*ciiNode {
+	nodes := []*ciiNode{n}
+	for _, name := range strings.Split(path, "/") {
+		var next []*ciiNode
+		for _, node := range nodes {
+			for _, child := range node.children {
+				if child.name == name {
+					next = append(next, child)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (n *ciiNode) value(t *testing.T, path string) string {
+	t.Helper()
+	nodes := n.find(path)
+	if len(nodes) != 1 {
+		t.Fatalf("expected one %s, found %d", path, len(nodes))
+	}
+	return strings.TrimSpace(nodes[0].text)
+}
+
+func testDocument() *billing.Document {
+	return &billing.Document{
+		Contents: billing.Contents{
+			Currency: "EUR",
+			TimeEntries: []db.GetInvoiceTimeEntriesRow{
+				{
+					Date:        time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+					Hours:       "2.50",
+					Description: sql.NullString{String: "Design review", Valid: true},
+					HourlyRate:  sql.NullString{String: "85.0000", Valid: true},
+					Billable:    true,
+				},
+				{
+					Date:       time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+					Hours:      "1.25",
+					HourlyRate: sql.NullString{String: "85.0000", Valid: true},
+					Billable:   true,
+				},
+			},
+			LineItems: []db.InvoiceLineItem{
+				{Description: "Hosting", Quantity: "1", Unit: "month", UnitPrice: "49.99", TaxRate: "0"},
+				{Description: "Licence", Quantity: "3", Unit: "unit", UnitPrice: "19.333", TaxRate: "5.5"},
+			},
+			Taxes: []db.InvoiceTax{
+				{Name: "VAT", Rate: "20"},
+			},
+		},
+		Parties: billing.Parties{
+			Seller: db.GetUserBillingProfileRow{
+				Name:            "Jane Doe",
+				Email:           "jane@example.com",
+				BusinessName:    sql.NullString{String: "Doe & Co", Valid: true},
+				BusinessAddress: sql.NullString{String: "1 Rue de Rivoli, Paris", Valid: true},
+				CountryCode:     sql.NullString{String: "FR", Valid: true},
+				TaxID:           sql.NullString{String: "FR12345678901", Valid: true},
+			},
+			Client: db.GetClientByIDRow{
+				Name:           "John Smith",
+				Email:          "billing@acme.example",
+				Company:        sql.NullString{String: "Acme <GmbH>", Valid: true},
+				Address:        sql.NullString{String: "Hauptstraße 1, Berlin", Valid: true},
+				Currency:       "EUR",
+				CountryCode:    sql.NullString{String: "DE", Valid: true},
+				TaxID:          sql.NullString{String: "DE123456789", Valid: true},
+				BuyerReference: sql.NullString{String: "PO-7731", Valid: true},
+			},
+		},
+		Invoice: db.Invoice{
+			InvoiceNumber: "INV-2025-0042",
+			IssueDate:     time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			DueDate:       time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+			Notes:         sql.NullString{String: "Thank you for your business", Valid: true},
+		},
+	}
+}
+
+func TestBuildCIIFollowsSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc *billing.Document)
+		// categories are the expected VAT breakdown categories
+		categories []string
+	}{
+		{
+			name:       "standard rated",
+			categories: []string{"S", "S"},
+		},
+		{
+			name: "reverse charge",
+			modify: func(doc *billing.Document) {
+				doc.Invoice.ReverseCharge = true
+			},
+			categories: []string{"AE"},
+		},
+		{
+			name: "seller not VAT registered",
+			modify: func(doc *billing.Document) {
+				doc.Seller.TaxID = sql.NullString{}
+				doc.Taxes = nil
+				doc.LineItems = doc.LineItems[:1]
+			},
+			categories: []string{"O"},
+		},
+		{
+			name: "exempt",
+			modify: func(doc *billing.Document) {
+				doc.Taxes = nil
+				doc.LineItems = doc.LineItems[:1]
+				doc.Invoice.Notes = sql.NullString{}
+			},
+			categories: []string{"E"},
+		},
+		{
+			name: "zero decimal currency",
+			modify: func(doc *billing.Document) {
+				doc.Contents.Currency = "JPY"
+				doc.Client.Currency = "JPY"
+			},
+			categories: []string{"S", "S"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := testDocument()
+			if tt.modify != nil {
+				tt.modify(doc)
+			}
+
+			out, err := BuildCII(doc)
+			if err != nil {
+				t.Fatalf("BuildCII: %v", err)
+			}
+			root := parseCII(t, out)
+			if root.name != "rsm:CrossIndustryInvoice" {
+				t.Fatalf("root element is %s", root.name)
+			}
+			checkCIISchema(t, root, "")
+
+			if got := root.value(t, "rsm:ExchangedDocumentContext/ram:GuidelineSpecifiedDocumentContextParameter/ram:ID"); got != FacturXProfileEN16931 {
+				t.Errorf("guideline is %q, want %q", got, FacturXProfileEN16931)
+			}
+
+			var categories []string
+			for _, tax := range root.find("rsm:SupplyChainTradeTransaction/ram:ApplicableHeaderTradeSettlement/ram:ApplicableTradeTax") {
+				for _, child := range tax.children {
+					if child.name == "ram:CategoryCode" {
+						categories = append(categories, child.text)
+					}
+				}
+			}
+			if strings.Join(categories, ",") != strings.Join(tt.categories, ",") {
+				t.Errorf("VAT breakdown categories are %v, want %v", categories, tt.categories)
+			}
+		})
+	}
+}
+
+// TestBuildCIITotals checks the EN 16931 calculation rules on the totals
+func TestBuildCIITotals(t *testing.T) {
+	out, err := BuildCII(testDocument())
+	if err != nil {
+		t.Fatalf("BuildCII: %v", err)
+	}
+	root := parseCII(t, out)
+
+	settlement := "rsm:SupplyChainTradeTransaction/ram:ApplicableHeaderTradeSettlement"
+	summation := settlement + "/ram:SpecifiedTradeSettlementHeaderMonetarySummation"
+
+	// BR-CO-10: the sum of line net amounts
+	lineSum := decimal.Zero
+	for _, total := range root.find("rsm:SupplyChainTradeTransaction/ram:IncludedSupplyChainTradeLineItem/ram:SpecifiedLineTradeSettlement/ram:SpecifiedTradeSettlementLineMonetarySummation/ram:LineTotalAmount") {
+		lineSum = lineSum.Add(money.Parse(total.text))
+	}
+	lineTotal := money.Parse(root.value(t, summation+"/ram:LineTotalAmount"))
+	if !lineSum.Equal(lineTotal) {
+		t.Errorf("BR-CO-10: line total %s is not the sum of lines %s", lineTotal, lineSum)
+	}
+
+	// BR-CO-14: the sum of VAT category tax amounts
+	taxSum := decimal.Zero
+	for _, tax := range root.find(settlement + "/ram:ApplicableTradeTax/ram:CalculatedAmount") {
+		taxSum = taxSum.Add(money.Parse(tax.text))
+	}
+	taxTotal := money.Parse(root.value(t, summation+"/ram:TaxTotalAmount"))
+	if !taxSum.Equal(taxTotal) {
+		t.Errorf("BR-CO-14: tax total %s is not the sum of the breakdown %s", taxTotal, taxSum)
+	}
+
+	// BR-CO-15: grand total is the basis plus tax
+	basis := money.Parse(root.value(t, summation+"/ram:TaxBasisTotalAmount"))
+	grandTotal := money.Parse(root.value(t, summation+"/ram:GrandTotalAmount"))
+	if !basis.Add(taxTotal).Equal(grandTotal) {
+		t.Errorf("BR-CO-15: grand total %s is not %s + %s", grandTotal, basis, taxTotal)
+	}
+
+	// 2.5 h and 1.25 h at 85 = 318.75, 49.99 and 3 x 19.333 = 57.999 rounded
+	if want := money.Parse("426.74"); !lineTotal.Equal(want) {
+		t.Errorf("line total is %s, want %s", lineTotal, want)
+	}
+	// The time entries and hosting at 20%, the licences at 25.5%
+	if want := money.Parse("88.54"); !taxTotal.Equal(want) {
+		t.Errorf("tax total is %s, want %s", taxTotal, want)
+	}
+}
+
+func TestBuildCIIEscapesText(t *testing.T) {
+	out, err := BuildCII(testDocument())
+	if err != nil {
+		t.Fatalf("BuildCII: %v", err)
+	}
+	root := parseCII(t, out)
+	name := root.value(t, "rsm:SupplyChainTradeTransaction/ram:ApplicableHeaderTradeAgreement/ram:BuyerTradeParty/ram:Name")
+	if name != "Acme <GmbH>" {
+		t.Errorf("buyer name is %q", name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc *billing.Document)
+		rules  []string
+	}{
+		{
+			name:   "valid",
+			modify: func(doc *billing.Document) {},
+		},
+		{
+			name: "missing country codes",
+			modify: func(doc *billing.Document) {
+				doc.Seller.CountryCode = sql.NullString{}
+				doc.Client.CountryCode = sql.NullString{}
+			},
+			rules: []string{"BR-09", "BR-11"},
+		},
+		{
+			name: "no lines",
+			modify: func(doc *billing.Document) {
+				doc.TimeEntries = nil
+				doc.LineItems = nil
+			},
+			rules: []string{"BR-16"},
+		},
+		{
+			name: "taxed without seller VAT ID",
+			modify: func(doc *billing.Document) {
+				doc.Seller.TaxID = sql.NullString{}
+			},
+			rules: []string{"BR-S-02"},
+		},
+		{
+			name: "reverse charge without client VAT ID",
+			modify: func(doc *billing.Document) {
+				doc.Invoice.ReverseCharge = true
+				doc.Client.TaxID = sql.NullString{}
+			},
+			rules: []string{"BR-AE-02"},
+		},
+		{
+			name: "compound tax",
+			modify: func(doc *billing.Document) {
+				doc.Taxes = append(doc.Taxes, db.InvoiceTax{Name: "QST", Rate: "9.975", Compound: true})
+			},
+			rules: []string{`compound tax "QST"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := testDocument()
+			tt.modify(doc)
+
+			err := Validate(doc)
+			if len(tt.rules) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate returned %v, want a ValidationError", err)
+			}
+			if len(validationErr.Problems) != len(tt.rules) {
+				t.Errorf("problems are %q, want %d", validationErr.Problems, len(tt.rules))
+			}
+			for _, rule := range tt.rules {
+				if !strings.Contains(err.Error(), rule) {
+					t.Errorf("error %q does not mention %s", err, rule)
+				}
+			}
+			if _, err := BuildCII(doc); err == nil {
+				t.Error("BuildCII accepted an invalid document")
+			}
+		})
+	}
+}
+
+// TestBuildCIIValidatesAgainstXSD validates the XML with xmllint against the
+// Factur-X EN 16931 XSD. The schema is distributed by FNFE-MPE and is not
+// part of the repository: set FACTURX_XSD to the path of
+// Factur-X_*_EN16931.xsd to run it.
+func TestBuildCIIValidatesAgainstXSD(t *testing.T) {
+	xsd := os.Getenv("FACTURX_XSD")
+	if xsd == "" {
+		t.Skip("FACTURX_XSD is not set")
+	}
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint is not installed")
+	}
+
+	out, err := BuildCII(testDocument())
+	if err != nil {
+		t.Fatalf("BuildCII: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), FacturXFilename)
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if output, err := exec.Command(xmllint, "--noout", "--schema", xsd, path).CombinedOutput(); err != nil {
+		t.Fatalf("xmllint: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,189 @@
+// Package einvoice serializes invoices into the structured e-invoice formats
+// required by EU public and private buyers: UN/CEFACT Cross Industry Invoice
+// (used by Factur-X / ZUGFeRD) and UBL.
+package einvoice
+
+import (
+	"fmt"
+	"strings"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/iso4217"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// ValidationError lists the EN 16931 business rules a document does not satisfy
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invoice cannot be exported as an e-invoice: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the EN 16931 business rules that depend on data the user
+// has to provide (rather than data this package derives)
+func Validate(doc *billing.Document) error {
+	var problems []string
+
+	if doc.Invoice.InvoiceNumber == "" {
+		problems = append(problems, "invoice number is required (BR-02)")
+	}
+	if doc.SellerName() == "" {
+		problems = append(problems, "seller name is required (BR-06)")
+	}
+	if len(doc.Seller.CountryCode.String) != 2 {
+		problems = append(problems, "seller country code is required in the billing profile (BR-09)")
+	}
+	if doc.BuyerName() == "" {
+		problems = append(problems, "buyer name is required (BR-07)")
+	}
+	if len(doc.Client.CountryCode.String) != 2 {
+		problems = append(problems, "client country code is required (BR-11)")
+	}
+	lines := doc.Lines()
+	if len(lines) == 0 {
+		problems = append(problems, "at least one invoice line is required (BR-16)")
+	}
+	for _, line := range lines {
+		if lineVAT(doc, line).CategoryCode == "S" && !hasSellerVATID(doc) {
+			problems = append(problems, "seller tax ID is required in the billing profile when lines are taxed (BR-S-02)")
+			break
+		}
+	}
+	for _, tax := range doc.Taxes {
+		if tax.Compound {
+			problems = append(problems, fmt.Sprintf("compound tax %q cannot be expressed in EN 16931", tax.Name))
+		}
+		if tax.Withholding {
+			problems = append(problems, fmt.Sprintf("withholding tax %q cannot be expressed in EN 16931", tax.Name))
+		}
+	}
+	if doc.Invoice.ReverseCharge {
+		if !hasSellerVATID(doc) {
+			problems = append(problems, "seller tax ID is required for reverse charge invoices (BR-AE-02)")
+		}
+		if doc.Client.TaxID.String == "" {
+			problems = append(problems, "client tax ID is required for reverse charge invoices (BR-AE-02)")
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// vatCategory describes how VAT applies to the whole document
+type vatCategory struct {
+	CategoryCode    string
+	Rate            *decimal.Decimal
+	ExemptionReason string
+}
+
+func (v vatCategory) ratePercent() string {
+	if v.Rate == nil {
+		return ""
+	}
+	return formatPercent(*v.Rate)
+}
+
+// vatBreakdown is one entry of the document level VAT breakdown (BG-23)
+type vatBreakdown struct {
+	vatCategory
+	Basis decimal.Decimal
+	Tax   decimal.Decimal
+}
+
+// lineVAT returns the VAT category of a line. Reverse charge invoices use
+// category AE. Otherwise a line is standard rated (S) at its own tax rate plus
+// the invoice-level tax rates; untaxed lines are exempt (E) when the seller is
+// VAT registered and outside the scope of VAT (O) otherwise.
+func lineVAT(doc *billing.Document, line billing.Line) vatCategory {
+	zero := decimal.Zero
+	if doc.Invoice.ReverseCharge {
+		return vatCategory{CategoryCode: "AE", Rate: &zero, ExemptionReason: "Reverse charge"}
+	}
+
+	rate := line.TaxRate
+	for _, tax := range doc.Taxes {
+		if tax.Compound || tax.Withholding {
+			continue
+		}
+		rate = rate.Add(money.Parse(tax.Rate))
+	}
+	if rate.IsPositive() {
+		return vatCategory{CategoryCode: "S", Rate: &rate}
+	}
+	if hasSellerVATID(doc) {
+		return vatCategory{CategoryCode: "E", Rate: &zero, ExemptionReason: "Exempt from VAT"}
+	}
+	return vatCategory{CategoryCode: "O", ExemptionReason: "Not subject to VAT"}
+}
+
+// vatBreakdowns groups lines by VAT category and rate, in order of first
+// appearance. Tax is computed once per group (BR-CO-17).
+func vatBreakdowns(doc *billing.Document, lines []billing.Line) []vatBreakdown {
+	currency := doc.Currency()
+	var breakdowns []vatBreakdown
+	index := make(map[string]int)
+	for _, line := range lines {
+		category := lineVAT(doc, line)
+		key := category.CategoryCode + "/" + category.ratePercent()
+		i, ok := index[key]
+		if !ok {
+			i = len(breakdowns)
+			index[key] = i
+			breakdowns = append(breakdowns, vatBreakdown{vatCategory: category})
+		}
+		breakdowns[i].Basis = breakdowns[i].Basis.Add(roundAmount(line.Amount, currency))
+	}
+	for i := range breakdowns {
+		if breakdowns[i].Rate != nil {
+			breakdowns[i].Tax = roundAmount(money.Percent(breakdowns[i].Basis, *breakdowns[i].Rate), currency)
+		}
+	}
+	return breakdowns
+}
+
+func hasSellerVATID(doc *billing.Document) bool {
+	return doc.Seller.TaxID.Valid && doc.Seller.TaxID.String != ""
+}
+
+// buyerVATID returns the buyer VAT identifier to declare. Documents with
+// lines outside the scope of VAT must not carry one (BR-O-02).
+func buyerVATID(doc *billing.Document, breakdowns []vatBreakdown) string {
+	for _, breakdown := range breakdowns {
+		if breakdown.CategoryCode == "O" {
+			return ""
+		}
+	}
+	return doc.Client.TaxID.String
+}
+
+// amountDecimals returns the number of decimals amounts in currency are
+// written with: the currency's minor units, capped at the two decimals
+// EN 16931 allows
+func amountDecimals(currency string) int32 {
+	return int32(min(iso4217.MinorUnits(currency), 2))
+}
+
+// roundAmount rounds to the currency's minor unit so header totals equal the
+// sum of printed line totals
+func roundAmount(amount decimal.Decimal, currency string) decimal.Decimal {
+	return money.RoundTo(amount, amountDecimals(currency))
+}
+
+func formatAmount(amount decimal.Decimal, currency string) string {
+	return money.FormatTo(amount, amountDecimals(currency))
+}
+
+func formatPercent(rate decimal.Decimal) string {
+	return rate.StringFixed(2)
+}
+
+func formatQuantity(quantity decimal.Decimal) string {
+	return quantity.StringFixed(2)
+}
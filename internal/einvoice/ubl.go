@@ -0,0 +1,315 @@
+package einvoice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+
+	"github.com/shopspring/decimal"
+)
+
+// Peppol BIS Billing 3.0 identifiers
+const (
+	PeppolCustomizationID = "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0"
+	PeppolProfileID       = "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+)
+
+// UBL 2.1 namespaces
+const (
+	nsUBLInvoice = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	nsCAC        = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	nsCBC        = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+)
+
+// Element order in the structs below follows the UBL 2.1 Invoice XSD sequence.
+
+type ublInvoice struct {
+	XMLName              xml.Name         `xml:"Invoice"`
+	Xmlns                string           `xml:"xmlns,attr"`
+	XmlnsCAC             string           `xml:"xmlns:cac,attr"`
+	XmlnsCBC             string           `xml:"xmlns:cbc,attr"`
+	CustomizationID      string           `xml:"cbc:CustomizationID"`
+	ProfileID            string           `xml:"cbc:ProfileID"`
+	ID                   string           `xml:"cbc:ID"`
+	IssueDate            string           `xml:"cbc:IssueDate"`
+	DueDate              string           `xml:"cbc:DueDate"`
+	InvoiceTypeCode      string           `xml:"cbc:InvoiceTypeCode"`
+	Notes                []string         `xml:"cbc:Note,omitempty"`
+	DocumentCurrencyCode string           `xml:"cbc:DocumentCurrencyCode"`
+	BuyerReference       string           `xml:"cbc:BuyerReference,omitempty"`
+	Supplier             ublParty         `xml:"cac:AccountingSupplierParty>cac:Party"`
+	Customer             ublParty         `xml:"cac:AccountingCustomerParty>cac:Party"`
+	TaxTotal             ublTaxTotal      `xml:"cac:TaxTotal"`
+	MonetaryTotal        ublMonetaryTotal `xml:"cac:LegalMonetaryTotal"`
+	Lines                []ublInvoiceLine `xml:"cac:InvoiceLine"`
+}
+
+type ublParty struct {
+	EndpointID   ublSchemedID `xml:"cbc:EndpointID"`
+	Name         string       `xml:"cac:PartyName>cbc:Name"`
+	Address      ublAddress   `xml:"cac:PostalAddress"`
+	TaxScheme    *ublPartyTax `xml:"cac:PartyTaxScheme,omitempty"`
+	LegalName    string       `xml:"cac:PartyLegalEntity>cbc:RegistrationName"`
+	ContactEmail string       `xml:"cac:Contact>cbc:ElectronicMail,omitempty"`
+}
+
+type ublSchemedID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublAddress struct {
+	StreetName  string `xml:"cbc:StreetName,omitempty"`
+	CountryCode string `xml:"cac:Country>cbc:IdentificationCode"`
+}
+
+type ublPartyTax struct {
+	CompanyID   string `xml:"cbc:CompanyID"`
+	TaxSchemeID string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount ublAmount        `xml:"cbc:TaxAmount"`
+	Subtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	TaxableAmount ublAmount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     ublAmount      `xml:"cbc:TaxAmount"`
+	Category      ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxCategory struct {
+	ID              string `xml:"cbc:ID"`
+	Percent         string `xml:"cbc:Percent,omitempty"`
+	ExemptionReason string `xml:"cbc:TaxExemptionReason,omitempty"`
+	TaxSchemeID     string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtensionAmount ublAmount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount  ublAmount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount  ublAmount `xml:"cbc:TaxInclusiveAmount"`
+	PayableAmount       ublAmount `xml:"cbc:PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	ID                  string      `xml:"cbc:ID"`
+	InvoicedQuantity    ublQuantity `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount ublAmount   `xml:"cbc:LineExtensionAmount"`
+	Item                ublItem     `xml:"cac:Item"`
+	Price               ublAmount   `xml:"cac:Price>cbc:PriceAmount"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublItem struct {
+	Name        string             `xml:"cbc:Name"`
+	TaxCategory ublLineTaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
+
+type ublLineTaxCategory struct {
+	ID          string `xml:"cbc:ID"`
+	Percent     string `xml:"cbc:Percent,omitempty"`
+	TaxSchemeID string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+// peppolVATSchemes are the Peppol electronic address schemes (EAS) that
+// identify a party by its VAT number, by country
+var peppolVATSchemes = map[string]string{
+	"AT": "9914", "BE": "9925", "BG": "9926", "CH": "9927", "CY": "9928",
+	"CZ": "9929", "DE": "9930", "EE": "9931", "ES": "9920", "FR": "9957",
+	"GB": "9932", "GR": "9933", "HR": "9934", "IE": "9935", "IT": "9906",
+	"LI": "9936", "LT": "9937", "LU": "9938", "LV": "9939", "MC": "9940",
+	"ME": "9941", "MK": "9942", "MT": "9943", "NL": "9944", "PL": "9945",
+	"PT": "9946", "RO": "9947", "RS": "9948", "SI": "9949", "SK": "9950",
+	"SM": "9951", "TR": "9952", "VA": "9953",
+}
+
+// peppolEndpoint returns the Peppol electronic address of a party in
+// countryCode with the given VAT number. Norwegian parties are addressed by
+// their organisation number (0192), which is the VAT number without the NO
+// prefix and MVA suffix.
+func peppolEndpoint(countryCode, vatID string) (ublSchemedID, bool) {
+	vatID = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(vatID), " ", ""))
+	if vatID == "" {
+		return ublSchemedID{}, false
+	}
+	if countryCode == "NO" {
+		orgNumber := strings.TrimSuffix(strings.TrimPrefix(vatID, "NO"), "MVA")
+		if len(orgNumber) != 9 || strings.Trim(orgNumber, "0123456789") != "" {
+			return ublSchemedID{}, false
+		}
+		return ublSchemedID{SchemeID: "0192", Value: orgNumber}, true
+	}
+	scheme, ok := peppolVATSchemes[countryCode]
+	if !ok {
+		return ublSchemedID{}, false
+	}
+	return ublSchemedID{SchemeID: scheme, Value: vatID}, true
+}
+
+// ValidatePeppol checks the EN 16931 rules of Validate and the Peppol BIS
+// rules BuildUBL depends on
+func ValidatePeppol(doc *billing.Document) error {
+	var problems []string
+	if err := Validate(doc); err != nil {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+		problems = validationErr.Problems
+	}
+
+	if doc.Client.BuyerReference.String == "" {
+		problems = append(problems, "client buyer reference is required for Peppol invoices (PEPPOL-EN16931-R003)")
+	}
+	if _, ok := peppolEndpoint(doc.Seller.CountryCode.String, doc.Seller.TaxID.String); !ok {
+		problems = append(problems, "seller VAT number from a Peppol country is required in the billing profile for the seller electronic address (PEPPOL-EN16931-R020)")
+	}
+	if _, ok := peppolEndpoint(doc.Client.CountryCode.String, doc.Client.TaxID.String); !ok {
+		problems = append(problems, "client VAT number from a Peppol country is required for the buyer electronic address (PEPPOL-EN16931-R010)")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// BuildUBL serializes doc as a Peppol BIS Billing 3.0 UBL 2.1 invoice
+func BuildUBL(doc *billing.Document) ([]byte, error) {
+	if err := ValidatePeppol(doc); err != nil {
+		return nil, err
+	}
+
+	currency := doc.Currency()
+	amount := func(value decimal.Decimal) ublAmount {
+		return ublAmount{CurrencyID: currency, Value: formatAmount(value, currency)}
+	}
+
+	lines := doc.Lines()
+	breakdowns := vatBreakdowns(doc, lines)
+
+	ublLines := make([]ublInvoiceLine, len(lines))
+	var lineTotal decimal.Decimal
+	for i, line := range lines {
+		lineTotal = lineTotal.Add(roundAmount(line.Amount, currency))
+		tax := lineVAT(doc, line)
+		ublLines[i] = ublInvoiceLine{
+			ID:                  fmt.Sprintf("%d", i+1),
+			InvoicedQuantity:    ublQuantity{UnitCode: line.Unit, Value: formatQuantity(line.Quantity)},
+			LineExtensionAmount: amount(line.Amount),
+			Item: ublItem{
+				Name: line.Description,
+				TaxCategory: ublLineTaxCategory{
+					ID:          tax.CategoryCode,
+					Percent:     tax.ratePercent(),
+					TaxSchemeID: "VAT",
+				},
+			},
+			Price: amount(line.UnitPrice),
+		}
+	}
+
+	var taxTotal decimal.Decimal
+	subtotals := make([]ublTaxSubtotal, len(breakdowns))
+	for i, breakdown := range breakdowns {
+		taxTotal = taxTotal.Add(breakdown.Tax)
+		subtotals[i] = ublTaxSubtotal{
+			TaxableAmount: amount(breakdown.Basis),
+			TaxAmount:     amount(breakdown.Tax),
+			Category: ublTaxCategory{
+				ID:              breakdown.CategoryCode,
+				Percent:         breakdown.ratePercent(),
+				ExemptionReason: breakdown.ExemptionReason,
+				TaxSchemeID:     "VAT",
+			},
+		}
+	}
+	grandTotal := lineTotal.Add(taxTotal)
+
+	sellerEndpoint, _ := peppolEndpoint(doc.Seller.CountryCode.String, doc.Seller.TaxID.String)
+	buyerEndpoint, _ := peppolEndpoint(doc.Client.CountryCode.String, doc.Client.TaxID.String)
+
+	invoice := doc.Invoice
+	var notes []string
+	if invoice.Notes.Valid && invoice.Notes.String != "" {
+		notes = append(notes, invoice.Notes.String)
+	}
+
+	out := ublInvoice{
+		Xmlns:                nsUBLInvoice,
+		XmlnsCAC:             nsCAC,
+		XmlnsCBC:             nsCBC,
+		CustomizationID:      PeppolCustomizationID,
+		ProfileID:            PeppolProfileID,
+		ID:                   invoice.InvoiceNumber,
+		IssueDate:            ublDate(invoice.IssueDate),
+		DueDate:              ublDate(invoice.DueDate),
+		InvoiceTypeCode:      TypeCodeCommercialInvoice,
+		Notes:                notes,
+		DocumentCurrencyCode: currency,
+		BuyerReference:       doc.Client.BuyerReference.String,
+		Supplier: ublParty{
+			EndpointID:   sellerEndpoint,
+			Name:         doc.SellerName(),
+			Address:      ublAddress{StreetName: doc.Seller.BusinessAddress.String, CountryCode: doc.Seller.CountryCode.String},
+			TaxScheme:    ublVATRegistration(doc.Seller.TaxID.String),
+			LegalName:    doc.SellerName(),
+			ContactEmail: doc.Seller.Email,
+		},
+		Customer: ublParty{
+			EndpointID:   buyerEndpoint,
+			Name:         doc.BuyerName(),
+			Address:      ublAddress{StreetName: doc.Client.Address.String, CountryCode: doc.Client.CountryCode.String},
+			TaxScheme:    ublVATRegistration(buyerVATID(doc, breakdowns)),
+			LegalName:    doc.BuyerName(),
+			ContactEmail: doc.Client.Email,
+		},
+		TaxTotal: ublTaxTotal{
+			TaxAmount: amount(taxTotal),
+			Subtotals: subtotals,
+		},
+		MonetaryTotal: ublMonetaryTotal{
+			LineExtensionAmount: amount(lineTotal),
+			TaxExclusiveAmount:  amount(lineTotal),
+			TaxInclusiveAmount:  amount(grandTotal),
+			PayableAmount:       amount(grandTotal),
+		},
+		Lines: ublLines,
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return nil, fmt.Errorf("failed to encode UBL invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func ublVATRegistration(taxID string) *ublPartyTax {
+	if taxID == "" {
+		return nil
+	}
+	return &ublPartyTax{CompanyID: taxID, TaxSchemeID: "VAT"}
+}
+
+func ublDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,220 @@
+package einvoice
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+)
+
+var ublPrefixes = map[string]string{
+	nsUBLInvoice: "inv",
+	nsCAC:        "cac",
+	nsCBC:        "cbc",
+}
+
+func parseUBL(t *testing.T, data []byte) *ciiNode {
+	t.Helper()
+	return parseXML(t, data, ublPrefixes)
+}
+
+func TestBuildUBL(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc *billing.Document)
+		// categories are the expected VAT breakdown categories
+		categories []string
+		// buyerEndpoint is the expected buyer electronic address
+		buyerEndpoint string
+	}{
+		{
+			name:          "standard rated",
+			categories:    []string{"S", "S"},
+			buyerEndpoint: "9930:DE123456789",
+		},
+		{
+			name: "minimal",
+			modify: func(doc *billing.Document) {
+				doc.TimeEntries = nil
+				doc.LineItems = doc.LineItems[:1]
+				doc.Taxes = nil
+				doc.Invoice.Notes = sql.NullString{}
+				doc.Seller.BusinessName = sql.NullString{}
+				doc.Seller.BusinessAddress = sql.NullString{}
+				doc.Client.Company = sql.NullString{}
+				doc.Client.Address = sql.NullString{}
+			},
+			categories:    []string{"E"},
+			buyerEndpoint: "9930:DE123456789",
+		},
+		{
+			name: "reverse charge",
+			modify: func(doc *billing.Document) {
+				doc.Invoice.ReverseCharge = true
+			},
+			categories:    []string{"AE"},
+			buyerEndpoint: "9930:DE123456789",
+		},
+		{
+			name: "Norwegian buyer",
+			modify: func(doc *billing.Document) {
+				doc.Client.CountryCode = sql.NullString{String: "NO", Valid: true}
+				doc.Client.TaxID = sql.NullString{String: "NO 987654321 MVA", Valid: true}
+				doc.Invoice.ReverseCharge = true
+			},
+			categories:    []string{"AE"},
+			buyerEndpoint: "0192:987654321",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := testDocument()
+			if tt.modify != nil {
+				tt.modify(doc)
+			}
+
+			out, err := BuildUBL(doc)
+			if err != nil {
+				t.Fatalf("BuildUBL: %v", err)
+			}
+			root := parseUBL(t, out)
+			if root.name != "inv:Invoice" {
+				t.Fatalf("root element is %s", root.name)
+			}
+
+			if got := root.value(t, "cbc:CustomizationID"); got != PeppolCustomizationID {
+				t.Errorf("customization is %q, want %q", got, PeppolCustomizationID)
+			}
+			if got := root.value(t, "cbc:ID"); got != "INV-2025-0042" {
+				t.Errorf("invoice number is %q", got)
+			}
+			// PEPPOL-EN16931-R003: the buyer's reference, not the invoice number
+			if got := root.value(t, "cbc:BuyerReference"); got != "PO-7731" {
+				t.Errorf("buyer reference is %q, want PO-7731", got)
+			}
+
+			endpoint := func(party string) string {
+				nodes := root.find(party + "/cac:Party/cbc:EndpointID")
+				if len(nodes) != 1 {
+					t.Fatalf("expected one %s endpoint, found %d", party, len(nodes))
+				}
+				return nodes[0].attrs["schemeID"] + ":" + strings.TrimSpace(nodes[0].text)
+			}
+			if got := endpoint("cac:AccountingSupplierParty"); got != "9957:FR12345678901" {
+				t.Errorf("seller endpoint is %q, want 9957:FR12345678901", got)
+			}
+			if got := endpoint("cac:AccountingCustomerParty"); got != tt.buyerEndpoint {
+				t.Errorf("buyer endpoint is %q, want %s", got, tt.buyerEndpoint)
+			}
+
+			var categories []string
+			for _, category := range root.find("cac:TaxTotal/cac:TaxSubtotal/cac:TaxCategory/cbc:ID") {
+				categories = append(categories, category.text)
+			}
+			if strings.Join(categories, ",") != strings.Join(tt.categories, ",") {
+				t.Errorf("VAT breakdown categories are %v, want %v", categories, tt.categories)
+			}
+
+			// BR-CO-15: the amount with VAT is the amount without VAT plus VAT
+			basis := money.Parse(root.value(t, "cac:LegalMonetaryTotal/cbc:TaxExclusiveAmount"))
+			tax := money.Parse(root.value(t, "cac:TaxTotal/cbc:TaxAmount"))
+			total := money.Parse(root.value(t, "cac:LegalMonetaryTotal/cbc:TaxInclusiveAmount"))
+			if !basis.Add(tax).Equal(total) {
+				t.Errorf("BR-CO-15: total %s is not %s + %s", total, basis, tax)
+			}
+		})
+	}
+}
+
+// TestBuildUBLTotals checks the totals against the CII export of the same
+// document
+func TestBuildUBLTotals(t *testing.T) {
+	out, err := BuildUBL(testDocument())
+	if err != nil {
+		t.Fatalf("BuildUBL: %v", err)
+	}
+	root := parseUBL(t, out)
+
+	if got, want := money.Parse(root.value(t, "cac:LegalMonetaryTotal/cbc:LineExtensionAmount")), money.Parse("426.74"); !got.Equal(want) {
+		t.Errorf("line total is %s, want %s", got, want)
+	}
+	if got, want := money.Parse(root.value(t, "cac:TaxTotal/cbc:TaxAmount")), money.Parse("88.54"); !got.Equal(want) {
+		t.Errorf("tax total is %s, want %s", got, want)
+	}
+	if got, want := money.Parse(root.value(t, "cac:LegalMonetaryTotal/cbc:PayableAmount")), money.Parse("515.28"); !got.Equal(want) {
+		t.Errorf("payable amount is %s, want %s", got, want)
+	}
+}
+
+func TestValidatePeppol(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc *billing.Document)
+		rules  []string
+	}{
+		{
+			name: "compound tax",
+			modify: func(doc *billing.Document) {
+				doc.Taxes = append(doc.Taxes, db.InvoiceTax{Name: "QST", Rate: "9.975", Compound: true})
+			},
+			rules: []string{`compound tax "QST"`},
+		},
+		{
+			name: "withholding tax",
+			modify: func(doc *billing.Document) {
+				doc.Taxes = append(doc.Taxes, db.InvoiceTax{Name: "IRPF", Rate: "15", Withholding: true})
+			},
+			rules: []string{`withholding tax "IRPF"`},
+		},
+		{
+			name: "missing buyer reference",
+			modify: func(doc *billing.Document) {
+				doc.Client.BuyerReference = sql.NullString{}
+			},
+			rules: []string{"PEPPOL-EN16931-R003"},
+		},
+		{
+			name: "seller without VAT number",
+			modify: func(doc *billing.Document) {
+				doc.Seller.TaxID = sql.NullString{}
+				doc.Taxes = nil
+				doc.LineItems = doc.LineItems[:1]
+			},
+			rules: []string{"PEPPOL-EN16931-R020"},
+		},
+		{
+			name: "buyer outside Peppol",
+			modify: func(doc *billing.Document) {
+				doc.Client.CountryCode = sql.NullString{String: "US", Valid: true}
+				doc.Client.TaxID = sql.NullString{String: "12-3456789", Valid: true}
+			},
+			rules: []string{"PEPPOL-EN16931-R010"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := testDocument()
+			tt.modify(doc)
+
+			_, err := BuildUBL(doc)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("BuildUBL returned %v, want a ValidationError", err)
+			}
+			if len(validationErr.Problems) != len(tt.rules) {
+				t.Errorf("problems are %q, want %d", validationErr.Problems, len(tt.rules))
+			}
+			for _, rule := range tt.rules {
+				if !strings.Contains(err.Error(), rule) {
+					t.Errorf("error %q does not mention %s", err, rule)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"html"
+)
+
+// BudgetAlertData describes a project budget that crossed an alert
+// threshold. Used and Budget are formatted for display, e.g. "36.50 hours"
+// or "$4,512.00".
+type BudgetAlertData struct {
+	ProjectID   int32
+	ProjectName string
+	ClientName  string
+	Threshold   int32
+	Used        string
+	Budget      string
+}
+
+// SendBudgetAlert emails the user that one of their projects used Threshold
+// percent of its budget
+func (s *Service) SendBudgetAlert(ctx context.Context, recipientEmail, recipientName string, data BudgetAlertData) error {
+	projectURL := fmt.Sprintf("%s/projects/%d", s.appURL, data.ProjectID)
+
+	subject := fmt.Sprintf("%s has used %d%% of its budget - FacturMe", data.ProjectName, data.Threshold)
+	htmlBody := s.getBudgetAlertEmailHTML(recipientName, data, projectURL)
+	textBody := s.getBudgetAlertEmailText(recipientName, data, projectURL)
+
+	return s.Send(ctx, Message{
+		To:       recipientEmail,
+		Subject:  subject,
+		TextBody: textBody,
+		HTMLBody: htmlBody,
+	})
+}
+
+// getBudgetAlertEmailHTML returns the HTML template for budget alert email
+func (s *Service) getBudgetAlertEmailHTML(name string, data BudgetAlertData, projectURL string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Project Budget Alert</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; background-color: #0f172a;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse; background-color: #0f172a;">
+        <tr>
+            <td align="center" style="padding: 40px 20px;">
+                <table role="presentation" style="width: 100%%; max-width: 600px; border-collapse: collapse; background-color: #1e293b; border-radius: 16px; overflow: hidden; box-shadow: 0 20px 25px -5px rgba(0, 0, 0, 0.3);">
+                    <!-- Header -->
+                    <tr>
+                        <td align="center" style="padding: 40px 40px 30px 40px; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%);">
+                            <h1 style="margin: 0; color: #ffffff; font-size: 28px; font-weight: 700;">FacturMe</h1>
+                        </td>
+                    </tr>
+
+                    <!-- Content -->
+                    <tr>
+                        <td style="padding: 40px;">
+                            <h2 style="margin: 0 0 20px 0; color: #f1f5f9; font-size: 24px; font-weight: 600;">Hi %s!</h2>
+                            <p style="margin: 0 0 20px 0; color: #cbd5e1; font-size: 16px; line-height: 1.6;">
+                                Your project <strong>%s</strong> for %s has used <strong>%d%%</strong> of its budget.
+                            </p>
+                            <p style="margin: 0; padding: 12px; background-color: #334155; border-radius: 6px; color: #f1f5f9; font-size: 15px;">
+                                %s used of %s
+                            </p>
+
+                            <!-- Button -->
+                            <table role="presentation" style="margin: 30px 0;">
+                                <tr>
+                                    <td align="center">
+                                        <a href="%s" style="display: inline-block; padding: 16px 32px; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: #ffffff; text-decoration: none; border-radius: 8px; font-weight: 600; font-size: 16px;">
+                                            View Project
+                                        </a>
+                                    </td>
+                                </tr>
+                            </table>
+                        </td>
+                    </tr>
+
+                    <!-- Footer -->
+                    <tr>
+                        <td style="padding: 30px 40px; background-color: #0f172a; border-top: 1px solid #334155;">
+                            <p style="margin: 0 0 10px 0; color: #64748b; font-size: 12px; line-height: 1.5;">
+                                You receive budget alerts for projects with a budget. Change the project's alert thresholds to stop them.
+                            </p>
+                            <p style="margin: 0; color: #64748b; font-size: 12px;">
+                                © 2025 FacturMe. All rights reserved.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, html.EscapeString(name), html.EscapeString(data.ProjectName), html.EscapeString(data.ClientName), data.Threshold,
+		html.EscapeString(data.Used), html.EscapeString(data.Budget), projectURL)
+}
+
+// getBudgetAlertEmailText returns the plain text template for budget alert email
+func (s *Service) getBudgetAlertEmailText(name string, data BudgetAlertData, projectURL string) string {
+	return fmt.Sprintf(`
+Hi %s!
+
+Your project %s for %s has used %d%% of its budget: %s used of %s.
+
+View the project:
+
+%s
+
+You receive budget alerts for projects with a budget. Change the project's alert thresholds to stop them.
+
+© 2025 FacturMe. All rights reserved.
+`, name, data.ProjectName, data.ClientName, data.Threshold, data.Used, data.Budget, projectURL)
+}
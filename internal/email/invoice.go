@@ -0,0 +1,36 @@
+package email
+
+import "strings"
+
+// InvoiceData describes an invoice emailed to a client. Message optionally
+// replaces the default wording and is sent as written.
+type InvoiceData struct {
+	ClientName    string
+	SellerName    string
+	InvoiceNumber string
+	AmountDue     string
+	DueDate       string
+	Message       string
+}
+
+// InvoiceEmail returns the email delivering an invoice, addressed to
+// recipientEmail. The caller attaches the invoice PDF.
+func InvoiceEmail(recipientEmail string, data InvoiceData) Message {
+	message := strings.TrimSpace(data.Message)
+	if message == "" {
+		message = strings.NewReplacer(
+			"{client_name}", data.ClientName,
+			"{seller_name}", data.SellerName,
+			"{invoice_number}", data.InvoiceNumber,
+			"{amount_due}", data.AmountDue,
+			"{due_date}", data.DueDate,
+		).Replace("Hi {client_name},\n\nPlease find attached invoice {invoice_number} for {amount_due}, due on {due_date}.\n\nIf you have any questions, simply reply to this email.\n\nThank you for your business,\n{seller_name}")
+	}
+
+	return Message{
+		To:       recipientEmail,
+		Subject:  "Invoice " + data.InvoiceNumber + " from " + data.SellerName,
+		TextBody: getSellerEmailText(data.SellerName, message),
+		HTMLBody: getSellerEmailHTML("Invoice "+data.InvoiceNumber, data.SellerName, message),
+	}
+}
@@ -0,0 +1,267 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain text body, an optional HTML alternative
+// and optional attachments
+type Message struct {
+	To          string
+	Cc          []string
+	ReplyTo     string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+	// MessageID is the Message-ID header, including angle brackets. Send
+	// generates one when it is empty.
+	MessageID string
+}
+
+// Attachment is a file attached to a Message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Send delivers msg from the configured sender over SMTP
+func (s *Service) Send(ctx context.Context, msg Message) error {
+	body, err := s.buildMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Authenticate
+	if s.smtpUsername != "" {
+		auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
+		if err = client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	// Set sender
+	if err = client.Mail(s.senderEmail); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+
+	// Set recipients
+	if err = client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("failed to set recipient: %w", err)
+	}
+	for _, cc := range msg.Cc {
+		if err = client.Rcpt(cc); err != nil {
+			return fmt.Errorf("failed to set CC recipient: %w", err)
+		}
+	}
+
+	// Send email body
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to get data writer: %w", err)
+	}
+
+	_, err = w.Write(body)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close data writer: %w", err)
+	}
+
+	err = client.Quit()
+	if err != nil {
+		return fmt.Errorf("failed to quit: %w", err)
+	}
+
+	return nil
+}
+
+// dial connects to the SMTP server using the configured security mode
+func (s *Service) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.smtpHost, s.smtpPort)
+	tlsConfig := &tls.Config{
+		ServerName: s.smtpHost,
+	}
+
+	var conn net.Conn
+	var err error
+	if s.smtpSecurity == SecurityTLS {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, s.smtpHost)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+
+	if s.smtpSecurity == SecuritySTARTTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	return client, nil
+}
+
+// buildMessage encodes msg as a MIME message. The text and HTML bodies are
+// sent as multipart/alternative, wrapped in multipart/mixed when there are
+// attachments.
+func (s *Service) buildMessage(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("From: %s <%s>\r\n", mime.QEncoding.Encode("utf-8", s.senderName), s.senderEmail))
+	buf.WriteString(fmt.Sprintf("To: %s\r\n", msg.To))
+	if len(msg.Cc) > 0 {
+		buf.WriteString(fmt.Sprintf("Cc: %s\r\n", strings.Join(msg.Cc, ", ")))
+	}
+	if msg.ReplyTo != "" {
+		buf.WriteString(fmt.Sprintf("Reply-To: %s\r\n", msg.ReplyTo))
+	}
+	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject)))
+	buf.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	messageID := msg.MessageID
+	if messageID == "" {
+		messageID = s.NewMessageID()
+	}
+	buf.WriteString(fmt.Sprintf("Message-ID: %s\r\n", messageID))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	var alternative bytes.Buffer
+	alternativeType, err := writeAlternative(&alternative, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Attachments) == 0 {
+		buf.WriteString(fmt.Sprintf("Content-Type: %s\r\n\r\n", alternativeType))
+		buf.Write(alternative.Bytes())
+		return buf.Bytes(), nil
+	}
+
+	var mixedBody bytes.Buffer
+	mixed := multipart.NewWriter(&mixedBody)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", alternativeType)
+	part, err := mixed.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(alternative.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range msg.Attachments {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Filename}))
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		header.Set("Content-Transfer-Encoding", "base64")
+		part, err := mixed.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary()))
+	buf.Write(mixedBody.Bytes())
+	return buf.Bytes(), nil
+}
+
+// NewMessageID returns a unique Message-ID in the sender's domain, for
+// callers that need to record the ID of a message before sending it
+func (s *Service) NewMessageID() string {
+	domain := "localhost"
+	if at := strings.LastIndex(s.senderEmail, "@"); at >= 0 && at < len(s.senderEmail)-1 {
+		domain = s.senderEmail[at+1:]
+	}
+
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
+
+// writeAlternative writes the text and HTML bodies of msg to w as the body of
+// a multipart/alternative entity and returns its Content-Type
+func writeAlternative(w io.Writer, msg Message) (string, error) {
+	alternative := multipart.NewWriter(w)
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", msg.TextBody},
+		{"text/html", msg.HTMLBody},
+	}
+	for _, p := range parts {
+		if p.body == "" {
+			continue
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", p.contentType+"; charset=\"UTF-8\"")
+		header.Set("Content-Transfer-Encoding", "base64")
+		part, err := alternative.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if err := writeBase64(part, []byte(p.body)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := alternative.Close(); err != nil {
+		return "", err
+	}
+	return "multipart/alternative; boundary=" + alternative.Boundary(), nil
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := fmt.Fprintf(w, "%s\r\n", encoded)
+	return err
+}
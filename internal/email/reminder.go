@@ -0,0 +1,138 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ReminderData describes the invoice a payment reminder is about. Subject and
+// Message optionally override the default wording and may use the
+// placeholders {client_name}, {seller_name}, {invoice_number}, {amount_due},
+// {due_date} and {days}.
+type ReminderData struct {
+	ClientName    string
+	SellerName    string
+	InvoiceNumber string
+	AmountDue     string
+	DueDate       string
+	// OffsetDays is the number of days after the due date, negative before it
+	OffsetDays int
+	Subject    string
+	Message    string
+}
+
+// PaymentReminder returns the payment reminder email for data, addressed to
+// recipientEmail
+func PaymentReminder(recipientEmail string, data ReminderData) Message {
+	days := data.OffsetDays
+	if days < 0 {
+		days = -days
+	}
+	replacer := strings.NewReplacer(
+		"{client_name}", data.ClientName,
+		"{seller_name}", data.SellerName,
+		"{invoice_number}", data.InvoiceNumber,
+		"{amount_due}", data.AmountDue,
+		"{due_date}", data.DueDate,
+		"{days}", fmt.Sprintf("%d", days),
+	)
+
+	subject, message := defaultReminderText(data.OffsetDays)
+	if data.Subject != "" {
+		subject = data.Subject
+	}
+	if data.Message != "" {
+		message = data.Message
+	}
+	subject = replacer.Replace(subject)
+	message = replacer.Replace(message)
+
+	return Message{
+		To:       recipientEmail,
+		Subject:  subject,
+		TextBody: getSellerEmailText(data.SellerName, message),
+		HTMLBody: getSellerEmailHTML("Payment Reminder", data.SellerName, message),
+	}
+}
+
+// defaultReminderText returns the default subject and message of a reminder
+// sent offsetDays after the due date
+func defaultReminderText(offsetDays int) (string, string) {
+	switch {
+	case offsetDays < 0:
+		return "Upcoming payment: invoice {invoice_number} is due on {due_date}",
+			"Hi {client_name},\n\nThis is a friendly reminder that invoice {invoice_number} for {amount_due} is due on {due_date}, in {days} days.\n\nThe invoice is attached for your convenience. If you have already paid, please disregard this message.\n\nThank you,\n{seller_name}"
+	case offsetDays == 0:
+		return "Invoice {invoice_number} is due today",
+			"Hi {client_name},\n\nInvoice {invoice_number} for {amount_due} is due today, {due_date}.\n\nThe invoice is attached for your convenience. If you have already paid, please disregard this message.\n\nThank you,\n{seller_name}"
+	default:
+		return "Overdue: invoice {invoice_number} was due on {due_date}",
+			"Hi {client_name},\n\nOur records show that invoice {invoice_number} was due on {due_date}, {days} days ago, and {amount_due} is still outstanding.\n\nThe invoice is attached. Please arrange payment at your earliest convenience, or reply to this email if there is anything we should know.\n\nThank you,\n{seller_name}"
+	}
+}
+
+// getSellerEmailHTML returns the HTML template for emails sent on behalf of a
+// seller to their clients, such as invoices and payment reminders
+func getSellerEmailHTML(title, sellerName, message string) string {
+	paragraphs := strings.Split(strings.TrimSpace(message), "\n\n")
+	var body strings.Builder
+	for _, paragraph := range paragraphs {
+		escaped := strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>")
+		body.WriteString(fmt.Sprintf(`                            <p style="margin: 0 0 16px 0; color: #334155; font-size: 15px; line-height: 1.6;">%s</p>
+`, escaped))
+	}
+
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>%s</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; background-color: #f1f5f9;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse; background-color: #f1f5f9;">
+        <tr>
+            <td align="center" style="padding: 40px 20px;">
+                <table role="presentation" style="width: 100%%; max-width: 600px; border-collapse: collapse; background-color: #ffffff; border-radius: 12px; overflow: hidden;">
+                    <!-- Header -->
+                    <tr>
+                        <td style="padding: 24px 40px; background-color: #1e3a8a;">
+                            <h1 style="margin: 0; color: #ffffff; font-size: 20px; font-weight: 600;">%s</h1>
+                        </td>
+                    </tr>
+
+                    <!-- Content -->
+                    <tr>
+                        <td style="padding: 32px 40px 16px 40px;">
+%s                        </td>
+                    </tr>
+
+                    <!-- Footer -->
+                    <tr>
+                        <td style="padding: 20px 40px; background-color: #f8fafc; border-top: 1px solid #e2e8f0;">
+                            <p style="margin: 0; color: #94a3b8; font-size: 12px;">
+                                Sent with FacturMe on behalf of %s.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(sellerName), body.String(), html.EscapeString(sellerName))
+}
+
+// getSellerEmailText returns the plain text template for emails sent on behalf
+// of a seller to their clients
+func getSellerEmailText(sellerName, message string) string {
+	return fmt.Sprintf(`
+%s
+
+--
+Sent with FacturMe on behalf of %s.
+`, strings.TrimSpace(message), sellerName)
+}
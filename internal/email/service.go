@@ -2,11 +2,20 @@
 package email
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
-	"net/smtp"
+)
+
+// SMTP connection security modes
+const (
+	// SecurityTLS connects over implicit TLS, usually on port 465
+	SecurityTLS = "tls"
+	// SecuritySTARTTLS connects in plain text and upgrades with STARTTLS,
+	// usually on port 587
+	SecuritySTARTTLS = "starttls"
+	// SecurityNone never encrypts the connection. Only meant for local SMTP
+	// stand-ins such as MailHog or smtp4dev.
+	SecurityNone = "none"
 )
 
 // Service handles email operations using SMTP
@@ -15,18 +24,29 @@ type Service struct {
 	smtpPort     string
 	smtpUsername string
 	smtpPassword string
+	smtpSecurity string
 	senderEmail  string
 	senderName   string
 	appURL       string
 }
 
-// NewService creates a new email service instance
-func NewService(smtpHost, smtpPort, smtpUsername, smtpPassword, senderEmail, senderName, appURL string) (*Service, error) {
+// NewService creates a new email service instance. smtpSecurity is one of
+// SecurityTLS, SecuritySTARTTLS or SecurityNone and defaults to SecurityTLS.
+func NewService(smtpHost, smtpPort, smtpUsername, smtpPassword, smtpSecurity, senderEmail, senderName, appURL string) (*Service, error) {
+	switch smtpSecurity {
+	case "":
+		smtpSecurity = SecurityTLS
+	case SecurityTLS, SecuritySTARTTLS, SecurityNone:
+	default:
+		return nil, fmt.Errorf("unknown SMTP security mode %q", smtpSecurity)
+	}
+
 	return &Service{
 		smtpHost:     smtpHost,
 		smtpPort:     smtpPort,
 		smtpUsername: smtpUsername,
 		smtpPassword: smtpPassword,
+		smtpSecurity: smtpSecurity,
 		senderEmail:  senderEmail,
 		senderName:   senderName,
 		appURL:       appURL,
@@ -41,95 +61,12 @@ func (s *Service) SendVerificationEmail(ctx context.Context, recipientEmail, rec
 	htmlBody := s.getVerificationEmailHTML(recipientName, verificationURL)
 	textBody := s.getVerificationEmailText(recipientName, verificationURL)
 
-	// Build email message
-	var msg bytes.Buffer
-	msg.WriteString(fmt.Sprintf("From: %s <%s>\r\n", s.senderName, s.senderEmail))
-	msg.WriteString(fmt.Sprintf("To: %s\r\n", recipientEmail))
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-	msg.WriteString("MIME-Version: 1.0\r\n")
-	msg.WriteString("Content-Type: multipart/alternative; boundary=\"boundary-string\"\r\n")
-	msg.WriteString("\r\n")
-
-	// Plain text part
-	msg.WriteString("--boundary-string\r\n")
-	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
-	msg.WriteString("Content-Transfer-Encoding: 7bit\r\n")
-	msg.WriteString("\r\n")
-	msg.WriteString(textBody)
-	msg.WriteString("\r\n")
-
-	// HTML part
-	msg.WriteString("--boundary-string\r\n")
-	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
-	msg.WriteString("Content-Transfer-Encoding: 7bit\r\n")
-	msg.WriteString("\r\n")
-	msg.WriteString(htmlBody)
-	msg.WriteString("\r\n")
-
-	msg.WriteString("--boundary-string--")
-
-	// Set up authentication
-	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
-
-	// Connect to the SMTP server with TLS
-	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
-
-	// Create TLS config
-	tlsConfig := &tls.Config{
-		ServerName: s.smtpHost,
-	}
-
-	// Dial with TLS
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
-	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %w", err)
-	}
-	defer conn.Close()
-
-	// Create SMTP client
-	client, err := smtp.NewClient(conn, s.smtpHost)
-	if err != nil {
-		return fmt.Errorf("failed to create SMTP client: %w", err)
-	}
-	defer client.Close()
-
-	// Authenticate
-	if err = client.Auth(auth); err != nil {
-		return fmt.Errorf("failed to authenticate: %w", err)
-	}
-
-	// Set sender
-	if err = client.Mail(s.senderEmail); err != nil {
-		return fmt.Errorf("failed to set sender: %w", err)
-	}
-
-	// Set recipient
-	if err = client.Rcpt(recipientEmail); err != nil {
-		return fmt.Errorf("failed to set recipient: %w", err)
-	}
-
-	// Send email body
-	w, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("failed to get data writer: %w", err)
-	}
-
-	_, err = w.Write(msg.Bytes())
-	if err != nil {
-		return fmt.Errorf("failed to write message: %w", err)
-	}
-
-	err = w.Close()
-	if err != nil {
-		return fmt.Errorf("failed to close data writer: %w", err)
-	}
-
-	err = client.Quit()
-	if err != nil {
-		return fmt.Errorf("failed to quit: %w", err)
-	}
-
-	return nil
+	return s.Send(ctx, Message{
+		To:       recipientEmail,
+		Subject:  subject,
+		TextBody: textBody,
+		HTMLBody: htmlBody,
+	})
 }
 
 // getVerificationEmailHTML returns the HTML template for verification email
@@ -233,95 +170,12 @@ func (s *Service) SendPasswordResetEmail(ctx context.Context, recipientEmail, re
 	htmlBody := s.getPasswordResetEmailHTML(recipientName, resetURL)
 	textBody := s.getPasswordResetEmailText(recipientName, resetURL)
 
-	// Build email message
-	var msg bytes.Buffer
-	msg.WriteString(fmt.Sprintf("From: %s <%s>\r\n", s.senderName, s.senderEmail))
-	msg.WriteString(fmt.Sprintf("To: %s\r\n", recipientEmail))
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-	msg.WriteString("MIME-Version: 1.0\r\n")
-	msg.WriteString("Content-Type: multipart/alternative; boundary=\"boundary-string\"\r\n")
-	msg.WriteString("\r\n")
-
-	// Plain text part
-	msg.WriteString("--boundary-string\r\n")
-	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
-	msg.WriteString("Content-Transfer-Encoding: 7bit\r\n")
-	msg.WriteString("\r\n")
-	msg.WriteString(textBody)
-	msg.WriteString("\r\n")
-
-	// HTML part
-	msg.WriteString("--boundary-string\r\n")
-	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
-	msg.WriteString("Content-Transfer-Encoding: 7bit\r\n")
-	msg.WriteString("\r\n")
-	msg.WriteString(htmlBody)
-	msg.WriteString("\r\n")
-
-	msg.WriteString("--boundary-string--")
-
-	// Set up authentication
-	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
-
-	// Connect to the SMTP server with TLS
-	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
-
-	// Create TLS config
-	tlsConfig := &tls.Config{
-		ServerName: s.smtpHost,
-	}
-
-	// Dial with TLS
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
-	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %w", err)
-	}
-	defer conn.Close()
-
-	// Create SMTP client
-	client, err := smtp.NewClient(conn, s.smtpHost)
-	if err != nil {
-		return fmt.Errorf("failed to create SMTP client: %w", err)
-	}
-	defer client.Close()
-
-	// Authenticate
-	if err = client.Auth(auth); err != nil {
-		return fmt.Errorf("failed to authenticate: %w", err)
-	}
-
-	// Set sender
-	if err = client.Mail(s.senderEmail); err != nil {
-		return fmt.Errorf("failed to set sender: %w", err)
-	}
-
-	// Set recipient
-	if err = client.Rcpt(recipientEmail); err != nil {
-		return fmt.Errorf("failed to set recipient: %w", err)
-	}
-
-	// Send email body
-	w, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("failed to get data writer: %w", err)
-	}
-
-	_, err = w.Write(msg.Bytes())
-	if err != nil {
-		return fmt.Errorf("failed to write message: %w", err)
-	}
-
-	err = w.Close()
-	if err != nil {
-		return fmt.Errorf("failed to close data writer: %w", err)
-	}
-
-	err = client.Quit()
-	if err != nil {
-		return fmt.Errorf("failed to quit: %w", err)
-	}
-
-	return nil
+	return s.Send(ctx, Message{
+		To:       recipientEmail,
+		Subject:  subject,
+		TextBody: textBody,
+		HTMLBody: htmlBody,
+	})
 }
 
 // getPasswordResetEmailHTML returns the HTML template for password reset email
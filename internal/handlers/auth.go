@@ -7,10 +7,12 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"net/http"
+	"strings"
 	"time"
 
 	"worklio-api/internal/db"
 	"worklio-api/internal/email"
+	"worklio-api/internal/iso4217"
 	"worklio-api/internal/models"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -209,6 +211,11 @@ func (h *AuthHandler) CompleteOnboarding(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
 	}
 
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if !iso4217.IsValid(req.Currency) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unsupported currency. Must be an ISO 4217 currency code"})
+	}
+
 	// Update user with onboarding completion
 	user, err := h.queries.CompleteOnboarding(c.Request().Context(), db.CompleteOnboardingParams{
 		ID:         userID,
@@ -678,6 +685,10 @@ func (h *AuthHandler) UpdateCurrency(c echo.Context) error {
 	if req.Currency == "" {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Currency is required"})
 	}
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if !iso4217.IsValid(req.Currency) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unsupported currency. Must be an ISO 4217 currency code"})
+	}
 
 	// Update currency
 	user, err := h.queries.UpdateUserCurrency(c.Request().Context(), db.UpdateUserCurrencyParams{
@@ -714,3 +725,149 @@ func (h *AuthHandler) getUserByIDToUserInfo(user db.GetUserByIDRow) models.UserI
 		Currency:            user.Currency.String,
 	}
 }
+
+// GetBillingProfile godoc
+// @Summary Get billing profile
+// @Description Get the seller details printed on invoices and e-invoices for the current user
+// @Tags users
+// @Produce json
+// @Success 200 {object} models.BillingProfileResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /api/users/billing-profile [get]
+func (h *AuthHandler) GetBillingProfile(c echo.Context) error {
+	// Get user ID from context
+	userID, ok := c.Get("user_id").(int32)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+	}
+
+	profile, err := h.queries.GetUserBillingProfile(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch billing profile"})
+	}
+
+	return c.JSON(http.StatusOK, models.BillingProfileResponse{
+		BusinessName:    profile.BusinessName.String,
+		BusinessAddress: profile.BusinessAddress.String,
+		CountryCode:     profile.CountryCode.String,
+		TaxID:           profile.TaxID.String,
+	})
+}
+
+// UpdateBillingProfile godoc
+// @Summary Update billing profile
+// @Description Update the seller details (business name, address, country, tax ID) used on invoices
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateBillingProfileRequest true "Billing Profile"
+// @Success 200 {object} models.BillingProfileResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /api/users/billing-profile [put]
+func (h *AuthHandler) UpdateBillingProfile(c echo.Context) error {
+	// Get user ID from context
+	userID, ok := c.Get("user_id").(int32)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+	}
+
+	var req models.UpdateBillingProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request"})
+	}
+
+	// Country codes are ISO 3166-1 alpha-2
+	countryCode := strings.ToUpper(strings.TrimSpace(req.CountryCode))
+	if countryCode != "" && len(countryCode) != 2 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "country_code must be a two-letter ISO 3166-1 code"})
+	}
+
+	profile, err := h.queries.UpdateUserBillingProfile(c.Request().Context(), db.UpdateUserBillingProfileParams{
+		ID:              userID,
+		BusinessName:    sql.NullString{String: req.BusinessName, Valid: req.BusinessName != ""},
+		BusinessAddress: sql.NullString{String: req.BusinessAddress, Valid: req.BusinessAddress != ""},
+		CountryCode:     sql.NullString{String: countryCode, Valid: countryCode != ""},
+		TaxID:           sql.NullString{String: req.TaxID, Valid: req.TaxID != ""},
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update billing profile"})
+	}
+
+	return c.JSON(http.StatusOK, models.BillingProfileResponse{
+		BusinessName:    profile.BusinessName.String,
+		BusinessAddress: profile.BusinessAddress.String,
+		CountryCode:     profile.CountryCode.String,
+		TaxID:           profile.TaxID.String,
+	})
+}
+
+// GetTimezone godoc
+// @Summary Get timezone
+// @Description Get the IANA timezone the authenticated user works in, in which the time their timers record is dated
+// @Tags users
+// @Produce json
+// @Success 200 {object} models.TimezoneResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /api/users/timezone [get]
+func (h *AuthHandler) GetTimezone(c echo.Context) error {
+	// Get user ID from context
+	userID, ok := c.Get("user_id").(int32)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+	}
+
+	timezone, err := h.queries.GetUserTimezone(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch timezone"})
+	}
+
+	return c.JSON(http.StatusOK, models.TimezoneResponse{Timezone: timezone})
+}
+
+// UpdateTimezone godoc
+// @Summary Update timezone
+// @Description Set the IANA timezone the authenticated user works in, e.g. Europe/Paris
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.TimezoneRequest true "Timezone"
+// @Success 200 {object} models.TimezoneResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /api/users/timezone [put]
+func (h *AuthHandler) UpdateTimezone(c echo.Context) error {
+	// Get user ID from context
+	userID, ok := c.Get("user_id").(int32)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+	}
+
+	var req models.TimezoneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request"})
+	}
+
+	// "Local" would be the server's timezone, not the user's
+	if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "" || req.Timezone == "Local" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "timezone must be an IANA timezone, e.g. Europe/Paris"})
+	}
+
+	timezone, err := h.queries.UpdateUserTimezone(c.Request().Context(), db.UpdateUserTimezoneParams{
+		ID:       userID,
+		Timezone: req.Timezone,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update timezone"})
+	}
+
+	return c.JSON(http.StatusOK, models.TimezoneResponse{Timezone: timezone})
+}
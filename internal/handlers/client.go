@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"worklio-api/internal/billing"
 	"worklio-api/internal/db"
+	"worklio-api/internal/iso4217"
 	"worklio-api/internal/models"
+	"worklio-api/internal/money"
 
 	"github.com/labstack/echo/v4"
 )
@@ -44,10 +47,23 @@ func (h *ClientHandler) CreateClient(c echo.Context) error {
 	}
 
 	// Default to USD if currency is not provided
-	currency := req.Currency
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
 	if currency == "" {
 		currency = "USD"
 	}
+	if !iso4217.IsValid(currency) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unsupported currency. Must be an ISO 4217 currency code"})
+	}
+
+	invoicePrefix := strings.TrimSpace(req.InvoicePrefix)
+	if len(invoicePrefix) > 20 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invoice_prefix must be at most 20 characters"})
+	}
+
+	buyerReference := strings.TrimSpace(req.BuyerReference)
+	if len(buyerReference) > 100 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "buyer_reference must be at most 100 characters"})
+	}
 
 	client, err := h.queries.CreateClient(c.Request().Context(), db.CreateClientParams{
 		UserID:  userID,
@@ -57,10 +73,16 @@ func (h *ClientHandler) CreateClient(c echo.Context) error {
 		Company: sql.NullString{String: req.Company, Valid: req.Company != ""},
 		Address: sql.NullString{String: req.Address, Valid: req.Address != ""},
 		HourlyRate: sql.NullString{
-			String: fmt.Sprintf("%.2f", req.HourlyRate),
+			String: billing.FormatPrice(req.HourlyRate),
 			Valid:  true,
 		},
-		Currency: currency,
+		Currency:        currency,
+		CountryCode:     sql.NullString{String: strings.ToUpper(req.CountryCode), Valid: req.CountryCode != ""},
+		TaxID:           sql.NullString{String: req.TaxID, Valid: req.TaxID != ""},
+		RemindersOptOut: req.RemindersOptOut,
+		InvoicePrefix:   sql.NullString{String: invoicePrefix, Valid: invoicePrefix != ""},
+		Billable:        req.Billable == nil || *req.Billable,
+		BuyerReference:  sql.NullString{String: buyerReference, Valid: buyerReference != ""},
 	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create client"})
@@ -159,10 +181,23 @@ func (h *ClientHandler) UpdateClient(c echo.Context) error {
 	}
 
 	// Default to USD if currency is not provided
-	currency := req.Currency
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
 	if currency == "" {
 		currency = "USD"
 	}
+	if !iso4217.IsValid(currency) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unsupported currency. Must be an ISO 4217 currency code"})
+	}
+
+	invoicePrefix := strings.TrimSpace(req.InvoicePrefix)
+	if len(invoicePrefix) > 20 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invoice_prefix must be at most 20 characters"})
+	}
+
+	buyerReference := strings.TrimSpace(req.BuyerReference)
+	if len(buyerReference) > 100 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "buyer_reference must be at most 100 characters"})
+	}
 
 	client, err := h.queries.UpdateClient(c.Request().Context(), db.UpdateClientParams{
 		ID:      int32(id),
@@ -173,10 +208,16 @@ func (h *ClientHandler) UpdateClient(c echo.Context) error {
 		Company: sql.NullString{String: req.Company, Valid: req.Company != ""},
 		Address: sql.NullString{String: req.Address, Valid: req.Address != ""},
 		HourlyRate: sql.NullString{
-			String: fmt.Sprintf("%.2f", req.HourlyRate),
+			String: billing.FormatPrice(req.HourlyRate),
 			Valid:  true,
 		},
-		Currency: currency,
+		Currency:        currency,
+		CountryCode:     sql.NullString{String: strings.ToUpper(req.CountryCode), Valid: req.CountryCode != ""},
+		TaxID:           sql.NullString{String: req.TaxID, Valid: req.TaxID != ""},
+		RemindersOptOut: req.RemindersOptOut,
+		InvoicePrefix:   sql.NullString{String: invoicePrefix, Valid: invoicePrefix != ""},
+		Billable:        req.Billable == nil || *req.Billable,
+		BuyerReference:  sql.NullString{String: buyerReference, Valid: buyerReference != ""},
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -220,69 +261,89 @@ func (h *ClientHandler) DeleteClient(c echo.Context) error {
 }
 
 func createClientRowToResponse(client db.CreateClientRow) models.ClientResponse {
-	hourlyRate, _ := strconv.ParseFloat(client.HourlyRate.String, 64)
 	return models.ClientResponse{
-		ID:         client.ID,
-		UserID:     client.UserID,
-		Name:       client.Name,
-		Email:      client.Email,
-		Phone:      client.Phone.String,
-		Company:    client.Company.String,
-		Address:    client.Address.String,
-		HourlyRate: hourlyRate,
-		Currency:   client.Currency,
-		CreatedAt:  client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:  client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:              client.ID,
+		UserID:          client.UserID,
+		Name:            client.Name,
+		Email:           client.Email,
+		Phone:           client.Phone.String,
+		Company:         client.Company.String,
+		Address:         client.Address.String,
+		HourlyRate:      money.ParseNull(client.HourlyRate),
+		Currency:        client.Currency,
+		CountryCode:     client.CountryCode.String,
+		TaxID:           client.TaxID.String,
+		RemindersOptOut: client.RemindersOptOut,
+		InvoicePrefix:   client.InvoicePrefix.String,
+		Billable:        client.Billable,
+		BuyerReference:  client.BuyerReference.String,
+		CreatedAt:       client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func getClientsByUserIDRowToResponse(client db.GetClientsByUserIDRow) models.ClientResponse {
-	hourlyRate, _ := strconv.ParseFloat(client.HourlyRate.String, 64)
 	return models.ClientResponse{
-		ID:         client.ID,
-		UserID:     client.UserID,
-		Name:       client.Name,
-		Email:      client.Email,
-		Phone:      client.Phone.String,
-		Company:    client.Company.String,
-		Address:    client.Address.String,
-		HourlyRate: hourlyRate,
-		Currency:   client.Currency,
-		CreatedAt:  client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:  client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:              client.ID,
+		UserID:          client.UserID,
+		Name:            client.Name,
+		Email:           client.Email,
+		Phone:           client.Phone.String,
+		Company:         client.Company.String,
+		Address:         client.Address.String,
+		HourlyRate:      money.ParseNull(client.HourlyRate),
+		Currency:        client.Currency,
+		CountryCode:     client.CountryCode.String,
+		TaxID:           client.TaxID.String,
+		RemindersOptOut: client.RemindersOptOut,
+		InvoicePrefix:   client.InvoicePrefix.String,
+		Billable:        client.Billable,
+		BuyerReference:  client.BuyerReference.String,
+		CreatedAt:       client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func getClientByIDRowToResponse(client db.GetClientByIDRow) models.ClientResponse {
-	hourlyRate, _ := strconv.ParseFloat(client.HourlyRate.String, 64)
 	return models.ClientResponse{
-		ID:         client.ID,
-		UserID:     client.UserID,
-		Name:       client.Name,
-		Email:      client.Email,
-		Phone:      client.Phone.String,
-		Company:    client.Company.String,
-		Address:    client.Address.String,
-		HourlyRate: hourlyRate,
-		Currency:   client.Currency,
-		CreatedAt:  client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:  client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:              client.ID,
+		UserID:          client.UserID,
+		Name:            client.Name,
+		Email:           client.Email,
+		Phone:           client.Phone.String,
+		Company:         client.Company.String,
+		Address:         client.Address.String,
+		HourlyRate:      money.ParseNull(client.HourlyRate),
+		Currency:        client.Currency,
+		CountryCode:     client.CountryCode.String,
+		TaxID:           client.TaxID.String,
+		RemindersOptOut: client.RemindersOptOut,
+		InvoicePrefix:   client.InvoicePrefix.String,
+		Billable:        client.Billable,
+		BuyerReference:  client.BuyerReference.String,
+		CreatedAt:       client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func updateClientRowToResponse(client db.UpdateClientRow) models.ClientResponse {
-	hourlyRate, _ := strconv.ParseFloat(client.HourlyRate.String, 64)
 	return models.ClientResponse{
-		ID:         client.ID,
-		UserID:     client.UserID,
-		Name:       client.Name,
-		Email:      client.Email,
-		Phone:      client.Phone.String,
-		Company:    client.Company.String,
-		Address:    client.Address.String,
-		HourlyRate: hourlyRate,
-		Currency:   client.Currency,
-		CreatedAt:  client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:  client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:              client.ID,
+		UserID:          client.UserID,
+		Name:            client.Name,
+		Email:           client.Email,
+		Phone:           client.Phone.String,
+		Company:         client.Company.String,
+		Address:         client.Address.String,
+		HourlyRate:      money.ParseNull(client.HourlyRate),
+		Currency:        client.Currency,
+		CountryCode:     client.CountryCode.String,
+		TaxID:           client.TaxID.String,
+		RemindersOptOut: client.RemindersOptOut,
+		InvoicePrefix:   client.InvoicePrefix.String,
+		Billable:        client.Billable,
+		BuyerReference:  client.BuyerReference.String,
+		CreatedAt:       client.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       client.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
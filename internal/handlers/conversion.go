@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/services"
+
+	"github.com/shopspring/decimal"
+)
+
+// Codes of the currency warnings attached to reports
+const (
+	warningRateMissing = "rate_missing"
+	warningRateStale   = "rate_stale"
+)
+
+// converter converts report amounts into the user's currency. It records the
+// rates it used, warns about currencies whose rate is stale or missing, and
+// collects the amounts that could not be converted so they can be reported
+// separately instead of being added to the totals at a made up rate.
+type converter struct {
+	to          string
+	rates       map[string]services.Rate
+	used        map[string]models.ConversionRateResponse
+	warnings    map[string]models.CurrencyWarning
+	unconverted map[string]map[string]decimal.Decimal
+}
+
+// newConverter loads today's rate converting each needed currency into the
+// user's currency
+func newConverter(ctx context.Context, exchangeService *services.ExchangeRateService, userID int32, currenciesNeeded map[string]bool, userCurrency string) *converter {
+	conv := &converter{
+		to:          userCurrency,
+		rates:       make(map[string]services.Rate, len(currenciesNeeded)),
+		used:        make(map[string]models.ConversionRateResponse),
+		warnings:    make(map[string]models.CurrencyWarning),
+		unconverted: make(map[string]map[string]decimal.Decimal),
+	}
+	for currency := range currenciesNeeded {
+		rate, err := exchangeService.GetRate(ctx, userID, currency, userCurrency)
+		if err != nil {
+			if !errors.Is(err, services.ErrRateUnavailable) {
+				log.Printf("Failed to convert %s to %s: %v", currency, userCurrency, err)
+			}
+			continue
+		}
+		conv.rates[currency] = rate
+	}
+	return conv
+}
+
+// rate returns today's rate converting currency into the user's currency,
+// and false if there is none
+func (conv *converter) rate(currency string) (services.Rate, bool) {
+	if currency == conv.to {
+		return services.Rate{Value: 1.0, Source: services.RateSourceMarket}, true
+	}
+	rate, ok := conv.rates[currency]
+	if ok {
+		conv.use(currency, rate)
+	}
+	return rate, ok
+}
+
+// invoiceRate returns the rate converting the invoice's amounts into the
+// user's currency: the rate frozen when the invoice was issued, or the
+// current rate for drafts and invoices issued without one. It returns false
+// if there is no rate.
+func (conv *converter) invoiceRate(invoice db.Invoice, clientCurrency string) (services.Rate, bool) {
+	frozen, ok := billing.IssuedRate(invoice, conv.to)
+	if !ok {
+		return conv.rate(clientCurrency)
+	}
+
+	source := invoice.ExchangeRateSource.String
+	if source == "" {
+		source = services.RateSourceMarket
+	}
+	rate := services.Rate{Value: frozen, Source: source}
+	if clientCurrency != conv.to {
+		conv.use(clientCurrency, rate)
+	}
+	return rate, true
+}
+
+// use records that amounts in currency were converted at rate. Invoices
+// issued at different rates are listed separately.
+func (conv *converter) use(currency string, rate services.Rate) {
+	entry := models.ConversionRateResponse{
+		From:   currency,
+		To:     conv.to,
+		Rate:   rate.Value,
+		Source: rate.Source,
+		Stale:  rate.Stale,
+	}
+	if !rate.UpdatedAt.IsZero() {
+		entry.UpdatedAt = rate.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	conv.used[fmt.Sprintf("%s/%s/%v", currency, rate.Source, rate.Value)] = entry
+
+	if rate.Stale {
+		conv.warnings[currency] = models.CurrencyWarning{
+			Currency: currency,
+			Code:     warningRateStale,
+			Message:  fmt.Sprintf("The %s to %s rate was last updated %s and may be out of date", currency, conv.to, entry.UpdatedAt),
+		}
+	}
+}
+
+// skip records amount, in currency, as left out of the named total because
+// there was no rate to convert it with
+func (conv *converter) skip(currency, total string, amount decimal.Decimal) {
+	if _, ok := conv.unconverted[currency]; !ok {
+		conv.unconverted[currency] = make(map[string]decimal.Decimal)
+	}
+	if !amount.IsZero() {
+		conv.unconverted[currency][total] = conv.unconverted[currency][total].Add(amount)
+	}
+
+	conv.warnings[currency] = models.CurrencyWarning{
+		Currency: currency,
+		Code:     warningRateMissing,
+		Message:  fmt.Sprintf("No %s to %s exchange rate is available; %s amounts are reported separately and left out of the totals", currency, conv.to, currency),
+	}
+}
+
+// ratesUsed returns the recorded rates ordered by currency and source
+func (conv *converter) ratesUsed() []models.ConversionRateResponse {
+	list := make([]models.ConversionRateResponse, 0, len(conv.used))
+	for _, entry := range conv.used {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].From != list[j].From {
+			return list[i].From < list[j].From
+		}
+		if list[i].Source != list[j].Source {
+			return list[i].Source < list[j].Source
+		}
+		return list[i].Rate < list[j].Rate
+	})
+	return list
+}
+
+// warningList returns the currency warnings ordered by currency
+func (conv *converter) warningList() []models.CurrencyWarning {
+	list := make([]models.CurrencyWarning, 0, len(conv.warnings))
+	for _, warning := range conv.warnings {
+		list = append(list, warning)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Currency < list[j].Currency
+	})
+	return list
+}
+
+// unconvertedList returns the amounts left out of the totals ordered by
+// currency
+func (conv *converter) unconvertedList() []models.UnconvertedTotal {
+	list := make([]models.UnconvertedTotal, 0, len(conv.unconverted))
+	for currency, totals := range conv.unconverted {
+		list = append(list, models.UnconvertedTotal{Currency: currency, Totals: totals})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Currency < list[j].Currency
+	})
+	return list
+}
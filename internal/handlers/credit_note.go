@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+	"worklio-api/internal/pdf"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CreditNoteHandler struct {
+	queries           *db.Queries
+	creditNoteService *services.CreditNoteService
+}
+
+func NewCreditNoteHandler(queries *db.Queries, creditNoteService *services.CreditNoteService) *CreditNoteHandler {
+	return &CreditNoteHandler{
+		queries:           queries,
+		creditNoteService: creditNoteService,
+	}
+}
+
+// CreateCreditNote godoc
+// @Summary Issue a credit note
+// @Description Issue a credit note cancelling all (full) or part (line_items) of an invoice that is no longer a draft. Credited lines are taxed like the invoice, and the invoice's balance and status are updated. Credit notes cannot be changed once issued.
+// @Tags credit-notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body models.CreateCreditNoteRequest true "Create Credit Note Request"
+// @Success 201 {object} models.CreditNoteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/credit-notes [post]
+func (h *CreditNoteHandler) CreateCreditNote(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	var req models.CreateCreditNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	issueDate, err := time.Parse("2006-01-02", req.IssueDate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid issue date format. Use YYYY-MM-DD"})
+	}
+
+	if req.Full && len(req.LineItems) > 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Provide either full or line_items, not both"})
+	}
+	if !req.Full && len(req.LineItems) == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Either full or line_items is required"})
+	}
+	if msg := validateLineItems(req.LineItems); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	creditNote, err := h.creditNoteService.CreateCreditNote(c.Request().Context(), userID, int32(invoiceID), services.NewCreditNote{
+		CreditNoteNumber: strings.TrimSpace(req.CreditNoteNumber),
+		IssueDate:        issueDate,
+		Reason:           strings.TrimSpace(req.Reason),
+		Full:             req.Full,
+		LineItems:        req.LineItems,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvoiceNotFound):
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		case errors.Is(err, services.ErrInvoiceNotIssued),
+			errors.Is(err, services.ErrNothingToCredit),
+			errors.Is(err, services.ErrCreditExceedsInvoice):
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrCreditNoteNumberTaken):
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Credit note number already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create credit note"})
+	}
+
+	return h.creditNoteResponse(c, http.StatusCreated, creditNote)
+}
+
+// GetInvoiceCreditNotes godoc
+// @Summary Get invoice credit notes
+// @Description Get all credit notes issued against an invoice, oldest first
+// @Tags credit-notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} models.CreditNoteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/credit-notes [get]
+func (h *CreditNoteHandler) GetInvoiceCreditNotes(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     int32(invoiceID),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	creditNotes, err := h.queries.GetCreditNotesByInvoiceID(c.Request().Context(), invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch credit notes"})
+	}
+
+	response := make([]models.CreditNoteResponse, len(creditNotes))
+	for i, creditNote := range creditNotes {
+		lineItems, err := h.queries.GetCreditNoteLineItems(c.Request().Context(), creditNote.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+		}
+		response[i] = creditNoteToResponse(creditNote, invoice, lineItems)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetCreditNotes godoc
+// @Summary Get all credit notes
+// @Description Get all credit notes for the authenticated user, most recent first
+// @Tags credit-notes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CreditNoteResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/credit-notes [get]
+func (h *CreditNoteHandler) GetCreditNotes(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	creditNotes, err := h.queries.GetCreditNotesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch credit notes"})
+	}
+
+	response := make([]models.CreditNoteResponse, len(creditNotes))
+	for i, creditNote := range creditNotes {
+		invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+			ID:     creditNote.InvoiceID,
+			UserID: userID,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+		}
+
+		lineItems, err := h.queries.GetCreditNoteLineItems(c.Request().Context(), creditNote.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+		}
+		response[i] = creditNoteToResponse(creditNote, invoice, lineItems)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetCreditNote godoc
+// @Summary Get a credit note
+// @Description Get a specific credit note by ID
+// @Tags credit-notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Credit Note ID"
+// @Success 200 {object} models.CreditNoteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/credit-notes/{id} [get]
+func (h *CreditNoteHandler) GetCreditNote(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid credit note ID"})
+	}
+
+	creditNote, err := h.queries.GetCreditNoteByID(c.Request().Context(), db.GetCreditNoteByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Credit note not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch credit note"})
+	}
+
+	return h.creditNoteResponse(c, http.StatusOK, creditNote)
+}
+
+// GetNextCreditNoteNumber godoc
+// @Summary Preview the next credit note number
+// @Description Get the number the next credit note issued to the client would be given, without allocating it
+// @Tags credit-notes
+// @Produce json
+// @Security BearerAuth
+// @Param client_id query int true "Client ID"
+// @Param issue_date query string false "Issue date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.NextCreditNoteNumberResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/credit-notes/next-number [get]
+func (h *CreditNoteHandler) GetNextCreditNoteNumber(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	clientIDStr := c.QueryParam("client_id")
+	if clientIDStr == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "client_id query parameter is required"})
+	}
+
+	clientID, err := strconv.ParseInt(clientIDStr, 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid client ID"})
+	}
+
+	issueDate := time.Now().UTC()
+	if issueDateStr := c.QueryParam("issue_date"); issueDateStr != "" {
+		issueDate, err = time.Parse("2006-01-02", issueDateStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid issue date format. Use YYYY-MM-DD"})
+		}
+	}
+
+	number, err := h.creditNoteService.PreviewCreditNoteNumber(c.Request().Context(), userID, int32(clientID), issueDate)
+	if err != nil {
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to preview credit note number"})
+	}
+
+	return c.JSON(http.StatusOK, models.NextCreditNoteNumberResponse{CreditNoteNumber: number})
+}
+
+// DownloadCreditNotePDF godoc
+// @Summary Download credit note as PDF
+// @Description Download a credit note as a PDF file
+// @Tags credit-notes
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Credit Note ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/credit-notes/{id}/pdf [get]
+func (h *CreditNoteHandler) DownloadCreditNotePDF(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid credit note ID"})
+	}
+
+	doc, err := billing.LoadCreditNoteDocument(c.Request().Context(), h.queries, userID, int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Credit note not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch credit note data"})
+	}
+
+	creditNotePDF := pdf.RenderCreditNote(doc)
+
+	filename := fmt.Sprintf("%s.pdf", doc.CreditNote.CreditNoteNumber)
+	c.Response().Header().Set("Content-Type", "application/pdf")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	err = creditNotePDF.Output(c.Response().Writer)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate PDF"})
+	}
+
+	return nil
+}
+
+// creditNoteResponse writes creditNote with the number of the invoice it
+// credits and its line items
+func (h *CreditNoteHandler) creditNoteResponse(c echo.Context, status int, creditNote db.CreditNote) error {
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     creditNote.InvoiceID,
+		UserID: creditNote.UserID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	lineItems, err := h.queries.GetCreditNoteLineItems(c.Request().Context(), creditNote.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+	}
+
+	return c.JSON(status, creditNoteToResponse(creditNote, invoice, lineItems))
+}
+
+func creditNoteToResponse(creditNote db.CreditNote, invoice db.Invoice, lineItems []db.CreditNoteLineItem) models.CreditNoteResponse {
+	lineItemResponses := make([]models.CreditNoteLineItemResponse, len(lineItems))
+	for i, item := range lineItems {
+		quantity := money.Parse(item.Quantity)
+		unitPrice := money.Parse(item.UnitPrice)
+		lineItemResponses[i] = models.CreditNoteLineItemResponse{
+			ID:          item.ID,
+			Description: item.Description,
+			Quantity:    quantity,
+			Unit:        item.Unit,
+			UnitPrice:   unitPrice,
+			TaxRate:     money.Parse(item.TaxRate),
+			Amount:      money.Round(quantity.Mul(unitPrice), invoice.Currency.String),
+		}
+	}
+
+	return models.CreditNoteResponse{
+		ID:               creditNote.ID,
+		UserID:           creditNote.UserID,
+		InvoiceID:        creditNote.InvoiceID,
+		InvoiceNumber:    invoice.InvoiceNumber,
+		ClientID:         creditNote.ClientID,
+		CreditNoteNumber: creditNote.CreditNoteNumber,
+		IssueDate:        creditNote.IssueDate.Format("2006-01-02"),
+		Reason:           creditNote.Reason.String,
+		LineItems:        lineItemResponses,
+		Subtotal:         money.Parse(creditNote.Subtotal),
+		TaxAmount:        money.Parse(creditNote.TaxAmount),
+		Withholding:      money.Parse(creditNote.WithholdingAmount),
+		TotalAmount:      money.Parse(creditNote.Total),
+		CreatedAt:        creditNote.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
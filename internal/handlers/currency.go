@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
+	"worklio-api/internal/iso4217"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
 	"worklio-api/internal/services"
 
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 )
 
 type CurrencyHandler struct {
@@ -20,46 +25,28 @@ func NewCurrencyHandler(exchangeService *services.ExchangeRateService) *Currency
 
 // SupportedCurrency represents a supported currency
 type SupportedCurrency struct {
-	Code   string `json:"code"`
-	Symbol string `json:"symbol"`
-	Name   string `json:"name"`
+	Code       string `json:"code"`
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	MinorUnits int    `json:"minor_units"`
 }
 
 // GetSupportedCurrencies godoc
 // @Summary Get supported currencies
-// @Description Returns a list of all supported currencies
+// @Description Returns every ISO 4217 currency invoices can be issued in, with the number of decimals amounts in it are rounded to
 // @Tags currency
 // @Produce json
 // @Success 200 {object} []SupportedCurrency
 // @Router /api/supported-currencies [get]
 func (h *CurrencyHandler) GetSupportedCurrencies(c echo.Context) error {
-	currencies := []SupportedCurrency{
-		{Code: "USD", Symbol: "$", Name: "US Dollar"},
-		{Code: "EUR", Symbol: "€", Name: "Euro"},
-		{Code: "GBP", Symbol: "£", Name: "British Pound"},
-		{Code: "JPY", Symbol: "¥", Name: "Japanese Yen"},
-		{Code: "AUD", Symbol: "A$", Name: "Australian Dollar"},
-		{Code: "CAD", Symbol: "C$", Name: "Canadian Dollar"},
-		{Code: "CHF", Symbol: "CHF", Name: "Swiss Franc"},
-		{Code: "CNY", Symbol: "¥", Name: "Chinese Yuan"},
-		{Code: "SEK", Symbol: "kr", Name: "Swedish Krona"},
-		{Code: "NZD", Symbol: "NZ$", Name: "New Zealand Dollar"},
-		{Code: "IDR", Symbol: "Rp", Name: "Indonesian Rupiah"},
-		{Code: "SGD", Symbol: "S$", Name: "Singapore Dollar"},
-		{Code: "INR", Symbol: "₹", Name: "Indian Rupee"},
-	}
-
-	// Verify all currencies are in the service's supported list
-	supportedMap := make(map[string]bool)
-	for _, code := range services.SupportedCurrencies {
-		supportedMap[code] = true
-	}
-
-	// Filter to only return currencies that are actually supported
-	var result []SupportedCurrency
-	for _, currency := range currencies {
-		if supportedMap[currency.Code] {
-			result = append(result, currency)
+	all := iso4217.All()
+	result := make([]SupportedCurrency, len(all))
+	for i, currency := range all {
+		result[i] = SupportedCurrency{
+			Code:       currency.Code,
+			Symbol:     iso4217.Symbol(currency.Code),
+			Name:       currency.Name,
+			MinorUnits: currency.MinorUnits,
 		}
 	}
 
@@ -68,17 +55,17 @@ func (h *CurrencyHandler) GetSupportedCurrencies(c echo.Context) error {
 
 // ConvertCurrencyRequest represents a currency conversion request
 type ConvertCurrencyRequest struct {
-	Amount   float64 `json:"amount" validate:"required"`
+	Amount   decimal.Decimal `json:"amount" validate:"required" swaggertype:"number"`
 	From     string  `json:"from" validate:"required"`
 	To       string  `json:"to" validate:"required"`
 }
 
 // ConvertCurrencyResponse represents a currency conversion response
 type ConvertCurrencyResponse struct {
-	Amount         float64 `json:"amount"`
+	Amount         decimal.Decimal `json:"amount" swaggertype:"number"`
 	From           string  `json:"from"`
 	To             string  `json:"to"`
-	ConvertedAmount float64 `json:"converted_amount"`
+	ConvertedAmount decimal.Decimal `json:"converted_amount" swaggertype:"number"`
 	Rate           float64 `json:"rate"`
 }
 
@@ -102,27 +89,112 @@ func (h *CurrencyHandler) ConvertCurrency(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing required parameters"})
 	}
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := decimal.NewFromString(amountStr)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid amount"})
 	}
 
-	convertedAmount, err := h.exchangeService.ConvertAmount(c.Request().Context(), amount, from, to)
+	convertedAmount, err := h.exchangeService.ConvertAmount(c.Request().Context(), 0, amount, from, to)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
 	// Calculate the rate
 	rate := 1.0
-	if amount > 0 {
-		rate = convertedAmount / amount
+	if amount.IsPositive() {
+		rate = convertedAmount.Div(amount).InexactFloat64()
 	}
 
 	return c.JSON(http.StatusOK, ConvertCurrencyResponse{
 		Amount:          amount,
 		From:            from,
 		To:              to,
-		ConvertedAmount: convertedAmount,
+		ConvertedAmount: money.Round(convertedAmount, to),
 		Rate:            rate,
 	})
 }
+
+// ExchangeRatePoint is the exchange rate on one day
+type ExchangeRatePoint struct {
+	Date string  `json:"date"`
+	Rate float64 `json:"rate"`
+}
+
+// ExchangeRateHistoryResponse represents the daily rates of a currency pair
+type ExchangeRateHistoryResponse struct {
+	Base   string              `json:"base"`
+	Target string              `json:"target"`
+	From   string              `json:"from"`
+	To     string              `json:"to"`
+	Rates  []ExchangeRatePoint `json:"rates"`
+}
+
+// GetExchangeRateHistory godoc
+// @Summary Get exchange rate history
+// @Description Returns the daily exchange rates from base to target between two dates, oldest first. Days without published rates, such as weekends, are omitted.
+// @Tags currency
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD format), defaults to 30 days before to"
+// @Param to query string false "End date (YYYY-MM-DD format), defaults to today"
+// @Param base query string false "Base currency" default(USD)
+// @Param target query string true "Target currency"
+// @Success 200 {object} ExchangeRateHistoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/exchange-rates/history [get]
+func (h *CurrencyHandler) GetExchangeRateHistory(c echo.Context) error {
+	base := strings.ToUpper(c.QueryParam("base"))
+	if base == "" {
+		base = "USD"
+	}
+	target := strings.ToUpper(c.QueryParam("target"))
+	if target == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Target currency is required"})
+	}
+	if !iso4217.IsValid(base) || !iso4217.IsValid(target) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unsupported currency"})
+	}
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr := c.QueryParam("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid to date format. Use YYYY-MM-DD"})
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid from date format. Use YYYY-MM-DD"})
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "From date must not be after to date"})
+	}
+
+	history, err := h.exchangeService.GetRateHistory(c.Request().Context(), base, target, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch exchange rate history"})
+	}
+
+	rates := make([]ExchangeRatePoint, len(history))
+	for i, rate := range history {
+		rates[i] = ExchangeRatePoint{
+			Date: rate.Date.Format("2006-01-02"),
+			Rate: rate.Rate,
+		}
+	}
+
+	return c.JSON(http.StatusOK, ExchangeRateHistoryResponse{
+		Base:   base,
+		Target: target,
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Rates:  rates,
+	})
+}
+
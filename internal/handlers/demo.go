@@ -49,6 +49,7 @@ func (h *DemoHandler) GenerateDemoData(c echo.Context) error {
 		Company:    sql.NullString{String: "Acme Corporation", Valid: true},
 		HourlyRate: sql.NullString{String: "75", Valid: true},
 		Currency:   "USD",
+		Billable:   true,
 	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create demo client"})
@@ -61,6 +62,7 @@ func (h *DemoHandler) GenerateDemoData(c echo.Context) error {
 		Company:    sql.NullString{String: "TechStart Incorporated", Valid: true},
 		HourlyRate: sql.NullString{String: "100", Valid: true},
 		Currency:   "USD",
+		Billable:   true,
 	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create demo client"})
@@ -73,6 +75,7 @@ func (h *DemoHandler) GenerateDemoData(c echo.Context) error {
 		Company:    sql.NullString{String: "Creative Design Studio", Valid: true},
 		HourlyRate: sql.NullString{String: "85", Valid: true},
 		Currency:   "USD",
+		Billable:   true,
 	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create demo client"})
@@ -142,6 +145,7 @@ func (h *DemoHandler) GenerateDemoData(c echo.Context) error {
 			Hours:       fmt.Sprintf("%d", hours),
 			Description: sql.NullString{String: task, Valid: true},
 			HourlyRate:  client.HourlyRate,
+			Billable:    client.Billable,
 		})
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create demo time entry"})
@@ -0,0 +1,547 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+	"worklio-api/internal/pdf"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EstimateHandler struct {
+	queries         *db.Queries
+	estimateService *services.EstimateService
+}
+
+func NewEstimateHandler(queries *db.Queries, estimateService *services.EstimateService) *EstimateHandler {
+	return &EstimateHandler{
+		queries:         queries,
+		estimateService: estimateService,
+	}
+}
+
+// CreateEstimate godoc
+// @Summary Create an estimate
+// @Description Create an estimate (quote) for a client with line items and a validity date
+// @Tags estimates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.EstimateRequest true "Create Estimate Request"
+// @Success 201 {object} models.EstimateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates [post]
+func (h *EstimateHandler) CreateEstimate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.EstimateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	estimate, msg := parseEstimateRequest(req)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	created, err := h.estimateService.CreateEstimate(c.Request().Context(), userID, estimate)
+	if err != nil {
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		if errors.Is(err, services.ErrEstimateNumberTaken) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Estimate number already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create estimate"})
+	}
+
+	return h.estimateResponse(c, http.StatusCreated, created)
+}
+
+// GetEstimates godoc
+// @Summary Get all estimates
+// @Description Get all estimates for the authenticated user
+// @Tags estimates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.EstimateResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates [get]
+func (h *EstimateHandler) GetEstimates(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	estimates, err := h.queries.GetEstimatesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch estimates"})
+	}
+
+	clients, err := h.queries.GetClientsByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch clients"})
+	}
+	currencies := make(map[int32]string, len(clients))
+	for _, client := range clients {
+		currencies[client.ID] = client.Currency
+	}
+
+	response := make([]models.EstimateResponse, len(estimates))
+	for i, estimate := range estimates {
+		lineItems, err := h.queries.GetEstimateLineItems(c.Request().Context(), estimate.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+		}
+		response[i] = estimateToResponse(estimate, lineItems, currencies[estimate.ClientID])
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetEstimate godoc
+// @Summary Get an estimate
+// @Description Get a specific estimate by ID
+// @Tags estimates
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Estimate ID"
+// @Success 200 {object} models.EstimateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/{id} [get]
+func (h *EstimateHandler) GetEstimate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate ID"})
+	}
+
+	estimate, err := h.queries.GetEstimateByID(c.Request().Context(), db.GetEstimateByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Estimate not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch estimate"})
+	}
+
+	return h.estimateResponse(c, http.StatusOK, estimate)
+}
+
+// UpdateEstimate godoc
+// @Summary Update an estimate
+// @Description Replace an estimate's details and line items. Estimates that have been converted into an invoice cannot be changed.
+// @Tags estimates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Estimate ID"
+// @Param request body models.EstimateRequest true "Update Estimate Request"
+// @Success 200 {object} models.EstimateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/{id} [put]
+func (h *EstimateHandler) UpdateEstimate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate ID"})
+	}
+
+	var req models.EstimateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	estimate, msg := parseEstimateRequest(req)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	updated, err := h.estimateService.UpdateEstimate(c.Request().Context(), userID, int32(id), estimate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Estimate not found"})
+		}
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		if errors.Is(err, services.ErrEstimateConverted) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Estimate has already been converted into an invoice"})
+		}
+		if errors.Is(err, services.ErrEstimateNumberTaken) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Estimate number already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update estimate"})
+	}
+
+	return h.estimateResponse(c, http.StatusOK, updated)
+}
+
+// UpdateEstimateStatus godoc
+// @Summary Update estimate status
+// @Description Update only the status of an estimate, e.g. to record that the client accepted or declined it
+// @Tags estimates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Estimate ID"
+// @Param request body models.UpdateEstimateStatusRequest true "Update Estimate Status Request"
+// @Success 200 {object} models.EstimateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/{id}/status [patch]
+func (h *EstimateHandler) UpdateEstimateStatus(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate ID"})
+	}
+
+	var req models.UpdateEstimateStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if !isValidEstimateStatus(req.Status) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Status must be one of: draft, sent, accepted, declined, expired"})
+	}
+
+	estimate, err := h.estimateService.UpdateEstimateStatus(c.Request().Context(), userID, int32(id), req.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Estimate not found"})
+		}
+		if errors.Is(err, services.ErrEstimateConverted) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Estimate has already been converted into an invoice"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update estimate status"})
+	}
+
+	return h.estimateResponse(c, http.StatusOK, estimate)
+}
+
+// DeleteEstimate godoc
+// @Summary Delete an estimate
+// @Description Delete an estimate by ID. An invoice converted from the estimate is kept.
+// @Tags estimates
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Estimate ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/{id} [delete]
+func (h *EstimateHandler) DeleteEstimate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate ID"})
+	}
+
+	err = h.queries.DeleteEstimate(c.Request().Context(), db.DeleteEstimateParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete estimate"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetNextEstimateNumber godoc
+// @Summary Preview the next estimate number
+// @Description Get the number the next estimate created for the client without an estimate number would be given, without allocating it
+// @Tags estimates
+// @Produce json
+// @Security BearerAuth
+// @Param client_id query int true "Client ID"
+// @Param issue_date query string false "Issue date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.NextEstimateNumberResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/next-number [get]
+func (h *EstimateHandler) GetNextEstimateNumber(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	clientIDStr := c.QueryParam("client_id")
+	if clientIDStr == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "client_id query parameter is required"})
+	}
+
+	clientID, err := strconv.ParseInt(clientIDStr, 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid client ID"})
+	}
+
+	issueDate := time.Now().UTC()
+	if issueDateStr := c.QueryParam("issue_date"); issueDateStr != "" {
+		issueDate, err = time.Parse("2006-01-02", issueDateStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid issue date format. Use YYYY-MM-DD"})
+		}
+	}
+
+	number, err := h.estimateService.PreviewEstimateNumber(c.Request().Context(), userID, int32(clientID), issueDate)
+	if err != nil {
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to preview estimate number"})
+	}
+
+	return c.JSON(http.StatusOK, models.NextEstimateNumberResponse{EstimateNumber: number})
+}
+
+// ConvertEstimate godoc
+// @Summary Convert an estimate into an invoice
+// @Description Create a draft invoice from the estimate's line items and mark the estimate accepted. The estimate is linked to the invoice and cannot be changed or converted again. Declined and expired estimates cannot be converted.
+// @Tags estimates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Estimate ID"
+// @Param request body models.ConvertEstimateRequest false "Convert Estimate Request"
+// @Success 201 {object} models.InvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/{id}/convert [post]
+func (h *EstimateHandler) ConvertEstimate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate ID"})
+	}
+
+	var req models.ConvertEstimateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	now := time.Now().UTC()
+	issueDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.IssueDate != "" {
+		issueDate, err = time.Parse("2006-01-02", req.IssueDate)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid issue date format. Use YYYY-MM-DD"})
+		}
+	}
+
+	dueDate := issueDate.AddDate(0, 0, 30)
+	if req.DueDate != "" {
+		dueDate, err = time.Parse("2006-01-02", req.DueDate)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid due date format. Use YYYY-MM-DD"})
+		}
+	}
+
+	invoice, err := h.estimateService.ConvertEstimate(c.Request().Context(), userID, int32(id), services.EstimateConversion{
+		IssueDate:  issueDate,
+		DueDate:    dueDate,
+		TaxRateIDs: req.TaxRateIDs,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Estimate not found"})
+		}
+		if errors.Is(err, services.ErrEstimateConverted) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Estimate has already been converted into an invoice"})
+		}
+		if errors.Is(err, services.ErrEstimateNotConvertible) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Declined and expired estimates cannot be converted"})
+		}
+		if errors.Is(err, services.ErrTaxRateNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Tax rate not found"})
+		}
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to convert estimate"})
+	}
+
+	contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice contents"})
+	}
+
+	return c.JSON(http.StatusCreated, buildInvoiceResponse(invoice, contents))
+}
+
+// DownloadEstimatePDF godoc
+// @Summary Download estimate as PDF
+// @Description Download an estimate as a PDF file laid out like an invoice
+// @Tags estimates
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Estimate ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/estimates/{id}/pdf [get]
+func (h *EstimateHandler) DownloadEstimatePDF(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate ID"})
+	}
+
+	doc, err := billing.LoadEstimateDocument(c.Request().Context(), h.queries, userID, int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Estimate not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch estimate data"})
+	}
+
+	estimatePDF := pdf.RenderEstimate(doc)
+
+	filename := fmt.Sprintf("%s.pdf", doc.Estimate.EstimateNumber)
+	c.Response().Header().Set("Content-Type", "application/pdf")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	err = estimatePDF.Output(c.Response().Writer)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate PDF"})
+	}
+
+	return nil
+}
+
+// parseEstimateRequest validates req and converts it for EstimateService. It
+// returns an error message when the request is invalid.
+func parseEstimateRequest(req models.EstimateRequest) (services.NewEstimate, string) {
+	issueDate, err := time.Parse("2006-01-02", req.IssueDate)
+	if err != nil {
+		return services.NewEstimate{}, "Invalid issue date format. Use YYYY-MM-DD"
+	}
+
+	validUntil, err := time.Parse("2006-01-02", req.ValidUntil)
+	if err != nil {
+		return services.NewEstimate{}, "Invalid valid until date format. Use YYYY-MM-DD"
+	}
+	if validUntil.Before(issueDate) {
+		return services.NewEstimate{}, "Valid until date cannot be before the issue date"
+	}
+
+	if !isValidEstimateStatus(req.Status) {
+		return services.NewEstimate{}, "Status must be one of: draft, sent, accepted, declined, expired"
+	}
+
+	if len(req.LineItems) == 0 {
+		return services.NewEstimate{}, "Estimate must include at least one line item"
+	}
+	if msg := validateLineItems(req.LineItems); msg != "" {
+		return services.NewEstimate{}, msg
+	}
+
+	return services.NewEstimate{
+		ClientID:       req.ClientID,
+		EstimateNumber: strings.TrimSpace(req.EstimateNumber),
+		IssueDate:      issueDate,
+		ValidUntil:     validUntil,
+		Status:         req.Status,
+		Notes:          req.Notes,
+		LineItems:      req.LineItems,
+	}, ""
+}
+
+func isValidEstimateStatus(status string) bool {
+	switch status {
+	case "draft", "sent", "accepted", "declined", "expired":
+		return true
+	}
+	return false
+}
+
+// estimateResponse writes estimate with its line items
+func (h *EstimateHandler) estimateResponse(c echo.Context, status int, estimate db.Estimate) error {
+	lineItems, err := h.queries.GetEstimateLineItems(c.Request().Context(), estimate.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+	}
+
+	client, err := h.queries.GetClientByID(c.Request().Context(), db.GetClientByIDParams{
+		ID:     estimate.ClientID,
+		UserID: estimate.UserID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch client"})
+	}
+
+	return c.JSON(status, estimateToResponse(estimate, lineItems, client.Currency))
+}
+
+// estimateToResponse converts an estimate quoted in currency to a response
+func estimateToResponse(estimate db.Estimate, lineItems []db.EstimateLineItem, currency string) models.EstimateResponse {
+	lines := billing.EstimateLines(lineItems)
+	totals := billing.Summarize(lines, nil, false, currency)
+
+	lineItemResponses := make([]models.EstimateLineItemResponse, len(lineItems))
+	for i, item := range lineItems {
+		lineItemResponses[i] = models.EstimateLineItemResponse{
+			ID:          item.ID,
+			Description: item.Description,
+			Quantity:    lines[i].Quantity,
+			Unit:        item.Unit,
+			UnitPrice:   lines[i].UnitPrice,
+			TaxRate:     lines[i].TaxRate,
+			Amount:      money.Round(lines[i].Amount, currency),
+		}
+	}
+
+	return models.EstimateResponse{
+		ID:             estimate.ID,
+		UserID:         estimate.UserID,
+		ClientID:       estimate.ClientID,
+		EstimateNumber: estimate.EstimateNumber,
+		IssueDate:      estimate.IssueDate.Format("2006-01-02"),
+		ValidUntil:     estimate.ValidUntil.Format("2006-01-02"),
+		Status:         estimate.Status,
+		Notes:          estimate.Notes.String,
+		InvoiceID:      estimate.InvoiceID.Int32,
+		Currency:       currency,
+		LineItems:      lineItemResponses,
+		Subtotal:       totals.Subtotal,
+		TaxAmount:      totals.Tax,
+		TotalAmount:    totals.Total,
+		CreatedAt:      estimate.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      estimate.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/iso4217"
+	"worklio-api/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ExchangeRateOverrideHandler struct {
+	queries *db.Queries
+}
+
+func NewExchangeRateOverrideHandler(queries *db.Queries) *ExchangeRateOverrideHandler {
+	return &ExchangeRateOverrideHandler{
+		queries: queries,
+	}
+}
+
+// CreateExchangeRateOverride godoc
+// @Summary Create an exchange rate override
+// @Description Set a fixed rate for a currency pair, used instead of the market rate when converting the authenticated user's amounts. The rate applies in both directions and can be limited to a date range.
+// @Tags exchange-rate-overrides
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateExchangeRateOverrideRequest true "Create Exchange Rate Override Request"
+// @Success 201 {object} models.ExchangeRateOverrideResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/exchange-rate-overrides [post]
+func (h *ExchangeRateOverrideHandler) CreateExchangeRateOverride(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.CreateExchangeRateOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	override, msg := parseExchangeRateOverride(req.BaseCurrency, req.TargetCurrency, req.Rate, req.ValidFrom, req.ValidTo)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	created, err := h.queries.CreateExchangeRateOverride(c.Request().Context(), db.CreateExchangeRateOverrideParams{
+		UserID:         userID,
+		BaseCurrency:   override.BaseCurrency,
+		TargetCurrency: override.TargetCurrency,
+		Rate:           override.Rate,
+		ValidFrom:      override.ValidFrom,
+		ValidTo:        override.ValidTo,
+		Note:           sql.NullString{String: req.Note, Valid: req.Note != ""},
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create exchange rate override"})
+	}
+
+	return c.JSON(http.StatusCreated, exchangeRateOverrideToResponse(created))
+}
+
+// GetExchangeRateOverrides godoc
+// @Summary Get all exchange rate overrides
+// @Description Get all exchange rate overrides for the authenticated user
+// @Tags exchange-rate-overrides
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ExchangeRateOverrideResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/exchange-rate-overrides [get]
+func (h *ExchangeRateOverrideHandler) GetExchangeRateOverrides(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	overrides, err := h.queries.GetExchangeRateOverridesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch exchange rate overrides"})
+	}
+
+	response := make([]models.ExchangeRateOverrideResponse, len(overrides))
+	for i, override := range overrides {
+		response[i] = exchangeRateOverrideToResponse(override)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateExchangeRateOverride godoc
+// @Summary Update an exchange rate override
+// @Description Update an exchange rate override. Invoices already issued keep the rate they were issued with.
+// @Tags exchange-rate-overrides
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Exchange Rate Override ID"
+// @Param request body models.UpdateExchangeRateOverrideRequest true "Update Exchange Rate Override Request"
+// @Success 200 {object} models.ExchangeRateOverrideResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/exchange-rate-overrides/{id} [put]
+func (h *ExchangeRateOverrideHandler) UpdateExchangeRateOverride(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid exchange rate override ID"})
+	}
+
+	var req models.UpdateExchangeRateOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	override, msg := parseExchangeRateOverride(req.BaseCurrency, req.TargetCurrency, req.Rate, req.ValidFrom, req.ValidTo)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	updated, err := h.queries.UpdateExchangeRateOverride(c.Request().Context(), db.UpdateExchangeRateOverrideParams{
+		ID:             int32(id),
+		UserID:         userID,
+		BaseCurrency:   override.BaseCurrency,
+		TargetCurrency: override.TargetCurrency,
+		Rate:           override.Rate,
+		ValidFrom:      override.ValidFrom,
+		ValidTo:        override.ValidTo,
+		Note:           sql.NullString{String: req.Note, Valid: req.Note != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Exchange rate override not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update exchange rate override"})
+	}
+
+	return c.JSON(http.StatusOK, exchangeRateOverrideToResponse(updated))
+}
+
+// DeleteExchangeRateOverride godoc
+// @Summary Delete an exchange rate override
+// @Description Delete an exchange rate override by ID. Conversions fall back to the market rate.
+// @Tags exchange-rate-overrides
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Exchange Rate Override ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/exchange-rate-overrides/{id} [delete]
+func (h *ExchangeRateOverrideHandler) DeleteExchangeRateOverride(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid exchange rate override ID"})
+	}
+
+	err = h.queries.DeleteExchangeRateOverride(c.Request().Context(), db.DeleteExchangeRateOverrideParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete exchange rate override"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseExchangeRateOverride validates an override request and converts it to
+// the stored representation. It returns an error message if the request is
+// invalid, or an empty string otherwise.
+func parseExchangeRateOverride(baseCurrency, targetCurrency string, rate float64, validFrom, validTo string) (db.ExchangeRateOverride, string) {
+	override := db.ExchangeRateOverride{
+		BaseCurrency:   strings.ToUpper(strings.TrimSpace(baseCurrency)),
+		TargetCurrency: strings.ToUpper(strings.TrimSpace(targetCurrency)),
+		Rate:           fmt.Sprintf("%.10f", rate),
+	}
+	if !iso4217.IsValid(override.BaseCurrency) || !iso4217.IsValid(override.TargetCurrency) {
+		return override, "Unsupported currency"
+	}
+	if override.BaseCurrency == override.TargetCurrency {
+		return override, "Base and target currency must differ"
+	}
+	if rate <= 0 {
+		return override, "Rate must be greater than 0"
+	}
+
+	if validFrom != "" {
+		from, err := time.Parse("2006-01-02", validFrom)
+		if err != nil {
+			return override, "Invalid valid_from format. Use YYYY-MM-DD"
+		}
+		override.ValidFrom = sql.NullTime{Time: from, Valid: true}
+	}
+	if validTo != "" {
+		to, err := time.Parse("2006-01-02", validTo)
+		if err != nil {
+			return override, "Invalid valid_to format. Use YYYY-MM-DD"
+		}
+		override.ValidTo = sql.NullTime{Time: to, Valid: true}
+	}
+	if override.ValidFrom.Valid && override.ValidTo.Valid && override.ValidTo.Time.Before(override.ValidFrom.Time) {
+		return override, "valid_to must not be before valid_from"
+	}
+	return override, ""
+}
+
+func exchangeRateOverrideToResponse(override db.ExchangeRateOverride) models.ExchangeRateOverrideResponse {
+	rate, _ := strconv.ParseFloat(override.Rate, 64)
+	response := models.ExchangeRateOverrideResponse{
+		ID:             override.ID,
+		UserID:         override.UserID,
+		BaseCurrency:   override.BaseCurrency,
+		TargetCurrency: override.TargetCurrency,
+		Rate:           rate,
+		Note:           override.Note.String,
+		CreatedAt:      override.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      override.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+	if override.ValidFrom.Valid {
+		response.ValidFrom = override.ValidFrom.Time.Format("2006-01-02")
+	}
+	if override.ValidTo.Valid {
+		response.ValidTo = override.ValidTo.Time.Format("2006-01-02")
+	}
+	return response
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"worklio-api/internal/models"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HealthHandler struct {
+	exchangeService *services.ExchangeRateService
+}
+
+func NewHealthHandler(exchangeService *services.ExchangeRateService) *HealthHandler {
+	return &HealthHandler{
+		exchangeService: exchangeService,
+	}
+}
+
+// GetHealth godoc
+// @Summary Health check
+// @Description Reports whether the API is up. The status is "degraded" when exchange rates are missing or older than the configured maximum age; the API keeps serving requests, but converted totals may be incomplete or out of date.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Router /health [get]
+func (h *HealthHandler) GetHealth(c echo.Context) error {
+	response := models.HealthResponse{
+		Status: "ok",
+		ExchangeRates: models.ExchangeRatesHealth{
+			Status: "ok",
+			MaxAge: h.exchangeService.MaxAge().String(),
+		},
+	}
+
+	status, err := h.exchangeService.Status(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("Failed to check exchange rates: %v", err)
+		response.Status = "degraded"
+		response.ExchangeRates.Status = "unknown"
+		return c.JSON(http.StatusOK, response)
+	}
+
+	if !status.UpdatedAt.IsZero() {
+		response.ExchangeRates.UpdatedAt = status.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	response.ExchangeRates.Missing = status.Missing
+	if status.Stale {
+		response.Status = "degraded"
+		response.ExchangeRates.Status = "stale"
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
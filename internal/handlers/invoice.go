@@ -2,32 +2,39 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"worklio-api/internal/billing"
 	"worklio-api/internal/db"
+	"worklio-api/internal/einvoice"
 	"worklio-api/internal/models"
-	"worklio-api/internal/utils"
+	"worklio-api/internal/money"
+	"worklio-api/internal/pdf"
+	"worklio-api/internal/services"
 
-	"github.com/jung-kurt/gofpdf"
 	"github.com/labstack/echo/v4"
 )
 
 type InvoiceHandler struct {
-	queries *db.Queries
+	queries        *db.Queries
+	invoiceService *services.InvoiceService
 }
 
-func NewInvoiceHandler(queries *db.Queries) *InvoiceHandler {
+func NewInvoiceHandler(queries *db.Queries, invoiceService *services.InvoiceService) *InvoiceHandler {
 	return &InvoiceHandler{
-		queries: queries,
+		queries:        queries,
+		invoiceService: invoiceService,
 	}
 }
 
 // CreateInvoice godoc
 // @Summary Create a new invoice
-// @Description Create a new invoice with time entries for the authenticated user
+// @Description Create a new invoice with time entries and/or line items for the authenticated user. Only billable time entries can be invoiced. Without an invoice number, the invoice is numbered from the invoice numbering pattern when it leaves draft.
 // @Tags invoices
 // @Accept json
 // @Produce json
@@ -36,6 +43,7 @@ func NewInvoiceHandler(queries *db.Queries) *InvoiceHandler {
 // @Success 201 {object} models.InvoiceResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/invoices [post]
 func (h *InvoiceHandler) CreateInvoice(c echo.Context) error {
@@ -57,32 +65,50 @@ func (h *InvoiceHandler) CreateInvoice(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid due date format. Use YYYY-MM-DD"})
 	}
 
-	// Create invoice
-	invoice, err := h.queries.CreateInvoice(c.Request().Context(), db.CreateInvoiceParams{
-		UserID:        userID,
+	if req.Status == "partially_paid" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Status partially_paid is set automatically when payments are recorded"})
+	}
+
+	if len(req.TimeEntryIDs) == 0 && len(req.LineItems) == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invoice must include at least one time entry or line item"})
+	}
+
+	if msg := validateLineItems(req.LineItems); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	// Create the invoice with its time entries, line items and taxes
+	invoice, err := h.invoiceService.CreateInvoice(c.Request().Context(), userID, services.NewInvoice{
 		ClientID:      req.ClientID,
-		InvoiceNumber: req.InvoiceNumber,
+		InvoiceNumber: strings.TrimSpace(req.InvoiceNumber),
 		IssueDate:     issueDate,
 		DueDate:       dueDate,
 		Status:        req.Status,
-		Notes:         sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		Notes:         req.Notes,
+		TimeEntryIDs:  req.TimeEntryIDs,
+		LineItems:     req.LineItems,
+		TaxRateIDs:    req.TaxRateIDs,
 	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create invoice"})
-	}
-
-	// Add time entries to invoice
-	for _, timeEntryID := range req.TimeEntryIDs {
-		err := h.queries.AddTimeEntryToInvoice(c.Request().Context(), db.AddTimeEntryToInvoiceParams{
-			InvoiceID:   invoice.ID,
-			TimeEntryID: timeEntryID,
-		})
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to add time entries to invoice"})
+		if errors.Is(err, services.ErrTaxRateNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Tax rate not found"})
+		}
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
 		}
+		if errors.Is(err, services.ErrTimeEntryNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Time entry not found"})
+		}
+		if errors.Is(err, services.ErrTimeEntryNotBillable) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Non-billable time entries cannot be invoiced"})
+		}
+		if errors.Is(err, services.ErrInvoiceNumberTaken) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Invoice number already exists"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create invoice"})
 	}
 
-	// Get the complete invoice with time entries
+	// Get the complete invoice with time entries and line items
 	return h.getInvoiceResponse(c, invoice.ID, userID)
 }
 
@@ -106,13 +132,13 @@ func (h *InvoiceHandler) GetInvoices(c echo.Context) error {
 
 	response := make([]models.InvoiceResponse, len(invoices))
 	for i, invoice := range invoices {
-		// Get time entries for this invoice
-		timeEntries, err := h.queries.GetInvoiceTimeEntries(c.Request().Context(), invoice.ID)
+		// Get time entries, line items and taxes for this invoice
+		contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice time entries"})
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice contents"})
 		}
 
-		response[i] = h.buildInvoiceResponseWithClient(invoice, timeEntries)
+		response[i] = buildInvoiceResponse(invoice, contents)
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -144,7 +170,7 @@ func (h *InvoiceHandler) GetInvoice(c echo.Context) error {
 
 // UpdateInvoice godoc
 // @Summary Update an invoice
-// @Description Update an invoice's information
+// @Description Update a draft invoice's information. Invoices that have left draft are read-only and must be revised instead.
 // @Tags invoices
 // @Accept json
 // @Produce json
@@ -155,6 +181,7 @@ func (h *InvoiceHandler) GetInvoice(c echo.Context) error {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/invoices/{id} [put]
 func (h *InvoiceHandler) UpdateInvoice(c echo.Context) error {
@@ -181,20 +208,39 @@ func (h *InvoiceHandler) UpdateInvoice(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid due date format. Use YYYY-MM-DD"})
 	}
 
-	invoice, err := h.queries.UpdateInvoice(c.Request().Context(), db.UpdateInvoiceParams{
-		ID:            int32(id),
-		UserID:        userID,
+	if req.LineItems != nil {
+		if msg := validateLineItems(*req.LineItems); msg != "" {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+		}
+	}
+
+	// Invoices that have left draft can only be changed by revising them
+	invoice, err := h.invoiceService.UpdateInvoice(c.Request().Context(), userID, int32(id), services.InvoiceUpdate{
 		ClientID:      req.ClientID,
-		InvoiceNumber: req.InvoiceNumber,
+		InvoiceNumber: strings.TrimSpace(req.InvoiceNumber),
 		IssueDate:     issueDate,
 		DueDate:       dueDate,
 		Status:        req.Status,
-		Notes:         sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		Notes:         req.Notes,
+		LineItems:     req.LineItems,
+		TaxRateIDs:    req.TaxRateIDs,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
 		}
+		if errors.Is(err, services.ErrInvoiceLocked) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Invoice has been issued and can no longer be edited. Revise it instead"})
+		}
+		if errors.Is(err, services.ErrTaxRateNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Tax rate not found"})
+		}
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		if errors.Is(err, services.ErrInvoiceNumberTaken) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Invoice number already exists"})
+		}
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update invoice"})
 	}
 
@@ -203,7 +249,7 @@ func (h *InvoiceHandler) UpdateInvoice(c echo.Context) error {
 
 // UpdateInvoiceStatus godoc
 // @Summary Update invoice status
-// @Description Update only the status of an invoice
+// @Description Update only the status of an invoice. Use the payments endpoints to record partial payments.
 // @Tags invoices
 // @Accept json
 // @Produce json
@@ -214,6 +260,7 @@ func (h *InvoiceHandler) UpdateInvoice(c echo.Context) error {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/invoices/{id}/status [patch]
 func (h *InvoiceHandler) UpdateInvoiceStatus(c echo.Context) error {
@@ -229,15 +276,18 @@ func (h *InvoiceHandler) UpdateInvoiceStatus(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
 	}
 
-	invoice, err := h.queries.UpdateInvoiceStatus(c.Request().Context(), db.UpdateInvoiceStatusParams{
-		ID:     int32(id),
-		UserID: userID,
-		Status: req.Status,
-	})
+	if req.Status == "partially_paid" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Status partially_paid is set automatically when payments are recorded"})
+	}
+
+	invoice, err := h.invoiceService.UpdateInvoiceStatus(c.Request().Context(), userID, int32(id), req.Status)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
 		}
+		if errors.Is(err, services.ErrInvoiceIssued) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Issued invoices cannot be moved back to draft"})
+		}
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update invoice status"})
 	}
 
@@ -246,7 +296,7 @@ func (h *InvoiceHandler) UpdateInvoiceStatus(c echo.Context) error {
 
 // DeleteInvoice godoc
 // @Summary Delete an invoice
-// @Description Delete an invoice by ID
+// @Description Delete a draft invoice by ID. Invoices that have left draft cannot be deleted; issue a credit note instead.
 // @Tags invoices
 // @Produce json
 // @Security BearerAuth
@@ -254,6 +304,8 @@ func (h *InvoiceHandler) UpdateInvoiceStatus(c echo.Context) error {
 // @Success 204 "No Content"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/invoices/{id} [delete]
 func (h *InvoiceHandler) DeleteInvoice(c echo.Context) error {
@@ -264,7 +316,7 @@ func (h *InvoiceHandler) DeleteInvoice(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
 	}
 
-	err = h.queries.DeleteInvoice(c.Request().Context(), db.DeleteInvoiceParams{
+	deleted, err := h.queries.DeleteInvoice(c.Request().Context(), db.DeleteInvoiceParams{
 		ID:     int32(id),
 		UserID: userID,
 	})
@@ -272,12 +324,27 @@ func (h *InvoiceHandler) DeleteInvoice(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete invoice"})
 	}
 
+	// Nothing was deleted: the invoice is missing or has left draft
+	if deleted == 0 {
+		_, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+			ID:     int32(id),
+			UserID: userID,
+		})
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+		}
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Issued invoices cannot be deleted. Issue a credit note instead"})
+	}
+
 	return c.NoContent(http.StatusNoContent)
 }
 
 // GetAvailableTimeEntries godoc
 // @Summary Get available time entries for invoicing
-// @Description Get time entries for a client that haven't been invoiced yet
+// @Description Get billable time entries for a client that haven't been invoiced yet
 // @Tags invoices
 // @Produce json
 // @Security BearerAuth
@@ -310,14 +377,16 @@ func (h *InvoiceHandler) GetAvailableTimeEntries(c echo.Context) error {
 
 	response := make([]models.TimeEntryResponse, len(timeEntries))
 	for i, entry := range timeEntries {
-		hours, _ := strconv.ParseFloat(entry.Hours, 64)
 		response[i] = models.TimeEntryResponse{
 			ID:          entry.ID,
 			UserID:      entry.UserID,
 			ClientID:    entry.ClientID,
+			ProjectID:   entry.ProjectID.Int32,
+			TaskID:      entry.TaskID.Int32,
 			Date:        entry.Date.Format("2006-01-02"),
-			Hours:       hours,
+			Hours:       money.Parse(entry.Hours),
 			Description: entry.Description.String,
+			Billable:    entry.Billable,
 			CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 		}
@@ -326,6 +395,140 @@ func (h *InvoiceHandler) GetAvailableTimeEntries(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// GetNextInvoiceNumber godoc
+// @Summary Preview the next invoice number
+// @Description Get the number the next invoice for a client would be given by the invoice numbering pattern. The number is not reserved.
+// @Tags invoices
+// @Produce json
+// @Security BearerAuth
+// @Param client_id query int true "Client ID"
+// @Param issue_date query string false "Issue date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.NextInvoiceNumberResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/next-number [get]
+func (h *InvoiceHandler) GetNextInvoiceNumber(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	clientIDStr := c.QueryParam("client_id")
+	if clientIDStr == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "client_id query parameter is required"})
+	}
+
+	clientID, err := strconv.ParseInt(clientIDStr, 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid client ID"})
+	}
+
+	issueDate := time.Now().UTC()
+	if issueDateStr := c.QueryParam("issue_date"); issueDateStr != "" {
+		issueDate, err = time.Parse("2006-01-02", issueDateStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid issue date format. Use YYYY-MM-DD"})
+		}
+	}
+
+	number, err := h.invoiceService.PreviewInvoiceNumber(c.Request().Context(), userID, int32(clientID), issueDate)
+	if err != nil {
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to preview invoice number"})
+	}
+
+	return c.JSON(http.StatusOK, models.NextInvoiceNumberResponse{InvoiceNumber: number})
+}
+
+// GetInvoiceNumbering godoc
+// @Summary Get invoice numbering
+// @Description Get the patterns used to number invoices created without an invoice number, credit notes and estimates
+// @Tags invoices
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.InvoiceNumberingResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoice-numbering [get]
+func (h *InvoiceHandler) GetInvoiceNumbering(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	patterns, err := h.queries.GetUserNumberPatterns(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice numbering"})
+	}
+
+	return c.JSON(http.StatusOK, models.InvoiceNumberingResponse{
+		Pattern:           patterns.InvoiceNumberPattern,
+		CreditNotePattern: patterns.CreditNoteNumberPattern,
+		EstimatePattern:   patterns.EstimateNumberPattern,
+	})
+}
+
+// UpdateInvoiceNumbering godoc
+// @Summary Update invoice numbering
+// @Description Set the invoice number pattern and, optionally, the credit note and estimate number patterns. Placeholders: {YYYY}, {YY}, {MM}, {PREFIX} (the client's invoice prefix) and {SEQ} or {SEQ:n} for a sequence zero-padded to n digits. The sequence restarts whenever the rest of the number changes, e.g. every year with {YYYY}.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateInvoiceNumberingRequest true "Invoice Numbering"
+// @Success 200 {object} models.InvoiceNumberingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoice-numbering [put]
+func (h *InvoiceHandler) UpdateInvoiceNumbering(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.UpdateInvoiceNumberingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	pattern := billing.NumberPattern(strings.TrimSpace(req.Pattern))
+	if err := pattern.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid pattern: " + err.Error()})
+	}
+
+	current, err := h.queries.GetUserNumberPatterns(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice numbering"})
+	}
+
+	creditNotePattern := billing.NumberPattern(current.CreditNoteNumberPattern)
+	if trimmed := strings.TrimSpace(req.CreditNotePattern); trimmed != "" {
+		creditNotePattern = billing.NumberPattern(trimmed)
+		if err := creditNotePattern.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid credit note pattern: " + err.Error()})
+		}
+	}
+
+	estimatePattern := billing.NumberPattern(current.EstimateNumberPattern)
+	if trimmed := strings.TrimSpace(req.EstimatePattern); trimmed != "" {
+		estimatePattern = billing.NumberPattern(trimmed)
+		if err := estimatePattern.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid estimate pattern: " + err.Error()})
+		}
+	}
+
+	updated, err := h.queries.UpdateUserNumberPatterns(c.Request().Context(), db.UpdateUserNumberPatternsParams{
+		ID:                      userID,
+		InvoiceNumberPattern:    string(pattern),
+		CreditNoteNumberPattern: string(creditNotePattern),
+		EstimateNumberPattern:   string(estimatePattern),
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update invoice numbering"})
+	}
+
+	return c.JSON(http.StatusOK, models.InvoiceNumberingResponse{
+		Pattern:           updated.InvoiceNumberPattern,
+		CreditNotePattern: updated.CreditNoteNumberPattern,
+		EstimatePattern:   updated.EstimateNumberPattern,
+	})
+}
+
 // Helper functions
 func (h *InvoiceHandler) getInvoiceResponse(c echo.Context, invoiceID int32, userID int32) error {
 	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
@@ -339,67 +542,184 @@ func (h *InvoiceHandler) getInvoiceResponse(c echo.Context, invoiceID int32, use
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
 	}
 
-	timeEntries, err := h.queries.GetInvoiceTimeEntries(c.Request().Context(), invoiceID)
+	contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoiceID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice time entries"})
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice contents"})
 	}
 
-	response := h.buildInvoiceResponseWithClient(invoice, timeEntries)
+	response := buildInvoiceResponse(invoice, contents)
 	return c.JSON(http.StatusOK, response)
 }
 
-func (h *InvoiceHandler) buildInvoiceResponseWithClient(invoice db.Invoice, timeEntries []db.GetInvoiceTimeEntriesRow) models.InvoiceResponse {
-	timeEntryResponses := make([]models.TimeEntryResponse, len(timeEntries))
-	totalHours := 0.0
-	totalAmount := 0.0
+func buildInvoiceResponse(invoice db.Invoice, contents billing.Contents) models.InvoiceResponse {
+	totals := billing.IssuedTotals(invoice, contents)
 
-	for i, entry := range timeEntries {
-		hours, _ := strconv.ParseFloat(entry.Hours, 64)
-		hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
-		totalHours += hours
-		totalAmount += hours * hourlyRate
+	return models.InvoiceResponse{
+		ID:                 invoice.ID,
+		UserID:             invoice.UserID,
+		ClientID:           invoice.ClientID,
+		InvoiceNumber:      invoice.InvoiceNumber,
+		IssueDate:          invoice.IssueDate.Format("2006-01-02"),
+		DueDate:            invoice.DueDate.Format("2006-01-02"),
+		Status:             invoice.Status,
+		Notes:              invoice.Notes.String,
+		TimeEntries:        buildInvoiceTimeEntryResponses(contents.TimeEntries),
+		Projects:           buildInvoiceProjectResponses(contents.TimeEntries, contents.Currency),
+		LineItems:          buildLineItemResponses(contents.LineItems, contents.Currency),
+		TotalHours:         totals.Hours,
+		Subtotal:           totals.Subtotal,
+		Taxes:              buildInvoiceTaxResponses(totals.Taxes),
+		TaxAmount:          totals.Tax,
+		Withholding:        totals.Withholding,
+		TotalAmount:        totals.Total,
+		AmountPaid:         totals.Paid,
+		AmountCredited:     totals.Credited,
+		BalanceDue:         totals.Balance,
+		ReverseCharge:      invoice.ReverseCharge,
+		ExchangeRate:       parseNullFloat(invoice.ExchangeRate),
+		ExchangeRateSource: invoice.ExchangeRateSource.String,
+		ReportingCurrency:  invoice.ReportingCurrency.String,
+		OverdueAt:          formatNullTime(invoice.OverdueAt),
+		CreatedAt:          invoice.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:          invoice.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// parseNullFloat parses a numeric column, or returns 0 if it is null
+func parseNullFloat(s sql.NullString) float64 {
+	if !s.Valid {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(s.String, 64)
+	return f
+}
+
+// formatNullTime formats t as a timestamp, or returns an empty string if t
+// is null
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02T15:04:05Z")
+}
 
-		timeEntryResponses[i] = models.TimeEntryResponse{
+func buildInvoiceTimeEntryResponses(timeEntries []db.GetInvoiceTimeEntriesRow) []models.TimeEntryResponse {
+	responses := make([]models.TimeEntryResponse, len(timeEntries))
+	for i, entry := range timeEntries {
+		responses[i] = models.TimeEntryResponse{
 			ID:          entry.ID,
 			UserID:      entry.UserID,
 			ClientID:    entry.ClientID,
+			ProjectID:   entry.ProjectID.Int32,
+			ProjectName: entry.ProjectName.String,
+			TaskID:      entry.TaskID.Int32,
 			Date:        entry.Date.Format("2006-01-02"),
-			Hours:       hours,
+			Hours:       money.Parse(entry.Hours),
 			Description: entry.Description.String,
-			HourlyRate:  hourlyRate,
+			HourlyRate:  money.ParseNull(entry.HourlyRate),
+			Billable:    entry.Billable,
 			CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 		}
 	}
+	return responses
+}
 
-	return models.InvoiceResponse{
-		ID:            invoice.ID,
-		UserID:        invoice.UserID,
-		ClientID:      invoice.ClientID,
-		InvoiceNumber: invoice.InvoiceNumber,
-		IssueDate:     invoice.IssueDate.Format("2006-01-02"),
-		DueDate:       invoice.DueDate.Format("2006-01-02"),
-		Status:        invoice.Status,
-		Notes:         invoice.Notes.String,
-		TimeEntries:   timeEntryResponses,
-		TotalHours:    totalHours,
-		TotalAmount:   totalAmount,
-		CreatedAt:     invoice.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:     invoice.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+// buildInvoiceProjectResponses sums the time entries of an invoice by
+// project, with amounts rounded to the minor unit of currency
+func buildInvoiceProjectResponses(timeEntries []db.GetInvoiceTimeEntriesRow, currency string) []models.InvoiceProjectResponse {
+	groups := billing.GroupByProject(billing.BuildLines(timeEntries, nil))
+	responses := make([]models.InvoiceProjectResponse, len(groups))
+	next := 0
+	for i, group := range groups {
+		ids := make([]int32, len(group.Lines))
+		for j := range group.Lines {
+			// Lines are built from the time entries in order
+			ids[j] = timeEntries[next].ID
+			next++
+		}
+		responses[i] = models.InvoiceProjectResponse{
+			ProjectID:    group.ProjectID,
+			Name:         group.Name,
+			Hours:        group.Hours(),
+			Amount:       money.Round(group.Amount(), currency),
+			TimeEntryIDs: ids,
+		}
+	}
+	return responses
+}
+
+// buildLineItemResponses converts line items to responses, with amounts
+// rounded to the minor unit of currency
+func buildLineItemResponses(lineItems []db.InvoiceLineItem, currency string) []models.InvoiceLineItemResponse {
+	lines := billing.BuildLines(nil, lineItems)
+	responses := make([]models.InvoiceLineItemResponse, len(lineItems))
+	for i, item := range lineItems {
+		responses[i] = models.InvoiceLineItemResponse{
+			ID:          item.ID,
+			Description: item.Description,
+			Quantity:    lines[i].Quantity,
+			Unit:        item.Unit,
+			UnitPrice:   lines[i].UnitPrice,
+			TaxRate:     lines[i].TaxRate,
+			Amount:      money.Round(lines[i].Amount, currency),
+			TaxAmount:   money.Round(lines[i].TaxAmount(), currency),
+		}
+	}
+	return responses
+}
+
+func buildInvoiceTaxResponses(taxes []billing.AppliedTax) []models.InvoiceTaxResponse {
+	responses := make([]models.InvoiceTaxResponse, len(taxes))
+	for i, tax := range taxes {
+		responses[i] = models.InvoiceTaxResponse{
+			Name:        tax.Name,
+			Rate:        tax.Rate,
+			Compound:    tax.Compound,
+			Withholding: tax.Withholding,
+			Basis:       tax.Basis,
+			Amount:      tax.Amount,
+		}
+	}
+	return responses
+}
+
+// validateLineItems returns an error message for the first invalid line item,
+// or an empty string when all are valid
+func validateLineItems(lineItems []models.InvoiceLineItemRequest) string {
+	for i, item := range lineItems {
+		if strings.TrimSpace(item.Description) == "" {
+			return fmt.Sprintf("Line item %d: description is required", i+1)
+		}
+		if !item.Quantity.IsPositive() {
+			return fmt.Sprintf("Line item %d: quantity must be greater than 0", i+1)
+		}
+		if item.Unit != "" && !billing.IsValidLineItemUnit(item.Unit) {
+			return fmt.Sprintf("Line item %d: unit must be one of: unit, hour, day, month", i+1)
+		}
+		if item.UnitPrice.IsNegative() {
+			return fmt.Sprintf("Line item %d: unit price cannot be negative", i+1)
+		}
+		if item.TaxRate.IsNegative() || item.TaxRate.GreaterThan(maxTaxRate) {
+			return fmt.Sprintf("Line item %d: tax rate must be between 0 and 100", i+1)
+		}
 	}
+	return ""
 }
 
 // DownloadInvoicePDF godoc
 // @Summary Download invoice as PDF
-// @Description Download an invoice as a PDF file
+// @Description Download an invoice as a PDF file. With format=facturx the PDF embeds a Factur-X (EN 16931) XML invoice.
 // @Tags invoices
 // @Produce application/pdf
 // @Security BearerAuth
 // @Param id path int true "Invoice ID"
+// @Param format query string false "PDF format" Enums(standard, facturx) default(standard)
 // @Success 200 {file} binary
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/invoices/{id}/pdf [get]
 func (h *InvoiceHandler) DownloadInvoicePDF(c echo.Context) error {
@@ -410,273 +730,46 @@ func (h *InvoiceHandler) DownloadInvoicePDF(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
 	}
 
-	// Get invoice data
-	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
-		ID:     int32(id),
-		UserID: userID,
-	})
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "standard"
+	}
+	if format != "standard" && format != "facturx" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid format. Must be one of: standard, facturx"})
+	}
+
+	doc, err := billing.LoadDocument(c.Request().Context(), h.queries, userID, int32(id))
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
 		}
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice data"})
 	}
 
-	// Get client data
-	client, err := h.queries.GetClientByID(c.Request().Context(), db.GetClientByIDParams{
-		ID:     invoice.ClientID,
-		UserID: userID,
-	})
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch client data"})
-	}
+	invoicePDF := pdf.RenderInvoice(doc)
 
-	// Get time entries
-	timeEntries, err := h.queries.GetInvoiceTimeEntries(c.Request().Context(), int32(id))
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch time entries"})
-	}
-
-	// Use client's currency for invoice
-	currency := client.Currency
-	if currency == "" {
-		currency = "USD" // Default fallback
-	}
-
-	// Generate PDF
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetMargins(20, 20, 20)
-	pdf.SetAutoPageBreak(true, 20)
-
-	// Header Section with Blue Background
-	pdf.SetFillColor(30, 58, 138) // blue-900
-	pdf.Rect(0, 0, 210, 50, "F")
-
-	// Invoice Title
-	pdf.SetTextColor(255, 255, 255)
-	pdf.SetFont("Arial", "B", 32)
-	pdf.SetY(15)
-	pdf.Cell(0, 10, "INVOICE")
-	pdf.Ln(12)
-
-	// Invoice Number
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(0, 8, invoice.InvoiceNumber)
-	pdf.Ln(20)
-
-	// Reset text color for body
-	pdf.SetTextColor(0, 0, 0)
-
-	// Two column layout for Bill To and Invoice Details
-	leftX := 20.0
-	rightX := 115.0
-	currentY := pdf.GetY()
-
-	// Left Column - Bill To Section
-	pdf.SetXY(leftX, currentY)
-	pdf.SetFillColor(241, 245, 249) // slate-100
-	pdf.Rect(leftX, currentY, 85, 45, "F")
-
-	pdf.SetXY(leftX+5, currentY+5)
-	pdf.SetFont("Arial", "B", 10)
-	pdf.SetTextColor(100, 116, 139) // slate-500
-	pdf.Cell(0, 5, "BILL TO")
-	pdf.Ln(7)
-
-	pdf.SetX(leftX+5)
-	pdf.SetFont("Arial", "B", 12)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.Cell(0, 6, client.Name)
-	pdf.Ln(6)
-
-	pdf.SetX(leftX+5)
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetTextColor(71, 85, 105) // slate-600
-	pdf.Cell(0, 5, client.Email)
-	pdf.Ln(5)
-
-	if client.Company.Valid && client.Company.String != "" {
-		pdf.SetX(leftX+5)
-		pdf.Cell(0, 5, client.Company.String)
-		pdf.Ln(5)
-	}
-
-	// Right Column - Invoice Details
-	pdf.SetXY(rightX, currentY)
-	pdf.SetFillColor(241, 245, 249) // slate-100
-	pdf.Rect(rightX, currentY, 75, 45, "F")
-
-	pdf.SetXY(rightX+5, currentY+5)
-	pdf.SetFont("Arial", "B", 10)
-	pdf.SetTextColor(100, 116, 139) // slate-500
-	pdf.Cell(0, 5, "INVOICE DETAILS")
-	pdf.Ln(7)
-
-	// Status
-	pdf.SetX(rightX+5)
-	pdf.SetFont("Arial", "B", 9)
-	pdf.SetTextColor(71, 85, 105)
-	pdf.Cell(25, 5, "Status:")
-	pdf.SetFont("Arial", "", 9)
-	statusColor := getStatusColorRGB(invoice.Status)
-	pdf.SetTextColor(statusColor[0], statusColor[1], statusColor[2])
-	pdf.Cell(0, 5, invoice.Status)
-	pdf.Ln(5)
-
-	// Issue Date
-	pdf.SetX(rightX+5)
-	pdf.SetFont("Arial", "B", 9)
-	pdf.SetTextColor(71, 85, 105)
-	pdf.Cell(25, 5, "Issue Date:")
-	pdf.SetFont("Arial", "", 9)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.Cell(0, 5, invoice.IssueDate.Format("Jan 2, 2006"))
-	pdf.Ln(5)
-
-	// Due Date
-	pdf.SetX(rightX+5)
-	pdf.SetFont("Arial", "B", 9)
-	pdf.SetTextColor(71, 85, 105)
-	pdf.Cell(25, 5, "Due Date:")
-	pdf.SetFont("Arial", "", 9)
-	if invoice.Status == "overdue" {
-		pdf.SetTextColor(239, 68, 68) // red
-	} else {
-		pdf.SetTextColor(0, 0, 0)
-	}
-	pdf.Cell(0, 5, invoice.DueDate.Format("Jan 2, 2006"))
-
-	pdf.SetTextColor(0, 0, 0)
-	pdf.SetY(currentY + 50)
-	pdf.Ln(10)
-
-	// Line Items Header
-	pdf.SetFont("Arial", "B", 12)
-	pdf.SetTextColor(30, 58, 138) // blue-900
-	pdf.Cell(0, 8, "Line Items")
-	pdf.Ln(10)
-
-	// Table Header with colored background
-	pdf.SetFillColor(30, 58, 138) // blue-900
-	pdf.SetTextColor(255, 255, 255)
-	pdf.SetFont("Arial", "B", 9)
-	pdf.SetDrawColor(30, 58, 138)
-
-	pdf.CellFormat(30, 9, "DATE", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(68, 9, "DESCRIPTION", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(22, 9, "HOURS", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(22, 9, "RATE", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(28, 9, "AMOUNT", "1", 0, "R", true, 0, "")
-	pdf.Ln(-1)
-
-	// Table Body with alternating row colors
-	pdf.SetFont("Arial", "", 9)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.SetDrawColor(226, 232, 240) // slate-200
-	totalHours := 0.0
-	totalAmount := 0.0
-	hourlyRateFloat, _ := strconv.ParseFloat(client.HourlyRate.String, 64)
-
-	rowIndex := 0
-	for _, entry := range timeEntries {
-		hours, _ := strconv.ParseFloat(entry.Hours, 64)
-		amount := hours * hourlyRateFloat
-		totalHours += hours
-		totalAmount += amount
-
-		description := entry.Description.String
-		if description == "" {
-			description = "No description"
-		}
-
-		// Truncate long descriptions
-		if len(description) > 40 {
-			description = description[:37] + "..."
-		}
-
-		// Alternate row colors
-		if rowIndex%2 == 0 {
-			pdf.SetFillColor(248, 250, 252) // slate-50
-		} else {
-			pdf.SetFillColor(255, 255, 255) // white
-		}
-
-		pdf.CellFormat(30, 8, entry.Date.Format("Jan 2, 2006"), "1", 0, "L", true, 0, "")
-		pdf.CellFormat(68, 8, description, "1", 0, "L", true, 0, "")
-		pdf.CellFormat(22, 8, utils.FormatNumber(hours, 2), "1", 0, "C", true, 0, "")
-		pdf.CellFormat(22, 8, utils.FormatCurrencyRateForPDF(hourlyRateFloat, currency), "1", 0, "C", true, 0, "")
-		pdf.SetFont("Arial", "B", 9)
-		pdf.CellFormat(28, 8, utils.FormatCurrencyForPDF(amount, currency), "1", 0, "R", true, 0, "")
-		pdf.SetFont("Arial", "", 9)
-		pdf.Ln(-1)
-		rowIndex++
-	}
-
-	// Subtotal Section
-	pdf.SetDrawColor(30, 58, 138)
-	pdf.SetLineWidth(0.5)
-	pdf.Line(20, pdf.GetY(), 190, pdf.GetY())
-	pdf.Ln(3)
-
-	// Summary box on the right - aligned with HOURS, RATE, AMOUNT columns
-	// Table structure: 30 (DATE) + 68 (DESC) = 98mm, then HOURS(22) + RATE(22) + AMOUNT(28) = 72mm
-	summaryLabelX := 118.0  // Start where HOURS column starts (20 + 30 + 68)
-	summaryValueX := 162.0  // Start where AMOUNT column starts (20 + 30 + 68 + 22 + 22)
-	summaryY := pdf.GetY()
-
-	// Total Hours Row
-	pdf.SetXY(summaryLabelX, summaryY)
-	pdf.SetFont("Arial", "B", 10)
-	pdf.SetTextColor(71, 85, 105) // slate-600
-	pdf.CellFormat(44, 7, "Total Hours:", "", 0, "L", false, 0, "")
-	pdf.SetXY(summaryValueX, summaryY)
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.CellFormat(28, 7, utils.FormatNumber(totalHours, 2), "", 0, "R", false, 0, "")
-	pdf.Ln(9)
-
-	// Total Amount with colored background
-	pdf.SetX(summaryLabelX)
-	pdf.SetFillColor(30, 58, 138) // blue-900
-	pdf.SetTextColor(255, 255, 255)
-	pdf.SetFont("Arial", "B", 12)
-	pdf.CellFormat(44, 10, "TOTAL:", "1", 0, "L", true, 0, "")
-	pdf.SetFont("Arial", "B", 14)
-	pdf.CellFormat(28, 10, utils.FormatCurrencyForPDF(totalAmount, currency), "1", 0, "R", true, 0, "")
-	pdf.Ln(15)
-
-	// Notes Section
-	pdf.SetTextColor(0, 0, 0)
-	if invoice.Notes.Valid && invoice.Notes.String != "" {
-		pdf.SetFont("Arial", "B", 11)
-		pdf.SetTextColor(30, 58, 138) // blue-900
-		pdf.Cell(0, 8, "Notes")
-		pdf.Ln(8)
-
-		pdf.SetFont("Arial", "", 10)
-		pdf.SetTextColor(71, 85, 105) // slate-600
-		pdf.SetFillColor(248, 250, 252) // slate-50
-
-		// Draw background for notes
-		noteY := pdf.GetY()
-		pdf.Rect(20, noteY, 170, -1, "F") // Will be auto-sized
-		pdf.MultiCell(170, 6, invoice.Notes.String, "", "L", false)
-		pdf.Ln(5)
-	}
-
-	// Footer
-	pdf.SetY(-30)
-	pdf.SetFont("Arial", "I", 9)
-	pdf.SetTextColor(148, 163, 184) // slate-400
-	pdf.CellFormat(0, 10, "Thank you for your business!", "", 0, "C", false, 0, "")
+	var xml []byte
+	if format == "facturx" {
+		xml, err = einvoice.BuildCII(doc)
+		if err != nil {
+			var validationErr *einvoice.ValidationError
+			if errors.As(err, &validationErr) {
+				return c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{Error: validationErr.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate Factur-X XML"})
+		}
+	}
 
 	// Generate PDF and return as response
-	filename := fmt.Sprintf("%s.pdf", invoice.InvoiceNumber)
+	filename := invoiceFilename(doc.Invoice, "pdf")
 	c.Response().Header().Set("Content-Type", "application/pdf")
 	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
 
-	err = pdf.Output(c.Response().Writer)
+	if xml != nil {
+		err = pdf.OutputFacturX(c.Response().Writer, invoicePDF, doc, xml)
+	} else {
+		err = invoicePDF.Output(c.Response().Writer)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate PDF"})
 	}
@@ -684,17 +777,56 @@ func (h *InvoiceHandler) DownloadInvoicePDF(c echo.Context) error {
 	return nil
 }
 
-func getStatusColorRGB(status string) [3]int {
-	switch status {
-	case "draft":
-		return [3]int{107, 114, 128} // gray
-	case "sent":
-		return [3]int{59, 130, 246} // blue
-	case "paid":
-		return [3]int{34, 197, 94} // green
-	case "overdue":
-		return [3]int{239, 68, 68} // red
-	default:
-		return [3]int{107, 114, 128} // gray
+// DownloadInvoiceUBL godoc
+// @Summary Download invoice as UBL
+// @Description Download an invoice as a Peppol BIS Billing 3.0 UBL 2.1 XML document
+// @Tags invoices
+// @Produce application/xml
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/ubl [get]
+func (h *InvoiceHandler) DownloadInvoiceUBL(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	doc, err := billing.LoadDocument(c.Request().Context(), h.queries, userID, int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice data"})
+	}
+
+	xml, err := einvoice.BuildUBL(doc)
+	if err != nil {
+		var validationErr *einvoice.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{Error: validationErr.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate UBL XML"})
+	}
+
+	filename := invoiceFilename(doc.Invoice, "xml")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, xml)
+}
+
+// invoiceFilename names a downloaded invoice after its number. Drafts are
+// not numbered yet and are named after their ID instead.
+func invoiceFilename(invoice db.Invoice, extension string) string {
+	if invoice.InvoiceNumber == "" {
+		return fmt.Sprintf("draft-%d.%s", invoice.ID, extension)
 	}
+	return fmt.Sprintf("%s.%s", invoice.InvoiceNumber, extension)
 }
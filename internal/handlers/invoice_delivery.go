@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxInvoiceCc is the maximum number of CC recipients of an invoice email
+const maxInvoiceCc = 10
+
+type InvoiceDeliveryHandler struct {
+	queries         *db.Queries
+	deliveryService *services.DeliveryService
+}
+
+func NewInvoiceDeliveryHandler(queries *db.Queries, deliveryService *services.DeliveryService) *InvoiceDeliveryHandler {
+	return &InvoiceDeliveryHandler{
+		queries:         queries,
+		deliveryService: deliveryService,
+	}
+}
+
+// SendInvoice godoc
+// @Summary Send an invoice by email
+// @Description Email the invoice PDF to the client, optionally with CC recipients and a custom message. A draft invoice becomes sent. Every delivery is recorded with its Message-ID.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body models.SendInvoiceRequest false "Send Invoice Request"
+// @Success 201 {object} models.InvoiceDeliveryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/send [post]
+func (h *InvoiceDeliveryHandler) SendInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	if h.deliveryService == nil {
+		return c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Email service is not configured"})
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	var req models.SendInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	cc, msg := parseCcAddresses(req.Cc)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	delivery, err := h.deliveryService.SendInvoice(c.Request().Context(), userID, int32(id), services.SendOptions{
+		Cc:      cc,
+		Message: req.Message,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		case errors.Is(err, services.ErrInvoiceCancelled):
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Cancelled invoices cannot be sent"})
+		case errors.Is(err, services.ErrNoClientEmail):
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client has no email address"})
+		}
+		c.Logger().Error("Failed to send invoice: ", err)
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to send invoice"})
+	}
+
+	return c.JSON(http.StatusCreated, invoiceDeliveryToResponse(delivery))
+}
+
+// GetInvoiceDeliveries godoc
+// @Summary Get invoice deliveries
+// @Description Get every email delivery of an invoice, most recent first
+// @Tags invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} models.InvoiceDeliveryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/deliveries [get]
+func (h *InvoiceDeliveryHandler) GetInvoiceDeliveries(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	deliveries, err := h.queries.GetInvoiceDeliveries(c.Request().Context(), invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch deliveries"})
+	}
+
+	response := make([]models.InvoiceDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		response[i] = invoiceDeliveryToResponse(delivery)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parseCcAddresses validates the CC recipients of an invoice email and returns
+// their bare addresses, or an error message
+func parseCcAddresses(cc []string) ([]string, string) {
+	if len(cc) > maxInvoiceCc {
+		return nil, fmt.Sprintf("At most %d CC recipients are allowed", maxInvoiceCc)
+	}
+
+	addresses := make([]string, 0, len(cc))
+	for _, raw := range cc {
+		address, err := mail.ParseAddress(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Sprintf("Invalid CC email address: %s", raw)
+		}
+		addresses = append(addresses, address.Address)
+	}
+	return addresses, ""
+}
+
+func invoiceDeliveryToResponse(delivery db.InvoiceDelivery) models.InvoiceDeliveryResponse {
+	var cc []string
+	if delivery.Cc.Valid {
+		cc = strings.Split(delivery.Cc.String, ",")
+	}
+
+	return models.InvoiceDeliveryResponse{
+		ID:        delivery.ID,
+		InvoiceID: delivery.InvoiceID,
+		Recipient: delivery.Recipient,
+		Cc:        cc,
+		Subject:   delivery.Subject,
+		Message:   delivery.Message.String,
+		MessageID: delivery.MessageID,
+		SentAt:    delivery.SentAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReviseInvoice godoc
+// @Summary Revise an issued invoice
+// @Description Change an invoice that has left draft. The invoice as it was is kept as a numbered revision, and the response includes the diff from that revision to the revised invoice. The revised total cannot be less than what has already been paid and credited, and the status is updated to match it.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body models.ReviseInvoiceRequest true "Revise Invoice Request"
+// @Success 200 {object} models.ReviseInvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/revise [post]
+func (h *InvoiceHandler) ReviseInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	var req models.ReviseInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	// Parse dates
+	issueDate, err := time.Parse("2006-01-02", req.IssueDate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid issue date format. Use YYYY-MM-DD"})
+	}
+
+	dueDate, err := time.Parse("2006-01-02", req.DueDate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid due date format. Use YYYY-MM-DD"})
+	}
+
+	if strings.TrimSpace(req.InvoiceNumber) == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invoice number is required"})
+	}
+
+	if req.LineItems != nil {
+		if msg := validateLineItems(*req.LineItems); msg != "" {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+		}
+	}
+
+	diff, err := h.invoiceService.ReviseInvoice(c.Request().Context(), userID, int32(id), services.InvoiceChanges{
+		Reason:        strings.TrimSpace(req.Reason),
+		ClientID:      req.ClientID,
+		InvoiceNumber: strings.TrimSpace(req.InvoiceNumber),
+		IssueDate:     issueDate,
+		DueDate:       dueDate,
+		Notes:         req.Notes,
+		LineItems:     req.LineItems,
+		TaxRateIDs:    req.TaxRateIDs,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		if errors.Is(err, services.ErrInvoiceDraft) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Draft invoices are edited directly, not revised"})
+		}
+		if errors.Is(err, services.ErrTaxRateNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Tax rate not found"})
+		}
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		if errors.Is(err, services.ErrInvoiceNumberTaken) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Invoice number already exists"})
+		}
+		if errors.Is(err, services.ErrRevisionBelowSettled) {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The revised total would be less than what has already been paid and credited on the invoice"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revise invoice"})
+	}
+
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice contents"})
+	}
+
+	return c.JSON(http.StatusOK, models.ReviseInvoiceResponse{
+		Invoice: buildInvoiceResponse(invoice, contents),
+		Diff:    buildInvoiceDiffResponse(diff),
+	})
+}
+
+// GetInvoiceRevisions godoc
+// @Summary Get invoice revisions
+// @Description Get the earlier versions of an invoice kept each time it was revised, oldest first. The invoice as it is now is the revision after the last one listed.
+// @Tags invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} models.InvoiceRevisionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/revisions [get]
+func (h *InvoiceHandler) GetInvoiceRevisions(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	revisions, err := h.queries.GetInvoiceRevisions(c.Request().Context(), invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice revisions"})
+	}
+
+	response := make([]models.InvoiceRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		response[i] = models.InvoiceRevisionResponse{
+			ID:        revision.ID,
+			InvoiceID: revision.InvoiceID,
+			Revision:  revision.Revision,
+			Reason:    revision.Reason.String,
+			Snapshot:  revision.Snapshot,
+			CreatedAt: revision.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetInvoiceRevisionDiff godoc
+// @Summary Compare invoice revisions
+// @Description Get the fields that changed between two revisions of an invoice. By default the invoice as it is now is compared with the revision before it.
+// @Tags invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param from query int false "Revision to compare from, defaults to the revision before to"
+// @Param to query int false "Revision to compare to, defaults to the current invoice"
+// @Success 200 {object} models.InvoiceDiffResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/revisions/diff [get]
+func (h *InvoiceHandler) GetInvoiceRevisionDiff(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	var from, to int64
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err = strconv.ParseInt(fromStr, 10, 32)
+		if err != nil || from < 1 {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid from revision"})
+		}
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err = strconv.ParseInt(toStr, 10, 32)
+		if err != nil || to < 1 {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid to revision"})
+		}
+	}
+
+	diff, err := h.invoiceService.DiffRevisions(c.Request().Context(), userID, int32(id), int32(from), int32(to))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		if errors.Is(err, services.ErrRevisionNotFound) {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Revision not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to compare invoice revisions"})
+	}
+
+	return c.JSON(http.StatusOK, buildInvoiceDiffResponse(diff))
+}
+
+func buildInvoiceDiffResponse(diff services.InvoiceDiff) models.InvoiceDiffResponse {
+	changes := make([]models.InvoiceChangeResponse, len(diff.Changes))
+	for i, change := range diff.Changes {
+		changes[i] = models.InvoiceChangeResponse{
+			Field: change.Field,
+			From:  change.From,
+			To:    change.To,
+		}
+	}
+	return models.InvoiceDiffResponse{
+		From:    diff.From,
+		To:      diff.To,
+		Changes: changes,
+	}
+}
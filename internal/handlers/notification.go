@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	queries *db.Queries
+}
+
+func NewNotificationHandler(queries *db.Queries) *NotificationHandler {
+	return &NotificationHandler{
+		queries: queries,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the authenticated user's most recent in-app notifications, newest first, with the number of unread ones
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Maximum number of notifications (default 50, at most 100)"
+// @Success 200 {object} models.NotificationListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/notifications [get]
+func (h *NotificationHandler) GetNotifications(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	limit := 50
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			limit = min(parsedLimit, 100)
+		}
+	}
+
+	notifications, err := h.queries.GetNotificationsByUserID(c.Request().Context(), db.GetNotificationsByUserIDParams{
+		UserID: userID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch notifications"})
+	}
+
+	unread, err := h.queries.CountUnreadNotifications(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to count unread notifications"})
+	}
+
+	response := models.NotificationListResponse{
+		Notifications: make([]models.NotificationResponse, len(notifications)),
+		UnreadCount:   unread,
+	}
+	for i, notification := range notifications {
+		response.Notifications[i] = notificationToResponse(notification)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Description Mark one of the authenticated user's notifications as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 200 {object} models.NotificationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid notification ID"})
+	}
+
+	notification, err := h.queries.MarkNotificationRead(c.Request().Context(), db.MarkNotificationReadParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Notification not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update notification"})
+	}
+
+	return c.JSON(http.StatusOK, notificationToResponse(notification))
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark all of the authenticated user's unread notifications as read
+// @Tags notifications
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	if err := h.queries.MarkAllNotificationsRead(c.Request().Context(), userID); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update notifications"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func notificationToResponse(notification db.Notification) models.NotificationResponse {
+	response := models.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Message:   notification.Message,
+		ProjectID: notification.ProjectID.Int32,
+		Read:      notification.ReadAt.Valid,
+		CreatedAt: notification.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+	if notification.ReadAt.Valid {
+		response.ReadAt = notification.ReadAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentHandler struct {
+	queries        *db.Queries
+	paymentService *services.PaymentService
+}
+
+func NewPaymentHandler(queries *db.Queries, paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		queries:        queries,
+		paymentService: paymentService,
+	}
+}
+
+// CreatePayment godoc
+// @Summary Record a payment
+// @Description Record a payment against an invoice. The invoice becomes partially_paid, or paid once its balance reaches zero.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body models.CreatePaymentRequest true "Create Payment Request"
+// @Success 201 {object} models.PaymentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/payments [post]
+func (h *PaymentHandler) CreatePayment(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	var req models.CreatePaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	paymentDate, err := time.Parse("2006-01-02", req.PaymentDate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid payment date format. Use YYYY-MM-DD"})
+	}
+
+	method := req.Method
+	if method == "" {
+		method = "bank_transfer"
+	}
+	if msg := validatePayment(req.Amount, method); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	payment, err := h.paymentService.CreatePayment(c.Request().Context(), userID, int32(invoiceID), services.NewPayment{
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		PaymentDate: paymentDate,
+		Method:      method,
+		Reference:   req.Reference,
+	})
+	if err != nil {
+		return paymentError(c, err, "Failed to record payment")
+	}
+
+	return c.JSON(http.StatusCreated, paymentToResponse(payment))
+}
+
+// GetPayments godoc
+// @Summary Get invoice payments
+// @Description Get all payments recorded against an invoice, oldest first
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} models.PaymentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/payments [get]
+func (h *PaymentHandler) GetPayments(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     int32(invoiceID),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	payments, err := h.queries.GetPaymentsByInvoiceID(c.Request().Context(), invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch payments"})
+	}
+
+	response := make([]models.PaymentResponse, len(payments))
+	for i, payment := range payments {
+		response[i] = paymentToResponse(payment)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdatePayment godoc
+// @Summary Update a payment
+// @Description Update a payment recorded against an invoice. The invoice status follows the new balance.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param paymentId path int true "Payment ID"
+// @Param request body models.UpdatePaymentRequest true "Update Payment Request"
+// @Success 200 {object} models.PaymentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/payments/{paymentId} [put]
+func (h *PaymentHandler) UpdatePayment(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	paymentID, err := strconv.ParseInt(c.Param("paymentId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid payment ID"})
+	}
+
+	var req models.UpdatePaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	paymentDate, err := time.Parse("2006-01-02", req.PaymentDate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid payment date format. Use YYYY-MM-DD"})
+	}
+
+	method := req.Method
+	if method == "" {
+		method = "bank_transfer"
+	}
+	if msg := validatePayment(req.Amount, method); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	payment, err := h.paymentService.UpdatePayment(c.Request().Context(), userID, int32(invoiceID), int32(paymentID), services.NewPayment{
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		PaymentDate: paymentDate,
+		Method:      method,
+		Reference:   req.Reference,
+	})
+	if err != nil {
+		return paymentError(c, err, "Failed to update payment")
+	}
+
+	return c.JSON(http.StatusOK, paymentToResponse(payment))
+}
+
+// DeletePayment godoc
+// @Summary Delete a payment
+// @Description Delete a payment recorded against an invoice. The invoice status follows the new balance.
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param paymentId path int true "Payment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/payments/{paymentId} [delete]
+func (h *PaymentHandler) DeletePayment(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	paymentID, err := strconv.ParseInt(c.Param("paymentId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid payment ID"})
+	}
+
+	err = h.paymentService.DeletePayment(c.Request().Context(), userID, int32(invoiceID), int32(paymentID))
+	if err != nil {
+		return paymentError(c, err, "Failed to delete payment")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// validatePayment returns an error message if the payment is invalid, or an
+// empty string otherwise
+func validatePayment(amount decimal.Decimal, method string) string {
+	if !amount.IsPositive() {
+		return "Amount must be greater than 0"
+	}
+	if !billing.IsValidPaymentMethod(method) {
+		return "Invalid payment method. Must be one of: bank_transfer, card, cash, check, paypal, other"
+	}
+	return ""
+}
+
+// paymentError responds to an error from the payment service, with message
+// for unexpected errors
+func paymentError(c echo.Context, err error, message string) error {
+	if errors.Is(err, services.ErrInvoiceNotFound) {
+		return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+	}
+	if errors.Is(err, services.ErrPaymentNotFound) {
+		return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Payment not found"})
+	}
+	if errors.Is(err, services.ErrInvoiceNotPayable) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Cannot record payments on a draft invoice"})
+	}
+	if errors.Is(err, services.ErrPaymentTooSmall) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Amount rounds to zero in the invoice currency"})
+	}
+	var currencyErr *services.PaymentCurrencyError
+	if errors.As(err, &currencyErr) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Payment currency must match the invoice currency (%s)", currencyErr.InvoiceCurrency)})
+	}
+	var balanceErr *services.PaymentBalanceError
+	if errors.As(err, &balanceErr) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Payment exceeds the outstanding balance of %s %s", money.Format(balanceErr.Balance, balanceErr.Currency), balanceErr.Currency)})
+	}
+	return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: message})
+}
+
+func paymentToResponse(payment db.Payment) models.PaymentResponse {
+	return models.PaymentResponse{
+		ID:          payment.ID,
+		InvoiceID:   payment.InvoiceID,
+		Amount:      money.Parse(payment.Amount),
+		Currency:    payment.Currency,
+		PaymentDate: payment.PaymentDate.Format("2006-01-02"),
+		Method:      payment.Method,
+		Reference:   payment.Reference.String,
+		CreatedAt:   payment.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   payment.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,708 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+type ProjectHandler struct {
+	queries       *db.Queries
+	budgetService *services.BudgetService
+}
+
+func NewProjectHandler(queries *db.Queries, budgetService *services.BudgetService) *ProjectHandler {
+	return &ProjectHandler{
+		queries:       queries,
+		budgetService: budgetService,
+	}
+}
+
+// CreateProject godoc
+// @Summary Create a project
+// @Description Create a project for one of the authenticated user's clients. Time recorded against the project is billed at its hourly rate when it has one, otherwise at the client's.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateProjectRequest true "Create Project Request"
+// @Success 201 {object} models.ProjectResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects [post]
+func (h *ProjectHandler) CreateProject(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.CreateProjectRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	status := req.Status
+	if status == "" {
+		status = services.ProjectActive
+	}
+	if msg := validateProject(req.Name, status, req.HourlyRate, req.BudgetHours, req.BudgetAmount); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+	thresholds, msg := parseBudgetAlertThresholds(req.BudgetAlertThresholds)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	_, err := h.queries.GetClientByID(c.Request().Context(), db.GetClientByIDParams{
+		ID:     req.ClientID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch client"})
+	}
+
+	project, err := h.queries.CreateProject(c.Request().Context(), db.CreateProjectParams{
+		UserID:                userID,
+		ClientID:              req.ClientID,
+		Name:                  strings.TrimSpace(req.Name),
+		Description:           sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Status:                status,
+		HourlyRate:            nullPrice(req.HourlyRate),
+		BudgetHours:           nullHours(req.BudgetHours),
+		BudgetAmount:          nullPrice(req.BudgetAmount),
+		BudgetAlertThresholds: thresholds,
+		Billable:              nullBool(req.Billable),
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create project"})
+	}
+
+	return c.JSON(http.StatusCreated, projectToResponse(project))
+}
+
+// GetProjects godoc
+// @Summary Get all projects
+// @Description Get all projects of the authenticated user, optionally only those of one client
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param client_id query int false "Client ID"
+// @Success 200 {array} models.ProjectResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects [get]
+func (h *ProjectHandler) GetProjects(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var projects []db.Project
+	var err error
+	if clientIDStr := c.QueryParam("client_id"); clientIDStr != "" {
+		clientID, parseErr := strconv.ParseInt(clientIDStr, 10, 32)
+		if parseErr != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid client ID"})
+		}
+		projects, err = h.queries.GetProjectsByClientID(c.Request().Context(), db.GetProjectsByClientIDParams{
+			ClientID: int32(clientID),
+			UserID:   userID,
+		})
+	} else {
+		projects, err = h.queries.GetProjectsByUserID(c.Request().Context(), userID)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch projects"})
+	}
+
+	response := make([]models.ProjectResponse, len(projects))
+	for i, project := range projects {
+		response[i] = projectToResponse(project)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetProject godoc
+// @Summary Get a project by ID
+// @Description Get a specific project of the authenticated user, with its tasks
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.ProjectResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id} [get]
+func (h *ProjectHandler) GetProject(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	project, status, msg := h.loadProject(c, userID)
+	if msg != "" {
+		return c.JSON(status, models.ErrorResponse{Error: msg})
+	}
+
+	tasks, err := h.queries.GetTasksByProjectID(c.Request().Context(), project.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch tasks"})
+	}
+
+	response := projectToResponse(project)
+	response.Tasks = make([]models.TaskResponse, len(tasks))
+	for i, task := range tasks {
+		response.Tasks[i] = taskToResponse(task)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateProject godoc
+// @Summary Update a project
+// @Description Update a project. Changing its hourly rate does not change the rate of time already recorded against it. Changing its budgets alerts you of the thresholds they cross.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param request body models.UpdateProjectRequest true "Update Project Request"
+// @Success 200 {object} models.ProjectResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid project ID"})
+	}
+
+	var req models.UpdateProjectRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateProject(req.Name, req.Status, req.HourlyRate, req.BudgetHours, req.BudgetAmount); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+	thresholds, msg := parseBudgetAlertThresholds(req.BudgetAlertThresholds)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	project, err := h.queries.UpdateProject(c.Request().Context(), db.UpdateProjectParams{
+		ID:                    int32(id),
+		UserID:                userID,
+		Name:                  strings.TrimSpace(req.Name),
+		Description:           sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Status:                req.Status,
+		HourlyRate:            nullPrice(req.HourlyRate),
+		BudgetHours:           nullHours(req.BudgetHours),
+		BudgetAmount:          nullPrice(req.BudgetAmount),
+		BudgetAlertThresholds: thresholds,
+		Billable:              nullBool(req.Billable),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Project not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update project"})
+	}
+
+	// A lower budget may cross alert thresholds, and a higher one clears them
+	if err := h.budgetService.CheckProject(c.Request().Context(), userID, sql.NullInt32{Int32: project.ID, Valid: true}); err != nil {
+		c.Logger().Error("Failed to check project budget: ", err)
+	}
+
+	return c.JSON(http.StatusOK, projectToResponse(project))
+}
+
+// GetProjectBudget godoc
+// @Summary Get a project's budget burn-down
+// @Description Get how much of its hours and amount budgets a project has used, with the time recorded against it day by day and what was left of the budgets after each day. Hours count all time recorded against the project, amounts only billable time, in the client's currency at the rate each entry was recorded at.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.ProjectBudgetResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id}/budget [get]
+func (h *ProjectHandler) GetProjectBudget(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid project ID"})
+	}
+
+	report, err := h.budgetService.Report(c.Request().Context(), userID, int32(id))
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Project not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch project budget"})
+	}
+
+	response := models.ProjectBudgetResponse{
+		ProjectID:       report.Project.ID,
+		Currency:        report.Currency,
+		BudgetHours:     report.BudgetHours,
+		UsedHours:       report.UsedHours,
+		RemainingHours:  remainingBudget(report.BudgetHours, report.UsedHours),
+		HoursPercent:    roundPercent(services.UsedPercent(report.UsedHours, report.BudgetHours)),
+		BudgetAmount:    report.BudgetAmount,
+		UsedAmount:      report.UsedAmount,
+		RemainingAmount: remainingBudget(report.BudgetAmount, report.UsedAmount),
+		AmountPercent:   roundPercent(services.UsedPercent(report.UsedAmount, report.BudgetAmount)),
+		AlertThresholds: alertThresholds(report.Project),
+		BurnDown:        make([]models.BudgetBurnDownPoint, len(report.Days)),
+	}
+
+	var totalHours, totalAmount decimal.Decimal
+	for i, day := range report.Days {
+		totalHours = totalHours.Add(day.Hours)
+		totalAmount = totalAmount.Add(day.Amount)
+		response.BurnDown[i] = models.BudgetBurnDownPoint{
+			Date:            day.Date.Format("2006-01-02"),
+			Hours:           day.Hours,
+			Amount:          day.Amount,
+			TotalHours:      totalHours,
+			TotalAmount:     totalAmount,
+			RemainingHours:  remainingBudget(report.BudgetHours, totalHours),
+			RemainingAmount: remainingBudget(report.BudgetAmount, totalAmount),
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteProject godoc
+// @Summary Delete a project
+// @Description Delete a project and its tasks. Time recorded against it is kept, at the rate it was recorded at, without a project.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid project ID"})
+	}
+
+	err = h.queries.DeleteProject(c.Request().Context(), db.DeleteProjectParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete project"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CreateTask godoc
+// @Summary Create a task
+// @Description Create a task in a project. Time recorded against the task is billed at its hourly rate when it has one, otherwise at the project's or the client's.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param request body models.CreateTaskRequest true "Create Task Request"
+// @Success 201 {object} models.TaskResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id}/tasks [post]
+func (h *ProjectHandler) CreateTask(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	project, status, msg := h.loadProject(c, userID)
+	if msg != "" {
+		return c.JSON(status, models.ErrorResponse{Error: msg})
+	}
+
+	var req models.CreateTaskRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateTask(req.Name, req.HourlyRate); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	task, err := h.queries.CreateTask(c.Request().Context(), db.CreateTaskParams{
+		ProjectID:  project.ID,
+		Name:       strings.TrimSpace(req.Name),
+		HourlyRate: nullPrice(req.HourlyRate),
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create task"})
+	}
+
+	return c.JSON(http.StatusCreated, taskToResponse(task))
+}
+
+// GetTasks godoc
+// @Summary Get project tasks
+// @Description Get all tasks of a project, ordered by name
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {array} models.TaskResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id}/tasks [get]
+func (h *ProjectHandler) GetTasks(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	project, status, msg := h.loadProject(c, userID)
+	if msg != "" {
+		return c.JSON(status, models.ErrorResponse{Error: msg})
+	}
+
+	tasks, err := h.queries.GetTasksByProjectID(c.Request().Context(), project.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch tasks"})
+	}
+
+	response := make([]models.TaskResponse, len(tasks))
+	for i, task := range tasks {
+		response[i] = taskToResponse(task)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateTask godoc
+// @Summary Update a task
+// @Description Update a task of a project. Changing its hourly rate does not change the rate of time already recorded against it.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param taskId path int true "Task ID"
+// @Param request body models.UpdateTaskRequest true "Update Task Request"
+// @Success 200 {object} models.TaskResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id}/tasks/{taskId} [put]
+func (h *ProjectHandler) UpdateTask(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	project, status, msg := h.loadProject(c, userID)
+	if msg != "" {
+		return c.JSON(status, models.ErrorResponse{Error: msg})
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+	}
+
+	var req models.UpdateTaskRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateTask(req.Name, req.HourlyRate); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	task, err := h.queries.UpdateTask(c.Request().Context(), db.UpdateTaskParams{
+		ID:         int32(taskID),
+		ProjectID:  project.ID,
+		Name:       strings.TrimSpace(req.Name),
+		HourlyRate: nullPrice(req.HourlyRate),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update task"})
+	}
+
+	return c.JSON(http.StatusOK, taskToResponse(task))
+}
+
+// DeleteTask godoc
+// @Summary Delete a task
+// @Description Delete a task of a project. Time recorded against it is kept, at the rate it was recorded at, on the project.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param taskId path int true "Task ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/projects/{id}/tasks/{taskId} [delete]
+func (h *ProjectHandler) DeleteTask(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	project, status, msg := h.loadProject(c, userID)
+	if msg != "" {
+		return c.JSON(status, models.ErrorResponse{Error: msg})
+	}
+
+	taskID, err := strconv.ParseInt(c.Param("taskId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+	}
+
+	err = h.queries.DeleteTask(c.Request().Context(), db.DeleteTaskParams{
+		ID:        int32(taskID),
+		ProjectID: project.ID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete task"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// loadProject fetches the user's project whose ID is in the id path
+// parameter. When it cannot, it returns the status and message of the error
+// response to send.
+func (h *ProjectHandler) loadProject(c echo.Context, userID int32) (db.Project, int, string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return db.Project{}, http.StatusBadRequest, "Invalid project ID"
+	}
+
+	project, err := h.queries.GetProjectByID(c.Request().Context(), db.GetProjectByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Project{}, http.StatusNotFound, "Project not found"
+		}
+		return db.Project{}, http.StatusInternalServerError, "Failed to fetch project"
+	}
+	return project, 0, ""
+}
+
+// validateProject returns an error message if the project is invalid, or an
+// empty string otherwise
+func validateProject(name, status string, hourlyRate, budgetHours, budgetAmount *decimal.Decimal) string {
+	if strings.TrimSpace(name) == "" {
+		return "Name is required"
+	}
+	if !services.IsValidProjectStatus(status) {
+		return "Status must be active, completed or archived"
+	}
+	if hourlyRate != nil && hourlyRate.IsNegative() {
+		return "hourly_rate cannot be negative"
+	}
+	if budgetHours != nil && !budgetHours.IsPositive() {
+		return "budget_hours must be greater than zero"
+	}
+	if budgetAmount != nil && !budgetAmount.IsPositive() {
+		return "budget_amount must be greater than zero"
+	}
+	return ""
+}
+
+// parseBudgetAlertThresholds validates the alert thresholds of a request and
+// returns them in ascending order, or the default ones when the request has
+// none. It returns an error message when they are invalid.
+func parseBudgetAlertThresholds(thresholds []int32) ([]int32, string) {
+	if thresholds == nil {
+		return services.DefaultBudgetAlertThresholds, ""
+	}
+	if len(thresholds) > 10 {
+		return nil, "budget_alert_thresholds cannot have more than 10 thresholds"
+	}
+
+	sorted := make([]int32, len(thresholds))
+	copy(sorted, thresholds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i, threshold := range sorted {
+		if threshold < 1 || threshold > 1000 {
+			return nil, "budget_alert_thresholds must be percentages between 1 and 1000"
+		}
+		if i > 0 && threshold == sorted[i-1] {
+			return nil, "budget_alert_thresholds cannot repeat a threshold"
+		}
+	}
+	return sorted, ""
+}
+
+// validateTask returns an error message if the task is invalid, or an empty
+// string otherwise
+func validateTask(name string, hourlyRate *decimal.Decimal) string {
+	if strings.TrimSpace(name) == "" {
+		return "Name is required"
+	}
+	if hourlyRate != nil && hourlyRate.IsNegative() {
+		return "hourly_rate cannot be negative"
+	}
+	return ""
+}
+
+// parseWork builds what a time entry or timer is recorded against from the
+// IDs of a request, where 0 means none. It returns an error message when the
+// request is invalid.
+func parseWork(clientID, projectID, taskID int32) (services.Work, string) {
+	work := services.Work{
+		ClientID:  clientID,
+		ProjectID: sql.NullInt32{Int32: projectID, Valid: projectID != 0},
+		TaskID:    sql.NullInt32{Int32: taskID, Valid: taskID != 0},
+	}
+	if work.TaskID.Valid && !work.ProjectID.Valid {
+		return work, "project_id is required with task_id"
+	}
+	return work, ""
+}
+
+// workError maps the errors of resolving what time is recorded against to
+// responses, falling back to a 500 with fallback as the message
+func workError(c echo.Context, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrClientNotFound):
+		return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Client not found"})
+	case errors.Is(err, services.ErrProjectNotFound):
+		return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Project not found"})
+	case errors.Is(err, services.ErrTaskNotFound):
+		return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+	case errors.Is(err, services.ErrProjectInactive):
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Project is not active. Reopen it to record time against it"})
+	default:
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fallback})
+	}
+}
+
+// nullPrice formats an optional rate or amount as it is stored
+func nullPrice(price *decimal.Decimal) sql.NullString {
+	if price == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: billing.FormatPrice(*price), Valid: true}
+}
+
+// nullHours formats an optional number of hours as it is stored
+func nullHours(hours *decimal.Decimal) sql.NullString {
+	if hours == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: hours.StringFixed(2), Valid: true}
+}
+
+// nullBool stores an optional flag
+func nullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *b, Valid: true}
+}
+
+// parseNullBool returns an optional boolean column, or nil if it is null
+func parseNullBool(b sql.NullBool) *bool {
+	if !b.Valid {
+		return nil
+	}
+	return &b.Bool
+}
+
+// parseNullDecimal parses an optional decimal column, or returns nil if it is
+// null
+func parseNullDecimal(s sql.NullString) *decimal.Decimal {
+	if !s.Valid {
+		return nil
+	}
+	d := money.Parse(s.String)
+	return &d
+}
+
+// remainingBudget returns what is left of budget after used, or nil when
+// there is no budget. It is negative once the budget is overrun.
+func remainingBudget(budget *decimal.Decimal, used decimal.Decimal) *decimal.Decimal {
+	if budget == nil {
+		return nil
+	}
+	remaining := budget.Sub(used)
+	return &remaining
+}
+
+// roundPercent rounds an optional percentage to 2 decimals
+func roundPercent(percent *decimal.Decimal) *decimal.Decimal {
+	if percent == nil {
+		return nil
+	}
+	rounded := percent.Round(2)
+	return &rounded
+}
+
+// alertThresholds returns the project's budget alert thresholds, as an empty
+// list rather than null when alerts are off
+func alertThresholds(project db.Project) []int32 {
+	if project.BudgetAlertThresholds == nil {
+		return []int32{}
+	}
+	return project.BudgetAlertThresholds
+}
+
+func projectToResponse(project db.Project) models.ProjectResponse {
+	return models.ProjectResponse{
+		ID:                    project.ID,
+		UserID:                project.UserID,
+		ClientID:              project.ClientID,
+		Name:                  project.Name,
+		Description:           project.Description.String,
+		Status:                project.Status,
+		HourlyRate:            parseNullDecimal(project.HourlyRate),
+		BudgetHours:           parseNullDecimal(project.BudgetHours),
+		BudgetAmount:          parseNullDecimal(project.BudgetAmount),
+		BudgetAlertThresholds: alertThresholds(project),
+		Billable:              parseNullBool(project.Billable),
+		CreatedAt:             project.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:             project.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func taskToResponse(task db.Task) models.TaskResponse {
+	return models.TaskResponse{
+		ID:         task.ID,
+		ProjectID:  task.ProjectID,
+		Name:       task.Name,
+		HourlyRate: parseNullDecimal(task.HourlyRate),
+		CreatedAt:  task.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  task.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
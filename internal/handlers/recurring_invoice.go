@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxPreviewOccurrences is the maximum number of occurrences a recurring
+// invoice preview returns
+const maxPreviewOccurrences = 52
+
+type RecurringInvoiceHandler struct {
+	queries                 *db.Queries
+	recurringInvoiceService *services.RecurringInvoiceService
+}
+
+func NewRecurringInvoiceHandler(queries *db.Queries, recurringInvoiceService *services.RecurringInvoiceService) *RecurringInvoiceHandler {
+	return &RecurringInvoiceHandler{
+		queries:                 queries,
+		recurringInvoiceService: recurringInvoiceService,
+	}
+}
+
+// CreateRecurringInvoice godoc
+// @Summary Create a recurring invoice
+// @Description Create a recurring invoice. An invoice is issued from its line items on every occurrence of its schedule, starting from today, and emailed to the client when auto_send is set. The user's default tax rates are applied.
+// @Tags recurring-invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RecurringInvoiceRequest true "Recurring Invoice Request"
+// @Success 201 {object} models.RecurringInvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices [post]
+func (h *RecurringInvoiceHandler) CreateRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.RecurringInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	template, msg := parseRecurringTemplate(req)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	rec, err := h.recurringInvoiceService.CreateRecurringInvoice(c.Request().Context(), userID, template)
+	if err != nil {
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create recurring invoice"})
+	}
+
+	return h.recurringInvoiceResponse(c, http.StatusCreated, rec)
+}
+
+// GetRecurringInvoices godoc
+// @Summary Get all recurring invoices
+// @Description Get all recurring invoices for the authenticated user
+// @Tags recurring-invoices
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.RecurringInvoiceResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices [get]
+func (h *RecurringInvoiceHandler) GetRecurringInvoices(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	recs, err := h.queries.GetRecurringInvoicesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch recurring invoices"})
+	}
+
+	response := make([]models.RecurringInvoiceResponse, len(recs))
+	for i, rec := range recs {
+		lineItems, err := h.queries.GetRecurringInvoiceLineItems(c.Request().Context(), rec.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+		}
+		response[i] = recurringInvoiceToResponse(rec, lineItems)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetRecurringInvoice godoc
+// @Summary Get a recurring invoice
+// @Description Get a specific recurring invoice by ID
+// @Tags recurring-invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} models.RecurringInvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices/{id} [get]
+func (h *RecurringInvoiceHandler) GetRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid recurring invoice ID"})
+	}
+
+	rec, err := h.queries.GetRecurringInvoiceByID(c.Request().Context(), db.GetRecurringInvoiceByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Recurring invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch recurring invoice"})
+	}
+
+	return h.recurringInvoiceResponse(c, http.StatusOK, rec)
+}
+
+// UpdateRecurringInvoice godoc
+// @Summary Update a recurring invoice
+// @Description Replace the template and schedule of a recurring invoice. Invoices already issued are not changed.
+// @Tags recurring-invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Param request body models.RecurringInvoiceRequest true "Recurring Invoice Request"
+// @Success 200 {object} models.RecurringInvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices/{id} [put]
+func (h *RecurringInvoiceHandler) UpdateRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid recurring invoice ID"})
+	}
+
+	var req models.RecurringInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	template, msg := parseRecurringTemplate(req)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	rec, err := h.recurringInvoiceService.UpdateRecurringInvoice(c.Request().Context(), userID, int32(id), template)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Recurring invoice not found"})
+		}
+		if errors.Is(err, services.ErrClientNotFound) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update recurring invoice"})
+	}
+
+	return h.recurringInvoiceResponse(c, http.StatusOK, rec)
+}
+
+// DeleteRecurringInvoice godoc
+// @Summary Delete a recurring invoice
+// @Description Delete a recurring invoice by ID. Invoices already issued are kept.
+// @Tags recurring-invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices/{id} [delete]
+func (h *RecurringInvoiceHandler) DeleteRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid recurring invoice ID"})
+	}
+
+	err = h.queries.DeleteRecurringInvoice(c.Request().Context(), db.DeleteRecurringInvoiceParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete recurring invoice"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PauseRecurringInvoice godoc
+// @Summary Pause a recurring invoice
+// @Description Stop an active recurring invoice from issuing invoices until it is resumed
+// @Tags recurring-invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} models.RecurringInvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices/{id}/pause [post]
+func (h *RecurringInvoiceHandler) PauseRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid recurring invoice ID"})
+	}
+
+	rec, err := h.recurringInvoiceService.PauseRecurringInvoice(c.Request().Context(), userID, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Recurring invoice not found"})
+		}
+		if errors.Is(err, services.ErrRecurringInvoiceNotActive) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Only active recurring invoices can be paused"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to pause recurring invoice"})
+	}
+
+	return h.recurringInvoiceResponse(c, http.StatusOK, rec)
+}
+
+// ResumeRecurringInvoice godoc
+// @Summary Resume a recurring invoice
+// @Description Reactivate a paused recurring invoice. Occurrences missed while it was paused are skipped.
+// @Tags recurring-invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} models.RecurringInvoiceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices/{id}/resume [post]
+func (h *RecurringInvoiceHandler) ResumeRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid recurring invoice ID"})
+	}
+
+	rec, err := h.recurringInvoiceService.ResumeRecurringInvoice(c.Request().Context(), userID, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Recurring invoice not found"})
+		}
+		if errors.Is(err, services.ErrRecurringInvoiceNotPaused) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Only paused recurring invoices can be resumed"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to resume recurring invoice"})
+	}
+
+	return h.recurringInvoiceResponse(c, http.StatusOK, rec)
+}
+
+// PreviewRecurringInvoice godoc
+// @Summary Preview a recurring invoice
+// @Description Get the issue and due dates of the next invoices a recurring invoice will issue. A paused recurring invoice is previewed as if it were resumed today.
+// @Tags recurring-invoices
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Param count query int false "Number of occurrences (max 52)" default(5)
+// @Success 200 {array} models.RecurringInvoiceOccurrenceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/recurring-invoices/{id}/preview [get]
+func (h *RecurringInvoiceHandler) PreviewRecurringInvoice(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid recurring invoice ID"})
+	}
+
+	count := 5
+	if countParam := c.QueryParam("count"); countParam != "" {
+		count, err = strconv.Atoi(countParam)
+		if err != nil || count < 1 || count > maxPreviewOccurrences {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Count must be a number between 1 and 52"})
+		}
+	}
+
+	rec, err := h.queries.GetRecurringInvoiceByID(c.Request().Context(), db.GetRecurringInvoiceByIDParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Recurring invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch recurring invoice"})
+	}
+
+	occurrences := h.recurringInvoiceService.PreviewOccurrences(rec, count)
+	response := make([]models.RecurringInvoiceOccurrenceResponse, len(occurrences))
+	for i, occurrence := range occurrences {
+		response[i] = models.RecurringInvoiceOccurrenceResponse{
+			IssueDate: occurrence.IssueDate.Format("2006-01-02"),
+			DueDate:   occurrence.DueDate.Format("2006-01-02"),
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// recurringInvoiceResponse responds with rec and its line items
+func (h *RecurringInvoiceHandler) recurringInvoiceResponse(c echo.Context, status int, rec db.RecurringInvoice) error {
+	lineItems, err := h.queries.GetRecurringInvoiceLineItems(c.Request().Context(), rec.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch line items"})
+	}
+
+	return c.JSON(status, recurringInvoiceToResponse(rec, lineItems))
+}
+
+// parseRecurringTemplate validates a recurring invoice request and returns
+// the template it describes, or an error message
+func parseRecurringTemplate(req models.RecurringInvoiceRequest) (services.RecurringTemplate, string) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return services.RecurringTemplate{}, "Name is required"
+	}
+	if !billing.IsValidFrequency(req.Frequency) {
+		return services.RecurringTemplate{}, "Frequency must be one of: weekly, monthly, quarterly, yearly"
+	}
+	if !billing.IsValidAnchorDay(req.Frequency, int(req.AnchorDay)) {
+		if req.Frequency == billing.FrequencyWeekly {
+			return services.RecurringTemplate{}, "Anchor day must be a weekday between 1 (Monday) and 7 (Sunday)"
+		}
+		return services.RecurringTemplate{}, "Anchor day must be a day of the month between 1 and 31"
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return services.RecurringTemplate{}, "Invalid start date format. Use YYYY-MM-DD"
+	}
+
+	var endDate time.Time
+	if req.EndDate != "" {
+		endDate, err = time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return services.RecurringTemplate{}, "Invalid end date format. Use YYYY-MM-DD"
+		}
+		if endDate.Before(startDate) {
+			return services.RecurringTemplate{}, "End date cannot be before start date"
+		}
+	}
+
+	if req.PaymentTermsDays < 0 || req.PaymentTermsDays > 365 {
+		return services.RecurringTemplate{}, "Payment terms must be between 0 and 365 days"
+	}
+
+	if len(req.LineItems) == 0 {
+		return services.RecurringTemplate{}, "Recurring invoice must include at least one line item"
+	}
+	if msg := validateLineItems(req.LineItems); msg != "" {
+		return services.RecurringTemplate{}, msg
+	}
+
+	return services.RecurringTemplate{
+		ClientID:         req.ClientID,
+		Name:             name,
+		Frequency:        req.Frequency,
+		AnchorDay:        req.AnchorDay,
+		StartDate:        startDate,
+		EndDate:          endDate,
+		PaymentTermsDays: req.PaymentTermsDays,
+		Notes:            req.Notes,
+		AutoSend:         req.AutoSend,
+		LineItems:        req.LineItems,
+	}, ""
+}
+
+func recurringInvoiceToResponse(rec db.RecurringInvoice, lineItems []db.RecurringInvoiceLineItem) models.RecurringInvoiceResponse {
+	items := make([]models.RecurringInvoiceLineItemResponse, len(lineItems))
+	for i, item := range lineItems {
+		items[i] = models.RecurringInvoiceLineItemResponse{
+			ID:          item.ID,
+			Description: item.Description,
+			Quantity:    money.Parse(item.Quantity),
+			Unit:        item.Unit,
+			UnitPrice:   money.Parse(item.UnitPrice),
+			TaxRate:     money.Parse(item.TaxRate),
+		}
+	}
+
+	return models.RecurringInvoiceResponse{
+		ID:               rec.ID,
+		UserID:           rec.UserID,
+		ClientID:         rec.ClientID,
+		Name:             rec.Name,
+		Frequency:        rec.Frequency,
+		AnchorDay:        rec.AnchorDay,
+		StartDate:        rec.StartDate.Format("2006-01-02"),
+		EndDate:          formatNullDate(rec.EndDate),
+		PaymentTermsDays: rec.PaymentTermsDays,
+		Notes:            rec.Notes.String,
+		AutoSend:         rec.AutoSend,
+		Status:           rec.Status,
+		NextRunDate:      formatNullDate(rec.NextRunDate),
+		LastRunDate:      formatNullDate(rec.LastRunDate),
+		LineItems:        items,
+		CreatedAt:        rec.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:        rec.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// formatNullDate formats a nullable date as YYYY-MM-DD, or an empty string
+// when it is null
+func formatNullDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02")
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReminderHandler struct {
+	queries *db.Queries
+}
+
+func NewReminderHandler(queries *db.Queries) *ReminderHandler {
+	return &ReminderHandler{
+		queries: queries,
+	}
+}
+
+// CreateReminderRule godoc
+// @Summary Create a reminder rule
+// @Description Create a payment reminder rule. A reminder is emailed to the client of every unpaid invoice offset_days after its due date (before it when negative), unless the client opted out.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateReminderRuleRequest true "Create Reminder Rule Request"
+// @Success 201 {object} models.ReminderRuleResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/reminder-rules [post]
+func (h *ReminderHandler) CreateReminderRule(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.CreateReminderRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateReminderRule(req.OffsetDays); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	exists, err := h.offsetTaken(c, userID, 0, req.OffsetDays)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch reminder rules"})
+	}
+	if exists {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "A reminder rule with this offset already exists"})
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	rule, err := h.queries.CreateReminderRule(c.Request().Context(), db.CreateReminderRuleParams{
+		UserID:     userID,
+		OffsetDays: req.OffsetDays,
+		Subject:    sql.NullString{String: strings.TrimSpace(req.Subject), Valid: strings.TrimSpace(req.Subject) != ""},
+		Message:    sql.NullString{String: strings.TrimSpace(req.Message), Valid: strings.TrimSpace(req.Message) != ""},
+		Enabled:    enabled,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create reminder rule"})
+	}
+
+	return c.JSON(http.StatusCreated, reminderRuleToResponse(rule))
+}
+
+// GetReminderRules godoc
+// @Summary Get all reminder rules
+// @Description Get the payment reminder rules of the authenticated user, ordered by offset
+// @Tags reminders
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ReminderRuleResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/reminder-rules [get]
+func (h *ReminderHandler) GetReminderRules(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	rules, err := h.queries.GetReminderRulesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch reminder rules"})
+	}
+
+	response := make([]models.ReminderRuleResponse, len(rules))
+	for i, rule := range rules {
+		response[i] = reminderRuleToResponse(rule)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateReminderRule godoc
+// @Summary Update a reminder rule
+// @Description Update a payment reminder rule
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reminder Rule ID"
+// @Param request body models.UpdateReminderRuleRequest true "Update Reminder Rule Request"
+// @Success 200 {object} models.ReminderRuleResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/reminder-rules/{id} [put]
+func (h *ReminderHandler) UpdateReminderRule(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid reminder rule ID"})
+	}
+
+	var req models.UpdateReminderRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateReminderRule(req.OffsetDays); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	exists, err := h.offsetTaken(c, userID, int32(id), req.OffsetDays)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch reminder rules"})
+	}
+	if exists {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "A reminder rule with this offset already exists"})
+	}
+
+	rule, err := h.queries.UpdateReminderRule(c.Request().Context(), db.UpdateReminderRuleParams{
+		ID:         int32(id),
+		UserID:     userID,
+		OffsetDays: req.OffsetDays,
+		Subject:    sql.NullString{String: strings.TrimSpace(req.Subject), Valid: strings.TrimSpace(req.Subject) != ""},
+		Message:    sql.NullString{String: strings.TrimSpace(req.Message), Valid: strings.TrimSpace(req.Message) != ""},
+		Enabled:    req.Enabled,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Reminder rule not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update reminder rule"})
+	}
+
+	return c.JSON(http.StatusOK, reminderRuleToResponse(rule))
+}
+
+// DeleteReminderRule godoc
+// @Summary Delete a reminder rule
+// @Description Delete a payment reminder rule by ID. Reminders already sent stay in the invoice reminder log.
+// @Tags reminders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reminder Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/reminder-rules/{id} [delete]
+func (h *ReminderHandler) DeleteReminderRule(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid reminder rule ID"})
+	}
+
+	err = h.queries.DeleteReminderRule(c.Request().Context(), db.DeleteReminderRuleParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete reminder rule"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetInvoiceReminders godoc
+// @Summary Get invoice reminder log
+// @Description Get every payment reminder sent, or attempted, for an invoice, most recent first
+// @Tags reminders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {array} models.InvoiceReminderResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invoices/{id}/reminders [get]
+func (h *ReminderHandler) GetInvoiceReminders(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invoice ID"})
+	}
+
+	invoice, err := h.queries.GetInvoiceByID(c.Request().Context(), db.GetInvoiceByIDParams{
+		ID:     int32(invoiceID),
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invoice not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch invoice"})
+	}
+
+	reminders, err := h.queries.GetInvoiceReminders(c.Request().Context(), invoice.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch reminders"})
+	}
+
+	response := make([]models.InvoiceReminderResponse, len(reminders))
+	for i, reminder := range reminders {
+		var ruleID *int32
+		if reminder.ReminderRuleID.Valid {
+			ruleID = &reminder.ReminderRuleID.Int32
+		}
+		response[i] = models.InvoiceReminderResponse{
+			ID:             reminder.ID,
+			InvoiceID:      reminder.InvoiceID,
+			ReminderRuleID: ruleID,
+			OffsetDays:     reminder.OffsetDays,
+			Recipient:      reminder.Recipient,
+			Subject:        reminder.Subject,
+			Status:         reminder.Status,
+			Error:          reminder.Error.String,
+			SentAt:         reminder.SentAt.Time.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// offsetTaken reports whether the user has a reminder rule other than
+// excludeID with the given offset
+func (h *ReminderHandler) offsetTaken(c echo.Context, userID, excludeID, offsetDays int32) (bool, error) {
+	rules, err := h.queries.GetReminderRulesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return false, err
+	}
+	for _, rule := range rules {
+		if rule.ID != excludeID && rule.OffsetDays == offsetDays {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateReminderRule returns an error message if the reminder rule is
+// invalid, or an empty string otherwise
+func validateReminderRule(offsetDays int32) string {
+	if offsetDays < -365 || offsetDays > 365 {
+		return "Offset must be between -365 and 365 days"
+	}
+	return ""
+}
+
+func reminderRuleToResponse(rule db.ReminderRule) models.ReminderRuleResponse {
+	return models.ReminderRuleResponse{
+		ID:         rule.ID,
+		UserID:     rule.UserID,
+		OffsetDays: rule.OffsetDays,
+		Subject:    rule.Subject.String,
+		Message:    rule.Message.String,
+		Enabled:    rule.Enabled,
+		CreatedAt:  rule.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  rule.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
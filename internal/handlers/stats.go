@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
+	"worklio-api/internal/billing"
 	"worklio-api/internal/db"
 	"worklio-api/internal/models"
+	"worklio-api/internal/money"
 	"worklio-api/internal/services"
 
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 )
 
 type StatsHandler struct {
@@ -25,15 +30,29 @@ func NewStatsHandler(queries *db.Queries, exchangeService *services.ExchangeRate
 
 // DashboardStatsResponse represents the response for dashboard stats
 type DashboardStatsResponse struct {
-	TotalHours      float64 `json:"total_hours"`
-	TotalRevenue    float64 `json:"total_revenue"`
-	UnpaidInvoices  float64 `json:"unpaid_invoices"`
-	PaidInvoices    float64 `json:"paid_invoices"`
+	TotalHours     decimal.Decimal `json:"total_hours" swaggertype:"number"`
+	TotalRevenue   decimal.Decimal `json:"total_revenue" swaggertype:"number"`
+	UnpaidInvoices decimal.Decimal `json:"unpaid_invoices" swaggertype:"number"`
+	PaidInvoices   decimal.Decimal `json:"paid_invoices" swaggertype:"number"`
+	TaxCollected   decimal.Decimal `json:"tax_collected" swaggertype:"number"`
+	CreditedAmount decimal.Decimal `json:"credited_amount" swaggertype:"number"`
+	// BillableHours is the part of TotalHours recorded as billable, and
+	// BillableUtilization its percentage of TotalHours
+	BillableHours       decimal.Decimal `json:"billable_hours" swaggertype:"number"`
+	BillableUtilization decimal.Decimal `json:"billable_utilization" swaggertype:"number"`
+	// ConversionRates lists the exchange rates used to convert amounts into
+	// the user's currency and where each came from
+	ConversionRates []models.ConversionRateResponse `json:"conversion_rates"`
+	// Warnings flags currencies converted at stale rates or left unconverted
+	Warnings []models.CurrencyWarning `json:"warnings"`
+	// Unconverted holds the amounts left out of the totals because their
+	// currency has no exchange rate
+	Unconverted []models.UnconvertedTotal `json:"unconverted"`
 }
 
 // GetDashboardStats godoc
 // @Summary Get dashboard statistics
-// @Description Get calculated dashboard statistics with currency conversion
+// @Description Get calculated dashboard statistics with currency conversion. Total revenue is the subtotal of the invoices issued in the period, time entries and line items alike, net of the credit notes issued in the period. Invoice amounts use the totals and exchange rate frozen when each invoice was issued. Amounts in currencies without an exchange rate are left out of the totals and listed under unconverted.
 // @Tags stats
 // @Produce json
 // @Security BearerAuth
@@ -103,20 +122,11 @@ func (h *StatsHandler) GetDashboardStats(c echo.Context) error {
 	}
 
 	// Fetch conversion rates
-	conversionRates := make(map[string]float64)
-	for currency := range currenciesNeeded {
-		convertedAmount, err := h.exchangeService.ConvertAmount(c.Request().Context(), 1.0, currency, userCurrency)
-		if err != nil {
-			// Fallback to 1:1 if conversion fails
-			conversionRates[currency] = 1.0
-		} else {
-			conversionRates[currency] = convertedAmount
-		}
-	}
+	conv := newConverter(c.Request().Context(), h.exchangeService, userID, currenciesNeeded, userCurrency)
 
-	// Calculate total hours and revenue
-	var totalHours float64
-	var totalRevenue float64
+	// Calculate total and billable hours
+	var totalHours decimal.Decimal
+	var billableHours decimal.Decimal
 
 	for _, entry := range timeEntries {
 		// Apply date filter
@@ -127,24 +137,10 @@ func (h *StatsHandler) GetDashboardStats(c echo.Context) error {
 			continue
 		}
 
-		hours, _ := strconv.ParseFloat(entry.Hours, 64)
-		totalHours += hours
-
-		// Calculate revenue with currency conversion
-		if client, ok := clientsMap[entry.ClientID]; ok {
-			hourlyRate, _ := strconv.ParseFloat(client.HourlyRate.String, 64)
-			entryAmount := hours * hourlyRate
-			clientCurrency := client.Currency
-
-			if clientCurrency != userCurrency {
-				if rate, ok := conversionRates[clientCurrency]; ok {
-					totalRevenue += entryAmount * rate
-				} else {
-					totalRevenue += entryAmount
-				}
-			} else {
-				totalRevenue += entryAmount
-			}
+		hours := money.Parse(entry.Hours)
+		totalHours = totalHours.Add(hours)
+		if entry.Billable {
+			billableHours = billableHours.Add(hours)
 		}
 	}
 
@@ -154,9 +150,17 @@ func (h *StatsHandler) GetDashboardStats(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get invoices"})
 	}
 
-	// Calculate unpaid and paid invoices
-	var unpaidInvoices float64
-	var paidInvoices float64
+	invoicesMap := make(map[int32]db.Invoice)
+	for _, invoice := range invoices {
+		invoicesMap[invoice.ID] = invoice
+	}
+
+	// Calculate revenue, unpaid and paid invoices
+	var totalRevenue decimal.Decimal
+	var unpaidInvoices decimal.Decimal
+	var paidInvoices decimal.Decimal
+	var taxCollected decimal.Decimal
+	var creditedAmount decimal.Decimal
 
 	for _, invoice := range invoices {
 		// Apply date filter
@@ -167,18 +171,13 @@ func (h *StatsHandler) GetDashboardStats(c echo.Context) error {
 			continue
 		}
 
-		// Get time entries for this invoice to calculate total
-		invoiceTimeEntries, err := h.queries.GetInvoiceTimeEntries(c.Request().Context(), invoice.ID)
+		// Get time entries, line items and taxes for this invoice to calculate total
+		contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
 		if err != nil {
 			continue
 		}
 
-		var invoiceTotal float64
-		for _, entry := range invoiceTimeEntries {
-			hours, _ := strconv.ParseFloat(entry.Hours, 64)
-			hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
-			invoiceTotal += hours * hourlyRate
-		}
+		totals := billing.IssuedTotals(invoice, contents)
 
 		// Get client for currency conversion
 		client, ok := clientsMap[invoice.ClientID]
@@ -186,41 +185,98 @@ func (h *StatsHandler) GetDashboardStats(c echo.Context) error {
 			continue
 		}
 
-		clientCurrency := client.Currency
-		convertedAmount := invoiceTotal
-		if clientCurrency != userCurrency {
-			if rate, ok := conversionRates[clientCurrency]; ok {
-				convertedAmount = invoiceTotal * rate
-			}
+		// Drafts are not revenue until they are issued
+		var revenue decimal.Decimal
+		if invoice.Status != "draft" {
+			revenue = totals.Subtotal
+		}
+		paid, outstanding := paidAndOutstanding(invoice.Status, totals)
+		var tax decimal.Decimal
+		if invoice.Status == "paid" {
+			credited := billing.CreditedTotals(contents.CreditNotes)
+			tax = totals.Tax.Sub(credited.Tax)
 		}
 
-		if invoice.Status == "sent" || invoice.Status == "overdue" {
-			unpaidInvoices += convertedAmount
-		} else if invoice.Status == "paid" {
-			paidInvoices += convertedAmount
+		rate, ok := conv.invoiceRate(invoice, client.Currency)
+		if !ok {
+			conv.skip(client.Currency, "total_revenue", revenue)
+			conv.skip(client.Currency, "unpaid_invoices", outstanding)
+			conv.skip(client.Currency, "paid_invoices", paid)
+			conv.skip(client.Currency, "tax_collected", tax)
+			continue
 		}
+		totalRevenue = totalRevenue.Add(money.Convert(revenue, rate.Value))
+		unpaidInvoices = unpaidInvoices.Add(money.Convert(outstanding, rate.Value))
+		paidInvoices = paidInvoices.Add(money.Convert(paid, rate.Value))
+		taxCollected = taxCollected.Add(money.Convert(tax, rate.Value))
 	}
 
+	// Credit notes reduce the revenue of the period they are issued in
+	creditNotes, err := h.queries.GetCreditNotesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get credit notes"})
+	}
+
+	for _, creditNote := range creditNotes {
+		if fromDate != nil && creditNote.IssueDate.Before(*fromDate) {
+			continue
+		}
+		if toDate != nil && creditNote.IssueDate.After(*toDate) {
+			continue
+		}
+
+		client, ok := clientsMap[creditNote.ClientID]
+		if !ok {
+			continue
+		}
+		invoice, ok := invoicesMap[creditNote.InvoiceID]
+		if !ok {
+			continue
+		}
+
+		// Credit notes offset the subtotal the invoice they credit added to
+		// revenue, converted at the same rate
+		subtotal := money.Parse(creditNote.Subtotal)
+		rate, ok := conv.invoiceRate(invoice, client.Currency)
+		if !ok {
+			conv.skip(client.Currency, "total_revenue", subtotal.Neg())
+			conv.skip(client.Currency, "credited_amount", subtotal)
+			continue
+		}
+		totalRevenue = totalRevenue.Sub(money.Convert(subtotal, rate.Value))
+		creditedAmount = creditedAmount.Add(money.Convert(subtotal, rate.Value))
+	}
+
+	// Converted amounts are summed exactly and rounded once, to the user's
+	// currency
 	return c.JSON(http.StatusOK, DashboardStatsResponse{
-		TotalHours:     totalHours,
-		TotalRevenue:   totalRevenue,
-		UnpaidInvoices: unpaidInvoices,
-		PaidInvoices:   paidInvoices,
+		TotalHours:          totalHours,
+		TotalRevenue:        money.Round(totalRevenue, userCurrency),
+		UnpaidInvoices:      money.Round(unpaidInvoices, userCurrency),
+		PaidInvoices:        money.Round(paidInvoices, userCurrency),
+		TaxCollected:        money.Round(taxCollected, userCurrency),
+		CreditedAmount:      money.Round(creditedAmount, userCurrency),
+		BillableHours:       billableHours,
+		BillableUtilization: billableUtilization(billableHours, totalHours),
+		ConversionRates:     conv.ratesUsed(),
+		Warnings:            conv.warningList(),
+		Unconverted:         conv.unconvertedList(),
 	})
 }
 
 // RecentTimeEntryResponse represents a time entry with client information
 type RecentTimeEntryResponse struct {
-	ID          int32   `json:"id"`
-	UserID      int32   `json:"user_id"`
-	ClientID    int32   `json:"client_id"`
-	ClientName  string  `json:"client_name"`
-	Date        string  `json:"date"`
-	Hours       float64 `json:"hours"`
-	Description string  `json:"description"`
-	HourlyRate  float64 `json:"hourly_rate"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          int32           `json:"id"`
+	UserID      int32           `json:"user_id"`
+	ClientID    int32           `json:"client_id"`
+	ClientName  string          `json:"client_name"`
+	Date        string          `json:"date"`
+	Hours       decimal.Decimal `json:"hours" swaggertype:"number"`
+	Description string          `json:"description"`
+	HourlyRate  decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	Billable    bool            `json:"billable"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
 }
 
 // GetRecentTimeEntries godoc
@@ -315,8 +371,8 @@ func (h *StatsHandler) GetRecentTimeEntries(c echo.Context) error {
 	// Convert to response format
 	response := make([]RecentTimeEntryResponse, len(filtered))
 	for i, entry := range filtered {
-		hours, _ := strconv.ParseFloat(entry.Hours, 64)
-		hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
+		hours := money.Parse(entry.Hours)
+		hourlyRate := money.ParseNull(entry.HourlyRate)
 		clientName := "Unknown"
 		if name, ok := clientsMap[entry.ClientID]; ok {
 			clientName = name
@@ -330,6 +386,7 @@ func (h *StatsHandler) GetRecentTimeEntries(c echo.Context) error {
 			Hours:       hours,
 			Description: entry.Description.String,
 			HourlyRate:  hourlyRate,
+			Billable:    entry.Billable,
 			CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 		}
@@ -340,21 +397,28 @@ func (h *StatsHandler) GetRecentTimeEntries(c echo.Context) error {
 
 // RecentInvoiceResponse represents an invoice with client information
 type RecentInvoiceResponse struct {
-	ID             int32                    `json:"id"`
-	UserID         int32                    `json:"user_id"`
-	ClientID       int32                    `json:"client_id"`
-	ClientName     string                   `json:"client_name"`
-	ClientCurrency string                   `json:"client_currency"`
-	InvoiceNumber  string                   `json:"invoice_number"`
-	IssueDate      string                   `json:"issue_date"`
-	DueDate        string                   `json:"due_date"`
-	Status         string                   `json:"status"`
-	Notes          string                   `json:"notes"`
-	TimeEntries    []models.TimeEntryResponse `json:"time_entries"`
-	TotalHours     float64                  `json:"total_hours"`
-	TotalAmount    float64                  `json:"total_amount"`
-	CreatedAt      string                   `json:"created_at"`
-	UpdatedAt      string                   `json:"updated_at"`
+	ID             int32                            `json:"id"`
+	UserID         int32                            `json:"user_id"`
+	ClientID       int32                            `json:"client_id"`
+	ClientName     string                           `json:"client_name"`
+	ClientCurrency string                           `json:"client_currency"`
+	InvoiceNumber  string                           `json:"invoice_number"`
+	IssueDate      string                           `json:"issue_date"`
+	DueDate        string                           `json:"due_date"`
+	Status         string                           `json:"status"`
+	Notes          string                           `json:"notes"`
+	TimeEntries    []models.TimeEntryResponse       `json:"time_entries"`
+	LineItems      []models.InvoiceLineItemResponse `json:"line_items"`
+	TotalHours     decimal.Decimal                  `json:"total_hours" swaggertype:"number"`
+	Subtotal       decimal.Decimal                  `json:"subtotal" swaggertype:"number"`
+	TaxAmount      decimal.Decimal                  `json:"tax_amount" swaggertype:"number"`
+	Withholding    decimal.Decimal                  `json:"withholding_amount" swaggertype:"number"`
+	TotalAmount    decimal.Decimal                  `json:"total_amount" swaggertype:"number"`
+	AmountPaid     decimal.Decimal                  `json:"amount_paid" swaggertype:"number"`
+	AmountCredited decimal.Decimal                  `json:"amount_credited" swaggertype:"number"`
+	BalanceDue     decimal.Decimal                  `json:"balance_due" swaggertype:"number"`
+	CreatedAt      string                           `json:"created_at"`
+	UpdatedAt      string                           `json:"updated_at"`
 }
 
 // GetRecentInvoices godoc
@@ -457,20 +521,17 @@ func (h *StatsHandler) GetRecentInvoices(c echo.Context) error {
 	response := make([]RecentInvoiceResponse, len(filtered))
 	for i, invoice := range filtered {
 		// Get time entries for this invoice
-		timeEntries, err := h.queries.GetInvoiceTimeEntries(c.Request().Context(), invoice.ID)
+		contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
 		if err != nil {
 			continue
 		}
 
-		timeEntryResponses := make([]models.TimeEntryResponse, len(timeEntries))
-		totalHours := 0.0
-		totalAmount := 0.0
+		timeEntryResponses := make([]models.TimeEntryResponse, len(contents.TimeEntries))
+		totals := billing.IssuedTotals(invoice, contents)
 
-		for j, entry := range timeEntries {
-			hours, _ := strconv.ParseFloat(entry.Hours, 64)
-			hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
-			totalHours += hours
-			totalAmount += hours * hourlyRate
+		for j, entry := range contents.TimeEntries {
+			hours := money.Parse(entry.Hours)
+			hourlyRate := money.ParseNull(entry.HourlyRate)
 
 			timeEntryResponses[j] = models.TimeEntryResponse{
 				ID:          entry.ID,
@@ -480,6 +541,7 @@ func (h *StatsHandler) GetRecentInvoices(c echo.Context) error {
 				Hours:       hours,
 				Description: entry.Description.String,
 				HourlyRate:  hourlyRate,
+				Billable:    entry.Billable,
 				CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
 				UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 			}
@@ -505,8 +567,15 @@ func (h *StatsHandler) GetRecentInvoices(c echo.Context) error {
 			Status:         invoice.Status,
 			Notes:          invoice.Notes.String,
 			TimeEntries:    timeEntryResponses,
-			TotalHours:     totalHours,
-			TotalAmount:    totalAmount,
+			LineItems:      buildLineItemResponses(contents.LineItems, contents.Currency),
+			TotalHours:     totals.Hours,
+			Subtotal:       totals.Subtotal,
+			TaxAmount:      totals.Tax,
+			Withholding:    totals.Withholding,
+			TotalAmount:    totals.Total,
+			AmountPaid:     totals.Paid,
+			AmountCredited: totals.Credited,
+			BalanceDue:     totals.Balance,
 			CreatedAt:      invoice.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt:      invoice.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 		}
@@ -517,20 +586,29 @@ func (h *StatsHandler) GetRecentInvoices(c echo.Context) error {
 
 // InvoiceStatsResponse represents the response for invoice stats
 type InvoiceStatsResponse struct {
-	Invoices            []models.InvoiceResponse `json:"invoices"`
-	TotalInvoices       int                      `json:"total_invoices"`
-	TotalAmount         float64                  `json:"total_amount"`
-	PaidAmount          float64                  `json:"paid_amount"`
-	UnpaidAmount        float64                  `json:"unpaid_amount"`
+	Invoices       []models.InvoiceResponse `json:"invoices"`
+	TotalInvoices  int                      `json:"total_invoices"`
+	TotalAmount    decimal.Decimal          `json:"total_amount" swaggertype:"number"`
+	PaidAmount     decimal.Decimal          `json:"paid_amount" swaggertype:"number"`
+	UnpaidAmount   decimal.Decimal          `json:"unpaid_amount" swaggertype:"number"`
+	CreditedAmount decimal.Decimal          `json:"credited_amount" swaggertype:"number"`
+	// ConversionRates lists the exchange rates used to convert amounts into
+	// the user's currency and where each came from
+	ConversionRates []models.ConversionRateResponse `json:"conversion_rates"`
+	// Warnings flags currencies converted at stale rates or left unconverted
+	Warnings []models.CurrencyWarning `json:"warnings"`
+	// Unconverted holds the amounts left out of the totals because their
+	// currency has no exchange rate
+	Unconverted []models.UnconvertedTotal `json:"unconverted"`
 }
 
 // GetInvoiceStats godoc
 // @Summary Get invoice statistics
-// @Description Get all invoices with calculated totals in user's currency
+// @Description Get all invoices with calculated totals in user's currency. Total amounts are net of credit notes and unpaid amounts are outstanding balances net of recorded payments and credit notes. Issued invoices report the totals and exchange rate frozen when they were issued. Amounts in currencies without an exchange rate are left out of the totals and listed under unconverted.
 // @Tags stats
 // @Produce json
 // @Security BearerAuth
-// @Param status query string false "Filter by status (all, draft, sent, paid, overdue)" default(all)
+// @Param status query string false "Filter by status (all, draft, sent, partially_paid, paid, overdue)" default(all)
 // @Success 200 {object} InvoiceStatsResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
@@ -580,52 +658,20 @@ func (h *StatsHandler) GetInvoiceStats(c echo.Context) error {
 	}
 
 	// Fetch conversion rates
-	conversionRates := make(map[string]float64)
-	for currency := range currenciesNeeded {
-		convertedAmount, err := h.exchangeService.ConvertAmount(c.Request().Context(), 1.0, currency, userCurrency)
-		if err != nil {
-			// Log the error but continue with fallback
-			c.Logger().Errorf("Failed to convert %s to %s: %v", currency, userCurrency, err)
-			conversionRates[currency] = 1.0
-		} else {
-			c.Logger().Infof("Loaded conversion rate: 1.0 %s = %f %s", currency, convertedAmount, userCurrency)
-			conversionRates[currency] = convertedAmount
-		}
-	}
+	conv := newConverter(c.Request().Context(), h.exchangeService, userID, currenciesNeeded, userCurrency)
 
-	var totalAmount, paidAmount, unpaidAmount float64
+	var totalAmount, paidAmount, unpaidAmount, creditedAmount decimal.Decimal
 	invoiceResponses := make([]models.InvoiceResponse, 0)
 
 	for _, invoice := range invoices {
 		// Get time entries for this invoice
-		timeEntries, err := h.queries.GetInvoiceTimeEntries(c.Request().Context(), invoice.ID)
+		contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
 		if err != nil {
 			continue
 		}
 
-		// Convert time entries to response format
-		timeEntryResponses := make([]models.TimeEntryResponse, 0)
-		var totalHours float64
-		var invoiceTotal float64
-
-		for _, entry := range timeEntries {
-			hours, _ := strconv.ParseFloat(entry.Hours, 64)
-			hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
-			totalHours += hours
-			invoiceTotal += hours * hourlyRate
-
-			timeEntryResponses = append(timeEntryResponses, models.TimeEntryResponse{
-				ID:          entry.ID,
-				UserID:      entry.UserID,
-				ClientID:    entry.ClientID,
-				Date:        entry.Date.Format("2006-01-02"),
-				Hours:       hours,
-				Description: entry.Description.String,
-				HourlyRate:  hourlyRate,
-				CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-				UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
-			})
-		}
+		totals := billing.IssuedTotals(invoice, contents)
+		invoiceTotal := totals.Net()
 
 		// Get client info
 		clientName := "Unknown"
@@ -635,25 +681,24 @@ func (h *StatsHandler) GetInvoiceStats(c echo.Context) error {
 			clientCurrency = client.Currency
 		}
 
-		// Convert to user currency for totals
-		convertedAmount := invoiceTotal
-		if clientCurrency != userCurrency {
-			if rate, ok := conversionRates[clientCurrency]; ok {
-				convertedAmount = invoiceTotal * rate
-				c.Logger().Infof("Invoice %d: Converting %f %s to %f %s (rate: %f)", invoice.ID, invoiceTotal, clientCurrency, convertedAmount, userCurrency, rate)
-			} else {
-				c.Logger().Warnf("Invoice %d: No rate found for %s to %s (have %d rates)", invoice.ID, clientCurrency, userCurrency, len(conversionRates))
+		// Convert to user currency for totals, at the rate frozen when the
+		// invoice was issued
+		// Always calculate totals for ALL invoices (regardless of filter)
+		paid, outstanding := paidAndOutstanding(invoice.Status, totals)
+		if rate, ok := conv.invoiceRate(invoice, clientCurrency); ok {
+			conversionRate := rate.Value
+			if clientCurrency != userCurrency {
+				c.Logger().Infof("Invoice %d: Converting %s %s to %s %s (rate: %f, %s)", invoice.ID, invoiceTotal, clientCurrency, money.Convert(invoiceTotal, conversionRate), userCurrency, conversionRate, rate.Source)
 			}
+			totalAmount = totalAmount.Add(money.Convert(invoiceTotal, conversionRate))
+			paidAmount = paidAmount.Add(money.Convert(paid, conversionRate))
+			unpaidAmount = unpaidAmount.Add(money.Convert(outstanding, conversionRate))
+			creditedAmount = creditedAmount.Add(money.Convert(totals.Credited, conversionRate))
 		} else {
-			c.Logger().Infof("Invoice %d: Same currency %s, no conversion needed", invoice.ID, clientCurrency)
-		}
-
-		// Always calculate totals for ALL invoices (regardless of filter)
-		totalAmount += convertedAmount
-		if invoice.Status == "paid" {
-			paidAmount += convertedAmount
-		} else if invoice.Status == "sent" || invoice.Status == "overdue" {
-			unpaidAmount += convertedAmount
+			conv.skip(clientCurrency, "total_amount", invoiceTotal)
+			conv.skip(clientCurrency, "paid_amount", paid)
+			conv.skip(clientCurrency, "unpaid_amount", outstanding)
+			conv.skip(clientCurrency, "credited_amount", totals.Credited)
 		}
 
 		// Filter by status for the invoice list only
@@ -662,33 +707,252 @@ func (h *StatsHandler) GetInvoiceStats(c echo.Context) error {
 		}
 
 		invoiceResponse := models.InvoiceResponse{
-			ID:             invoice.ID,
-			UserID:         invoice.UserID,
-			ClientID:       invoice.ClientID,
-			ClientName:     clientName,
-			ClientCurrency: clientCurrency,
-			InvoiceNumber:  invoice.InvoiceNumber,
-			IssueDate:      invoice.IssueDate.Format("2006-01-02"),
-			DueDate:        invoice.DueDate.Format("2006-01-02"),
-			Status:         invoice.Status,
-			Notes:          invoice.Notes.String,
-			TimeEntries:    timeEntryResponses,
-			TotalHours:     totalHours,
-			TotalAmount:    invoiceTotal,
-			CreatedAt:      invoice.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:      invoice.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+			ID:                invoice.ID,
+			UserID:            invoice.UserID,
+			ClientID:          invoice.ClientID,
+			ClientName:        clientName,
+			ClientCurrency:    clientCurrency,
+			InvoiceNumber:     invoice.InvoiceNumber,
+			IssueDate:         invoice.IssueDate.Format("2006-01-02"),
+			DueDate:           invoice.DueDate.Format("2006-01-02"),
+			Status:            invoice.Status,
+			Notes:             invoice.Notes.String,
+			TimeEntries:       buildInvoiceTimeEntryResponses(contents.TimeEntries),
+			Projects:          buildInvoiceProjectResponses(contents.TimeEntries, contents.Currency),
+			LineItems:         buildLineItemResponses(contents.LineItems, contents.Currency),
+			TotalHours:        totals.Hours,
+			Subtotal:          totals.Subtotal,
+			Taxes:             buildInvoiceTaxResponses(totals.Taxes),
+			TaxAmount:         totals.Tax,
+			Withholding:       totals.Withholding,
+			TotalAmount:       totals.Total,
+			AmountPaid:        totals.Paid,
+			AmountCredited:    totals.Credited,
+			BalanceDue:        totals.Balance,
+			ReverseCharge:     invoice.ReverseCharge,
+			ExchangeRate:      parseNullFloat(invoice.ExchangeRate),
+			ReportingCurrency: invoice.ReportingCurrency.String,
+			OverdueAt:         formatNullTime(invoice.OverdueAt),
+			CreatedAt:         invoice.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+			UpdatedAt:         invoice.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 		}
 
 		invoiceResponses = append(invoiceResponses, invoiceResponse)
 	}
 
 	response := InvoiceStatsResponse{
-		Invoices:      invoiceResponses,
-		TotalInvoices: len(invoiceResponses),
-		TotalAmount:   totalAmount,
-		PaidAmount:    paidAmount,
-		UnpaidAmount:  unpaidAmount,
+		Invoices:        invoiceResponses,
+		TotalInvoices:   len(invoiceResponses),
+		TotalAmount:     money.Round(totalAmount, userCurrency),
+		PaidAmount:      money.Round(paidAmount, userCurrency),
+		UnpaidAmount:    money.Round(unpaidAmount, userCurrency),
+		CreditedAmount:  money.Round(creditedAmount, userCurrency),
+		ConversionRates: conv.ratesUsed(),
+		Warnings:        conv.warningList(),
+		Unconverted:     conv.unconvertedList(),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// TaxPeriodResponse is the tax collected on paid invoices issued in one period
+type TaxPeriodResponse struct {
+	Period       string          `json:"period"`
+	InvoiceCount int             `json:"invoice_count"`
+	Subtotal     decimal.Decimal `json:"subtotal" swaggertype:"number"`
+	TaxCollected decimal.Decimal `json:"tax_collected" swaggertype:"number"`
+	TaxWithheld  decimal.Decimal `json:"tax_withheld" swaggertype:"number"`
+}
+
+// TaxReportResponse represents the response for the tax report
+type TaxReportResponse struct {
+	Currency          string              `json:"currency"`
+	Period            string              `json:"period"`
+	Periods           []TaxPeriodResponse `json:"periods"`
+	TotalTaxCollected decimal.Decimal     `json:"total_tax_collected" swaggertype:"number"`
+	TotalTaxWithheld  decimal.Decimal     `json:"total_tax_withheld" swaggertype:"number"`
+	// ConversionRates lists the exchange rates used to convert amounts into
+	// the user's currency and where each came from
+	ConversionRates []models.ConversionRateResponse `json:"conversion_rates"`
+	// Warnings flags currencies converted at stale rates or left unconverted
+	Warnings []models.CurrencyWarning `json:"warnings"`
+	// Unconverted holds the amounts left out of the totals because their
+	// currency has no exchange rate
+	Unconverted []models.UnconvertedTotal `json:"unconverted"`
+}
+
+// GetTaxReport godoc
+// @Summary Get collected tax per period
+// @Description Get the tax collected and withheld on paid invoices net of their credit notes, grouped by the period of their issue date and converted to the user's currency. Amounts in currencies without an exchange rate are left out of the totals and listed under unconverted.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD format)"
+// @Param to query string false "End date (YYYY-MM-DD format)"
+// @Param period query string false "Grouping period (month, quarter, year)" default(month)
+// @Success 200 {object} TaxReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/stats/tax [get]
+func (h *StatsHandler) GetTaxReport(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	period := c.QueryParam("period")
+	if period == "" {
+		period = "month"
+	}
+	if period != "month" && period != "quarter" && period != "year" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid period. Must be one of: month, quarter, year"})
+	}
+
+	// Get user's currency preference
+	user, err := h.queries.GetUserByID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user info"})
+	}
+
+	userCurrency := "USD"
+	if user.Currency.Valid {
+		userCurrency = user.Currency.String
 	}
 
+	// Parse date range filters
+	var fromDate, toDate *time.Time
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid from date format. Use YYYY-MM-DD"})
+		}
+		fromDate = &parsed
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid to date format. Use YYYY-MM-DD"})
+		}
+		// Set to end of day
+		endOfDay := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 999999999, parsed.Location())
+		toDate = &endOfDay
+	}
+
+	invoices, err := h.queries.GetInvoicesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get invoices"})
+	}
+
+	clients, err := h.queries.GetClientsByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get clients"})
+	}
+
+	clientCurrencies := make(map[int32]string)
+	currenciesNeeded := make(map[string]bool)
+	for _, client := range clients {
+		clientCurrencies[client.ID] = client.Currency
+		if client.Currency != userCurrency {
+			currenciesNeeded[client.Currency] = true
+		}
+	}
+
+	// Fetch conversion rates
+	conv := newConverter(c.Request().Context(), h.exchangeService, userID, currenciesNeeded, userCurrency)
+
+	periods := make(map[string]*TaxPeriodResponse)
+	var totalTaxCollected, totalTaxWithheld decimal.Decimal
+
+	for _, invoice := range invoices {
+		if invoice.Status != "paid" {
+			continue
+		}
+
+		// Apply date filter
+		if fromDate != nil && invoice.IssueDate.Before(*fromDate) {
+			continue
+		}
+		if toDate != nil && invoice.IssueDate.After(*toDate) {
+			continue
+		}
+
+		contents, err := billing.LoadContents(c.Request().Context(), h.queries, invoice.ID)
+		if err != nil {
+			continue
+		}
+		totals := billing.IssuedTotals(invoice, contents)
+		credited := billing.CreditedTotals(contents.CreditNotes)
+		subtotal := totals.Subtotal.Sub(credited.Subtotal)
+		tax := totals.Tax.Sub(credited.Tax)
+		withheld := totals.Withholding.Sub(credited.Withholding)
+
+		currency := clientCurrencies[invoice.ClientID]
+		rate, ok := conv.invoiceRate(invoice, currency)
+		if !ok {
+			conv.skip(currency, "total_tax_collected", tax)
+			conv.skip(currency, "total_tax_withheld", withheld)
+			continue
+		}
+
+		key := taxPeriodKey(invoice.IssueDate, period)
+		entry, ok := periods[key]
+		if !ok {
+			entry = &TaxPeriodResponse{Period: key}
+			periods[key] = entry
+		}
+		entry.InvoiceCount++
+		entry.Subtotal = entry.Subtotal.Add(money.Convert(subtotal, rate.Value))
+		entry.TaxCollected = entry.TaxCollected.Add(money.Convert(tax, rate.Value))
+		entry.TaxWithheld = entry.TaxWithheld.Add(money.Convert(withheld, rate.Value))
+
+		totalTaxCollected = totalTaxCollected.Add(money.Convert(tax, rate.Value))
+		totalTaxWithheld = totalTaxWithheld.Add(money.Convert(withheld, rate.Value))
+	}
+
+	response := TaxReportResponse{
+		Currency:          userCurrency,
+		Period:            period,
+		Periods:           make([]TaxPeriodResponse, 0, len(periods)),
+		TotalTaxCollected: money.Round(totalTaxCollected, userCurrency),
+		TotalTaxWithheld:  money.Round(totalTaxWithheld, userCurrency),
+		ConversionRates:   conv.ratesUsed(),
+		Warnings:          conv.warningList(),
+		Unconverted:       conv.unconvertedList(),
+	}
+	for _, entry := range periods {
+		entry.Subtotal = money.Round(entry.Subtotal, userCurrency)
+		entry.TaxCollected = money.Round(entry.TaxCollected, userCurrency)
+		entry.TaxWithheld = money.Round(entry.TaxWithheld, userCurrency)
+		response.Periods = append(response.Periods, *entry)
+	}
+	sort.Slice(response.Periods, func(i, j int) bool {
+		return response.Periods[i].Period < response.Periods[j].Period
+	})
+
 	return c.JSON(http.StatusOK, response)
 }
+
+// paidAndOutstanding splits an invoice total, net of credit notes, into the
+// amount received and the balance still owed. Drafts count towards neither, and invoices marked paid
+// without recorded payments count as fully paid.
+func paidAndOutstanding(status string, totals billing.Totals) (paid, outstanding decimal.Decimal) {
+	switch status {
+	case "draft":
+		return decimal.Zero, decimal.Zero
+	case "paid":
+		return totals.Net(), decimal.Zero
+	default:
+		return totals.Paid, totals.Balance
+	}
+}
+
+// taxPeriodKey returns the label of the period containing date, e.g.
+// "2025-03", "2025-Q1" or "2025"
+func taxPeriodKey(date time.Time, period string) string {
+	switch period {
+	case "quarter":
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+	case "year":
+		return fmt.Sprintf("%d", date.Year())
+	default:
+		return date.Format("2006-01")
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+type TaxRateHandler struct {
+	queries *db.Queries
+}
+
+func NewTaxRateHandler(queries *db.Queries) *TaxRateHandler {
+	return &TaxRateHandler{
+		queries: queries,
+	}
+}
+
+// CreateTaxRate godoc
+// @Summary Create a tax rate
+// @Description Create a named tax rate for the authenticated user. Default rates are applied to new invoices automatically.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateTaxRateRequest true "Create Tax Rate Request"
+// @Success 201 {object} models.TaxRateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/tax-rates [post]
+func (h *TaxRateHandler) CreateTaxRate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.CreateTaxRateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateTaxRate(req.Name, req.Rate, req.Compound, req.Withholding); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	taxRate, err := h.queries.CreateTaxRate(c.Request().Context(), db.CreateTaxRateParams{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Rate:        req.Rate.StringFixed(2),
+		Compound:    req.Compound,
+		Withholding: req.Withholding,
+		IsDefault:   req.IsDefault,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create tax rate"})
+	}
+
+	return c.JSON(http.StatusCreated, taxRateToResponse(taxRate))
+}
+
+// GetTaxRates godoc
+// @Summary Get all tax rates
+// @Description Get all tax rates for the authenticated user
+// @Tags tax-rates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TaxRateResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/tax-rates [get]
+func (h *TaxRateHandler) GetTaxRates(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	taxRates, err := h.queries.GetTaxRatesByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch tax rates"})
+	}
+
+	response := make([]models.TaxRateResponse, len(taxRates))
+	for i, taxRate := range taxRates {
+		response[i] = taxRateToResponse(taxRate)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateTaxRate godoc
+// @Summary Update a tax rate
+// @Description Update a tax rate. Invoices that already use it keep the values they were created with.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tax Rate ID"
+// @Param request body models.UpdateTaxRateRequest true "Update Tax Rate Request"
+// @Success 200 {object} models.TaxRateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/tax-rates/{id} [put]
+func (h *TaxRateHandler) UpdateTaxRate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid tax rate ID"})
+	}
+
+	var req models.UpdateTaxRateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	if msg := validateTaxRate(req.Name, req.Rate, req.Compound, req.Withholding); msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	taxRate, err := h.queries.UpdateTaxRate(c.Request().Context(), db.UpdateTaxRateParams{
+		ID:          int32(id),
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Rate:        req.Rate.StringFixed(2),
+		Compound:    req.Compound,
+		Withholding: req.Withholding,
+		IsDefault:   req.IsDefault,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Tax rate not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update tax rate"})
+	}
+
+	return c.JSON(http.StatusOK, taxRateToResponse(taxRate))
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete a tax rate
+// @Description Delete a tax rate by ID. Invoices that already use it are not affected.
+// @Tags tax-rates
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tax Rate ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/tax-rates/{id} [delete]
+func (h *TaxRateHandler) DeleteTaxRate(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid tax rate ID"})
+	}
+
+	err = h.queries.DeleteTaxRate(c.Request().Context(), db.DeleteTaxRateParams{
+		ID:     int32(id),
+		UserID: userID,
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete tax rate"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// maxTaxRate is the highest tax rate, in percent, a tax rate or line item can
+// have
+var maxTaxRate = decimal.NewFromInt(100)
+
+// validateTaxRate returns an error message if the tax rate is invalid, or an
+// empty string otherwise
+func validateTaxRate(name string, rate decimal.Decimal, compound, withholding bool) string {
+	if strings.TrimSpace(name) == "" {
+		return "Name is required"
+	}
+	if rate.IsNegative() || rate.GreaterThan(maxTaxRate) {
+		return "Rate must be between 0 and 100"
+	}
+	if compound && withholding {
+		return "A tax rate cannot be both compound and withholding"
+	}
+	return ""
+}
+
+func taxRateToResponse(taxRate db.TaxRate) models.TaxRateResponse {
+	return models.TaxRateResponse{
+		ID:          taxRate.ID,
+		UserID:      taxRate.UserID,
+		Name:        taxRate.Name,
+		Rate:        money.Parse(taxRate.Rate),
+		Compound:    taxRate.Compound,
+		Withholding: taxRate.Withholding,
+		IsDefault:   taxRate.IsDefault,
+		CreatedAt:   taxRate.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   taxRate.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -9,26 +9,30 @@ import (
 
 	"worklio-api/internal/db"
 	"worklio-api/internal/models"
+	"worklio-api/internal/money"
 	"worklio-api/internal/services"
 
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 )
 
 type TimeEntryHandler struct {
 	queries         *db.Queries
 	exchangeService *services.ExchangeRateService
+	budgetService   *services.BudgetService
 }
 
-func NewTimeEntryHandler(queries *db.Queries, exchangeService *services.ExchangeRateService) *TimeEntryHandler {
+func NewTimeEntryHandler(queries *db.Queries, exchangeService *services.ExchangeRateService, budgetService *services.BudgetService) *TimeEntryHandler {
 	return &TimeEntryHandler{
 		queries:         queries,
 		exchangeService: exchangeService,
+		budgetService:   budgetService,
 	}
 }
 
 // CreateTimeEntry godoc
 // @Summary Create a new time entry
-// @Description Create a new time entry for the authenticated user
+// @Description Create a new time entry for the authenticated user, billed at the hourly rate of its task, project or client, either as a number of hours on a date or as the period between started_at and ended_at, from which hours is derived less paused_seconds. Entries whose period overlaps another of the user's entries are rejected unless allow_overlap is set.
 // @Tags time-entries
 // @Accept json
 // @Produce json
@@ -37,6 +41,8 @@ func NewTimeEntryHandler(queries *db.Queries, exchangeService *services.Exchange
 // @Success 201 {object} models.TimeEntryResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.TimeEntryOverlapResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/time-entries [post]
 func (h *TimeEntryHandler) CreateTimeEntry(c echo.Context) error {
@@ -47,37 +53,58 @@ func (h *TimeEntryHandler) CreateTimeEntry(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
 	}
 
-	// Parse date in local timezone
-	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	period, msg := parseTimeEntryPeriod(req.Date, req.Hours, req.StartedAt, req.EndedAt, req.PausedSeconds)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	work, msg := parseWork(req.ClientID, req.ProjectID, req.TaskID)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	// Capture the current hourly rate of the task, project or client, and
+	// whether the time is billable unless the request says
+	terms, err := services.ResolveWork(c.Request().Context(), h.queries, userID, work)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid date format. Use YYYY-MM-DD"})
+		return workError(c, err, "Failed to fetch hourly rate")
+	}
+	billable := terms.Billable
+	if req.Billable != nil {
+		billable = *req.Billable
 	}
 
-	// Fetch client to get their current hourly rate
-	client, err := h.queries.GetClientByID(c.Request().Context(), db.GetClientByIDParams{
-		ID:     req.ClientID,
-		UserID: userID,
-	})
+	overlaps, err := services.FindOverlaps(c.Request().Context(), h.queries, userID, 0, period.StartedAt, period.EndedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Client not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch client"})
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check for overlapping time entries"})
+	}
+	if len(overlaps) > 0 && !req.AllowOverlap {
+		return c.JSON(http.StatusConflict, overlapResponse(overlaps))
 	}
 
 	timeEntry, err := h.queries.CreateTimeEntry(c.Request().Context(), db.CreateTimeEntryParams{
-		UserID:      userID,
-		ClientID:    req.ClientID,
-		Date:        date,
-		Hours:       fmt.Sprintf("%.2f", req.Hours),
-		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		HourlyRate:  client.HourlyRate,
+		UserID:        userID,
+		ClientID:      work.ClientID,
+		ProjectID:     work.ProjectID,
+		TaskID:        work.TaskID,
+		Date:          period.Date,
+		Hours:         period.Hours.StringFixed(2),
+		Description:   sql.NullString{String: req.Description, Valid: req.Description != ""},
+		HourlyRate:    terms.HourlyRate,
+		Billable:      billable,
+		StartedAt:     period.StartedAt,
+		EndedAt:       period.EndedAt,
+		PausedSeconds: period.PausedSeconds,
 	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create time entry"})
 	}
 
-	return c.JSON(http.StatusCreated, createTimeEntryRowToResponse(timeEntry))
+	h.checkBudget(c, userID, timeEntry.ProjectID)
+
+	response := createTimeEntryRowToResponse(timeEntry)
+	response.Overlaps = overlapIDs(overlaps)
+	return c.JSON(http.StatusCreated, response)
 }
 
 // GetTimeEntries godoc
@@ -144,7 +171,7 @@ func (h *TimeEntryHandler) GetTimeEntries(c echo.Context) error {
 
 // GetTimeEntriesStats godoc
 // @Summary Get time entries statistics
-// @Description Get statistics for time entries filtered by view_mode and date
+// @Description Get statistics for time entries filtered by view_mode and date. Amounts in currencies without an exchange rate are left out of the totals and listed under unconverted.
 // @Tags time-entries
 // @Produce json
 // @Security BearerAuth
@@ -289,21 +316,13 @@ func (h *TimeEntryHandler) getFilteredTimeEntriesWithStats(c echo.Context, userI
 	}
 
 	// Fetch conversion rates
-	conversionRates := make(map[string]float64)
-	for currency := range currenciesNeeded {
-		convertedAmount, err := h.exchangeService.ConvertAmount(c.Request().Context(), 1.0, currency, userCurrency)
-		if err != nil {
-			// Fallback to 1:1 if conversion fails
-			conversionRates[currency] = 1.0
-		} else {
-			conversionRates[currency] = convertedAmount
-		}
-	}
+	conv := newConverter(c.Request().Context(), h.exchangeService, userID, currenciesNeeded, userCurrency)
 
 	// Filter entries by date range and calculate stats
 	var filteredEntries []models.TimeEntryResponse
-	var totalHours float64
-	var totalRevenue float64
+	var totalHours decimal.Decimal
+	var billableHours decimal.Decimal
+	var totalRevenue decimal.Decimal
 
 	for _, entry := range timeEntries {
 		// Apply date filter
@@ -311,29 +330,29 @@ func (h *TimeEntryHandler) getFilteredTimeEntriesWithStats(c echo.Context, userI
 			continue
 		}
 
-		hours, _ := strconv.ParseFloat(entry.Hours, 64)
-		totalHours += hours
+		hours := money.Parse(entry.Hours)
+		totalHours = totalHours.Add(hours)
+		if entry.Billable {
+			billableHours = billableHours.Add(hours)
+		}
 
 		// Get client info
 		clientName := "Unknown"
-		hourlyRate := 0.0
 		clientCurrency := userCurrency
 
 		if client, ok := clientsMap[entry.ClientID]; ok {
 			clientName = client.Name
-			hourlyRate, _ = strconv.ParseFloat(client.HourlyRate.String, 64)
 			clientCurrency = client.Currency
 
-			// Calculate revenue with currency conversion
-			entryAmount := hours * hourlyRate
-			if clientCurrency != userCurrency {
-				if rate, ok := conversionRates[clientCurrency]; ok {
-					totalRevenue += entryAmount * rate
+			// Calculate revenue at the entry's rate with currency conversion;
+			// non-billable time earns nothing
+			if entry.Billable {
+				entryAmount := hours.Mul(money.ParseNull(entry.HourlyRate))
+				if rate, ok := conv.rate(clientCurrency); ok {
+					totalRevenue = totalRevenue.Add(money.Convert(entryAmount, rate.Value))
 				} else {
-					totalRevenue += entryAmount
+					conv.skip(clientCurrency, "total_revenue", entryAmount)
 				}
-			} else {
-				totalRevenue += entryAmount
 			}
 		}
 
@@ -344,9 +363,14 @@ func (h *TimeEntryHandler) getFilteredTimeEntriesWithStats(c echo.Context, userI
 	}
 
 	return c.JSON(http.StatusOK, models.TimeEntriesWithStatsResponse{
-		Entries:      filteredEntries,
-		TotalHours:   totalHours,
-		TotalRevenue: totalRevenue,
+		Entries:             filteredEntries,
+		TotalHours:          totalHours,
+		BillableHours:       billableHours,
+		BillableUtilization: billableUtilization(billableHours, totalHours),
+		TotalRevenue:        money.Round(totalRevenue, userCurrency),
+		ConversionRates:     conv.ratesUsed(),
+		Warnings:            conv.warningList(),
+		Unconverted:         conv.unconvertedList(),
 	})
 }
 
@@ -387,7 +411,7 @@ func (h *TimeEntryHandler) GetTimeEntry(c echo.Context) error {
 
 // UpdateTimeEntry godoc
 // @Summary Update a time entry
-// @Description Update a time entry's information
+// @Description Update a time entry's information. The hourly rate is captured again when its client, project or task changes. Time entries billed on an invoice that has left draft cannot be changed, and ones billed on a draft invoice cannot be made non-billable. Hours derived from a period keep leaving out the time the entry was paused, unless paused_seconds is given. Entries whose period overlaps another of the user's entries are rejected unless allow_overlap is set.
 // @Tags time-entries
 // @Accept json
 // @Produce json
@@ -398,6 +422,7 @@ func (h *TimeEntryHandler) GetTimeEntry(c echo.Context) error {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/time-entries/{id} [put]
 func (h *TimeEntryHandler) UpdateTimeEntry(c echo.Context) error {
@@ -413,13 +438,12 @@ func (h *TimeEntryHandler) UpdateTimeEntry(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
 	}
 
-	// Parse date in local timezone
-	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid date format. Use YYYY-MM-DD"})
+	work, msg := parseWork(req.ClientID, req.ProjectID, req.TaskID)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
 	}
 
-	// Get existing time entry to check if client changed
+	// Get existing time entry to check if what it is recorded against changed
 	existingEntry, err := h.queries.GetTimeEntryByID(c.Request().Context(), db.GetTimeEntryByIDParams{
 		ID:     int32(id),
 		UserID: userID,
@@ -431,30 +455,76 @@ func (h *TimeEntryHandler) UpdateTimeEntry(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch time entry"})
 	}
 
-	// Determine hourly rate: if client changed, fetch new client's rate; otherwise keep existing rate
+	// Keep the time the entry was paused, e.g. while its timer was, so that
+	// its hours do not grow back to the whole period
+	pausedSeconds := existingEntry.PausedSeconds
+	if req.PausedSeconds != nil {
+		pausedSeconds = *req.PausedSeconds
+	}
+	period, msg := parseTimeEntryPeriod(req.Date, req.Hours, req.StartedAt, req.EndedAt, pausedSeconds)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	locked, err := h.queries.IsTimeEntryLocked(c.Request().Context(), existingEntry.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch time entry"})
+	}
+	if locked {
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Time entry is billed on an issued invoice and can no longer be edited"})
+	}
+
+	// Determine hourly rate and billability: if the client, project or task
+	// changed, capture the new one's current rate and default; otherwise keep
+	// the existing ones
 	hourlyRate := existingEntry.HourlyRate
-	if existingEntry.ClientID != req.ClientID {
-		client, err := h.queries.GetClientByID(c.Request().Context(), db.GetClientByIDParams{
-			ID:     req.ClientID,
-			UserID: userID,
-		})
+	billable := existingEntry.Billable
+	if existingEntry.ClientID != work.ClientID || existingEntry.ProjectID != work.ProjectID || existingEntry.TaskID != work.TaskID {
+		terms, err := services.ResolveWork(c.Request().Context(), h.queries, userID, work)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Client not found"})
-			}
-			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch client"})
+			return workError(c, err, "Failed to fetch hourly rate")
+		}
+		hourlyRate = terms.HourlyRate
+		billable = terms.Billable
+	}
+	if req.Billable != nil {
+		billable = *req.Billable
+	}
+
+	// Time billed on a draft invoice has to stay billable, or the invoice
+	// would bill time that no longer is
+	if billable != existingEntry.Billable {
+		invoiced, err := h.queries.IsTimeEntryInvoiced(c.Request().Context(), existingEntry.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch time entry"})
 		}
-		hourlyRate = client.HourlyRate
+		if invoiced {
+			return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Time entry is billed on an invoice, so whether it is billable cannot change. Delete the draft invoice first"})
+		}
+	}
+
+	overlaps, err := services.FindOverlaps(c.Request().Context(), h.queries, userID, existingEntry.ID, period.StartedAt, period.EndedAt)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check for overlapping time entries"})
+	}
+	if len(overlaps) > 0 && !req.AllowOverlap {
+		return c.JSON(http.StatusConflict, overlapResponse(overlaps))
 	}
 
 	timeEntry, err := h.queries.UpdateTimeEntry(c.Request().Context(), db.UpdateTimeEntryParams{
-		ID:          int32(id),
-		UserID:      userID,
-		ClientID:    req.ClientID,
-		Date:        date,
-		Hours:       fmt.Sprintf("%.2f", req.Hours),
-		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		HourlyRate:  hourlyRate,
+		ID:            int32(id),
+		UserID:        userID,
+		ClientID:      work.ClientID,
+		ProjectID:     work.ProjectID,
+		TaskID:        work.TaskID,
+		Date:          period.Date,
+		Hours:         period.Hours.StringFixed(2),
+		Description:   sql.NullString{String: req.Description, Valid: req.Description != ""},
+		HourlyRate:    hourlyRate,
+		Billable:      billable,
+		StartedAt:     period.StartedAt,
+		EndedAt:       period.EndedAt,
+		PausedSeconds: period.PausedSeconds,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -463,12 +533,19 @@ func (h *TimeEntryHandler) UpdateTimeEntry(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update time entry"})
 	}
 
-	return c.JSON(http.StatusOK, updateTimeEntryRowToResponse(timeEntry))
+	h.checkBudget(c, userID, timeEntry.ProjectID)
+	if existingEntry.ProjectID != timeEntry.ProjectID {
+		h.checkBudget(c, userID, existingEntry.ProjectID)
+	}
+
+	response := updateTimeEntryRowToResponse(timeEntry)
+	response.Overlaps = overlapIDs(overlaps)
+	return c.JSON(http.StatusOK, response)
 }
 
 // DeleteTimeEntry godoc
 // @Summary Delete a time entry
-// @Description Delete a time entry by ID
+// @Description Delete a time entry by ID. Time entries billed on an invoice that has left draft cannot be deleted.
 // @Tags time-entries
 // @Produce json
 // @Security BearerAuth
@@ -476,6 +553,8 @@ func (h *TimeEntryHandler) UpdateTimeEntry(c echo.Context) error {
 // @Success 204 "No Content"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/time-entries/{id} [delete]
 func (h *TimeEntryHandler) DeleteTimeEntry(c echo.Context) error {
@@ -486,14 +565,29 @@ func (h *TimeEntryHandler) DeleteTimeEntry(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid time entry ID"})
 	}
 
-	err = h.queries.DeleteTimeEntry(c.Request().Context(), db.DeleteTimeEntryParams{
+	locked, err := h.queries.IsTimeEntryLocked(c.Request().Context(), int32(id))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to fetch time entry"})
+	}
+	if locked {
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Time entry is billed on an issued invoice and can no longer be deleted"})
+	}
+
+	projectID, err := h.queries.DeleteTimeEntry(c.Request().Context(), db.DeleteTimeEntryParams{
 		ID:     int32(id),
 		UserID: userID,
 	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Time entry not found"})
+		}
 		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete time entry"})
 	}
 
+	// Clear the alerts for thresholds the project is now back under, so they
+	// are alerted again when crossed again
+	h.checkBudget(c, userID, projectID)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -552,11 +646,13 @@ func (h *TimeEntryHandler) GetHeatmap(c echo.Context) error {
 	}
 
 	// Build heatmap data (aggregated hours per day)
-	heatmapData := make(map[string]float64)
+	heatmapData := make(map[string]decimal.Decimal)
+	billableHours := decimal.Zero
 	for _, entry := range entries {
 		dateKey := entry.Date.Format("2006-01-02")
-		hours, _ := strconv.ParseFloat(entry.TotalHours, 64)
+		hours := money.Parse(entry.TotalHours)
 		heatmapData[dateKey] = hours
+		billableHours = billableHours.Add(money.Parse(entry.BillableHours))
 	}
 
 	// Get individual time entries within date range (for tooltips)
@@ -591,26 +687,32 @@ func (h *TimeEntryHandler) GetHeatmap(c echo.Context) error {
 		}
 
 		entryResponse := toTimeEntryResponse(db.TimeEntry{
-			ID:          entry.ID,
-			UserID:      entry.UserID,
-			ClientID:    entry.ClientID,
-			Date:        entry.Date,
-			Hours:       entry.Hours,
-			Description: entry.Description,
-			HourlyRate:  entry.HourlyRate,
-			CreatedAt:   entry.CreatedAt,
-			UpdatedAt:   entry.UpdatedAt,
+			ID:            entry.ID,
+			UserID:        entry.UserID,
+			ClientID:      entry.ClientID,
+			ProjectID:     entry.ProjectID,
+			TaskID:        entry.TaskID,
+			Date:          entry.Date,
+			Hours:         entry.Hours,
+			Description:   entry.Description,
+			HourlyRate:    entry.HourlyRate,
+			Billable:      entry.Billable,
+			StartedAt:     entry.StartedAt,
+			EndedAt:       entry.EndedAt,
+			PausedSeconds: entry.PausedSeconds,
+			CreatedAt:     entry.CreatedAt,
+			UpdatedAt:     entry.UpdatedAt,
 		})
 		entryResponse.ClientName = clientName
 		entriesMap[dateKey] = append(entriesMap[dateKey], entryResponse)
 	}
 
 	// Calculate statistics
-	totalHours := 0.0
+	totalHours := decimal.Zero
 	daysWorked := 0
 	for _, hours := range heatmapData {
-		totalHours += hours
-		if hours > 0 {
+		totalHours = totalHours.Add(hours)
+		if hours.IsPositive() {
 			daysWorked++
 		}
 	}
@@ -618,101 +720,246 @@ func (h *TimeEntryHandler) GetHeatmap(c echo.Context) error {
 	// Calculate total days in the date range
 	totalDays := int(endDate.Sub(startDate).Hours()/24) + 1
 	daysOff := totalDays - daysWorked
-	averageHours := 0.0
+	averageHours := decimal.Zero
 	if daysWorked > 0 {
-		averageHours = totalHours / float64(daysWorked)
+		averageHours = totalHours.Div(decimal.NewFromInt(int64(daysWorked))).Round(2)
 	}
 
 	response := models.HeatmapResponse{
-		StartDate:    startDate.Format("2006-01-02"),
-		EndDate:      endDate.Format("2006-01-02"),
-		Data:         heatmapData,
-		Entries:      entriesMap,
-		TotalHours:   totalHours,
-		DaysWorked:   daysWorked,
-		DaysOff:      daysOff,
-		AverageHours: averageHours,
+		StartDate:           startDate.Format("2006-01-02"),
+		EndDate:             endDate.Format("2006-01-02"),
+		Data:                heatmapData,
+		Entries:             entriesMap,
+		TotalHours:          totalHours,
+		BillableHours:       billableHours,
+		BillableUtilization: billableUtilization(billableHours, totalHours),
+		DaysWorked:          daysWorked,
+		DaysOff:             daysOff,
+		AverageHours:        averageHours,
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
+// timeEntryPeriod is when the work of a time entry was done
+type timeEntryPeriod struct {
+	Date          time.Time
+	Hours         decimal.Decimal
+	StartedAt     sql.NullTime
+	EndedAt       sql.NullTime
+	PausedSeconds int32
+}
+
+// parseTimeEntryPeriod parses the date, hours and optional start, end and
+// paused time of a time entry request. When the start and end are given,
+// hours is derived from them less the paused time, and the date defaults to
+// the day work started. It returns an error message when the request is
+// invalid.
+func parseTimeEntryPeriod(date string, hours decimal.Decimal, startedAt, endedAt string, pausedSeconds int32) (timeEntryPeriod, string) {
+	period := timeEntryPeriod{Hours: hours}
+
+	if startedAt != "" || endedAt != "" {
+		if startedAt == "" || endedAt == "" {
+			return period, "started_at and ended_at must be given together"
+		}
+		start, err := time.Parse(time.RFC3339, startedAt)
+		if err != nil {
+			return period, "Invalid started_at format. Use RFC 3339, e.g. 2025-01-31T09:00:00+01:00"
+		}
+		end, err := time.Parse(time.RFC3339, endedAt)
+		if err != nil {
+			return period, "Invalid ended_at format. Use RFC 3339, e.g. 2025-01-31T17:00:00+01:00"
+		}
+		if !end.After(start) {
+			return period, "ended_at must be after started_at"
+		}
+		if end.Sub(start) > services.MaxTimeEntryPeriod {
+			return period, "A time entry cannot span more than 24 hours"
+		}
+
+		if pausedSeconds < 0 {
+			return period, "paused_seconds cannot be negative"
+		}
+
+		period.StartedAt = sql.NullTime{Time: start, Valid: true}
+		period.EndedAt = sql.NullTime{Time: end, Valid: true}
+		period.PausedSeconds = pausedSeconds
+		period.Hours = services.ElapsedHours(int32(end.Sub(start)/time.Second) - pausedSeconds)
+		if !period.Hours.IsPositive() {
+			return period, "The period between started_at and ended_at, less paused_seconds, is too short to record"
+		}
+
+		if date == "" {
+			// The day work started, in the timezone it was recorded in
+			period.Date = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+			return period, ""
+		}
+	}
+
+	// Dates are midnight UTC of the calendar day, like those timers record
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return period, "Invalid date format. Use YYYY-MM-DD"
+	}
+	period.Date = parsed
+	return period, ""
+}
+
+// checkBudget alerts the user when time recorded against the project pushed
+// its budgets over an alert threshold. The time entry is saved either way, so
+// failures are only logged.
+func (h *TimeEntryHandler) checkBudget(c echo.Context, userID int32, projectID sql.NullInt32) {
+	if err := h.budgetService.CheckProject(c.Request().Context(), userID, projectID); err != nil {
+		c.Logger().Error("Failed to check project budget: ", err)
+	}
+}
+
+// overlapResponse describes the entries a rejected time entry overlaps
+func overlapResponse(overlaps []db.TimeEntry) models.TimeEntryOverlapResponse {
+	response := models.TimeEntryOverlapResponse{
+		Error:    "Time entry overlaps other time entries. Set allow_overlap to record it anyway",
+		Overlaps: make([]models.TimeEntryResponse, len(overlaps)),
+	}
+	for i, entry := range overlaps {
+		response.Overlaps[i] = toTimeEntryResponse(entry)
+	}
+	return response
+}
+
+func overlapIDs(overlaps []db.TimeEntry) []int32 {
+	if len(overlaps) == 0 {
+		return nil
+	}
+	ids := make([]int32, len(overlaps))
+	for i, entry := range overlaps {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+// billableUtilization returns the percentage of hours that were billable,
+// rounded to two decimals, or zero when no time was recorded
+func billableUtilization(billableHours, totalHours decimal.Decimal) decimal.Decimal {
+	if !totalHours.IsPositive() {
+		return decimal.Zero
+	}
+	return billableHours.Mul(decimal.NewFromInt(100)).Div(totalHours).Round(2)
+}
+
+// formatTimestamp formats a timezone-aware timestamp in UTC
+func formatTimestamp(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
+
 func toTimeEntryResponse(entry db.TimeEntry) models.TimeEntryResponse {
-	hours, _ := strconv.ParseFloat(entry.Hours, 64)
-	hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
+	hours := money.Parse(entry.Hours)
+	hourlyRate := money.ParseNull(entry.HourlyRate)
 	return models.TimeEntryResponse{
-		ID:          entry.ID,
-		UserID:      entry.UserID,
-		ClientID:    entry.ClientID,
-		Date:        entry.Date.Format("2006-01-02"),
-		Hours:       hours,
-		Description: entry.Description.String,
-		HourlyRate:  hourlyRate,
-		CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		ClientID:      entry.ClientID,
+		ProjectID:     entry.ProjectID.Int32,
+		TaskID:        entry.TaskID.Int32,
+		Date:          entry.Date.Format("2006-01-02"),
+		Hours:         hours,
+		Description:   entry.Description.String,
+		HourlyRate:    hourlyRate,
+		Billable:      entry.Billable,
+		StartedAt:     formatTimestamp(entry.StartedAt),
+		EndedAt:       formatTimestamp(entry.EndedAt),
+		PausedSeconds: entry.PausedSeconds,
+		CreatedAt:     entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func createTimeEntryRowToResponse(entry db.CreateTimeEntryRow) models.TimeEntryResponse {
-	hours, _ := strconv.ParseFloat(entry.Hours, 64)
-	hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
+	hours := money.Parse(entry.Hours)
+	hourlyRate := money.ParseNull(entry.HourlyRate)
 	return models.TimeEntryResponse{
-		ID:          entry.ID,
-		UserID:      entry.UserID,
-		ClientID:    entry.ClientID,
-		Date:        entry.Date.Format("2006-01-02"),
-		Hours:       hours,
-		Description: entry.Description.String,
-		HourlyRate:  hourlyRate,
-		CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		ClientID:      entry.ClientID,
+		ProjectID:     entry.ProjectID.Int32,
+		TaskID:        entry.TaskID.Int32,
+		Date:          entry.Date.Format("2006-01-02"),
+		Hours:         hours,
+		Description:   entry.Description.String,
+		HourlyRate:    hourlyRate,
+		Billable:      entry.Billable,
+		StartedAt:     formatTimestamp(entry.StartedAt),
+		EndedAt:       formatTimestamp(entry.EndedAt),
+		PausedSeconds: entry.PausedSeconds,
+		CreatedAt:     entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func getTimeEntryByIDRowToResponse(entry db.GetTimeEntryByIDRow) models.TimeEntryResponse {
-	hours, _ := strconv.ParseFloat(entry.Hours, 64)
-	hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
+	hours := money.Parse(entry.Hours)
+	hourlyRate := money.ParseNull(entry.HourlyRate)
 	return models.TimeEntryResponse{
-		ID:          entry.ID,
-		UserID:      entry.UserID,
-		ClientID:    entry.ClientID,
-		Date:        entry.Date.Format("2006-01-02"),
-		Hours:       hours,
-		Description: entry.Description.String,
-		HourlyRate:  hourlyRate,
-		CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		ClientID:      entry.ClientID,
+		ProjectID:     entry.ProjectID.Int32,
+		TaskID:        entry.TaskID.Int32,
+		Date:          entry.Date.Format("2006-01-02"),
+		Hours:         hours,
+		Description:   entry.Description.String,
+		HourlyRate:    hourlyRate,
+		Billable:      entry.Billable,
+		StartedAt:     formatTimestamp(entry.StartedAt),
+		EndedAt:       formatTimestamp(entry.EndedAt),
+		PausedSeconds: entry.PausedSeconds,
+		CreatedAt:     entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func getTimeEntriesByUserIDRowToResponse(entry db.GetTimeEntriesByUserIDRow) models.TimeEntryResponse {
-	hours, _ := strconv.ParseFloat(entry.Hours, 64)
-	hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
+	hours := money.Parse(entry.Hours)
+	hourlyRate := money.ParseNull(entry.HourlyRate)
 	return models.TimeEntryResponse{
-		ID:          entry.ID,
-		UserID:      entry.UserID,
-		ClientID:    entry.ClientID,
-		Date:        entry.Date.Format("2006-01-02"),
-		Hours:       hours,
-		Description: entry.Description.String,
-		HourlyRate:  hourlyRate,
-		CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		ClientID:      entry.ClientID,
+		ProjectID:     entry.ProjectID.Int32,
+		TaskID:        entry.TaskID.Int32,
+		Date:          entry.Date.Format("2006-01-02"),
+		Hours:         hours,
+		Description:   entry.Description.String,
+		HourlyRate:    hourlyRate,
+		Billable:      entry.Billable,
+		StartedAt:     formatTimestamp(entry.StartedAt),
+		EndedAt:       formatTimestamp(entry.EndedAt),
+		PausedSeconds: entry.PausedSeconds,
+		CreatedAt:     entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
 func updateTimeEntryRowToResponse(entry db.UpdateTimeEntryRow) models.TimeEntryResponse {
-	hours, _ := strconv.ParseFloat(entry.Hours, 64)
-	hourlyRate, _ := strconv.ParseFloat(entry.HourlyRate.String, 64)
+	hours := money.Parse(entry.Hours)
+	hourlyRate := money.ParseNull(entry.HourlyRate)
 	return models.TimeEntryResponse{
-		ID:          entry.ID,
-		UserID:      entry.UserID,
-		ClientID:    entry.ClientID,
-		Date:        entry.Date.Format("2006-01-02"),
-		Hours:       hours,
-		Description: entry.Description.String,
-		HourlyRate:  hourlyRate,
-		CreatedAt:   entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		ClientID:      entry.ClientID,
+		ProjectID:     entry.ProjectID.Int32,
+		TaskID:        entry.TaskID.Int32,
+		Date:          entry.Date.Format("2006-01-02"),
+		Hours:         hours,
+		Description:   entry.Description.String,
+		HourlyRate:    hourlyRate,
+		Billable:      entry.Billable,
+		StartedAt:     formatTimestamp(entry.StartedAt),
+		EndedAt:       formatTimestamp(entry.EndedAt),
+		PausedSeconds: entry.PausedSeconds,
+		CreatedAt:     entry.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     entry.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
 	}
 }
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TimerHandler struct {
+	timerService *services.TimerService
+}
+
+func NewTimerHandler(timerService *services.TimerService) *TimerHandler {
+	return &TimerHandler{
+		timerService: timerService,
+	}
+}
+
+// GetCurrentTimer godoc
+// @Summary Get the current timer
+// @Description Get the authenticated user's running or paused timer
+// @Tags timers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimerResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/timers/current [get]
+func (h *TimerHandler) GetCurrentTimer(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	timer, err := h.timerService.Current(c.Request().Context(), userID)
+	if err != nil {
+		return timerError(c, err, "Failed to get timer")
+	}
+
+	return c.JSON(http.StatusOK, h.timerToResponse(timer))
+}
+
+// StartTimer godoc
+// @Summary Start a timer
+// @Description Start a timer for a client, optionally for one of its projects and tasks. Only one timer can be running or paused at a time; stop it to record its time as a time entry.
+// @Tags timers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.StartTimerRequest true "Start Timer Request"
+// @Success 201 {object} models.TimerResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/timers/start [post]
+func (h *TimerHandler) StartTimer(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.StartTimerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+	if req.ClientID == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Client is required"})
+	}
+	work, msg := parseWork(req.ClientID, req.ProjectID, req.TaskID)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+	}
+
+	timer, err := h.timerService.Start(c.Request().Context(), userID, work, req.Description)
+	if err != nil {
+		return timerError(c, err, "Failed to start timer")
+	}
+
+	return c.JSON(http.StatusCreated, h.timerToResponse(timer))
+}
+
+// PauseTimer godoc
+// @Summary Pause the running timer
+// @Description Pause the authenticated user's running timer. Paused time is not counted.
+// @Tags timers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimerResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/timers/pause [post]
+func (h *TimerHandler) PauseTimer(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	timer, err := h.timerService.Pause(c.Request().Context(), userID)
+	if err != nil {
+		return timerError(c, err, "Failed to pause timer")
+	}
+
+	return c.JSON(http.StatusOK, h.timerToResponse(timer))
+}
+
+// ResumeTimer godoc
+// @Summary Resume the paused timer
+// @Description Resume the authenticated user's paused timer
+// @Tags timers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimerResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/timers/resume [post]
+func (h *TimerHandler) ResumeTimer(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	timer, err := h.timerService.Resume(c.Request().Context(), userID)
+	if err != nil {
+		return timerError(c, err, "Failed to resume timer")
+	}
+
+	return c.JSON(http.StatusOK, h.timerToResponse(timer))
+}
+
+// StopTimer godoc
+// @Summary Stop the current timer
+// @Description Stop the authenticated user's running or paused timer and record the time it ran as a time entry, dated the day the timer was started in the user's timezone and rounded to the nearest hundredth of an hour. The time the timer was paused is recorded as the entry's paused_seconds and left out of its hours. The entry is rejected when its period overlaps another of the user's entries unless allow_overlap is set, and when the timer was started more than 24 hours ago.
+// @Tags timers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.StopTimerRequest false "Stop Timer Request"
+// @Success 200 {object} models.TimerResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.TimeEntryOverlapResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/timers/stop [post]
+func (h *TimerHandler) StopTimer(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	var req models.StopTimerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+	}
+
+	timer, entry, err := h.timerService.Stop(c.Request().Context(), userID, req.AllowOverlap)
+	if err != nil {
+		return timerError(c, err, "Failed to stop timer")
+	}
+
+	response := h.timerToResponse(timer)
+	timeEntry := createTimeEntryRowToResponse(entry)
+	response.TimeEntry = &timeEntry
+	return c.JSON(http.StatusOK, response)
+}
+
+// DiscardTimer godoc
+// @Summary Discard the current timer
+// @Description Delete the authenticated user's running or paused timer without recording its time
+// @Tags timers
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/timers/current [delete]
+func (h *TimerHandler) DiscardTimer(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+
+	if err := h.timerService.Discard(c.Request().Context(), userID); err != nil {
+		return timerError(c, err, "Failed to discard timer")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// timerError maps timer service errors to responses, falling back to
+// workError
+func timerError(c echo.Context, err error, fallback string) error {
+	var overlapErr *services.OverlapError
+	switch {
+	case errors.As(err, &overlapErr):
+		return c.JSON(http.StatusConflict, overlapResponse(overlapErr.Overlaps))
+	case errors.Is(err, services.ErrNoActiveTimer):
+		return c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "No timer is running"})
+	case errors.Is(err, services.ErrTimerActive):
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "A timer is already running. Stop it before starting another"})
+	case errors.Is(err, services.ErrTimerNotRunning):
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Timer is already paused"})
+	case errors.Is(err, services.ErrTimerNotPaused):
+		return c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Timer is already running"})
+	case errors.Is(err, services.ErrTimerTooShort):
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Timer has not run long enough to record. Discard it instead"})
+	case errors.Is(err, services.ErrTimerTooLong):
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Timer was started more than 24 hours ago, longer than a time entry can span. Discard it and record the time by hand"})
+	default:
+		return workError(c, err, fallback)
+	}
+}
+
+func (h *TimerHandler) timerToResponse(timer db.Timer) models.TimerResponse {
+	elapsed := h.timerService.Elapsed(timer)
+	response := models.TimerResponse{
+		ID:             timer.ID,
+		UserID:         timer.UserID,
+		ClientID:       timer.ClientID,
+		ProjectID:      timer.ProjectID.Int32,
+		TaskID:         timer.TaskID.Int32,
+		Description:    timer.Description.String,
+		Status:         timer.Status,
+		StartedAt:      timer.StartedAt.Format("2006-01-02T15:04:05Z"),
+		ElapsedSeconds: elapsed,
+		Hours:          services.ElapsedHours(elapsed),
+		CreatedAt:      timer.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      timer.UpdatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+	if timer.StoppedAt.Valid {
+		response.StoppedAt = timer.StoppedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
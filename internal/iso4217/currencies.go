@@ -0,0 +1,165 @@
+package iso4217
+
+// currencies lists the active ISO 4217 currencies, leaving out funds,
+// precious metals and testing codes. Minor units follow ISO 4217 except for
+// IDR, listed with 2 but invoiced in whole rupiah since smaller units are no
+// longer in use.
+var currencies = map[string]Currency{
+	"AED": {Code: "AED", Symbol: "د.إ", Name: "UAE Dirham", MinorUnits: 2},
+	"AFN": {Code: "AFN", Symbol: "؋", Name: "Afghani", MinorUnits: 2},
+	"ALL": {Code: "ALL", Symbol: "L", Name: "Lek", MinorUnits: 2},
+	"AMD": {Code: "AMD", Symbol: "֏", Name: "Armenian Dram", MinorUnits: 2},
+	"ANG": {Code: "ANG", Symbol: "ƒ", Name: "Netherlands Antillean Guilder", MinorUnits: 2},
+	"AOA": {Code: "AOA", Symbol: "Kz", Name: "Kwanza", MinorUnits: 2},
+	"ARS": {Code: "ARS", Symbol: "$", Name: "Argentine Peso", MinorUnits: 2},
+	"AUD": {Code: "AUD", Symbol: "A$", Name: "Australian Dollar", MinorUnits: 2},
+	"AWG": {Code: "AWG", Symbol: "ƒ", Name: "Aruban Florin", MinorUnits: 2},
+	"AZN": {Code: "AZN", Symbol: "₼", Name: "Azerbaijan Manat", MinorUnits: 2},
+	"BAM": {Code: "BAM", Symbol: "KM", Name: "Convertible Mark", MinorUnits: 2},
+	"BBD": {Code: "BBD", Symbol: "Bds$", Name: "Barbados Dollar", MinorUnits: 2},
+	"BDT": {Code: "BDT", Symbol: "৳", Name: "Taka", MinorUnits: 2},
+	"BGN": {Code: "BGN", Symbol: "лв", Name: "Bulgarian Lev", MinorUnits: 2},
+	"BHD": {Code: "BHD", Symbol: "BD", Name: "Bahraini Dinar", MinorUnits: 3},
+	"BIF": {Code: "BIF", Symbol: "FBu", Name: "Burundi Franc", MinorUnits: 0},
+	"BMD": {Code: "BMD", Symbol: "$", Name: "Bermudian Dollar", MinorUnits: 2},
+	"BND": {Code: "BND", Symbol: "B$", Name: "Brunei Dollar", MinorUnits: 2},
+	"BOB": {Code: "BOB", Symbol: "Bs.", Name: "Boliviano", MinorUnits: 2},
+	"BRL": {Code: "BRL", Symbol: "R$", Name: "Brazilian Real", MinorUnits: 2},
+	"BSD": {Code: "BSD", Symbol: "$", Name: "Bahamian Dollar", MinorUnits: 2},
+	"BTN": {Code: "BTN", Symbol: "Nu.", Name: "Ngultrum", MinorUnits: 2},
+	"BWP": {Code: "BWP", Symbol: "P", Name: "Pula", MinorUnits: 2},
+	"BYN": {Code: "BYN", Symbol: "Br", Name: "Belarusian Ruble", MinorUnits: 2},
+	"BZD": {Code: "BZD", Symbol: "BZ$", Name: "Belize Dollar", MinorUnits: 2},
+	"CAD": {Code: "CAD", Symbol: "C$", Name: "Canadian Dollar", MinorUnits: 2},
+	"CDF": {Code: "CDF", Symbol: "FC", Name: "Congolese Franc", MinorUnits: 2},
+	"CHF": {Code: "CHF", Symbol: "CHF", Name: "Swiss Franc", MinorUnits: 2},
+	"CLP": {Code: "CLP", Symbol: "$", Name: "Chilean Peso", MinorUnits: 0},
+	"CNY": {Code: "CNY", Symbol: "¥", Name: "Chinese Yuan", MinorUnits: 2},
+	"COP": {Code: "COP", Symbol: "$", Name: "Colombian Peso", MinorUnits: 2},
+	"CRC": {Code: "CRC", Symbol: "₡", Name: "Costa Rican Colon", MinorUnits: 2},
+	"CUP": {Code: "CUP", Symbol: "$", Name: "Cuban Peso", MinorUnits: 2},
+	"CVE": {Code: "CVE", Symbol: "Esc", Name: "Cabo Verde Escudo", MinorUnits: 2},
+	"CZK": {Code: "CZK", Symbol: "Kč", Name: "Czech Koruna", MinorUnits: 2},
+	"DJF": {Code: "DJF", Symbol: "Fdj", Name: "Djibouti Franc", MinorUnits: 0},
+	"DKK": {Code: "DKK", Symbol: "kr", Name: "Danish Krone", MinorUnits: 2},
+	"DOP": {Code: "DOP", Symbol: "RD$", Name: "Dominican Peso", MinorUnits: 2},
+	"DZD": {Code: "DZD", Symbol: "DA", Name: "Algerian Dinar", MinorUnits: 2},
+	"EGP": {Code: "EGP", Symbol: "E£", Name: "Egyptian Pound", MinorUnits: 2},
+	"ERN": {Code: "ERN", Symbol: "Nfk", Name: "Nakfa", MinorUnits: 2},
+	"ETB": {Code: "ETB", Symbol: "Br", Name: "Ethiopian Birr", MinorUnits: 2},
+	"EUR": {Code: "EUR", Symbol: "€", Name: "Euro", MinorUnits: 2},
+	"FJD": {Code: "FJD", Symbol: "FJ$", Name: "Fiji Dollar", MinorUnits: 2},
+	"FKP": {Code: "FKP", Symbol: "£", Name: "Falkland Islands Pound", MinorUnits: 2},
+	"GBP": {Code: "GBP", Symbol: "£", Name: "British Pound", MinorUnits: 2},
+	"GEL": {Code: "GEL", Symbol: "₾", Name: "Lari", MinorUnits: 2},
+	"GHS": {Code: "GHS", Symbol: "GH₵", Name: "Ghana Cedi", MinorUnits: 2},
+	"GIP": {Code: "GIP", Symbol: "£", Name: "Gibraltar Pound", MinorUnits: 2},
+	"GMD": {Code: "GMD", Symbol: "D", Name: "Dalasi", MinorUnits: 2},
+	"GNF": {Code: "GNF", Symbol: "FG", Name: "Guinean Franc", MinorUnits: 0},
+	"GTQ": {Code: "GTQ", Symbol: "Q", Name: "Quetzal", MinorUnits: 2},
+	"GYD": {Code: "GYD", Symbol: "G$", Name: "Guyana Dollar", MinorUnits: 2},
+	"HKD": {Code: "HKD", Symbol: "HK$", Name: "Hong Kong Dollar", MinorUnits: 2},
+	"HNL": {Code: "HNL", Symbol: "L", Name: "Lempira", MinorUnits: 2},
+	"HTG": {Code: "HTG", Symbol: "G", Name: "Gourde", MinorUnits: 2},
+	"HUF": {Code: "HUF", Symbol: "Ft", Name: "Forint", MinorUnits: 2},
+	"IDR": {Code: "IDR", Symbol: "Rp", Name: "Indonesian Rupiah", MinorUnits: 0},
+	"ILS": {Code: "ILS", Symbol: "₪", Name: "New Israeli Shekel", MinorUnits: 2},
+	"INR": {Code: "INR", Symbol: "₹", Name: "Indian Rupee", MinorUnits: 2},
+	"IQD": {Code: "IQD", Symbol: "IQD", Name: "Iraqi Dinar", MinorUnits: 3},
+	"IRR": {Code: "IRR", Symbol: "﷼", Name: "Iranian Rial", MinorUnits: 2},
+	"ISK": {Code: "ISK", Symbol: "kr", Name: "Iceland Krona", MinorUnits: 0},
+	"JMD": {Code: "JMD", Symbol: "J$", Name: "Jamaican Dollar", MinorUnits: 2},
+	"JOD": {Code: "JOD", Symbol: "JD", Name: "Jordanian Dinar", MinorUnits: 3},
+	"JPY": {Code: "JPY", Symbol: "¥", Name: "Japanese Yen", MinorUnits: 0},
+	"KES": {Code: "KES", Symbol: "KSh", Name: "Kenyan Shilling", MinorUnits: 2},
+	"KGS": {Code: "KGS", Symbol: "с", Name: "Som", MinorUnits: 2},
+	"KHR": {Code: "KHR", Symbol: "៛", Name: "Riel", MinorUnits: 2},
+	"KMF": {Code: "KMF", Symbol: "CF", Name: "Comorian Franc", MinorUnits: 0},
+	"KPW": {Code: "KPW", Symbol: "₩", Name: "North Korean Won", MinorUnits: 2},
+	"KRW": {Code: "KRW", Symbol: "₩", Name: "South Korean Won", MinorUnits: 0},
+	"KWD": {Code: "KWD", Symbol: "KD", Name: "Kuwaiti Dinar", MinorUnits: 3},
+	"KYD": {Code: "KYD", Symbol: "CI$", Name: "Cayman Islands Dollar", MinorUnits: 2},
+	"KZT": {Code: "KZT", Symbol: "₸", Name: "Tenge", MinorUnits: 2},
+	"LAK": {Code: "LAK", Symbol: "₭", Name: "Lao Kip", MinorUnits: 2},
+	"LBP": {Code: "LBP", Symbol: "L£", Name: "Lebanese Pound", MinorUnits: 2},
+	"LKR": {Code: "LKR", Symbol: "Rs", Name: "Sri Lanka Rupee", MinorUnits: 2},
+	"LRD": {Code: "LRD", Symbol: "L$", Name: "Liberian Dollar", MinorUnits: 2},
+	"LSL": {Code: "LSL", Symbol: "L", Name: "Loti", MinorUnits: 2},
+	"LYD": {Code: "LYD", Symbol: "LD", Name: "Libyan Dinar", MinorUnits: 3},
+	"MAD": {Code: "MAD", Symbol: "DH", Name: "Moroccan Dirham", MinorUnits: 2},
+	"MDL": {Code: "MDL", Symbol: "L", Name: "Moldovan Leu", MinorUnits: 2},
+	"MGA": {Code: "MGA", Symbol: "Ar", Name: "Malagasy Ariary", MinorUnits: 2},
+	"MKD": {Code: "MKD", Symbol: "ден", Name: "Denar", MinorUnits: 2},
+	"MMK": {Code: "MMK", Symbol: "K", Name: "Kyat", MinorUnits: 2},
+	"MNT": {Code: "MNT", Symbol: "₮", Name: "Tugrik", MinorUnits: 2},
+	"MOP": {Code: "MOP", Symbol: "MOP$", Name: "Pataca", MinorUnits: 2},
+	"MRU": {Code: "MRU", Symbol: "UM", Name: "Ouguiya", MinorUnits: 2},
+	"MUR": {Code: "MUR", Symbol: "Rs", Name: "Mauritius Rupee", MinorUnits: 2},
+	"MVR": {Code: "MVR", Symbol: "Rf", Name: "Rufiyaa", MinorUnits: 2},
+	"MWK": {Code: "MWK", Symbol: "MK", Name: "Malawi Kwacha", MinorUnits: 2},
+	"MXN": {Code: "MXN", Symbol: "MX$", Name: "Mexican Peso", MinorUnits: 2},
+	"MYR": {Code: "MYR", Symbol: "RM", Name: "Malaysian Ringgit", MinorUnits: 2},
+	"MZN": {Code: "MZN", Symbol: "MT", Name: "Mozambique Metical", MinorUnits: 2},
+	"NAD": {Code: "NAD", Symbol: "N$", Name: "Namibia Dollar", MinorUnits: 2},
+	"NGN": {Code: "NGN", Symbol: "₦", Name: "Naira", MinorUnits: 2},
+	"NIO": {Code: "NIO", Symbol: "C$", Name: "Cordoba Oro", MinorUnits: 2},
+	"NOK": {Code: "NOK", Symbol: "kr", Name: "Norwegian Krone", MinorUnits: 2},
+	"NPR": {Code: "NPR", Symbol: "Rs", Name: "Nepalese Rupee", MinorUnits: 2},
+	"NZD": {Code: "NZD", Symbol: "NZ$", Name: "New Zealand Dollar", MinorUnits: 2},
+	"OMR": {Code: "OMR", Symbol: "RO", Name: "Rial Omani", MinorUnits: 3},
+	"PAB": {Code: "PAB", Symbol: "B/.", Name: "Balboa", MinorUnits: 2},
+	"PEN": {Code: "PEN", Symbol: "S/", Name: "Sol", MinorUnits: 2},
+	"PGK": {Code: "PGK", Symbol: "K", Name: "Kina", MinorUnits: 2},
+	"PHP": {Code: "PHP", Symbol: "₱", Name: "Philippine Peso", MinorUnits: 2},
+	"PKR": {Code: "PKR", Symbol: "Rs", Name: "Pakistan Rupee", MinorUnits: 2},
+	"PLN": {Code: "PLN", Symbol: "zł", Name: "Zloty", MinorUnits: 2},
+	"PYG": {Code: "PYG", Symbol: "₲", Name: "Guarani", MinorUnits: 0},
+	"QAR": {Code: "QAR", Symbol: "QR", Name: "Qatari Rial", MinorUnits: 2},
+	"RON": {Code: "RON", Symbol: "lei", Name: "Romanian Leu", MinorUnits: 2},
+	"RSD": {Code: "RSD", Symbol: "дин.", Name: "Serbian Dinar", MinorUnits: 2},
+	"RUB": {Code: "RUB", Symbol: "₽", Name: "Russian Ruble", MinorUnits: 2},
+	"RWF": {Code: "RWF", Symbol: "FRw", Name: "Rwanda Franc", MinorUnits: 0},
+	"SAR": {Code: "SAR", Symbol: "SR", Name: "Saudi Riyal", MinorUnits: 2},
+	"SBD": {Code: "SBD", Symbol: "SI$", Name: "Solomon Islands Dollar", MinorUnits: 2},
+	"SCR": {Code: "SCR", Symbol: "SR", Name: "Seychelles Rupee", MinorUnits: 2},
+	"SDG": {Code: "SDG", Symbol: "£", Name: "Sudanese Pound", MinorUnits: 2},
+	"SEK": {Code: "SEK", Symbol: "kr", Name: "Swedish Krona", MinorUnits: 2},
+	"SGD": {Code: "SGD", Symbol: "S$", Name: "Singapore Dollar", MinorUnits: 2},
+	"SHP": {Code: "SHP", Symbol: "£", Name: "Saint Helena Pound", MinorUnits: 2},
+	"SLE": {Code: "SLE", Symbol: "Le", Name: "Leone", MinorUnits: 2},
+	"SOS": {Code: "SOS", Symbol: "Sh", Name: "Somali Shilling", MinorUnits: 2},
+	"SRD": {Code: "SRD", Symbol: "$", Name: "Surinam Dollar", MinorUnits: 2},
+	"SSP": {Code: "SSP", Symbol: "£", Name: "South Sudanese Pound", MinorUnits: 2},
+	"STN": {Code: "STN", Symbol: "Db", Name: "Dobra", MinorUnits: 2},
+	"SVC": {Code: "SVC", Symbol: "₡", Name: "El Salvador Colon", MinorUnits: 2},
+	"SYP": {Code: "SYP", Symbol: "£S", Name: "Syrian Pound", MinorUnits: 2},
+	"SZL": {Code: "SZL", Symbol: "E", Name: "Lilangeni", MinorUnits: 2},
+	"THB": {Code: "THB", Symbol: "฿", Name: "Baht", MinorUnits: 2},
+	"TJS": {Code: "TJS", Symbol: "SM", Name: "Somoni", MinorUnits: 2},
+	"TMT": {Code: "TMT", Symbol: "m", Name: "Turkmenistan New Manat", MinorUnits: 2},
+	"TND": {Code: "TND", Symbol: "DT", Name: "Tunisian Dinar", MinorUnits: 3},
+	"TOP": {Code: "TOP", Symbol: "T$", Name: "Pa'anga", MinorUnits: 2},
+	"TRY": {Code: "TRY", Symbol: "₺", Name: "Turkish Lira", MinorUnits: 2},
+	"TTD": {Code: "TTD", Symbol: "TT$", Name: "Trinidad and Tobago Dollar", MinorUnits: 2},
+	"TWD": {Code: "TWD", Symbol: "NT$", Name: "New Taiwan Dollar", MinorUnits: 2},
+	"TZS": {Code: "TZS", Symbol: "TSh", Name: "Tanzanian Shilling", MinorUnits: 2},
+	"UAH": {Code: "UAH", Symbol: "₴", Name: "Hryvnia", MinorUnits: 2},
+	"UGX": {Code: "UGX", Symbol: "USh", Name: "Uganda Shilling", MinorUnits: 0},
+	"USD": {Code: "USD", Symbol: "$", Name: "US Dollar", MinorUnits: 2},
+	"UYU": {Code: "UYU", Symbol: "$U", Name: "Peso Uruguayo", MinorUnits: 2},
+	"UZS": {Code: "UZS", Symbol: "so'm", Name: "Uzbekistan Sum", MinorUnits: 2},
+	"VED": {Code: "VED", Symbol: "Bs.D", Name: "Bolivar Digital", MinorUnits: 2},
+	"VES": {Code: "VES", Symbol: "Bs.S", Name: "Bolivar Soberano", MinorUnits: 2},
+	"VND": {Code: "VND", Symbol: "₫", Name: "Dong", MinorUnits: 0},
+	"VUV": {Code: "VUV", Symbol: "VT", Name: "Vatu", MinorUnits: 0},
+	"WST": {Code: "WST", Symbol: "WS$", Name: "Tala", MinorUnits: 2},
+	"XAF": {Code: "XAF", Symbol: "FCFA", Name: "CFA Franc BEAC", MinorUnits: 0},
+	"XCD": {Code: "XCD", Symbol: "EC$", Name: "East Caribbean Dollar", MinorUnits: 2},
+	"XCG": {Code: "XCG", Symbol: "Cg", Name: "Caribbean Guilder", MinorUnits: 2},
+	"XOF": {Code: "XOF", Symbol: "CFA", Name: "CFA Franc BCEAO", MinorUnits: 0},
+	"XPF": {Code: "XPF", Symbol: "F", Name: "CFP Franc", MinorUnits: 0},
+	"YER": {Code: "YER", Symbol: "﷼", Name: "Yemeni Rial", MinorUnits: 2},
+	"ZAR": {Code: "ZAR", Symbol: "R", Name: "Rand", MinorUnits: 2},
+	"ZMW": {Code: "ZMW", Symbol: "ZK", Name: "Zambian Kwacha", MinorUnits: 2},
+	"ZWG": {Code: "ZWG", Symbol: "ZiG", Name: "Zimbabwe Gold", MinorUnits: 2},
+}
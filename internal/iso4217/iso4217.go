@@ -0,0 +1,77 @@
+// Package iso4217 is a registry of the ISO 4217 currencies that invoices can
+// be issued in, with the symbol and number of minor units of each.
+package iso4217
+
+import (
+	"sort"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency. MinorUnits is the number of decimals
+// amounts in the currency are expressed with, e.g. 2 for USD and 0 for JPY.
+type Currency struct {
+	Code       string
+	Symbol     string
+	Name       string
+	MinorUnits int
+}
+
+// DefaultMinorUnits is used for codes missing from the registry
+const DefaultMinorUnits = 2
+
+// MaxMinorUnits is the largest number of minor units of any currency in the
+// registry, which is the precision amounts are stored with
+const MaxMinorUnits = 3
+
+// Lookup returns the currency with the given code
+func Lookup(code string) (Currency, bool) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	return currency, ok
+}
+
+// IsValid reports whether code is a currency of the registry. Like Lookup,
+// it ignores case.
+func IsValid(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
+
+// All returns every currency of the registry ordered by code
+func All() []Currency {
+	list := make([]Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		list = append(list, currency)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Code < list[j].Code
+	})
+	return list
+}
+
+// Codes returns the code of every currency of the registry, in order
+func Codes() []string {
+	all := All()
+	codes := make([]string, len(all))
+	for i, currency := range all {
+		codes[i] = currency.Code
+	}
+	return codes
+}
+
+// MinorUnits returns the number of minor units of the currency, or
+// DefaultMinorUnits if code is not in the registry
+func MinorUnits(code string) int {
+	if currency, ok := Lookup(code); ok {
+		return currency.MinorUnits
+	}
+	return DefaultMinorUnits
+}
+
+// Symbol returns the symbol of the currency, or the code itself if it has
+// none or is not in the registry
+func Symbol(code string) string {
+	if currency, ok := Lookup(code); ok && currency.Symbol != "" {
+		return currency.Symbol
+	}
+	return code
+}
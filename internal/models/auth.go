@@ -29,7 +29,7 @@ type UserInfo struct {
 }
 
 type CompleteOnboardingRequest struct {
-	Currency string `json:"currency" validate:"required"`
+	Currency string `json:"currency" validate:"required,iso4217"`
 }
 
 type VerifyEmailRequest struct {
@@ -41,5 +41,28 @@ type ErrorResponse struct {
 }
 
 type UpdateCurrencyRequest struct {
-	Currency string `json:"currency" validate:"required"`
+	Currency string `json:"currency" validate:"required,iso4217"`
+}
+
+type UpdateBillingProfileRequest struct {
+	BusinessName    string `json:"business_name"`
+	BusinessAddress string `json:"business_address"`
+	CountryCode     string `json:"country_code"`
+	TaxID           string `json:"tax_id"`
+}
+
+type BillingProfileResponse struct {
+	BusinessName    string `json:"business_name"`
+	BusinessAddress string `json:"business_address"`
+	CountryCode     string `json:"country_code"`
+	TaxID           string `json:"tax_id"`
+}
+
+// TimezoneRequest sets the IANA timezone the user works in, e.g. Europe/Paris
+type TimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required"`
+}
+
+type TimezoneResponse struct {
+	Timezone string `json:"timezone"`
 }
@@ -1,35 +1,67 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type CreateClientRequest struct {
-	Name       string  `json:"name" validate:"required"`
-	Email      string  `json:"email" validate:"required,email"`
-	Phone      string  `json:"phone"`
-	Company    string  `json:"company"`
-	Address    string  `json:"address"`
-	HourlyRate float64 `json:"hourly_rate"`
-	Currency   string  `json:"currency" validate:"required"`
+	Name        string          `json:"name" validate:"required"`
+	Email       string          `json:"email" validate:"required,email"`
+	Phone       string          `json:"phone"`
+	Company     string          `json:"company"`
+	Address     string          `json:"address"`
+	HourlyRate  decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	Currency    string          `json:"currency" validate:"required,iso4217"`
+	CountryCode string          `json:"country_code"`
+	TaxID       string          `json:"tax_id"`
+	// RemindersOptOut stops payment reminders from being emailed to the client
+	RemindersOptOut bool `json:"reminders_opt_out"`
+	// InvoicePrefix replaces {PREFIX} in the invoice number pattern
+	InvoicePrefix string `json:"invoice_prefix" validate:"max=20"`
+	// Billable is whether time recorded for the client is billable by
+	// default, unless its project says otherwise. Defaults to true.
+	Billable *bool `json:"billable,omitempty"`
+	// BuyerReference is the reference the client asks to be quoted on its
+	// invoices, e.g. a Leitweg-ID. E-invoices sent over Peppol require it.
+	BuyerReference string `json:"buyer_reference" validate:"max=100"`
 }
 
 type UpdateClientRequest struct {
-	Name       string  `json:"name" validate:"required"`
-	Email      string  `json:"email" validate:"required,email"`
-	Phone      string  `json:"phone"`
-	Company    string  `json:"company"`
-	Address    string  `json:"address"`
-	HourlyRate float64 `json:"hourly_rate"`
-	Currency   string  `json:"currency" validate:"required"`
+	Name        string          `json:"name" validate:"required"`
+	Email       string          `json:"email" validate:"required,email"`
+	Phone       string          `json:"phone"`
+	Company     string          `json:"company"`
+	Address     string          `json:"address"`
+	HourlyRate  decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	Currency    string          `json:"currency" validate:"required,iso4217"`
+	CountryCode string          `json:"country_code"`
+	TaxID       string          `json:"tax_id"`
+	// RemindersOptOut stops payment reminders from being emailed to the client
+	RemindersOptOut bool `json:"reminders_opt_out"`
+	// InvoicePrefix replaces {PREFIX} in the invoice number pattern
+	InvoicePrefix string `json:"invoice_prefix" validate:"max=20"`
+	// Billable is whether time recorded for the client is billable by
+	// default, unless its project says otherwise. Defaults to true.
+	Billable *bool `json:"billable,omitempty"`
+	// BuyerReference is the reference the client asks to be quoted on its
+	// invoices, e.g. a Leitweg-ID. E-invoices sent over Peppol require it.
+	BuyerReference string `json:"buyer_reference" validate:"max=100"`
 }
 
 type ClientResponse struct {
-	ID         int32   `json:"id"`
-	UserID     int32   `json:"user_id"`
-	Name       string  `json:"name"`
-	Email      string  `json:"email"`
-	Phone      string  `json:"phone,omitempty"`
-	Company    string  `json:"company,omitempty"`
-	Address    string  `json:"address,omitempty"`
-	HourlyRate float64 `json:"hourly_rate"`
-	Currency   string  `json:"currency"`
-	CreatedAt  string  `json:"created_at"`
-	UpdatedAt  string  `json:"updated_at"`
+	ID              int32           `json:"id"`
+	UserID          int32           `json:"user_id"`
+	Name            string          `json:"name"`
+	Email           string          `json:"email"`
+	Phone           string          `json:"phone,omitempty"`
+	Company         string          `json:"company,omitempty"`
+	Address         string          `json:"address,omitempty"`
+	HourlyRate      decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	Currency        string          `json:"currency"`
+	CountryCode     string          `json:"country_code,omitempty"`
+	TaxID           string          `json:"tax_id,omitempty"`
+	RemindersOptOut bool            `json:"reminders_opt_out"`
+	InvoicePrefix   string          `json:"invoice_prefix,omitempty"`
+	Billable        bool            `json:"billable"`
+	BuyerReference  string          `json:"buyer_reference,omitempty"`
+	CreatedAt       string          `json:"created_at"`
+	UpdatedAt       string          `json:"updated_at"`
 }
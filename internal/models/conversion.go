@@ -0,0 +1,32 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// ConversionRateResponse is an exchange rate used to convert amounts in a
+// report. Source is "override" for the user's own rate and "market" for the
+// provider rate. Market rates older than the configured maximum age are
+// marked stale.
+type ConversionRateResponse struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Rate      float64 `json:"rate"`
+	Source    string  `json:"source"`
+	UpdatedAt string  `json:"updated_at,omitempty"`
+	Stale     bool    `json:"stale,omitempty"`
+}
+
+// CurrencyWarning flags a currency whose amounts in a report were converted
+// at a stale rate ("rate_stale") or could not be converted ("rate_missing")
+type CurrencyWarning struct {
+	Currency string `json:"currency"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// UnconvertedTotal holds the amounts of one currency left out of a report's
+// totals because no rate was available, keyed by the name of the total they
+// belong to
+type UnconvertedTotal struct {
+	Currency string                     `json:"currency"`
+	Totals   map[string]decimal.Decimal `json:"totals" swaggertype:"object,number"`
+}
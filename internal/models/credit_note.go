@@ -0,0 +1,46 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type CreateCreditNoteRequest struct {
+	// CreditNoteNumber is allocated from the credit note numbering pattern
+	// when omitted
+	CreditNoteNumber string `json:"credit_note_number"`
+	IssueDate        string `json:"issue_date" validate:"required"`
+	Reason           string `json:"reason"`
+	// Full credits everything the invoice bills; otherwise LineItems lists
+	// what is credited
+	Full      bool                     `json:"full"`
+	LineItems []InvoiceLineItemRequest `json:"line_items"`
+}
+
+type CreditNoteLineItemResponse struct {
+	ID          int32           `json:"id"`
+	Description string          `json:"description"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit        string          `json:"unit"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"number"`
+	TaxRate     decimal.Decimal `json:"tax_rate" swaggertype:"number"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"number"`
+}
+
+type CreditNoteResponse struct {
+	ID               int32                        `json:"id"`
+	UserID           int32                        `json:"user_id"`
+	InvoiceID        int32                        `json:"invoice_id"`
+	InvoiceNumber    string                       `json:"invoice_number"`
+	ClientID         int32                        `json:"client_id"`
+	CreditNoteNumber string                       `json:"credit_note_number"`
+	IssueDate        string                       `json:"issue_date"`
+	Reason           string                       `json:"reason,omitempty"`
+	LineItems        []CreditNoteLineItemResponse `json:"line_items"`
+	Subtotal         decimal.Decimal              `json:"subtotal" swaggertype:"number"`
+	TaxAmount        decimal.Decimal              `json:"tax_amount" swaggertype:"number"`
+	Withholding      decimal.Decimal              `json:"withholding_amount" swaggertype:"number"`
+	TotalAmount      decimal.Decimal              `json:"total_amount" swaggertype:"number"`
+	CreatedAt        string                       `json:"created_at"`
+}
+
+type NextCreditNoteNumberResponse struct {
+	CreditNoteNumber string `json:"credit_note_number"`
+}
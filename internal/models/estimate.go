@@ -0,0 +1,62 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type EstimateRequest struct {
+	ClientID int32 `json:"client_id" validate:"required"`
+	// EstimateNumber is allocated from the estimate numbering pattern when
+	// omitted on create, and kept when omitted on update
+	EstimateNumber string                   `json:"estimate_number"`
+	IssueDate      string                   `json:"issue_date" validate:"required"`
+	ValidUntil     string                   `json:"valid_until" validate:"required"`
+	Status         string                   `json:"status" validate:"required,oneof=draft sent accepted declined expired"`
+	Notes          string                   `json:"notes"`
+	LineItems      []InvoiceLineItemRequest `json:"line_items"`
+}
+
+type UpdateEstimateStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=draft sent accepted declined expired"`
+}
+
+type ConvertEstimateRequest struct {
+	// IssueDate defaults to today
+	IssueDate string `json:"issue_date"`
+	// DueDate defaults to 30 days after the issue date
+	DueDate string `json:"due_date"`
+	// TaxRateIDs selects the tax rates to apply to the invoice. When
+	// omitted, the user's default tax rates are applied.
+	TaxRateIDs []int32 `json:"tax_rate_ids"`
+}
+
+type EstimateLineItemResponse struct {
+	ID          int32           `json:"id"`
+	Description string          `json:"description"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit        string          `json:"unit"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"number"`
+	TaxRate     decimal.Decimal `json:"tax_rate" swaggertype:"number"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"number"`
+}
+
+type EstimateResponse struct {
+	ID             int32                      `json:"id"`
+	UserID         int32                      `json:"user_id"`
+	ClientID       int32                      `json:"client_id"`
+	EstimateNumber string                     `json:"estimate_number"`
+	IssueDate      string                     `json:"issue_date"`
+	ValidUntil     string                     `json:"valid_until"`
+	Status         string                     `json:"status"`
+	Notes          string                     `json:"notes,omitempty"`
+	InvoiceID      int32                      `json:"invoice_id,omitempty"`
+	Currency       string                     `json:"currency"`
+	LineItems      []EstimateLineItemResponse `json:"line_items"`
+	Subtotal       decimal.Decimal            `json:"subtotal" swaggertype:"number"`
+	TaxAmount      decimal.Decimal            `json:"tax_amount" swaggertype:"number"`
+	TotalAmount    decimal.Decimal            `json:"total_amount" swaggertype:"number"`
+	CreatedAt      string                     `json:"created_at"`
+	UpdatedAt      string                     `json:"updated_at"`
+}
+
+type NextEstimateNumberResponse struct {
+	EstimateNumber string `json:"estimate_number"`
+}
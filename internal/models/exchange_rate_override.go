@@ -0,0 +1,32 @@
+package models
+
+type CreateExchangeRateOverrideRequest struct {
+	BaseCurrency   string  `json:"base_currency" validate:"required"`
+	TargetCurrency string  `json:"target_currency" validate:"required"`
+	Rate           float64 `json:"rate" validate:"gt=0"`
+	ValidFrom      string  `json:"valid_from,omitempty"`
+	ValidTo        string  `json:"valid_to,omitempty"`
+	Note           string  `json:"note,omitempty"`
+}
+
+type UpdateExchangeRateOverrideRequest struct {
+	BaseCurrency   string  `json:"base_currency" validate:"required"`
+	TargetCurrency string  `json:"target_currency" validate:"required"`
+	Rate           float64 `json:"rate" validate:"gt=0"`
+	ValidFrom      string  `json:"valid_from,omitempty"`
+	ValidTo        string  `json:"valid_to,omitempty"`
+	Note           string  `json:"note,omitempty"`
+}
+
+type ExchangeRateOverrideResponse struct {
+	ID             int32   `json:"id"`
+	UserID         int32   `json:"user_id"`
+	BaseCurrency   string  `json:"base_currency"`
+	TargetCurrency string  `json:"target_currency"`
+	Rate           float64 `json:"rate"`
+	ValidFrom      string  `json:"valid_from,omitempty"`
+	ValidTo        string  `json:"valid_to,omitempty"`
+	Note           string  `json:"note,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+type HealthResponse struct {
+	Status        string              `json:"status"`
+	ExchangeRates ExchangeRatesHealth `json:"exchange_rates"`
+}
+
+// ExchangeRatesHealth reports whether the stored market rates are current.
+// Status is "ok", "stale" when a rate is missing or older than MaxAge, or
+// "unknown" when the rates could not be checked.
+type ExchangeRatesHealth struct {
+	Status    string   `json:"status"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+	MaxAge    string   `json:"max_age,omitempty"`
+	Missing   []string `json:"missing,omitempty"`
+}
@@ -1,26 +1,54 @@
 package models
 
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
 type CreateInvoiceRequest struct {
-	ClientID      int32   `json:"client_id" validate:"required"`
-	InvoiceNumber string  `json:"invoice_number" validate:"required"`
-	IssueDate     string  `json:"issue_date" validate:"required"`
-	DueDate       string  `json:"due_date" validate:"required"`
-	Status        string  `json:"status" validate:"required,oneof=draft sent paid overdue"`
-	Notes         string  `json:"notes"`
-	TimeEntryIDs  []int32 `json:"time_entry_ids" validate:"required,min=1"`
+	ClientID int32 `json:"client_id" validate:"required"`
+	// InvoiceNumber is allocated from the invoice numbering pattern when the
+	// invoice leaves draft if omitted
+	InvoiceNumber string                   `json:"invoice_number"`
+	IssueDate     string                   `json:"issue_date" validate:"required"`
+	DueDate       string                   `json:"due_date" validate:"required"`
+	Status        string                   `json:"status" validate:"required,oneof=draft sent partially_paid paid overdue"`
+	Notes         string                   `json:"notes"`
+	TimeEntryIDs  []int32                  `json:"time_entry_ids"`
+	LineItems     []InvoiceLineItemRequest `json:"line_items"`
+	// TaxRateIDs selects the tax rates to apply. When omitted, the user's
+	// default tax rates are applied.
+	TaxRateIDs []int32 `json:"tax_rate_ids"`
 }
 
 type UpdateInvoiceRequest struct {
-	ClientID      int32  `json:"client_id" validate:"required"`
-	InvoiceNumber string `json:"invoice_number" validate:"required"`
+	ClientID int32 `json:"client_id" validate:"required"`
+	// InvoiceNumber is allocated from the invoice numbering pattern when the
+	// invoice leaves draft if omitted
+	InvoiceNumber string `json:"invoice_number"`
 	IssueDate     string `json:"issue_date" validate:"required"`
 	DueDate       string `json:"due_date" validate:"required"`
-	Status        string `json:"status" validate:"required,oneof=draft sent paid overdue"`
+	Status        string `json:"status" validate:"required,oneof=draft sent partially_paid paid overdue"`
 	Notes         string `json:"notes"`
+	// LineItems replaces the invoice's line items when present
+	LineItems *[]InvoiceLineItemRequest `json:"line_items,omitempty"`
+	// TaxRateIDs replaces the invoice's taxes when present
+	TaxRateIDs *[]int32 `json:"tax_rate_ids,omitempty"`
+}
+
+// InvoiceLineItemRequest is a billable line that is not backed by a time entry,
+// such as a fixed-fee deliverable, a licence or a reimbursement
+type InvoiceLineItemRequest struct {
+	Description string          `json:"description" validate:"required"`
+	Quantity    decimal.Decimal `json:"quantity" validate:"required,gt=0" swaggertype:"number"`
+	Unit        string          `json:"unit" validate:"omitempty,oneof=unit hour day month"`
+	UnitPrice   decimal.Decimal `json:"unit_price" validate:"min=0" swaggertype:"number"`
+	TaxRate     decimal.Decimal `json:"tax_rate" validate:"min=0,max=100" swaggertype:"number"`
 }
 
 type UpdateInvoiceStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=draft sent paid overdue"`
+	Status string `json:"status" validate:"required,oneof=draft sent partially_paid paid overdue"`
 }
 
 type InvoiceResponse struct {
@@ -35,8 +63,109 @@ type InvoiceResponse struct {
 	Status         string              `json:"status"`
 	Notes          string              `json:"notes,omitempty"`
 	TimeEntries    []TimeEntryResponse `json:"time_entries"`
-	TotalHours     float64             `json:"total_hours"`
-	TotalAmount    float64             `json:"total_amount"`
-	CreatedAt      string              `json:"created_at"`
-	UpdatedAt      string              `json:"updated_at"`
+	// Projects sums the time entries by the project they were recorded
+	// against, with the entries without a project last
+	Projects       []InvoiceProjectResponse  `json:"projects"`
+	LineItems      []InvoiceLineItemResponse `json:"line_items"`
+	TotalHours     decimal.Decimal           `json:"total_hours" swaggertype:"number"`
+	Subtotal       decimal.Decimal           `json:"subtotal" swaggertype:"number"`
+	Taxes          []InvoiceTaxResponse      `json:"taxes"`
+	TaxAmount      decimal.Decimal           `json:"tax_amount" swaggertype:"number"`
+	Withholding    decimal.Decimal           `json:"withholding_amount" swaggertype:"number"`
+	TotalAmount    decimal.Decimal           `json:"total_amount" swaggertype:"number"`
+	AmountPaid     decimal.Decimal           `json:"amount_paid" swaggertype:"number"`
+	AmountCredited decimal.Decimal           `json:"amount_credited" swaggertype:"number"`
+	BalanceDue     decimal.Decimal           `json:"balance_due" swaggertype:"number"`
+	ReverseCharge  bool                      `json:"reverse_charge"`
+	// ExchangeRate converts the invoice's amounts into ReportingCurrency, the
+	// user's currency, and was frozen when the invoice was issued.
+	// ExchangeRateSource tells whether it was the user's override or the
+	// market rate.
+	ExchangeRate       float64 `json:"exchange_rate,omitempty"`
+	ExchangeRateSource string  `json:"exchange_rate_source,omitempty"`
+	ReportingCurrency  string  `json:"reporting_currency,omitempty"`
+	OverdueAt          string  `json:"overdue_at,omitempty"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
+}
+
+// InvoiceProjectResponse sums the time entries of an invoice recorded against
+// one project. ProjectID and Name are omitted for the entries without a
+// project.
+type InvoiceProjectResponse struct {
+	ProjectID    int32           `json:"project_id,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	Hours        decimal.Decimal `json:"hours" swaggertype:"number"`
+	Amount       decimal.Decimal `json:"amount" swaggertype:"number"`
+	TimeEntryIDs []int32         `json:"time_entry_ids"`
+}
+
+type InvoiceLineItemResponse struct {
+	ID          int32           `json:"id"`
+	Description string          `json:"description"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit        string          `json:"unit"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"number"`
+	TaxRate     decimal.Decimal `json:"tax_rate" swaggertype:"number"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"number"`
+	TaxAmount   decimal.Decimal `json:"tax_amount" swaggertype:"number"`
+}
+
+type UpdateInvoiceNumberingRequest struct {
+	Pattern string `json:"pattern" validate:"required,max=100"`
+	// CreditNotePattern and EstimatePattern are left unchanged when omitted
+	CreditNotePattern string `json:"credit_note_pattern" validate:"max=100"`
+	EstimatePattern   string `json:"estimate_pattern" validate:"max=100"`
+}
+
+type InvoiceNumberingResponse struct {
+	Pattern           string `json:"pattern"`
+	CreditNotePattern string `json:"credit_note_pattern"`
+	EstimatePattern   string `json:"estimate_pattern"`
+}
+
+type NextInvoiceNumberResponse struct {
+	InvoiceNumber string `json:"invoice_number"`
+}
+
+// ReviseInvoiceRequest changes an invoice that has left draft. The previous
+// version is kept in the invoice's revision history.
+type ReviseInvoiceRequest struct {
+	Reason        string `json:"reason"`
+	ClientID      int32  `json:"client_id" validate:"required"`
+	InvoiceNumber string `json:"invoice_number" validate:"required"`
+	IssueDate     string `json:"issue_date" validate:"required"`
+	DueDate       string `json:"due_date" validate:"required"`
+	Notes         string `json:"notes"`
+	// LineItems replaces the invoice's line items when present
+	LineItems *[]InvoiceLineItemRequest `json:"line_items,omitempty"`
+	// TaxRateIDs replaces the invoice's taxes when present
+	TaxRateIDs *[]int32 `json:"tax_rate_ids,omitempty"`
+}
+
+type InvoiceRevisionResponse struct {
+	ID        int32  `json:"id"`
+	InvoiceID int32  `json:"invoice_id"`
+	Revision  int32  `json:"revision"`
+	Reason    string `json:"reason,omitempty"`
+	// Snapshot is the invoice as it was at this revision
+	Snapshot  json.RawMessage `json:"snapshot" swaggertype:"object"`
+	CreatedAt string          `json:"created_at"`
+}
+
+type InvoiceChangeResponse struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type InvoiceDiffResponse struct {
+	From    int32                   `json:"from"`
+	To      int32                   `json:"to"`
+	Changes []InvoiceChangeResponse `json:"changes"`
+}
+
+type ReviseInvoiceResponse struct {
+	Invoice InvoiceResponse     `json:"invoice"`
+	Diff    InvoiceDiffResponse `json:"diff"`
 }
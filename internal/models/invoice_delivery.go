@@ -0,0 +1,18 @@
+package models
+
+type SendInvoiceRequest struct {
+	Cc []string `json:"cc" validate:"omitempty,dive,email"`
+	// Message replaces the default email text when set
+	Message string `json:"message"`
+}
+
+type InvoiceDeliveryResponse struct {
+	ID        int32    `json:"id"`
+	InvoiceID int32    `json:"invoice_id"`
+	Recipient string   `json:"recipient"`
+	Cc        []string `json:"cc,omitempty"`
+	Subject   string   `json:"subject"`
+	Message   string   `json:"message,omitempty"`
+	MessageID string   `json:"message_id"`
+	SentAt    string   `json:"sent_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+type NotificationResponse struct {
+	ID int32 `json:"id"`
+	// Type is budget_alert for the alerts of project budgets
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	ProjectID int32  `json:"project_id,omitempty"`
+	Read      bool   `json:"read"`
+	ReadAt    string `json:"read_at,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type CreatePaymentRequest struct {
+	Amount      decimal.Decimal `json:"amount" validate:"required,gt=0" swaggertype:"number"`
+	Currency    string          `json:"currency"`
+	PaymentDate string          `json:"payment_date" validate:"required"`
+	Method      string          `json:"method" validate:"omitempty,oneof=bank_transfer card cash check paypal other"`
+	Reference   string          `json:"reference"`
+}
+
+type UpdatePaymentRequest struct {
+	Amount      decimal.Decimal `json:"amount" validate:"required,gt=0" swaggertype:"number"`
+	Currency    string          `json:"currency"`
+	PaymentDate string          `json:"payment_date" validate:"required"`
+	Method      string          `json:"method" validate:"omitempty,oneof=bank_transfer card cash check paypal other"`
+	Reference   string          `json:"reference"`
+}
+
+type PaymentResponse struct {
+	ID          int32           `json:"id"`
+	InvoiceID   int32           `json:"invoice_id"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"number"`
+	Currency    string          `json:"currency"`
+	PaymentDate string          `json:"payment_date"`
+	Method      string          `json:"method"`
+	Reference   string          `json:"reference,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+}
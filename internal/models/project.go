@@ -0,0 +1,129 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type CreateProjectRequest struct {
+	ClientID    int32  `json:"client_id" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	// Status is active, completed or archived, and defaults to active. Time
+	// can only be recorded against active projects.
+	Status string `json:"status"`
+	// HourlyRate replaces the client's hourly rate for time recorded against
+	// the project. Leave it out to bill at the client's rate.
+	HourlyRate *decimal.Decimal `json:"hourly_rate,omitempty" swaggertype:"number"`
+	// BudgetHours and BudgetAmount are the hours and the amount, in the
+	// client's currency, the project is expected to take
+	BudgetHours  *decimal.Decimal `json:"budget_hours,omitempty" swaggertype:"number"`
+	BudgetAmount *decimal.Decimal `json:"budget_amount,omitempty" swaggertype:"number"`
+	// BudgetAlertThresholds are the percentages of the budgets at which you
+	// are alerted, by email and in the app. Leave it out to be alerted at 75,
+	// 90 and 100 percent, or send an empty list to turn alerts off.
+	BudgetAlertThresholds []int32 `json:"budget_alert_thresholds,omitempty"`
+	// Billable replaces whether the client's time is billable by default
+	// for time recorded against the project. Leave it out to follow the
+	// client.
+	Billable *bool `json:"billable,omitempty"`
+}
+
+type UpdateProjectRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	// Status is active, completed or archived. Time can only be recorded
+	// against active projects.
+	Status string `json:"status" validate:"required"`
+	// HourlyRate replaces the client's hourly rate for time recorded against
+	// the project. Leave it out to bill at the client's rate.
+	HourlyRate *decimal.Decimal `json:"hourly_rate,omitempty" swaggertype:"number"`
+	// BudgetHours and BudgetAmount are the hours and the amount, in the
+	// client's currency, the project is expected to take
+	BudgetHours  *decimal.Decimal `json:"budget_hours,omitempty" swaggertype:"number"`
+	BudgetAmount *decimal.Decimal `json:"budget_amount,omitempty" swaggertype:"number"`
+	// BudgetAlertThresholds are the percentages of the budgets at which you
+	// are alerted, by email and in the app. Leave it out to be alerted at 75,
+	// 90 and 100 percent, or send an empty list to turn alerts off.
+	BudgetAlertThresholds []int32 `json:"budget_alert_thresholds,omitempty"`
+	// Billable replaces whether the client's time is billable by default
+	// for time recorded against the project. Leave it out to follow the
+	// client.
+	Billable *bool `json:"billable,omitempty"`
+}
+
+type ProjectResponse struct {
+	ID           int32            `json:"id"`
+	UserID       int32            `json:"user_id"`
+	ClientID     int32            `json:"client_id"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	Status       string           `json:"status"`
+	HourlyRate   *decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	BudgetHours  *decimal.Decimal `json:"budget_hours" swaggertype:"number"`
+	BudgetAmount *decimal.Decimal `json:"budget_amount" swaggertype:"number"`
+	// BudgetAlertThresholds are the percentages of the budgets at which you
+	// are alerted
+	BudgetAlertThresholds []int32 `json:"budget_alert_thresholds"`
+	// Billable is null when the project follows its client
+	Billable *bool `json:"billable"`
+	// Tasks is only included when a single project is fetched
+	Tasks     []TaskResponse `json:"tasks,omitempty"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
+}
+
+type CreateTaskRequest struct {
+	Name string `json:"name" validate:"required"`
+	// HourlyRate replaces the project's hourly rate, or the client's when the
+	// project has none, for time recorded against the task
+	HourlyRate *decimal.Decimal `json:"hourly_rate,omitempty" swaggertype:"number"`
+}
+
+type UpdateTaskRequest struct {
+	Name string `json:"name" validate:"required"`
+	// HourlyRate replaces the project's hourly rate, or the client's when the
+	// project has none, for time recorded against the task
+	HourlyRate *decimal.Decimal `json:"hourly_rate,omitempty" swaggertype:"number"`
+}
+
+type TaskResponse struct {
+	ID         int32            `json:"id"`
+	ProjectID  int32            `json:"project_id"`
+	Name       string           `json:"name"`
+	HourlyRate *decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	CreatedAt  string           `json:"created_at"`
+	UpdatedAt  string           `json:"updated_at"`
+}
+
+// ProjectBudgetResponse reports how much of its budgets a project has used.
+// Amounts are in the client's currency, at the rate each entry was recorded
+// at. The budget, remaining and percent fields are null when the project has
+// no such budget.
+type ProjectBudgetResponse struct {
+	ProjectID       int32            `json:"project_id"`
+	Currency        string           `json:"currency"`
+	BudgetHours     *decimal.Decimal `json:"budget_hours" swaggertype:"number"`
+	UsedHours       decimal.Decimal  `json:"used_hours" swaggertype:"number"`
+	RemainingHours  *decimal.Decimal `json:"remaining_hours" swaggertype:"number"`
+	HoursPercent    *decimal.Decimal `json:"hours_percent" swaggertype:"number"`
+	BudgetAmount    *decimal.Decimal `json:"budget_amount" swaggertype:"number"`
+	UsedAmount      decimal.Decimal  `json:"used_amount" swaggertype:"number"`
+	RemainingAmount *decimal.Decimal `json:"remaining_amount" swaggertype:"number"`
+	AmountPercent   *decimal.Decimal `json:"amount_percent" swaggertype:"number"`
+	// AlertThresholds are the percentages of the budgets at which you are
+	// alerted
+	AlertThresholds []int32 `json:"alert_thresholds"`
+	// BurnDown has one point per day time was recorded against the project,
+	// oldest first
+	BurnDown []BudgetBurnDownPoint `json:"burn_down"`
+}
+
+// BudgetBurnDownPoint is the time recorded against a project on one day and
+// what is left of its budgets at the end of it
+type BudgetBurnDownPoint struct {
+	Date            string           `json:"date"`
+	Hours           decimal.Decimal  `json:"hours" swaggertype:"number"`
+	Amount          decimal.Decimal  `json:"amount" swaggertype:"number"`
+	TotalHours      decimal.Decimal  `json:"total_hours" swaggertype:"number"`
+	TotalAmount     decimal.Decimal  `json:"total_amount" swaggertype:"number"`
+	RemainingHours  *decimal.Decimal `json:"remaining_hours" swaggertype:"number"`
+	RemainingAmount *decimal.Decimal `json:"remaining_amount" swaggertype:"number"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type RecurringInvoiceRequest struct {
+	ClientID  int32  `json:"client_id" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+	Frequency string `json:"frequency" validate:"required,oneof=weekly monthly quarterly yearly"`
+	// AnchorDay is the ISO weekday (1 = Monday, 7 = Sunday) invoices are
+	// issued on for weekly schedules, otherwise the day of the month. Days
+	// past the end of a month fall on its last day.
+	AnchorDay int32  `json:"anchor_day" validate:"required,min=1,max=31"`
+	StartDate string `json:"start_date" validate:"required"`
+	// EndDate is the last day an invoice may be issued on; empty means the
+	// schedule never ends
+	EndDate string `json:"end_date"`
+	// PaymentTermsDays is the number of days between the issue date and the
+	// due date of every issued invoice
+	PaymentTermsDays int32                    `json:"payment_terms_days" validate:"min=0,max=365"`
+	Notes            string                   `json:"notes"`
+	AutoSend         bool                     `json:"auto_send"`
+	LineItems        []InvoiceLineItemRequest `json:"line_items" validate:"required,min=1"`
+}
+
+type RecurringInvoiceLineItemResponse struct {
+	ID          int32           `json:"id"`
+	Description string          `json:"description"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit        string          `json:"unit"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"number"`
+	TaxRate     decimal.Decimal `json:"tax_rate" swaggertype:"number"`
+}
+
+type RecurringInvoiceResponse struct {
+	ID               int32                              `json:"id"`
+	UserID           int32                              `json:"user_id"`
+	ClientID         int32                              `json:"client_id"`
+	Name             string                             `json:"name"`
+	Frequency        string                             `json:"frequency"`
+	AnchorDay        int32                              `json:"anchor_day"`
+	StartDate        string                             `json:"start_date"`
+	EndDate          string                             `json:"end_date,omitempty"`
+	PaymentTermsDays int32                              `json:"payment_terms_days"`
+	Notes            string                             `json:"notes,omitempty"`
+	AutoSend         bool                               `json:"auto_send"`
+	Status           string                             `json:"status"`
+	NextRunDate      string                             `json:"next_run_date,omitempty"`
+	LastRunDate      string                             `json:"last_run_date,omitempty"`
+	LineItems        []RecurringInvoiceLineItemResponse `json:"line_items"`
+	CreatedAt        string                             `json:"created_at"`
+	UpdatedAt        string                             `json:"updated_at"`
+}
+
+type RecurringInvoiceOccurrenceResponse struct {
+	IssueDate string `json:"issue_date"`
+	DueDate   string `json:"due_date"`
+}
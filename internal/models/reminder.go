@@ -0,0 +1,40 @@
+package models
+
+type CreateReminderRuleRequest struct {
+	// OffsetDays is the number of days after the due date the reminder is
+	// sent; negative values send it before the due date
+	OffsetDays int32  `json:"offset_days" validate:"min=-365,max=365"`
+	Subject    string `json:"subject"`
+	Message    string `json:"message"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+type UpdateReminderRuleRequest struct {
+	OffsetDays int32  `json:"offset_days" validate:"min=-365,max=365"`
+	Subject    string `json:"subject"`
+	Message    string `json:"message"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type ReminderRuleResponse struct {
+	ID         int32  `json:"id"`
+	UserID     int32  `json:"user_id"`
+	OffsetDays int32  `json:"offset_days"`
+	Subject    string `json:"subject,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Enabled    bool   `json:"enabled"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type InvoiceReminderResponse struct {
+	ID             int32  `json:"id"`
+	InvoiceID      int32  `json:"invoice_id"`
+	ReminderRuleID *int32 `json:"reminder_rule_id"`
+	OffsetDays     int32  `json:"offset_days"`
+	Recipient      string `json:"recipient"`
+	Subject        string `json:"subject"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+	SentAt         string `json:"sent_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type CreateTaxRateRequest struct {
+	Name        string          `json:"name" validate:"required"`
+	Rate        decimal.Decimal `json:"rate" validate:"min=0,max=100" swaggertype:"number"`
+	Compound    bool            `json:"compound"`
+	Withholding bool            `json:"withholding"`
+	IsDefault   bool            `json:"is_default"`
+}
+
+type UpdateTaxRateRequest struct {
+	Name        string          `json:"name" validate:"required"`
+	Rate        decimal.Decimal `json:"rate" validate:"min=0,max=100" swaggertype:"number"`
+	Compound    bool            `json:"compound"`
+	Withholding bool            `json:"withholding"`
+	IsDefault   bool            `json:"is_default"`
+}
+
+type TaxRateResponse struct {
+	ID          int32           `json:"id"`
+	UserID      int32           `json:"user_id"`
+	Name        string          `json:"name"`
+	Rate        decimal.Decimal `json:"rate" swaggertype:"number"`
+	Compound    bool            `json:"compound"`
+	Withholding bool            `json:"withholding"`
+	IsDefault   bool            `json:"is_default"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+}
+
+type InvoiceTaxResponse struct {
+	Name        string          `json:"name"`
+	Rate        decimal.Decimal `json:"rate" swaggertype:"number"`
+	Compound    bool            `json:"compound"`
+	Withholding bool            `json:"withholding"`
+	Basis       decimal.Decimal `json:"basis" swaggertype:"number"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"number"`
+}
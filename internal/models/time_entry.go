@@ -1,46 +1,124 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type CreateTimeEntryRequest struct {
-	ClientID    int32   `json:"client_id" validate:"required"`
-	Date        string  `json:"date" validate:"required"`
-	Hours       float64 `json:"hours" validate:"required,gt=0"`
-	Description string  `json:"description"`
+	ClientID int32 `json:"client_id" validate:"required"`
+	// ProjectID and TaskID optionally record the entry against one of the
+	// client's projects and one of the project's tasks, whose hourly rate
+	// then replaces the client's
+	ProjectID int32 `json:"project_id,omitempty"`
+	TaskID    int32 `json:"task_id,omitempty"`
+	// Date is required unless StartedAt is given, in which case it defaults
+	// to the day work started
+	Date string `json:"date"`
+	// Hours is required unless StartedAt and EndedAt are given, in which
+	// case it is the time between them less PausedSeconds
+	Hours       decimal.Decimal `json:"hours" swaggertype:"number"`
+	Description string          `json:"description"`
+	// Billable defaults to whether the project, or the client when the
+	// project does not say, is billable. Non-billable time is never invoiced
+	// or counted as revenue.
+	Billable *bool `json:"billable,omitempty"`
+	// StartedAt and EndedAt are RFC 3339 timestamps, e.g.
+	// 2025-01-31T09:00:00+01:00, recording when the work was done
+	StartedAt string `json:"started_at,omitempty"`
+	EndedAt   string `json:"ended_at,omitempty"`
+	// PausedSeconds is how long the work was paused between StartedAt and
+	// EndedAt
+	PausedSeconds int32 `json:"paused_seconds,omitempty"`
+	// AllowOverlap records the entry even when its period overlaps another
+	// of the user's entries, instead of rejecting it
+	AllowOverlap bool `json:"allow_overlap,omitempty"`
 }
 
 type UpdateTimeEntryRequest struct {
-	ClientID    int32   `json:"client_id" validate:"required"`
-	Date        string  `json:"date" validate:"required"`
-	Hours       float64 `json:"hours" validate:"required,gt=0"`
-	Description string  `json:"description"`
+	ClientID int32 `json:"client_id" validate:"required"`
+	// ProjectID and TaskID optionally record the entry against one of the
+	// client's projects and one of the project's tasks, whose hourly rate
+	// then replaces the client's
+	ProjectID int32 `json:"project_id,omitempty"`
+	TaskID    int32 `json:"task_id,omitempty"`
+	// Date is required unless StartedAt is given, in which case it defaults
+	// to the day work started
+	Date string `json:"date"`
+	// Hours is required unless StartedAt and EndedAt are given, in which
+	// case it is the time between them less PausedSeconds
+	Hours       decimal.Decimal `json:"hours" swaggertype:"number"`
+	Description string          `json:"description"`
+	// Billable is kept when left out, unless the client, project or task
+	// changes, in which case it defaults as on creation. Non-billable time
+	// is never invoiced or counted as revenue.
+	Billable *bool `json:"billable,omitempty"`
+	// StartedAt and EndedAt are RFC 3339 timestamps, e.g.
+	// 2025-01-31T09:00:00+01:00, recording when the work was done
+	StartedAt string `json:"started_at,omitempty"`
+	EndedAt   string `json:"ended_at,omitempty"`
+	// PausedSeconds is how long the work was paused between StartedAt and
+	// EndedAt. It defaults to the time the entry was paused so far, e.g.
+	// while its timer was paused.
+	PausedSeconds *int32 `json:"paused_seconds,omitempty"`
+	// AllowOverlap records the entry even when its period overlaps another
+	// of the user's entries, instead of rejecting it
+	AllowOverlap bool `json:"allow_overlap,omitempty"`
 }
 
 type TimeEntryResponse struct {
-	ID             int32   `json:"id"`
-	UserID         int32   `json:"user_id"`
-	ClientID       int32   `json:"client_id"`
-	ClientName     string  `json:"client_name,omitempty"`
-	ClientCurrency string  `json:"client_currency,omitempty"`
-	Date           string  `json:"date"`
-	Hours          float64 `json:"hours"`
-	Description    string  `json:"description,omitempty"`
-	HourlyRate     float64 `json:"hourly_rate"`
-	CreatedAt      string  `json:"created_at"`
-	UpdatedAt      string  `json:"updated_at"`
+	ID             int32           `json:"id"`
+	UserID         int32           `json:"user_id"`
+	ClientID       int32           `json:"client_id"`
+	ClientName     string          `json:"client_name,omitempty"`
+	ClientCurrency string          `json:"client_currency,omitempty"`
+	ProjectID      int32           `json:"project_id,omitempty"`
+	ProjectName    string          `json:"project_name,omitempty"`
+	TaskID         int32           `json:"task_id,omitempty"`
+	Date           string          `json:"date"`
+	Hours          decimal.Decimal `json:"hours" swaggertype:"number"`
+	Description    string          `json:"description,omitempty"`
+	HourlyRate     decimal.Decimal `json:"hourly_rate" swaggertype:"number"`
+	Billable       bool            `json:"billable"`
+	StartedAt      string          `json:"started_at,omitempty"`
+	EndedAt        string          `json:"ended_at,omitempty"`
+	PausedSeconds  int32           `json:"paused_seconds,omitempty"`
+	// Overlaps lists the IDs of the user's entries whose period overlaps
+	// this one, when it was recorded with allow_overlap
+	Overlaps  []int32 `json:"overlaps,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// TimeEntryOverlapResponse is returned when a time entry is rejected because
+// its period overlaps other entries of the user
+type TimeEntryOverlapResponse struct {
+	Error    string              `json:"error"`
+	Overlaps []TimeEntryResponse `json:"overlaps"`
 }
 
 type HeatmapResponse struct {
-	StartDate    string                        `json:"start_date"`
-	EndDate      string                        `json:"end_date"`
-	Data         map[string]float64            `json:"data"`
-	Entries      map[string][]TimeEntryResponse `json:"entries"`
-	TotalHours   float64                       `json:"total_hours"`
-	DaysWorked   int                           `json:"days_worked"`
-	DaysOff      int                           `json:"days_off"`
-	AverageHours float64                       `json:"average_hours"`
+	StartDate  string                         `json:"start_date"`
+	EndDate    string                         `json:"end_date"`
+	Data       map[string]decimal.Decimal     `json:"data" swaggertype:"object,number"`
+	Entries    map[string][]TimeEntryResponse `json:"entries"`
+	TotalHours decimal.Decimal                `json:"total_hours" swaggertype:"number"`
+	// BillableHours is the part of TotalHours recorded as billable, and
+	// BillableUtilization its percentage of TotalHours
+	BillableHours       decimal.Decimal `json:"billable_hours" swaggertype:"number"`
+	BillableUtilization decimal.Decimal `json:"billable_utilization" swaggertype:"number"`
+	DaysWorked          int             `json:"days_worked"`
+	DaysOff             int             `json:"days_off"`
+	AverageHours        decimal.Decimal `json:"average_hours" swaggertype:"number"`
 }
 
 type TimeEntriesWithStatsResponse struct {
-	Entries      []TimeEntryResponse `json:"entries"`
-	TotalHours   float64            `json:"total_hours"`
-	TotalRevenue float64            `json:"total_revenue"`
+	Entries    []TimeEntryResponse `json:"entries"`
+	TotalHours decimal.Decimal     `json:"total_hours" swaggertype:"number"`
+	// BillableHours is the part of TotalHours recorded as billable, and
+	// BillableUtilization its percentage of TotalHours. TotalRevenue only
+	// counts billable time.
+	BillableHours       decimal.Decimal          `json:"billable_hours" swaggertype:"number"`
+	BillableUtilization decimal.Decimal          `json:"billable_utilization" swaggertype:"number"`
+	TotalRevenue        decimal.Decimal          `json:"total_revenue" swaggertype:"number"`
+	ConversionRates     []ConversionRateResponse `json:"conversion_rates"`
+	Warnings            []CurrencyWarning        `json:"warnings"`
+	Unconverted         []UnconvertedTotal       `json:"unconverted"`
 }
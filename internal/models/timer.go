@@ -0,0 +1,39 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type StartTimerRequest struct {
+	ClientID int32 `json:"client_id" validate:"required"`
+	// ProjectID and TaskID optionally narrow the work down to one of the
+	// client's projects and one of the project's tasks
+	ProjectID   int32  `json:"project_id,omitempty"`
+	TaskID      int32  `json:"task_id,omitempty"`
+	Description string `json:"description"`
+}
+
+type StopTimerRequest struct {
+	// AllowOverlap records the time entry even when its period overlaps
+	// another of the user's entries, instead of rejecting it
+	AllowOverlap bool `json:"allow_overlap,omitempty"`
+}
+
+type TimerResponse struct {
+	ID          int32  `json:"id"`
+	UserID      int32  `json:"user_id"`
+	ClientID    int32  `json:"client_id"`
+	ProjectID   int32  `json:"project_id,omitempty"`
+	TaskID      int32  `json:"task_id,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Status is running, paused or stopped
+	Status    string `json:"status"`
+	StartedAt string `json:"started_at"`
+	StoppedAt string `json:"stopped_at,omitempty"`
+	// ElapsedSeconds is how long the timer has run, not counting pauses
+	ElapsedSeconds int32 `json:"elapsed_seconds"`
+	// Hours is the elapsed time as it is recorded on a time entry
+	Hours decimal.Decimal `json:"hours" swaggertype:"number"`
+	// TimeEntry is the time entry a stopped timer was recorded as
+	TimeEntry *TimeEntryResponse `json:"time_entry,omitempty"`
+	CreatedAt string             `json:"created_at"`
+	UpdatedAt string             `json:"updated_at"`
+}
@@ -0,0 +1,109 @@
+// Package money does exact decimal arithmetic on amounts, prices, quantities
+// and tax rates, and rounds amounts to the minor unit of their currency with
+// the configured rounding rule. Amounts are stored as DECIMAL and read as
+// strings, so parsing them into decimals keeps every digit; converting them to
+// float64 would not.
+package money
+
+import (
+	"database/sql"
+	"fmt"
+
+	"worklio-api/internal/iso4217"
+
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	// Decimals are written to JSON as numbers, like the float64 amounts they
+	// replaced, instead of as strings
+	decimal.MarshalJSONWithoutQuotes = true
+}
+
+// Rounding is the rule used to round an amount to the minor unit of its
+// currency when it falls exactly halfway between two minor units
+type Rounding int
+
+const (
+	// RoundHalfUp rounds halves away from zero, e.g. 0.125 to 0.13
+	RoundHalfUp Rounding = iota
+	// RoundHalfEven rounds halves to the even neighbour, e.g. 0.125 to 0.12
+	// and 0.135 to 0.14, also known as banker's rounding
+	RoundHalfEven
+)
+
+// rounding is the rule in effect, set once at startup
+var rounding = RoundHalfUp
+
+// ParseRounding parses a rounding rule name: "half_up" or "half_even"
+func ParseRounding(name string) (Rounding, error) {
+	switch name {
+	case "half_up":
+		return RoundHalfUp, nil
+	case "half_even":
+		return RoundHalfEven, nil
+	default:
+		return 0, fmt.Errorf("unknown rounding rule %q, expected half_up or half_even", name)
+	}
+}
+
+// SetRounding sets the rule amounts are rounded with. It is meant to be
+// called once at startup, before any amount is rounded.
+func SetRounding(r Rounding) {
+	rounding = r
+}
+
+// Parse parses a decimal string as stored in the database. Empty and invalid
+// strings parse as zero.
+func Parse(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+// ParseNull parses a nullable decimal string. NULL parses as zero.
+func ParseNull(s sql.NullString) decimal.Decimal {
+	if !s.Valid {
+		return decimal.Zero
+	}
+	return Parse(s.String)
+}
+
+// Round rounds amount to the minor unit of the currency
+func Round(amount decimal.Decimal, currency string) decimal.Decimal {
+	return RoundTo(amount, int32(iso4217.MinorUnits(currency)))
+}
+
+// RoundTo rounds amount to the given number of decimals
+func RoundTo(amount decimal.Decimal, places int32) decimal.Decimal {
+	if rounding == RoundHalfEven {
+		return amount.RoundBank(places)
+	}
+	return amount.Round(places)
+}
+
+// Format formats amount as a plain decimal with the currency's number of
+// minor units, e.g. "1234.50" for USD or "1235" for JPY, as stored in the
+// database and exchanged with other systems
+func Format(amount decimal.Decimal, currency string) string {
+	return FormatTo(amount, int32(iso4217.MinorUnits(currency)))
+}
+
+// FormatTo rounds amount to the given number of decimals and formats it
+// with exactly that many
+func FormatTo(amount decimal.Decimal, places int32) string {
+	return RoundTo(amount, places).StringFixed(places)
+}
+
+// Percent returns rate percent of amount, exactly
+func Percent(amount, rate decimal.Decimal) decimal.Decimal {
+	return amount.Mul(rate).Shift(-2)
+}
+
+// Convert converts amount at an exchange rate. Rates are kept as float64 by
+// the exchange rate service; the conversion itself is exact.
+func Convert(amount decimal.Decimal, rate float64) decimal.Decimal {
+	return amount.Mul(decimal.NewFromFloat(rate))
+}
@@ -0,0 +1,262 @@
+package money
+
+import (
+	"database/sql"
+	"math/rand"
+	"testing"
+
+	"worklio-api/internal/iso4217"
+
+	"github.com/shopspring/decimal"
+)
+
+// withRounding sets the rounding rule for the rest of the test
+func withRounding(t *testing.T, r Rounding) {
+	t.Helper()
+	SetRounding(r)
+	t.Cleanup(func() { SetRounding(RoundHalfUp) })
+}
+
+// randomAmount returns an amount of up to a billion, positive or negative,
+// with up to four decimals
+func randomAmount(rng *rand.Rand) decimal.Decimal {
+	return decimal.New(rng.Int63n(2e13)-1e13, -int32(rng.Intn(5)))
+}
+
+func TestAddIsExact(t *testing.T) {
+	tests := []struct {
+		amounts []string
+		want    string
+	}{
+		{[]string{"0.1", "0.2"}, "0.3"},
+		{[]string{"0.01", "0.01", "0.01"}, "0.03"},
+		{[]string{"1234567.89", "0.01"}, "1234567.9"},
+		{[]string{"10.005", "-10.005"}, "0"},
+		{[]string{"999999999999.99", "0.01"}, "1000000000000"},
+	}
+	for _, tt := range tests {
+		sum := decimal.Zero
+		for _, amount := range tt.amounts {
+			sum = sum.Add(Parse(amount))
+		}
+		if !sum.Equal(Parse(tt.want)) {
+			t.Errorf("sum of %v = %s, want %s", tt.amounts, sum, tt.want)
+		}
+	}
+}
+
+func TestMulIsExact(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"1.1", "1.1", "1.21"},
+		{"2.5", "85", "212.5"},
+		{"3", "19.333", "57.999"},
+		{"0.3333", "3", "0.9999"},
+		{"-4.25", "0.5", "-2.125"},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.a).Mul(Parse(tt.b)); !got.Equal(Parse(tt.want)) {
+			t.Errorf("%s × %s = %s, want %s", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestArithmeticProperties(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, b, c := randomAmount(rng), randomAmount(rng), randomAmount(rng)
+
+		if !a.Add(b).Equal(b.Add(a)) {
+			t.Fatalf("%s + %s is not commutative", a, b)
+		}
+		if !a.Add(b).Add(c).Equal(a.Add(b.Add(c))) {
+			t.Fatalf("(%s + %s) + %s is not associative", a, b, c)
+		}
+		if !a.Mul(b).Equal(b.Mul(a)) {
+			t.Fatalf("%s × %s is not commutative", a, b)
+		}
+		if !a.Mul(b.Add(c)).Equal(a.Mul(b).Add(a.Mul(c))) {
+			t.Fatalf("%s × (%s + %s) does not distribute", a, b, c)
+		}
+		if !a.Add(b).Sub(b).Equal(a) {
+			t.Fatalf("%s + %s - %s is not %s", a, b, b, a)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		halfUp   string
+		halfEven string
+	}{
+		{"0.125", "USD", "0.13", "0.12"},
+		{"0.135", "USD", "0.14", "0.14"},
+		{"-0.125", "USD", "-0.13", "-0.12"},
+		{"0.124999", "EUR", "0.12", "0.12"},
+		{"2.5", "JPY", "3", "2"},
+		{"3.5", "JPY", "4", "4"},
+		{"1.0005", "BHD", "1.001", "1"},
+		{"1.0015", "KWD", "1.002", "1.002"},
+		{"10.555", "XXX", "10.56", "10.56"},
+		{"10.565", "", "10.57", "10.56"},
+		{"7", "EUR", "7", "7"},
+	}
+	for _, rule := range []Rounding{RoundHalfUp, RoundHalfEven} {
+		withRounding(t, rule)
+		for _, tt := range tests {
+			want := tt.halfUp
+			if rule == RoundHalfEven {
+				want = tt.halfEven
+			}
+			if got := Round(Parse(tt.amount), tt.currency); !got.Equal(Parse(want)) {
+				t.Errorf("rule %d: Round(%s, %q) = %s, want %s", rule, tt.amount, tt.currency, got, want)
+			}
+		}
+	}
+}
+
+func TestRoundProperties(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, rule := range []Rounding{RoundHalfUp, RoundHalfEven} {
+		withRounding(t, rule)
+		for i := 0; i < 1000; i++ {
+			amount := randomAmount(rng)
+			for _, currency := range []string{"USD", "JPY", "BHD"} {
+				units := int32(iso4217.MinorUnits(currency))
+				rounded := Round(amount, currency)
+
+				// Rounding lands on a whole minor unit at most half a minor
+				// unit away, is idempotent and is symmetric around zero
+				if !rounded.Shift(units).IsInteger() {
+					t.Fatalf("Round(%s, %s) = %s is not a whole minor unit", amount, currency, rounded)
+				}
+				if rounded.Sub(amount).Abs().GreaterThan(decimal.New(5, -units-1)) {
+					t.Fatalf("Round(%s, %s) = %s is more than half a minor unit away", amount, currency, rounded)
+				}
+				if !Round(rounded, currency).Equal(rounded) {
+					t.Fatalf("Round(%s, %s) is not idempotent", amount, currency)
+				}
+				if !Round(amount.Neg(), currency).Equal(rounded.Neg()) {
+					t.Fatalf("Round(-%s, %s) is not -%s", amount, currency, rounded)
+				}
+			}
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		amount string
+		rate   float64
+		want   string
+	}{
+		{"100", 1.08, "108"},
+		{"100", 0.925926, "92.5926"},
+		{"19.99", 157.5, "3148.425"},
+		{"0.1", 3, "0.3"},
+		{"-50", 1.25, "-62.5"},
+		{"123.45", 1, "123.45"},
+	}
+	for _, tt := range tests {
+		if got := Convert(Parse(tt.amount), tt.rate); !got.Equal(Parse(tt.want)) {
+			t.Errorf("Convert(%s, %v) = %s, want %s", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestConvertRoundTrips(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 1000; i++ {
+		amount := Round(randomAmount(rng), "EUR")
+		// Rates with six significant digits, between 0.00001 and 999999
+		rate, _ := decimal.New(rng.Int63n(900000)+100000, -int32(rng.Intn(11))).Float64()
+
+		// Converting to a currency and back at the inverse rate gives the
+		// amount back to the cent, as long as the converted amount is kept
+		// exact
+		there := Convert(amount, rate)
+		back := Round(Convert(there, 1/rate), "EUR")
+		if !back.Equal(amount) {
+			t.Fatalf("%s converted at %v and back is %s", amount, rate, back)
+		}
+
+		// Conversion is linear
+		other := Round(randomAmount(rng), "EUR")
+		if !Convert(amount.Add(other), rate).Equal(Convert(amount, rate).Add(Convert(other, rate))) {
+			t.Fatalf("Convert(%s + %s, %v) is not the sum of the conversions", amount, other, rate)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount, rate, want string
+	}{
+		{"100", "20", "20"},
+		{"426.74", "20", "85.348"},
+		{"57.999", "5.5", "3.189945"},
+		{"10", "0", "0"},
+	}
+	for _, tt := range tests {
+		if got := Percent(Parse(tt.amount), Parse(tt.rate)); !got.Equal(Parse(tt.want)) {
+			t.Errorf("Percent(%s, %s) = %s, want %s", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	withRounding(t, RoundHalfUp)
+	tests := []struct {
+		amount, currency, want string
+	}{
+		{"1234.5", "USD", "1234.50"},
+		{"1234.5", "JPY", "1235"},
+		{"1.2345", "BHD", "1.235"},
+		{"-0.005", "EUR", "-0.01"},
+		{"0", "EUR", "0.00"},
+	}
+	for _, tt := range tests {
+		if got := Format(Parse(tt.amount), tt.currency); got != tt.want {
+			t.Errorf("Format(%s, %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"12.50", "12.5"},
+		{"-0.001", "-0.001"},
+		{"", "0"},
+		{"abc", "0"},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.in); !got.Equal(Parse(tt.want)) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	if got := ParseNull(sql.NullString{}); !got.IsZero() {
+		t.Errorf("ParseNull(NULL) = %s", got)
+	}
+	if got := ParseNull(sql.NullString{String: "3.14", Valid: true}); !got.Equal(Parse("3.14")) {
+		t.Errorf("ParseNull(3.14) = %s", got)
+	}
+}
+
+func TestParseRounding(t *testing.T) {
+	for name, want := range map[string]Rounding{"half_up": RoundHalfUp, "half_even": RoundHalfEven} {
+		if got, err := ParseRounding(name); err != nil || got != want {
+			t.Errorf("ParseRounding(%q) = %d, %v", name, got, err)
+		}
+	}
+	if _, err := ParseRounding("bankers"); err == nil {
+		t.Error("ParseRounding accepted an unknown rule")
+	}
+}
@@ -0,0 +1,230 @@
+package pdf
+
+import (
+	"worklio-api/internal/billing"
+	"worklio-api/internal/money"
+	"worklio-api/internal/utils"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// RenderCreditNote lays out doc on an A4 page in the style of RenderInvoice
+// and returns the unwritten PDF
+func RenderCreditNote(doc *billing.CreditNoteDocument) *gofpdf.Fpdf {
+	creditNote := doc.CreditNote
+	client := doc.Client
+	currency := doc.Currency()
+
+	subtotal := money.Parse(creditNote.Subtotal)
+	taxAmount := money.Parse(creditNote.TaxAmount)
+	withholding := money.Parse(creditNote.WithholdingAmount)
+	total := money.Parse(creditNote.Total)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(creditNote.CreditNoteNumber, true)
+	pdf.SetAuthor(doc.SellerName(), true)
+	pdf.SetCreator("FacturMe", false)
+	pdf.AddPage()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+
+	// Header Section with Blue Background
+	pdf.SetFillColor(30, 58, 138) // blue-900
+	pdf.Rect(0, 0, 210, 50, "F")
+
+	// Credit Note Title
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 32)
+	pdf.SetY(15)
+	pdf.Cell(0, 10, "CREDIT NOTE")
+	pdf.Ln(12)
+
+	// Credit Note Number
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 8, creditNote.CreditNoteNumber)
+	pdf.Ln(20)
+
+	pdf.SetTextColor(0, 0, 0)
+
+	// Two column layout for Bill To and Credit Note Details
+	leftX := 20.0
+	rightX := 115.0
+	currentY := pdf.GetY()
+
+	// Left Column - Bill To Section
+	pdf.SetXY(leftX, currentY)
+	pdf.SetFillColor(241, 245, 249) // slate-100
+	pdf.Rect(leftX, currentY, 85, 45, "F")
+
+	pdf.SetXY(leftX+5, currentY+5)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetTextColor(100, 116, 139) // slate-500
+	pdf.Cell(0, 5, "BILL TO")
+	pdf.Ln(7)
+
+	pdf.SetX(leftX + 5)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Cell(0, 6, client.Name)
+	pdf.Ln(6)
+
+	pdf.SetX(leftX + 5)
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetTextColor(71, 85, 105) // slate-600
+	pdf.Cell(0, 5, client.Email)
+	pdf.Ln(5)
+
+	if client.Company.Valid && client.Company.String != "" {
+		pdf.SetX(leftX + 5)
+		pdf.Cell(0, 5, client.Company.String)
+		pdf.Ln(5)
+	}
+
+	if client.TaxID.Valid && client.TaxID.String != "" {
+		pdf.SetX(leftX + 5)
+		pdf.Cell(0, 5, "Tax ID: "+client.TaxID.String)
+		pdf.Ln(5)
+	}
+
+	// Right Column - Credit Note Details
+	pdf.SetXY(rightX, currentY)
+	pdf.SetFillColor(241, 245, 249) // slate-100
+	pdf.Rect(rightX, currentY, 75, 45, "F")
+
+	pdf.SetXY(rightX+5, currentY+5)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetTextColor(100, 116, 139) // slate-500
+	pdf.Cell(0, 5, "CREDIT NOTE DETAILS")
+	pdf.Ln(7)
+
+	detailRow := func(label, value string) {
+		pdf.SetX(rightX + 5)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetTextColor(71, 85, 105)
+		pdf.Cell(25, 5, label)
+		pdf.SetFont("Arial", "", 9)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Cell(0, 5, value)
+		pdf.Ln(5)
+	}
+
+	detailRow("Issue Date:", creditNote.IssueDate.Format("Jan 2, 2006"))
+	detailRow("Invoice:", doc.Invoice.InvoiceNumber)
+	detailRow("Invoiced On:", doc.Invoice.IssueDate.Format("Jan 2, 2006"))
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetY(currentY + 50)
+	pdf.Ln(10)
+
+	// Line Items Header
+	pdf.SetFont("Arial", "B", 12)
+	pdf.SetTextColor(30, 58, 138) // blue-900
+	pdf.Cell(0, 8, "Credited Items")
+	pdf.Ln(10)
+
+	// Table Header with colored background
+	pdf.SetFillColor(30, 58, 138) // blue-900
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetDrawColor(30, 58, 138)
+
+	pdf.CellFormat(98, 9, "DESCRIPTION", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(22, 9, "QTY", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(22, 9, "RATE", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(28, 9, "AMOUNT", "1", 0, "R", true, 0, "")
+	pdf.Ln(-1)
+
+	// Table Body with alternating row colors
+	pdf.SetFont("Arial", "", 9)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetDrawColor(226, 232, 240) // slate-200
+
+	for rowIndex, line := range doc.Lines() {
+		// Truncate long descriptions
+		description := line.Description
+		if len(description) > 58 {
+			description = description[:55] + "..."
+		}
+
+		// Alternate row colors
+		if rowIndex%2 == 0 {
+			pdf.SetFillColor(248, 250, 252) // slate-50
+		} else {
+			pdf.SetFillColor(255, 255, 255) // white
+		}
+
+		pdf.CellFormat(98, 8, description, "1", 0, "L", true, 0, "")
+		pdf.CellFormat(22, 8, utils.FormatNumber(line.Quantity, 2), "1", 0, "C", true, 0, "")
+		pdf.CellFormat(22, 8, utils.FormatCurrencyRateForPDF(line.UnitPrice, currency), "1", 0, "C", true, 0, "")
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(28, 8, "-"+utils.FormatCurrencyForPDF(line.Amount, currency), "1", 0, "R", true, 0, "")
+		pdf.SetFont("Arial", "", 9)
+		pdf.Ln(-1)
+	}
+
+	// Subtotal Section
+	pdf.SetDrawColor(30, 58, 138)
+	pdf.SetLineWidth(0.5)
+	pdf.Line(20, pdf.GetY(), 190, pdf.GetY())
+	pdf.Ln(3)
+
+	// Summary box on the right - aligned with QTY, RATE, AMOUNT columns
+	summaryLabelX := 118.0
+	summaryValueX := 162.0
+
+	summaryRow := func(label, value string) {
+		summaryY := pdf.GetY()
+		pdf.SetXY(summaryLabelX, summaryY)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetTextColor(71, 85, 105) // slate-600
+		pdf.CellFormat(44, 7, label, "", 0, "L", false, 0, "")
+		pdf.SetXY(summaryValueX, summaryY)
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(28, 7, value, "", 0, "R", false, 0, "")
+		pdf.Ln(7)
+	}
+
+	summaryRow("Subtotal:", "-"+utils.FormatCurrencyForPDF(subtotal, currency))
+	if taxAmount.IsPositive() {
+		summaryRow("Tax:", "-"+utils.FormatCurrencyForPDF(taxAmount, currency))
+	}
+	if withholding.IsPositive() {
+		summaryRow("Withholding:", utils.FormatCurrencyForPDF(withholding, currency))
+	}
+	if doc.Invoice.ReverseCharge {
+		summaryRow("VAT:", "Reverse charge")
+	}
+	pdf.Ln(2)
+
+	// Total Credited with colored background
+	pdf.SetX(summaryLabelX)
+	pdf.SetFillColor(30, 58, 138) // blue-900
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(44, 10, "TOTAL CREDIT:", "1", 0, "L", true, 0, "")
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(28, 10, "-"+utils.FormatCurrencyForPDF(total, currency), "1", 0, "R", true, 0, "")
+	pdf.Ln(15)
+
+	// Reason Section
+	pdf.SetTextColor(0, 0, 0)
+	if creditNote.Reason.Valid && creditNote.Reason.String != "" {
+		pdf.SetFont("Arial", "B", 11)
+		pdf.SetTextColor(30, 58, 138) // blue-900
+		pdf.Cell(0, 8, "Reason")
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(71, 85, 105) // slate-600
+		pdf.MultiCell(170, 6, creditNote.Reason.String, "", "L", false)
+		pdf.Ln(5)
+	}
+
+	// Reference to the credited invoice
+	pdf.SetFont("Arial", "I", 9)
+	pdf.SetTextColor(71, 85, 105) // slate-600
+	pdf.MultiCell(170, 5, "This credit note cancels the amounts above from invoice "+doc.Invoice.InvoiceNumber+".", "", "L", false)
+
+	return pdf
+}
@@ -0,0 +1,326 @@
+package pdf
+
+import (
+	"fmt"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/utils"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// document is what the shared invoice layout renders: a titled, numbered
+// document for a client with a details box, billable lines and totals
+type document struct {
+	parties  billing.Parties
+	title    string
+	number   string
+	details  []detail
+	lines    []billing.Line
+	totals   billing.Totals
+	currency string
+	// settlement rows are shown below the total, e.g. payments received
+	settlement    []detail
+	reverseCharge bool
+	notes         string
+}
+
+// detail is a labelled value in the details box or below the total
+type detail struct {
+	label string
+	value string
+	color [3]int
+}
+
+// render lays out doc on an A4 page and returns the unwritten PDF
+func render(doc document) *gofpdf.Fpdf {
+	client := doc.parties.Client
+	currency := doc.currency
+	totals := doc.totals
+
+	// Generate PDF
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(doc.number, true)
+	pdf.SetAuthor(doc.parties.SellerName(), true)
+	pdf.SetCreator("FacturMe", false)
+	pdf.AddPage()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+
+	// Header Section with Blue Background
+	pdf.SetFillColor(30, 58, 138) // blue-900
+	pdf.Rect(0, 0, 210, 50, "F")
+
+	// Document Title
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 32)
+	pdf.SetY(15)
+	pdf.Cell(0, 10, doc.title)
+	pdf.Ln(12)
+
+	// Document Number
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 8, doc.number)
+	pdf.Ln(20)
+
+	// Reset text color for body
+	pdf.SetTextColor(0, 0, 0)
+
+	// Two column layout for Bill To and Document Details
+	leftX := 20.0
+	rightX := 115.0
+	currentY := pdf.GetY()
+
+	// Left Column - Bill To Section
+	pdf.SetXY(leftX, currentY)
+	pdf.SetFillColor(241, 245, 249) // slate-100
+	pdf.Rect(leftX, currentY, 85, 45, "F")
+
+	pdf.SetXY(leftX+5, currentY+5)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetTextColor(100, 116, 139) // slate-500
+	pdf.Cell(0, 5, "BILL TO")
+	pdf.Ln(7)
+
+	pdf.SetX(leftX + 5)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Cell(0, 6, client.Name)
+	pdf.Ln(6)
+
+	pdf.SetX(leftX + 5)
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetTextColor(71, 85, 105) // slate-600
+	pdf.Cell(0, 5, client.Email)
+	pdf.Ln(5)
+
+	if client.Company.Valid && client.Company.String != "" {
+		pdf.SetX(leftX + 5)
+		pdf.Cell(0, 5, client.Company.String)
+		pdf.Ln(5)
+	}
+
+	if client.TaxID.Valid && client.TaxID.String != "" {
+		pdf.SetX(leftX + 5)
+		pdf.Cell(0, 5, "Tax ID: "+client.TaxID.String)
+		pdf.Ln(5)
+	}
+
+	// Right Column - Document Details
+	pdf.SetXY(rightX, currentY)
+	pdf.SetFillColor(241, 245, 249) // slate-100
+	pdf.Rect(rightX, currentY, 75, 45, "F")
+
+	pdf.SetXY(rightX+5, currentY+5)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetTextColor(100, 116, 139) // slate-500
+	pdf.Cell(0, 5, doc.title+" DETAILS")
+	pdf.Ln(7)
+
+	for _, d := range doc.details {
+		pdf.SetX(rightX + 5)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetTextColor(71, 85, 105)
+		pdf.Cell(25, 5, d.label)
+		pdf.SetFont("Arial", "", 9)
+		pdf.SetTextColor(d.color[0], d.color[1], d.color[2])
+		pdf.Cell(0, 5, d.value)
+		pdf.Ln(5)
+	}
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetY(currentY + 50)
+	pdf.Ln(10)
+
+	// Line Items Header
+	pdf.SetFont("Arial", "B", 12)
+	pdf.SetTextColor(30, 58, 138) // blue-900
+	pdf.Cell(0, 8, "Line Items")
+	pdf.Ln(10)
+
+	// Table Header with colored background
+	pdf.SetFillColor(30, 58, 138) // blue-900
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetDrawColor(30, 58, 138)
+
+	pdf.CellFormat(30, 9, "DATE", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(68, 9, "DESCRIPTION", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(22, 9, "QTY", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(22, 9, "RATE", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(28, 9, "AMOUNT", "1", 0, "R", true, 0, "")
+	pdf.Ln(-1)
+
+	// Table Body with alternating row colors
+	pdf.SetFont("Arial", "", 9)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetDrawColor(226, 232, 240) // slate-200
+
+	// Lines are headed by their project, with the project's subtotal, when
+	// any of them has one
+	groups := billing.GroupByProject(doc.lines)
+	byProject := len(groups) > 1 || (len(groups) == 1 && groups[0].ProjectID != 0)
+
+	rowIndex := 0
+	for _, group := range groups {
+		if byProject {
+			name := group.Name
+			if group.ProjectID == 0 {
+				name = "Other"
+			}
+			if len(name) > 70 {
+				name = name[:67] + "..."
+			}
+
+			pdf.SetFillColor(226, 232, 240) // slate-200
+			pdf.SetTextColor(30, 58, 138)   // blue-900
+			pdf.SetFont("Arial", "B", 9)
+			pdf.CellFormat(142, 8, name, "1", 0, "L", true, 0, "")
+			pdf.CellFormat(28, 8, utils.FormatCurrencyForPDF(group.Amount(), currency), "1", 0, "R", true, 0, "")
+			pdf.SetFont("Arial", "", 9)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.Ln(-1)
+		}
+
+		for _, line := range group.Lines {
+			// Truncate long descriptions
+			description := line.Description
+			if len(description) > 40 {
+				description = description[:37] + "..."
+			}
+
+			// Alternate row colors
+			if rowIndex%2 == 0 {
+				pdf.SetFillColor(248, 250, 252) // slate-50
+			} else {
+				pdf.SetFillColor(255, 255, 255) // white
+			}
+			rowIndex++
+
+			date := ""
+			if !line.Date.IsZero() {
+				date = line.Date.Format("Jan 2, 2006")
+			}
+
+			pdf.CellFormat(30, 8, date, "1", 0, "L", true, 0, "")
+			pdf.CellFormat(68, 8, description, "1", 0, "L", true, 0, "")
+			pdf.CellFormat(22, 8, utils.FormatNumber(line.Quantity, 2), "1", 0, "C", true, 0, "")
+			pdf.CellFormat(22, 8, utils.FormatCurrencyRateForPDF(line.UnitPrice, currency), "1", 0, "C", true, 0, "")
+			pdf.SetFont("Arial", "B", 9)
+			pdf.CellFormat(28, 8, utils.FormatCurrencyForPDF(line.Amount, currency), "1", 0, "R", true, 0, "")
+			pdf.SetFont("Arial", "", 9)
+			pdf.Ln(-1)
+		}
+	}
+
+	// Subtotal Section
+	pdf.SetDrawColor(30, 58, 138)
+	pdf.SetLineWidth(0.5)
+	pdf.Line(20, pdf.GetY(), 190, pdf.GetY())
+	pdf.Ln(3)
+
+	// Summary box on the right - aligned with QTY, RATE, AMOUNT columns
+	// Table structure: 30 (DATE) + 68 (DESC) = 98mm, then QTY(22) + RATE(22) + AMOUNT(28) = 72mm
+	summaryLabelX := 118.0 // Start where QTY column starts (20 + 30 + 68)
+	summaryValueX := 162.0 // Start where AMOUNT column starts (20 + 30 + 68 + 22 + 22)
+
+	summaryRow := func(label, value string) {
+		summaryY := pdf.GetY()
+		pdf.SetXY(summaryLabelX, summaryY)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetTextColor(71, 85, 105) // slate-600
+		pdf.CellFormat(44, 7, label, "", 0, "L", false, 0, "")
+		pdf.SetXY(summaryValueX, summaryY)
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(28, 7, value, "", 0, "R", false, 0, "")
+		pdf.Ln(7)
+	}
+
+	if totals.Hours.IsPositive() {
+		summaryRow("Total Hours:", utils.FormatNumber(totals.Hours, 2))
+	}
+	summaryRow("Subtotal:", utils.FormatCurrencyForPDF(totals.Subtotal, currency))
+	if totals.LineTax.IsPositive() {
+		summaryRow("Line Tax:", utils.FormatCurrencyForPDF(totals.LineTax, currency))
+	}
+	for _, tax := range totals.Taxes {
+		label := fmt.Sprintf("%s (%s%%):", tax.Name, utils.FormatNumber(tax.Rate, 2))
+		if tax.Withholding {
+			summaryRow(label, "-"+utils.FormatCurrencyForPDF(tax.Amount, currency))
+		} else {
+			summaryRow(label, utils.FormatCurrencyForPDF(tax.Amount, currency))
+		}
+	}
+	if doc.reverseCharge {
+		summaryRow("VAT:", "Reverse charge")
+	}
+	pdf.Ln(2)
+
+	// Total Amount with colored background
+	pdf.SetX(summaryLabelX)
+	pdf.SetFillColor(30, 58, 138) // blue-900
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(44, 10, "TOTAL:", "1", 0, "L", true, 0, "")
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(28, 10, utils.FormatCurrencyForPDF(totals.Total, currency), "1", 0, "R", true, 0, "")
+	pdf.Ln(12)
+
+	for _, row := range doc.settlement {
+		summaryRow(row.label, row.value)
+	}
+	pdf.Ln(3)
+
+	// Reverse charge notice required on EU intra-community B2B invoices
+	if doc.reverseCharge {
+		pdf.SetFont("Arial", "I", 9)
+		pdf.SetTextColor(71, 85, 105) // slate-600
+		pdf.MultiCell(170, 5, "Reverse charge: VAT is to be accounted for by the recipient (Article 196, Council Directive 2006/112/EC).", "", "L", false)
+		pdf.Ln(5)
+	}
+
+	// Notes Section
+	pdf.SetTextColor(0, 0, 0)
+	if doc.notes != "" {
+		pdf.SetFont("Arial", "B", 11)
+		pdf.SetTextColor(30, 58, 138) // blue-900
+		pdf.Cell(0, 8, "Notes")
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(71, 85, 105)   // slate-600
+		pdf.SetFillColor(248, 250, 252) // slate-50
+
+		// Draw background for notes
+		noteY := pdf.GetY()
+		pdf.Rect(20, noteY, 170, -1, "F") // Will be auto-sized
+		pdf.MultiCell(170, 6, doc.notes, "", "L", false)
+		pdf.Ln(5)
+	}
+
+	// Footer
+	pdf.SetY(-30)
+	pdf.SetFont("Arial", "I", 9)
+	pdf.SetTextColor(148, 163, 184) // slate-400
+	pdf.CellFormat(0, 10, "Thank you for your business!", "", 0, "C", false, 0, "")
+
+	return pdf
+}
+
+func getStatusColorRGB(status string) [3]int {
+	switch status {
+	case "draft":
+		return [3]int{107, 114, 128} // gray
+	case "sent":
+		return [3]int{59, 130, 246} // blue
+	case "partially_paid":
+		return [3]int{245, 158, 11} // amber
+	case "paid", "accepted":
+		return [3]int{34, 197, 94} // green
+	case "overdue", "declined":
+		return [3]int{239, 68, 68} // red
+	default:
+		return [3]int{107, 114, 128} // gray
+	}
+}
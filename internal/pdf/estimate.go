@@ -0,0 +1,28 @@
+package pdf
+
+import (
+	"worklio-api/internal/billing"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// RenderEstimate lays out doc with the invoice layout and returns the
+// unwritten PDF
+func RenderEstimate(doc *billing.EstimateDocument) *gofpdf.Fpdf {
+	estimate := doc.Estimate
+
+	return render(document{
+		parties: doc.Parties,
+		title:   "ESTIMATE",
+		number:  estimate.EstimateNumber,
+		details: []detail{
+			{label: "Status:", value: estimate.Status, color: getStatusColorRGB(estimate.Status)},
+			{label: "Issue Date:", value: estimate.IssueDate.Format("Jan 2, 2006")},
+			{label: "Valid Until:", value: estimate.ValidUntil.Format("Jan 2, 2006")},
+		},
+		lines:    doc.Lines(),
+		totals:   doc.Totals(),
+		currency: doc.Currency(),
+		notes:    estimate.Notes.String,
+	})
+}
@@ -0,0 +1,265 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/einvoice"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// OutputFacturX writes pdf to w with the CII XML embedded as a Factur-X
+// EN 16931 invoice, producing a PDF/A-3 hybrid PDF that both humans and
+// accounting software can read.
+//
+// gofpdf cannot write the associated file relationship (/AF) PDF/A-3 requires
+// for the embedded XML, an output intent, or a catalog reference to XMP
+// metadata, so the PDF it renders is completed by an incremental update: see
+// facturXUpdate. The standard fonts
+// the documents are set in are not embedded, which PDF/A also requires, so a
+// strict validator still reports the fonts.
+func OutputFacturX(w io.Writer, pdf *gofpdf.Fpdf, doc *billing.Document, xml []byte) error {
+	// PDF dates are written without a timezone by gofpdf, so use UTC
+	// throughout and state it in the Info dictionary of the update
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	pdf.SetCreationDate(createdAt)
+	pdf.SetModificationDate(createdAt)
+	pdf.SetProducer("FacturMe", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return err
+	}
+
+	update, err := facturXUpdate(buf.Bytes(), doc, xml, createdAt)
+	if err != nil {
+		return err
+	}
+	buf.Write(update)
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// gofpdfTrailer matches the trailer gofpdf ends every document with
+var gofpdfTrailer = regexp.MustCompile(`trailer\n<<\n/Size (\d+)\n/Root (\d+) 0 R\n/Info (\d+) 0 R\n>>\nstartxref\n(\d+)\n%%EOF\n?$`)
+
+// facturXUpdate returns an incremental update of the PDF gofpdf rendered,
+// which is appended to it. It adds the XML as an embedded file with its MIME
+// type and modification date, referenced from the catalog both by name and as
+// the document's associated file (/AF) with the Data relationship; the XMP
+// metadata declaring PDF/A-3 and Factur-X conformance; an sRGB output intent;
+// an Info dictionary whose entries match the XMP metadata; and the document
+// ID PDF/A requires in the trailer.
+func facturXUpdate(original []byte, doc *billing.Document, xml []byte, createdAt time.Time) ([]byte, error) {
+	match := gofpdfTrailer.FindSubmatch(original)
+	if match == nil {
+		return nil, errors.New("unexpected PDF trailer")
+	}
+	size, _ := strconv.Atoi(string(match[1]))
+	root, _ := strconv.Atoi(string(match[2]))
+	info, _ := strconv.Atoi(string(match[3]))
+	prevXref := string(match[4])
+
+	catalog, err := catalogEntries(original, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(xml)
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress Factur-X XML: %w", err)
+	}
+	checksum := md5.Sum(xml)
+	date := pdfString("D:" + createdAt.Format("20060102150405") + "Z")
+	profile := srgbProfile()
+	metadata := []byte(facturXMetadata(doc, createdAt))
+
+	// New objects are numbered after the existing ones; the catalog and Info
+	// dictionary replace theirs
+	embeddedFile := size
+	fileSpec := size + 1
+	iccProfile := size + 2
+	outputIntent := size + 3
+	xmp := size + 4
+
+	var update bytes.Buffer
+	update.WriteString("\n")
+	offsets := make(map[int]int)
+	object := func(number int, body string, stream []byte) {
+		offsets[number] = len(original) + update.Len()
+		fmt.Fprintf(&update, "%d 0 obj\n%s\n", number, body)
+		if stream != nil {
+			update.WriteString("stream\n")
+			update.Write(stream)
+			update.WriteString("\nendstream\n")
+		}
+		update.WriteString("endobj\n")
+	}
+
+	object(embeddedFile, fmt.Sprintf("<< /Type /EmbeddedFile /Subtype /text#2Fxml /Filter /FlateDecode /Length %d /Params << /Size %d /CheckSum <%s> /ModDate %s >> >>",
+		compressed.Len(), len(xml), hex.EncodeToString(checksum[:]), date), compressed.Bytes())
+	object(fileSpec, fmt.Sprintf("<< /Type /Filespec /F %s /UF %s /Desc %s /AFRelationship /Data /EF << /F %d 0 R /UF %d 0 R >> >>",
+		pdfString(einvoice.FacturXFilename), pdfString(einvoice.FacturXFilename), pdfString("Factur-X invoice"), embeddedFile, embeddedFile), nil)
+	object(iccProfile, fmt.Sprintf("<< /N 3 /Length %d >>", len(profile)), profile)
+	object(outputIntent, fmt.Sprintf("<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier %s /Info %s /RegistryName %s /DestOutputProfile %d 0 R >>",
+		pdfString(srgbDescription), pdfString(srgbDescription), pdfString("http://www.color.org"), iccProfile), nil)
+	// PDF/A forbids compressing the metadata stream
+	object(xmp, fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>", len(metadata)), metadata)
+	object(info, fmt.Sprintf("<< /Producer %s /Title %s /Author %s /CreationDate %s /ModDate %s >>",
+		pdfString("FacturMe"), pdfString(doc.Invoice.InvoiceNumber), pdfString(doc.SellerName()), date, date), nil)
+	object(root, fmt.Sprintf("<<%s/Names << /EmbeddedFiles << /Names [%s %d 0 R] >> >>\n/AF [%d 0 R]\n/OutputIntents [%d 0 R]\n/Metadata %d 0 R\n>>",
+		catalog, pdfString(einvoice.FacturXFilename), fileSpec, fileSpec, outputIntent, xmp), nil)
+
+	xref := len(original) + update.Len()
+	update.WriteString("xref\n")
+	numbers := make([]int, 0, len(offsets))
+	for number := range offsets {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		fmt.Fprintf(&update, "%d 1\n%010d 00000 n \n", number, offsets[number])
+	}
+
+	id := md5.Sum(append(checksum[:], date...))
+	fmt.Fprintf(&update, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R /Prev %s /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF\n",
+		xmp+1, root, info, prevXref, id, id, xref)
+	return update.Bytes(), nil
+}
+
+// catalogEntries returns the entries of the catalog gofpdf wrote as object
+// root, without its /Names dictionary, which gofpdf always writes last
+func catalogEntries(original []byte, root int) (string, error) {
+	header := []byte(fmt.Sprintf("\n%d 0 obj\n<<", root))
+	start := bytes.LastIndex(original, header)
+	if start < 0 {
+		return "", errors.New("PDF catalog not found")
+	}
+	start += len(header)
+	end := bytes.Index(original[start:], []byte("/Names <<"))
+	if end < 0 {
+		return "", errors.New("unexpected PDF catalog")
+	}
+	return string(original[start : start+end]), nil
+}
+
+// pdfString encodes s as a PDF text string: a literal string when it is
+// printable ASCII, UTF-16BE with a byte order mark otherwise
+func pdfString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+		return "(" + replacer.Replace(s) + ")"
+	}
+	encoded := utf16.Encode([]rune(s))
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range encoded {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// facturXMetadata builds the XMP packet declaring PDF/A-3B conformance and the
+// Factur-X extension schema
+func facturXMetadata(doc *billing.Document, createdAt time.Time) string {
+	timestamp := createdAt.Format(time.RFC3339)
+	return fmt.Sprintf(`<?xpacket begin="%s" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+<pdfaid:part>3</pdfaid:part>
+<pdfaid:conformance>B</pdfaid:conformance>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">%s</rdf:li></rdf:Alt></dc:title>
+<dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdf="http://ns.adobe.com/pdf/1.3/">
+<pdf:Producer>FacturMe</pdf:Producer>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/">
+<xmp:CreatorTool>FacturMe</xmp:CreatorTool>
+<xmp:CreateDate>%s</xmp:CreateDate>
+<xmp:ModifyDate>%s</xmp:ModifyDate>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#">
+<fx:DocumentType>INVOICE</fx:DocumentType>
+<fx:DocumentFileName>%s</fx:DocumentFileName>
+<fx:Version>1.0</fx:Version>
+<fx:ConformanceLevel>EN 16931</fx:ConformanceLevel>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/" xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#" xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+<pdfaExtension:schemas>
+<rdf:Bag>
+<rdf:li rdf:parseType="Resource">
+<pdfaSchema:schema>Factur-X PDFA Extension Schema</pdfaSchema:schema>
+<pdfaSchema:namespaceURI>urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#</pdfaSchema:namespaceURI>
+<pdfaSchema:prefix>fx</pdfaSchema:prefix>
+<pdfaSchema:property>
+<rdf:Seq>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>DocumentFileName</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>name of the embedded XML invoice file</pdfaProperty:description>
+</rdf:li>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>DocumentType</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>INVOICE</pdfaProperty:description>
+</rdf:li>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>Version</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>The actual version of the Factur-X XML schema</pdfaProperty:description>
+</rdf:li>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>ConformanceLevel</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>The conformance level of the embedded Factur-X data</pdfaProperty:description>
+</rdf:li>
+</rdf:Seq>
+</pdfaSchema:property>
+</rdf:li>
+</rdf:Bag>
+</pdfaExtension:schemas>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`,
+		"\ufeff",
+		html.EscapeString(doc.Invoice.InvoiceNumber),
+		html.EscapeString(doc.SellerName()),
+		timestamp,
+		timestamp,
+		einvoice.FacturXFilename,
+	)
+}
@@ -0,0 +1,248 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/einvoice"
+)
+
+func testDocument() *billing.Document {
+	return &billing.Document{
+		Contents: billing.Contents{
+			Currency: "EUR",
+			LineItems: []db.InvoiceLineItem{
+				{Description: "Consulting", Quantity: "10", Unit: "hour", UnitPrice: "90", TaxRate: "20"},
+			},
+		},
+		Parties: billing.Parties{
+			Seller: db.GetUserBillingProfileRow{
+				Name:        "Jane Doe",
+				Email:       "jane@example.com",
+				CountryCode: sql.NullString{String: "FR", Valid: true},
+				TaxID:       sql.NullString{String: "FR12345678901", Valid: true},
+			},
+			Client: db.GetClientByIDRow{
+				Name:        "Zoë Müller",
+				Email:       "zoe@example.com",
+				Currency:    "EUR",
+				CountryCode: sql.NullString{String: "FR", Valid: true},
+			},
+		},
+		Invoice: db.Invoice{
+			InvoiceNumber: "INV-(42)",
+			IssueDate:     time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			DueDate:       time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+			Status:        "sent",
+		},
+	}
+}
+
+// pdfObject returns the body of the last revision of object number in data,
+// and the stream following it if any
+func pdfObject(t *testing.T, data []byte, number int) (string, []byte) {
+	t.Helper()
+	header := []byte(fmt.Sprintf("\n%d 0 obj\n", number))
+	start := bytes.LastIndex(data, header)
+	if start < 0 {
+		t.Fatalf("object %d not found", number)
+	}
+	start += len(header)
+	end := bytes.Index(data[start:], []byte("endobj"))
+	body := data[start : start+end]
+	streamStart := bytes.Index(body, []byte("stream\n"))
+	if streamStart < 0 {
+		return string(body), nil
+	}
+	streamEnd := bytes.LastIndex(body, []byte("\nendstream"))
+	return string(body[:streamStart]), body[streamStart+len("stream\n") : streamEnd]
+}
+
+// referenced returns the object number referenced after key in dict
+func referenced(t *testing.T, dict, key string) int {
+	t.Helper()
+	match := regexp.MustCompile(regexp.QuoteMeta(key) + `\s*\[?\s*(\d+) 0 R`).FindStringSubmatch(dict)
+	if match == nil {
+		t.Fatalf("%s not found in %s", key, dict)
+	}
+	number, _ := strconv.Atoi(match[1])
+	return number
+}
+
+func TestOutputFacturX(t *testing.T) {
+	doc := testDocument()
+	xml, err := einvoice.BuildCII(doc)
+	if err != nil {
+		t.Fatalf("BuildCII: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := OutputFacturX(&out, RenderInvoice(doc), doc, xml); err != nil {
+		t.Fatalf("OutputFacturX: %v", err)
+	}
+	data := out.Bytes()
+
+	// The update chains to the xref section gofpdf wrote
+	startxrefs := regexp.MustCompile(`startxref\n(\d+)\n%%EOF`).FindAllSubmatch(data, -1)
+	if len(startxrefs) != 2 {
+		t.Fatalf("expected the original and an updated trailer, found %d", len(startxrefs))
+	}
+	trailer := data[bytes.LastIndex(data, []byte("trailer")):]
+	prev := regexp.MustCompile(`/Prev (\d+)`).FindSubmatch(trailer)
+	if prev == nil || !bytes.Equal(prev[1], startxrefs[0][1]) {
+		t.Fatalf("trailer does not chain to the original xref: %s", trailer)
+	}
+	if !regexp.MustCompile(`/ID \[<[0-9a-f]{32}> <[0-9a-f]{32}>\]`).Match(trailer) {
+		t.Errorf("trailer has no document ID: %s", trailer)
+	}
+
+	// Every entry of the new xref section points at its object
+	xrefOffset, _ := strconv.Atoi(string(startxrefs[1][1]))
+	if !bytes.HasPrefix(data[xrefOffset:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at an xref section", xrefOffset)
+	}
+	entries := regexp.MustCompile(`(\d+) 1\n(\d{10}) 00000 n \n`).FindAllSubmatch(data[xrefOffset:], -1)
+	if len(entries) == 0 {
+		t.Fatal("the update's xref section is empty")
+	}
+	for _, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[2]))
+		if want := fmt.Sprintf("%s 0 obj\n", entry[1]); !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry for object %s points at %q", entry[1], data[offset:offset+20])
+		}
+	}
+
+	root, _ := strconv.Atoi(string(regexp.MustCompile(`/Root (\d+) 0 R`).FindSubmatch(trailer)[1]))
+	catalog, _ := pdfObject(t, data, root)
+	for _, key := range []string{"/Type /Catalog", "/Pages 1 0 R", "/EmbeddedFiles"} {
+		if !bytes.Contains([]byte(catalog), []byte(key)) {
+			t.Errorf("catalog lacks %s: %s", key, catalog)
+		}
+	}
+
+	// The XML is the catalog's associated file, with the Data relationship
+	fileSpec, _ := pdfObject(t, data, referenced(t, catalog, "/AF"))
+	for _, key := range []string{"/Type /Filespec", "/F (factur-x.xml)", "/UF (factur-x.xml)", "/AFRelationship /Data"} {
+		if !bytes.Contains([]byte(fileSpec), []byte(key)) {
+			t.Errorf("file specification lacks %s: %s", key, fileSpec)
+		}
+	}
+	fileDict, stream := pdfObject(t, data, referenced(t, fileSpec, "/EF << /F"))
+	for _, key := range []string{"/Type /EmbeddedFile", "/Subtype /text#2Fxml", "/ModDate (D:"} {
+		if !bytes.Contains([]byte(fileDict), []byte(key)) {
+			t.Errorf("embedded file lacks %s: %s", key, fileDict)
+		}
+	}
+	reader, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("embedded file is not deflated: %v", err)
+	}
+	embedded, err := io.ReadAll(reader)
+	if err != nil || !bytes.Equal(embedded, xml) {
+		t.Errorf("embedded file is not the CII XML: %v", err)
+	}
+
+	// The output intent embeds the sRGB profile
+	intent, _ := pdfObject(t, data, referenced(t, catalog, "/OutputIntents"))
+	if !bytes.Contains([]byte(intent), []byte("/S /GTS_PDFA1")) {
+		t.Errorf("output intent is not for PDF/A: %s", intent)
+	}
+	profileDict, profile := pdfObject(t, data, referenced(t, intent, "/DestOutputProfile"))
+	if !bytes.Contains([]byte(profileDict), []byte("/N 3")) {
+		t.Errorf("ICC profile is not RGB: %s", profileDict)
+	}
+	if !bytes.Equal(profile, srgbProfile()) {
+		t.Error("output intent does not embed the sRGB profile")
+	}
+
+	// The XMP metadata is uncompressed and matches the Info dictionary
+	metadataDict, metadata := pdfObject(t, data, referenced(t, catalog, "/Metadata"))
+	if bytes.Contains([]byte(metadataDict), []byte("/Filter")) {
+		t.Errorf("metadata stream is compressed: %s", metadataDict)
+	}
+	for _, want := range []string{
+		"<pdfaid:part>3</pdfaid:part>",
+		"<pdfaid:conformance>B</pdfaid:conformance>",
+		"<fx:DocumentFileName>factur-x.xml</fx:DocumentFileName>",
+		"<fx:ConformanceLevel>EN 16931</fx:ConformanceLevel>",
+		"<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">INV-(42)</rdf:li></rdf:Alt></dc:title>",
+		"<pdf:Producer>FacturMe</pdf:Producer>",
+	} {
+		if !bytes.Contains(metadata, []byte(want)) {
+			t.Errorf("metadata lacks %s", want)
+		}
+	}
+
+	info, _ := pdfObject(t, data, referenced(t, string(trailer), "/Info"))
+	for _, want := range []string{`/Title (INV-\(42\))`, "/Producer (FacturMe)", "/Author (Jane Doe)"} {
+		if !bytes.Contains([]byte(info), []byte(want)) {
+			t.Errorf("Info dictionary lacks %s: %s", want, info)
+		}
+	}
+	created := regexp.MustCompile(`/CreationDate \(D:(\d{14})Z\)`).FindStringSubmatch(info)
+	if created == nil {
+		t.Fatalf("Info dictionary has no UTC creation date: %s", info)
+	}
+	createdAt, _ := time.Parse("20060102150405", created[1])
+	if want := "<xmp:CreateDate>" + createdAt.Format(time.RFC3339) + "</xmp:CreateDate>"; !bytes.Contains(metadata, []byte(want)) {
+		t.Errorf("metadata lacks %s", want)
+	}
+}
+
+func TestSRGBProfile(t *testing.T) {
+	profile := srgbProfile()
+
+	if size := binary.BigEndian.Uint32(profile); int(size) != len(profile) {
+		t.Errorf("profile size is %d, header says %d", len(profile), size)
+	}
+	if string(profile[36:40]) != "acsp" {
+		t.Errorf("profile signature is %q", profile[36:40])
+	}
+	if string(profile[12:20]) != "mntrRGB " {
+		t.Errorf("profile class and colour space are %q", profile[12:20])
+	}
+
+	// Every tag lies within the profile, aligned to 4 bytes
+	tags := map[string]bool{}
+	count := int(binary.BigEndian.Uint32(profile[128:]))
+	for i := 0; i < count; i++ {
+		entry := profile[132+12*i:]
+		signature := string(entry[:4])
+		offset := binary.BigEndian.Uint32(entry[4:])
+		size := binary.BigEndian.Uint32(entry[8:])
+		if offset%4 != 0 || int(offset+size) > len(profile) {
+			t.Errorf("tag %s at %d+%d is misplaced", signature, offset, size)
+		}
+		tags[signature] = true
+	}
+	for _, required := range []string{"desc", "cprt", "wtpt", "rXYZ", "gXYZ", "bXYZ", "rTRC", "gTRC", "bTRC"} {
+		if !tags[required] {
+			t.Errorf("profile lacks the %s tag", required)
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"factur-x.xml", "(factur-x.xml)"},
+		{`a (b) \c`, `(a \(b\) \\c)`},
+		{"Zoë", "<FEFF005A006F00EB>"},
+	}
+	for _, tt := range tests {
+		if got := pdfString(tt.in); got != tt.want {
+			t.Errorf("pdfString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
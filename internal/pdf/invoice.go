@@ -0,0 +1,58 @@
+// Package pdf renders invoices, estimates and credit notes as PDF documents
+// using gofpdf.
+package pdf
+
+import (
+	"worklio-api/internal/billing"
+	"worklio-api/internal/utils"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// RenderInvoice lays out doc on an A4 page and returns the unwritten PDF so
+// callers can attach extra content (e.g. Factur-X XML) before output.
+func RenderInvoice(doc *billing.Document) *gofpdf.Fpdf {
+	invoice := doc.Invoice
+	currency := doc.Currency()
+	// An issued invoice shows the totals frozen when it was issued
+	totals := billing.IssuedTotals(invoice, doc.Contents)
+
+	dueDateColor := [3]int{0, 0, 0}
+	if invoice.Status == "overdue" {
+		dueDateColor = [3]int{239, 68, 68} // red
+	}
+
+	var settlement []detail
+	if totals.Credited.IsPositive() {
+		settlement = append(settlement, detail{label: "Credited:", value: "-" + utils.FormatCurrencyForPDF(totals.Credited, currency)})
+	}
+	if totals.Paid.IsPositive() {
+		settlement = append(settlement, detail{label: "Amount Paid:", value: "-" + utils.FormatCurrencyForPDF(totals.Paid, currency)})
+	}
+	if totals.Paid.IsPositive() || totals.Credited.IsPositive() {
+		settlement = append(settlement, detail{label: "Balance Due:", value: utils.FormatCurrencyForPDF(totals.Balance, currency)})
+	}
+
+	// Drafts are numbered when they are issued
+	number := invoice.InvoiceNumber
+	if number == "" {
+		number = "DRAFT"
+	}
+
+	return render(document{
+		parties: doc.Parties,
+		title:   "INVOICE",
+		number:  number,
+		details: []detail{
+			{label: "Status:", value: invoice.Status, color: getStatusColorRGB(invoice.Status)},
+			{label: "Issue Date:", value: invoice.IssueDate.Format("Jan 2, 2006")},
+			{label: "Due Date:", value: invoice.DueDate.Format("Jan 2, 2006"), color: dueDateColor},
+		},
+		lines:         doc.Lines(),
+		totals:        totals,
+		currency:      currency,
+		settlement:    settlement,
+		reverseCharge: invoice.ReverseCharge,
+		notes:         invoice.Notes.String,
+	})
+}
@@ -0,0 +1,121 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// srgbDescription names the sRGB colour space, both in the ICC profile and in
+// the output intent that embeds it
+const srgbDescription = "sRGB IEC61966-2.1"
+
+// srgbProfile builds an ICC v2 display profile for sRGB: the D50-adapted
+// primaries and white point, and the sRGB transfer curve sampled into a
+// table. PDF/A requires the output intent to embed the profile the page
+// colours are meant for, and every colour in the rendered documents is sRGB.
+func srgbProfile() []byte {
+	curve := srgbCurve(1024)
+
+	type tag struct {
+		signature string
+		data      []byte
+	}
+	tags := []tag{
+		{"desc", iccDescription(srgbDescription)},
+		{"cprt", iccText("No copyright, use freely")},
+		{"wtpt", iccXYZ(0.9642, 1.0, 0.8249)},
+		{"rXYZ", iccXYZ(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", iccXYZ(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", iccXYZ(0.1431, 0.0606, 0.7141)},
+		{"rTRC", curve},
+		{"gTRC", curve},
+		{"bTRC", curve},
+	}
+
+	// Tag data follows the 128 byte header and the tag table, each tag
+	// aligned to 4 bytes
+	offset := 128 + 4 + 12*len(tags)
+	var table, data bytes.Buffer
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	for _, t := range tags {
+		table.WriteString(t.signature)
+		binary.Write(&table, binary.BigEndian, uint32(offset+data.Len()))
+		binary.Write(&table, binary.BigEndian, uint32(len(t.data)))
+		data.Write(t.data)
+		for data.Len()%4 != 0 {
+			data.WriteByte(0)
+		}
+	}
+
+	size := offset + data.Len()
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(size))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // version 2.1
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	for i, v := range []uint16{2025, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(header[24+2*i:], v)
+	}
+	copy(header[36:], "acsp")
+	// D50 illuminant of the profile connection space
+	copy(header[68:], iccXYZ(0.9642, 1.0, 0.8249)[8:])
+
+	profile := make([]byte, 0, size)
+	profile = append(profile, header...)
+	profile = append(profile, table.Bytes()...)
+	return append(profile, data.Bytes()...)
+}
+
+// srgbCurve samples the sRGB transfer function into a curveType tag
+func srgbCurve(points int) []byte {
+	var b bytes.Buffer
+	b.WriteString("curv")
+	b.Write(make([]byte, 4))
+	binary.Write(&b, binary.BigEndian, uint32(points))
+	for i := 0; i < points; i++ {
+		v := float64(i) / float64(points-1)
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		binary.Write(&b, binary.BigEndian, uint16(math.Round(v*65535)))
+	}
+	return b.Bytes()
+}
+
+// iccXYZ encodes an XYZType tag holding one colour
+func iccXYZ(x, y, z float64) []byte {
+	b := make([]byte, 20)
+	copy(b, "XYZ ")
+	for i, v := range []float64{x, y, z} {
+		binary.BigEndian.PutUint32(b[8+4*i:], uint32(int32(math.Round(v*65536))))
+	}
+	return b
+}
+
+// iccText encodes a textType tag
+func iccText(text string) []byte {
+	b := make([]byte, 8, 8+len(text)+1)
+	copy(b, "text")
+	b = append(b, text...)
+	return append(b, 0)
+}
+
+// iccDescription encodes a textDescriptionType tag with only the ASCII
+// description; the Unicode and ScriptCode descriptions are left empty
+func iccDescription(text string) []byte {
+	var b bytes.Buffer
+	b.WriteString("desc")
+	b.Write(make([]byte, 4))
+	binary.Write(&b, binary.BigEndian, uint32(len(text)+1))
+	b.WriteString(text)
+	b.WriteByte(0)
+	// Unicode language code and count
+	b.Write(make([]byte, 8))
+	// ScriptCode code, count and 67 byte description
+	b.Write(make([]byte, 2+1+67))
+	return b.Bytes()
+}
@@ -0,0 +1,110 @@
+package rates
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ECBURL is the address of the European Central Bank reference rate feeds
+const ECBURL = "https://www.ecb.europa.eu/stats/eurofxref"
+
+// ECB fetches the euro reference rates published by the European Central
+// Bank every working day. Rates against other bases are derived from them.
+type ECB struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewECB creates an ECB provider for the feeds at baseURL
+func NewECB(baseURL string, client *http.Client) *ECB {
+	return &ECB{baseURL: baseURL, client: client}
+}
+
+// ecbEnvelope is the layout of the ECB XML feeds: one Cube per day holding
+// one Cube per currency
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// Name returns "ecb"
+func (p *ECB) Name() string {
+	return "ecb"
+}
+
+// Latest returns the rates of the latest working day
+func (p *ECB) Latest(ctx context.Context, base string) (Rates, error) {
+	days, err := p.fetch(ctx, "eurofxref-daily.xml")
+	if err != nil {
+		return Rates{}, err
+	}
+	if len(days) == 0 {
+		return Rates{}, errors.New("feed has no rates")
+	}
+	return days[0].Rebase(base)
+}
+
+// History returns the rates of each working day between from and to. The
+// short 90 day feed is used when it covers the range.
+func (p *ECB) History(ctx context.Context, base string, from, to time.Time) ([]Rates, error) {
+	feed := "eurofxref-hist.xml"
+	if time.Since(from) < 89*24*time.Hour {
+		feed = "eurofxref-hist-90d.xml"
+	}
+	days, err := p.fetch(ctx, feed)
+	if err != nil {
+		return nil, err
+	}
+
+	// The feeds list the most recent day first
+	history := make([]Rates, 0, len(days))
+	for i := len(days) - 1; i >= 0; i-- {
+		if !inRange(days[i].Date, from, to) {
+			continue
+		}
+		rebased, err := days[i].Rebase(base)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, rebased)
+	}
+	return history, nil
+}
+
+// fetch downloads and parses one of the ECB feeds
+func (p *ECB) fetch(ctx context.Context, feed string) ([]Rates, error) {
+	body, err := get(ctx, p.client, p.baseURL+"/"+feed)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode ECB feed: %w", err)
+	}
+
+	days := make([]Rates, 0, len(envelope.Days))
+	for _, day := range envelope.Days {
+		date, err := time.Parse("2006-01-02", day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate date %q: %w", day.Time, err)
+		}
+		rates := make(map[string]float64, len(day.Rates)+1)
+		for _, rate := range day.Rates {
+			rates[rate.Currency] = rate.Rate
+		}
+		rates["EUR"] = 1.0
+		days = append(days, Rates{Base: "EUR", Date: date, Rates: rates})
+	}
+	return days, nil
+}
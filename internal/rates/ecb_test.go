@@ -0,0 +1,115 @@
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const ecbFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2025-03-04">
+			<Cube currency="USD" rate="1.3"/>
+			<Cube currency="GBP" rate="0.8"/>
+		</Cube>
+		<Cube time="2025-03-03">
+			<Cube currency="USD" rate="1.25"/>
+			<Cube currency="GBP" rate="0.8"/>
+		</Cube>
+		<Cube time="2025-02-28">
+			<Cube currency="USD" rate="1.2"/>
+			<Cube currency="GBP" rate="0.8"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+// ecbServer serves feed under every feed name and records the paths requested
+func ecbServer(t *testing.T, feed string) (*ECB, *[]string) {
+	t.Helper()
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(feed))
+	}))
+	t.Cleanup(server.Close)
+	return NewECB(server.URL, server.Client()), &paths
+}
+
+func TestECBLatest(t *testing.T) {
+	ecb, paths := ecbServer(t, ecbFeed)
+
+	latest, err := ecb.Latest(context.Background(), "USD")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if len(*paths) != 1 || (*paths)[0] != "/eurofxref-daily.xml" {
+		t.Errorf("requested %v", *paths)
+	}
+
+	// The first day of the feed, rebased from EUR
+	if latest.Base != "USD" || !latest.Date.Equal(date("2025-03-04")) {
+		t.Errorf("got %s rates of %s", latest.Base, latest.Date)
+	}
+	if !near(latest.Rates["EUR"], 1/1.3) || !near(latest.Rates["GBP"], 0.8/1.3) || latest.Rates["USD"] != 1 {
+		t.Errorf("got rates %v", latest.Rates)
+	}
+}
+
+func TestECBHistory(t *testing.T) {
+	ecb, paths := ecbServer(t, ecbFeed)
+
+	history, err := ecb.History(context.Background(), "EUR", date("2025-03-01"), date("2025-03-04"))
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if (*paths)[0] != "/eurofxref-hist.xml" {
+		t.Errorf("requested %v for a range older than 90 days", *paths)
+	}
+
+	// Days outside the range are left out and the rest come oldest first
+	if len(history) != 2 {
+		t.Fatalf("got %d days, want 2", len(history))
+	}
+	if !history[0].Date.Equal(date("2025-03-03")) || history[0].Rates["USD"] != 1.25 {
+		t.Errorf("first day is %s with rates %v", history[0].Date, history[0].Rates)
+	}
+	if !history[1].Date.Equal(date("2025-03-04")) || history[1].Rates["USD"] != 1.3 || history[1].Rates["EUR"] != 1 {
+		t.Errorf("second day is %s with rates %v", history[1].Date, history[1].Rates)
+	}
+
+	// Recent ranges use the shorter feed
+	recent := time.Now().AddDate(0, 0, -7)
+	if _, err := ecb.History(context.Background(), "EUR", recent, time.Now()); err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if (*paths)[1] != "/eurofxref-hist-90d.xml" {
+		t.Errorf("requested %s for the last week", (*paths)[1])
+	}
+}
+
+func TestECBErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		feed   string
+	}{
+		{"server error", http.StatusInternalServerError, ecbFeed},
+		{"malformed XML", http.StatusOK, `<gesmes:Envelope><Cube><Cube time="2025-03-04">`},
+		{"empty feed", http.StatusOK, `<Envelope><Cube></Cube></Envelope>`},
+		{"invalid date", http.StatusOK, `<Envelope><Cube><Cube time="4 March 2025"><Cube currency="USD" rate="1.3"/></Cube></Cube></Envelope>`},
+		{"invalid rate", http.StatusOK, `<Envelope><Cube><Cube time="2025-03-04"><Cube currency="USD" rate="n/a"/></Cube></Cube></Envelope>`},
+		{"currency not published", http.StatusOK, `<Envelope><Cube><Cube time="2025-03-04"><Cube currency="GBP" rate="0.8"/></Cube></Cube></Envelope>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := serve(t, tt.status, tt.feed)
+			if _, err := NewECB(server.URL, server.Client()).Latest(context.Background(), "USD"); err == nil {
+				t.Error("Latest succeeded")
+			}
+		})
+	}
+}
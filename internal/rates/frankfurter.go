@@ -0,0 +1,78 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// FrankfurterURL is the address of the public Frankfurter API
+const FrankfurterURL = "https://api.frankfurter.app"
+
+// Frankfurter fetches rates from the Frankfurter API, which republishes the
+// European Central Bank reference rates and needs no API key
+type Frankfurter struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewFrankfurter creates a Frankfurter provider for the API at baseURL
+func NewFrankfurter(baseURL string, client *http.Client) *Frankfurter {
+	return &Frankfurter{baseURL: baseURL, client: client}
+}
+
+// Name returns "frankfurter"
+func (p *Frankfurter) Name() string {
+	return "frankfurter"
+}
+
+// Latest returns the rates of the latest working day
+func (p *Frankfurter) Latest(ctx context.Context, base string) (Rates, error) {
+	var resp struct {
+		Base  string             `json:"base"`
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := getJSON(ctx, p.client, fmt.Sprintf("%s/latest?from=%s", p.baseURL, base), &resp); err != nil {
+		return Rates{}, err
+	}
+	if resp.Rates == nil {
+		return Rates{}, errors.New("response has no rates")
+	}
+
+	date, err := time.Parse("2006-01-02", resp.Date)
+	if err != nil {
+		return Rates{}, fmt.Errorf("invalid rate date %q: %w", resp.Date, err)
+	}
+	resp.Rates[base] = 1.0
+	return Rates{Base: base, Date: date, Rates: resp.Rates}, nil
+}
+
+// History returns the rates of each working day between from and to
+func (p *Frankfurter) History(ctx context.Context, base string, from, to time.Time) ([]Rates, error) {
+	var resp struct {
+		Base  string                        `json:"base"`
+		Rates map[string]map[string]float64 `json:"rates"`
+	}
+	url := fmt.Sprintf("%s/%s..%s?from=%s", p.baseURL, from.Format("2006-01-02"), to.Format("2006-01-02"), base)
+	if err := getJSON(ctx, p.client, url, &resp); err != nil {
+		return nil, err
+	}
+
+	history := make([]Rates, 0, len(resp.Rates))
+	for day, rates := range resp.Rates {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate date %q: %w", day, err)
+		}
+		rates[base] = 1.0
+		history = append(history, Rates{Base: base, Date: date, Rates: rates})
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Date.Before(history[j].Date)
+	})
+	return history, nil
+}
@@ -0,0 +1,91 @@
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFrankfurterLatest(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.RequestURI()
+		w.Write([]byte(`{"amount": 1.0, "base": "USD", "date": "2025-03-03", "rates": {"EUR": 0.8, "GBP": 0.64}}`))
+	}))
+	defer server.Close()
+
+	latest, err := NewFrankfurter(server.URL, server.Client()).Latest(context.Background(), "USD")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if path != "/latest?from=USD" {
+		t.Errorf("requested %s", path)
+	}
+	if latest.Base != "USD" || !latest.Date.Equal(date("2025-03-03")) {
+		t.Errorf("got %s rates of %s", latest.Base, latest.Date)
+	}
+	if latest.Rates["EUR"] != 0.8 || latest.Rates["USD"] != 1 {
+		t.Errorf("got rates %v", latest.Rates)
+	}
+}
+
+func TestFrankfurterHistory(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.RequestURI()
+		w.Write([]byte(`{"base": "EUR", "rates": {"2025-03-04": {"USD": 1.3}, "2025-03-03": {"USD": 1.25}}}`))
+	}))
+	defer server.Close()
+
+	history, err := NewFrankfurter(server.URL, server.Client()).History(context.Background(), "EUR", date("2025-03-01"), date("2025-03-04"))
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if path != "/2025-03-01..2025-03-04?from=EUR" {
+		t.Errorf("requested %s", path)
+	}
+
+	// Days are returned oldest first, whatever the order of the response
+	if len(history) != 2 {
+		t.Fatalf("got %d days, want 2", len(history))
+	}
+	for i, want := range []struct {
+		date string
+		usd  float64
+	}{{"2025-03-03", 1.25}, {"2025-03-04", 1.3}} {
+		if !history[i].Date.Equal(date(want.date)) || history[i].Rates["USD"] != want.usd || history[i].Rates["EUR"] != 1 {
+			t.Errorf("day %d is %s with rates %v, want %s with USD %v", i, history[i].Date, history[i].Rates, want.date, want.usd)
+		}
+	}
+}
+
+func TestFrankfurterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusBadGateway, ""},
+		{"not found", http.StatusNotFound, `{"message": "not found"}`},
+		{"malformed JSON", http.StatusOK, `{"base": "EUR", "rates": {"2025-03-03": `},
+		{"wrong types", http.StatusOK, `{"base": "EUR", "date": "2025-03-03", "rates": {"USD": "1.25"}}`},
+		{"no rates", http.StatusOK, `{"base": "EUR", "date": "2025-03-03"}`},
+		{"invalid date", http.StatusOK, `{"base": "EUR", "date": "03/03/2025", "rates": {"USD": 1.25}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := serve(t, tt.status, tt.body)
+			if _, err := NewFrankfurter(server.URL, server.Client()).Latest(context.Background(), "EUR"); err == nil {
+				t.Error("Latest succeeded")
+			}
+		})
+	}
+
+	t.Run("invalid history date", func(t *testing.T) {
+		server, _ := serve(t, http.StatusOK, `{"base": "EUR", "rates": {"2025-3-3": {"USD": 1.25}}}`)
+		if _, err := NewFrankfurter(server.URL, server.Client()).History(context.Background(), "EUR", date("2025-03-01"), date("2025-03-04")); err == nil {
+			t.Error("History succeeded")
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// OpenExchangeRatesURL is the address of the Open Exchange Rates API
+const OpenExchangeRatesURL = "https://openexchangerates.org/api"
+
+// OpenExchangeRates fetches rates from Open Exchange Rates. Rates are always
+// requested against USD, the only base of the free plan, and converted to
+// other bases locally.
+type OpenExchangeRates struct {
+	baseURL string
+	appID   string
+	client  *http.Client
+}
+
+// NewOpenExchangeRates creates an Open Exchange Rates provider for the API at
+// baseURL, authenticating with appID
+func NewOpenExchangeRates(baseURL, appID string, client *http.Client) *OpenExchangeRates {
+	return &OpenExchangeRates{baseURL: baseURL, appID: appID, client: client}
+}
+
+// Name returns "openexchangerates"
+func (p *OpenExchangeRates) Name() string {
+	return "openexchangerates"
+}
+
+// Latest returns the latest hourly rates
+func (p *OpenExchangeRates) Latest(ctx context.Context, base string) (Rates, error) {
+	latest, err := p.fetch(ctx, "latest.json")
+	if err != nil {
+		return Rates{}, err
+	}
+	return latest.Rebase(base)
+}
+
+// History returns the end of day rates of each day between from and to,
+// fetching one day per request
+func (p *OpenExchangeRates) History(ctx context.Context, base string, from, to time.Time) ([]Rates, error) {
+	var history []Rates
+	for day := from; inRange(day, from, to); day = day.AddDate(0, 0, 1) {
+		rates, err := p.fetch(ctx, "historical/"+day.Format("2006-01-02")+".json")
+		if err != nil {
+			return nil, err
+		}
+		rebased, err := rates.Rebase(base)
+		if err != nil {
+			return nil, err
+		}
+		// The rates are dated by the day requested, not their timestamp
+		rebased.Date = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+		history = append(history, rebased)
+	}
+	return history, nil
+}
+
+// fetch requests one of the API's rate endpoints
+func (p *OpenExchangeRates) fetch(ctx context.Context, endpoint string) (Rates, error) {
+	var resp struct {
+		Timestamp int64              `json:"timestamp"`
+		Base      string             `json:"base"`
+		Rates     map[string]float64 `json:"rates"`
+	}
+	u := fmt.Sprintf("%s/%s?app_id=%s", p.baseURL, endpoint, url.QueryEscape(p.appID))
+	if err := getJSON(ctx, p.client, u, &resp); err != nil {
+		return Rates{}, err
+	}
+	if resp.Rates == nil {
+		return Rates{}, errors.New("response has no rates")
+	}
+
+	published := time.Unix(resp.Timestamp, 0).UTC()
+	date := time.Date(published.Year(), published.Month(), published.Day(), 0, 0, 0, 0, time.UTC)
+	resp.Rates[resp.Base] = 1.0
+	return Rates{Base: resp.Base, Date: date, Rates: resp.Rates}, nil
+}
@@ -0,0 +1,88 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenExchangeRatesLatest(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RequestURI()
+		// 2025-03-04T15:00:00Z
+		w.Write([]byte(`{"timestamp": 1741100400, "base": "USD", "rates": {"EUR": 0.8, "GBP": 0.64}}`))
+	}))
+	defer server.Close()
+
+	latest, err := NewOpenExchangeRates(server.URL, "a&b", server.Client()).Latest(context.Background(), "EUR")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if query != "/latest.json?app_id=a%26b" {
+		t.Errorf("requested %s", query)
+	}
+	if latest.Base != "EUR" || !latest.Date.Equal(date("2025-03-04")) {
+		t.Errorf("got %s rates of %s", latest.Base, latest.Date)
+	}
+	if !near(latest.Rates["USD"], 1.25) || !near(latest.Rates["GBP"], 0.8) {
+		t.Errorf("got rates %v", latest.Rates)
+	}
+}
+
+func TestOpenExchangeRatesHistory(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		// End of day rates are timestamped just before midnight, or on the
+		// next day
+		fmt.Fprintf(w, `{"timestamp": %d, "base": "USD", "rates": {"EUR": 0.8}}`, date("2025-03-05").Unix()+60)
+	}))
+	defer server.Close()
+
+	history, err := NewOpenExchangeRates(server.URL, "id", server.Client()).History(context.Background(), "USD", date("2025-03-02"), date("2025-03-04"))
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+
+	want := []string{"2025-03-02", "2025-03-03", "2025-03-04"}
+	if len(paths) != len(want) || len(history) != len(want) {
+		t.Fatalf("requested %v and got %d days, want one of each per day", paths, len(history))
+	}
+	for i, day := range want {
+		if paths[i] != "/historical/"+day+".json" {
+			t.Errorf("request %d was for %s", i, paths[i])
+		}
+		// Days are dated as requested, not by their timestamp
+		if !history[i].Date.Equal(date(day)) || history[i].Rates["EUR"] != 0.8 {
+			t.Errorf("day %d is %s with rates %v, want %s", i, history[i].Date, history[i].Rates, day)
+		}
+	}
+}
+
+func TestOpenExchangeRatesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"invalid app ID", http.StatusUnauthorized, `{"error": true, "status": 401, "message": "invalid_app_id"}`},
+		{"malformed JSON", http.StatusOK, `{"timestamp": 1741100400, "base": "USD", "rates": {"EUR": 0.8`},
+		{"no rates", http.StatusOK, `{"timestamp": 1741100400, "base": "USD"}`},
+		{"currency not published", http.StatusOK, `{"timestamp": 1741100400, "base": "USD", "rates": {"GBP": 0.64}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := serve(t, tt.status, tt.body)
+			provider := NewOpenExchangeRates(server.URL, "id", server.Client())
+			if _, err := provider.Latest(context.Background(), "EUR"); err == nil {
+				t.Error("Latest succeeded")
+			}
+			if _, err := provider.History(context.Background(), "EUR", date("2025-03-03"), date("2025-03-04")); err == nil {
+				t.Error("History succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package rates fetches currency exchange rates from external providers.
+// Providers can be chained so that a provider that fails or is unavailable
+// falls back to the next one.
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds every request made by the HTTP providers
+const DefaultTimeout = 10 * time.Second
+
+// Rates are the exchange rates published for one day, as the amount of each
+// currency one unit of Base buys
+type Rates struct {
+	Base  string
+	Date  time.Time
+	Rates map[string]float64
+}
+
+// Provider fetches exchange rates
+type Provider interface {
+	// Name identifies the provider in logs
+	Name() string
+	// Latest returns the most recent rates against base
+	Latest(ctx context.Context, base string) (Rates, error)
+	// History returns the daily rates against base between from and to,
+	// inclusive, oldest first. Days without published rates are omitted.
+	History(ctx context.Context, base string, from, to time.Time) ([]Rates, error)
+}
+
+// Rebase expresses the rates against base instead of r.Base
+func (r Rates) Rebase(base string) (Rates, error) {
+	if r.Base == base {
+		return r, nil
+	}
+	pivot, ok := r.Rates[base]
+	if !ok || pivot == 0 {
+		return Rates{}, fmt.Errorf("no %s rate to convert %s rates with", base, r.Base)
+	}
+
+	rebased := make(map[string]float64, len(r.Rates)+1)
+	for currency, rate := range r.Rates {
+		rebased[currency] = rate / pivot
+	}
+	rebased[r.Base] = 1 / pivot
+	rebased[base] = 1.0
+	return Rates{Base: base, Date: r.Date, Rates: rebased}, nil
+}
+
+// Chain tries each provider in turn and returns the first successful result
+type Chain []Provider
+
+// Name returns the names of the chained providers
+func (c Chain) Name() string {
+	names := make([]string, len(c))
+	for i, provider := range c {
+		names[i] = provider.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Latest returns the latest rates from the first provider that has them
+func (c Chain) Latest(ctx context.Context, base string) (Rates, error) {
+	var errs []error
+	for _, provider := range c {
+		latest, err := provider.Latest(ctx, base)
+		if err == nil {
+			return latest, nil
+		}
+		log.Printf("Exchange rate provider %s failed, trying the next one: %v", provider.Name(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+	return Rates{}, fmt.Errorf("no exchange rate provider succeeded: %w", errors.Join(errs...))
+}
+
+// History returns the rate history from the first provider that has it
+func (c Chain) History(ctx context.Context, base string, from, to time.Time) ([]Rates, error) {
+	var errs []error
+	for _, provider := range c {
+		history, err := provider.History(ctx, base, from, to)
+		if err == nil {
+			return history, nil
+		}
+		log.Printf("Exchange rate provider %s failed, trying the next one: %v", provider.Name(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+	return nil, fmt.Errorf("no exchange rate provider succeeded: %w", errors.Join(errs...))
+}
+
+// Options configures the providers built by New
+type Options struct {
+	// OpenExchangeRatesAppID is the app ID of the openexchangerates provider
+	OpenExchangeRatesAppID string
+	// FixturePath is the rates file served by the static provider
+	FixturePath string
+	// Timeout bounds each HTTP request; zero means DefaultTimeout
+	Timeout time.Duration
+}
+
+// New builds a chain of the named providers, tried in the given order. Known
+// names are "frankfurter", "ecb", "openexchangerates" and "static".
+func New(names []string, opts Options) (Chain, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	var chain Chain
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+			continue
+		case "frankfurter":
+			chain = append(chain, NewFrankfurter(FrankfurterURL, client))
+		case "ecb":
+			chain = append(chain, NewECB(ECBURL, client))
+		case "openexchangerates":
+			if opts.OpenExchangeRatesAppID == "" {
+				return nil, errors.New("the openexchangerates provider needs an app ID")
+			}
+			chain = append(chain, NewOpenExchangeRates(OpenExchangeRatesURL, opts.OpenExchangeRatesAppID, client))
+		case "static":
+			if opts.FixturePath == "" {
+				return nil, errors.New("the static provider needs a fixture file")
+			}
+			chain = append(chain, NewStatic(opts.FixturePath))
+		default:
+			return nil, fmt.Errorf("unknown exchange rate provider %q", name)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no exchange rate provider configured")
+	}
+	return chain, nil
+}
+
+// get fetches url and returns the response body, which the caller closes
+func get(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	body, err := get(ctx, client, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return nil
+}
+
+// inRange reports whether day falls between from and to, inclusive, by date
+func inRange(day, from, to time.Time) bool {
+	d := day.Format("2006-01-02")
+	return d >= from.Format("2006-01-02") && d <= to.Format("2006-01-02")
+}
@@ -0,0 +1,164 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve starts a test server answering every request with status and body,
+// and counting the requests it receives
+func serve(t *testing.T, status int, body string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := new(int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRebase(t *testing.T) {
+	eur := Rates{Base: "EUR", Date: date("2025-03-03"), Rates: map[string]float64{"EUR": 1, "USD": 1.25, "GBP": 0.8}}
+
+	usd, err := eur.Rebase("USD")
+	if err != nil {
+		t.Fatalf("Rebase: %v", err)
+	}
+	want := map[string]float64{"USD": 1, "EUR": 0.8, "GBP": 0.64}
+	for currency, rate := range want {
+		if !near(usd.Rates[currency], rate) {
+			t.Errorf("USD/%s = %v, want %v", currency, usd.Rates[currency], rate)
+		}
+	}
+	if usd.Base != "USD" || !usd.Date.Equal(eur.Date) {
+		t.Errorf("rebased to %s on %s", usd.Base, usd.Date)
+	}
+
+	// Rebasing back gives the original rates
+	back, err := usd.Rebase("EUR")
+	if err != nil {
+		t.Fatalf("Rebase: %v", err)
+	}
+	for currency, rate := range eur.Rates {
+		if !near(back.Rates[currency], rate) {
+			t.Errorf("EUR/%s = %v after a round trip, want %v", currency, back.Rates[currency], rate)
+		}
+	}
+
+	if _, err := eur.Rebase("JPY"); err == nil {
+		t.Error("rebasing to a currency without a rate succeeded")
+	}
+}
+
+func TestChainFallsBack(t *testing.T) {
+	failing, failingRequests := serve(t, http.StatusServiceUnavailable, "")
+	malformed, malformedRequests := serve(t, http.StatusOK, `{"base": "EUR", "rates": `)
+	working, _ := serve(t, http.StatusOK, `{"base": "EUR", "date": "2025-03-03", "rates": {"USD": 1.25}}`)
+
+	chain := Chain{
+		NewFrankfurter(failing.URL, failing.Client()),
+		NewFrankfurter(malformed.URL, malformed.Client()),
+		NewFrankfurter(working.URL, working.Client()),
+	}
+	latest, err := chain.Latest(context.Background(), "EUR")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if latest.Rates["USD"] != 1.25 {
+		t.Errorf("USD rate %v, want the secondary provider's 1.25", latest.Rates["USD"])
+	}
+	if *failingRequests != 1 || *malformedRequests != 1 {
+		t.Errorf("providers tried %d and %d times, want once each", *failingRequests, *malformedRequests)
+	}
+}
+
+func TestChainStopsAtFirstSuccess(t *testing.T) {
+	primary, _ := serve(t, http.StatusOK, `{"base": "EUR", "date": "2025-03-03", "rates": {"USD": 1.1}}`)
+	secondary, secondaryRequests := serve(t, http.StatusOK, `{"base": "EUR", "date": "2025-03-03", "rates": {"USD": 1.25}}`)
+
+	chain := Chain{NewFrankfurter(primary.URL, primary.Client()), NewFrankfurter(secondary.URL, secondary.Client())}
+	latest, err := chain.Latest(context.Background(), "EUR")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if latest.Rates["USD"] != 1.1 || *secondaryRequests != 0 {
+		t.Errorf("got USD rate %v after %d requests to the secondary provider", latest.Rates["USD"], *secondaryRequests)
+	}
+}
+
+func TestChainFails(t *testing.T) {
+	primary, _ := serve(t, http.StatusInternalServerError, "")
+	secondary, _ := serve(t, http.StatusOK, "not json")
+
+	chain := Chain{NewFrankfurter(primary.URL, primary.Client()), NewECB(secondary.URL, secondary.Client())}
+	if chain.Name() != "frankfurter,ecb" {
+		t.Errorf("chain named %q", chain.Name())
+	}
+
+	_, err := chain.History(context.Background(), "EUR", date("2025-03-03"), date("2025-03-04"))
+	if err == nil {
+		t.Fatal("History succeeded with every provider failing")
+	}
+	for _, want := range []string{"frankfurter: API returned status 500", "ecb: failed to decode ECB feed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestChainPassesCancellation(t *testing.T) {
+	server, _ := serve(t, http.StatusOK, `{"base": "EUR", "date": "2025-03-03", "rates": {"USD": 1.25}}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Chain{NewFrankfurter(server.URL, server.Client())}.Latest(ctx, "EUR")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Latest with a cancelled context returned %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		names   []string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{names: []string{"frankfurter", " ECB "}, want: "frankfurter,ecb"},
+		{names: []string{"openexchangerates", "static"}, opts: Options{OpenExchangeRatesAppID: "id", FixturePath: "rates.json"}, want: "openexchangerates,static"},
+		{names: []string{"openexchangerates"}, wantErr: true},
+		{names: []string{"static"}, wantErr: true},
+		{names: []string{"fixer"}, wantErr: true},
+		{names: []string{"", " "}, wantErr: true},
+	}
+	for _, tt := range tests {
+		chain, err := New(tt.names, tt.opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%q) error = %v, want error %v", tt.names, err, tt.wantErr)
+			continue
+		}
+		if err == nil && chain.Name() != tt.want {
+			t.Errorf("New(%q) = %s, want %s", tt.names, chain.Name(), tt.want)
+		}
+	}
+}
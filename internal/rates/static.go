@@ -0,0 +1,97 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Static serves rates from a JSON fixture file, for offline development and
+// tests. The file holds the rates of each day against one base:
+//
+//	{"base": "USD", "rates": {"2025-01-02": {"EUR": 0.97, "GBP": 0.80}}}
+//
+// The file is read on every call, so it can be edited while the server runs.
+type Static struct {
+	path string
+}
+
+// NewStatic creates a provider serving the rates in the file at path
+func NewStatic(path string) *Static {
+	return &Static{path: path}
+}
+
+// Name returns "static"
+func (p *Static) Name() string {
+	return "static"
+}
+
+// Latest returns the rates of the latest day in the file
+func (p *Static) Latest(ctx context.Context, base string) (Rates, error) {
+	days, err := p.load()
+	if err != nil {
+		return Rates{}, err
+	}
+	if len(days) == 0 {
+		return Rates{}, errors.New("fixture has no rates")
+	}
+	return days[len(days)-1].Rebase(base)
+}
+
+// History returns the rates of the days in the file between from and to
+func (p *Static) History(ctx context.Context, base string, from, to time.Time) ([]Rates, error) {
+	days, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var history []Rates
+	for _, day := range days {
+		if !inRange(day.Date, from, to) {
+			continue
+		}
+		rebased, err := day.Rebase(base)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, rebased)
+	}
+	return history, nil
+}
+
+// load reads the fixture file, oldest day first
+func (p *Static) load() ([]Rates, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates fixture: %w", err)
+	}
+
+	var fixture struct {
+		Base  string                        `json:"base"`
+		Rates map[string]map[string]float64 `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode rates fixture: %w", err)
+	}
+	if fixture.Base == "" {
+		return nil, errors.New("rates fixture has no base currency")
+	}
+
+	days := make([]Rates, 0, len(fixture.Rates))
+	for day, rates := range fixture.Rates {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate date %q: %w", day, err)
+		}
+		rates[fixture.Base] = 1.0
+		days = append(days, Rates{Base: fixture.Base, Date: date, Rates: rates})
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days, nil
+}
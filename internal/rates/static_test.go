@@ -0,0 +1,67 @@
+package rates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFixture(t *testing.T, data string) *Static {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return NewStatic(path)
+}
+
+func TestStatic(t *testing.T) {
+	static := writeFixture(t, `{"base": "USD", "rates": {
+		"2025-03-04": {"EUR": 0.8},
+		"2025-03-02": {"EUR": 0.9},
+		"2025-03-03": {"EUR": 0.85}
+	}}`)
+
+	latest, err := static.Latest(context.Background(), "EUR")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if !latest.Date.Equal(date("2025-03-04")) || !near(latest.Rates["USD"], 1.25) {
+		t.Errorf("latest rates are %v of %s", latest.Rates, latest.Date)
+	}
+
+	history, err := static.History(context.Background(), "USD", date("2025-03-03"), date("2025-03-05"))
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 || !history[0].Date.Equal(date("2025-03-03")) || !history[1].Date.Equal(date("2025-03-04")) {
+		t.Fatalf("got history %v", history)
+	}
+	if history[0].Rates["EUR"] != 0.85 || history[0].Rates["USD"] != 1 {
+		t.Errorf("got rates %v", history[0].Rates)
+	}
+}
+
+func TestStaticErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{"malformed JSON", `{"base": "USD", "rates": {`},
+		{"no base", `{"rates": {"2025-03-04": {"EUR": 0.8}}}`},
+		{"invalid date", `{"base": "USD", "rates": {"2025-13-04": {"EUR": 0.8}}}`},
+		{"no rates", `{"base": "USD", "rates": {}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := writeFixture(t, tt.fixture).Latest(context.Background(), "USD"); err == nil {
+				t.Error("Latest succeeded")
+			}
+		})
+	}
+
+	if _, err := NewStatic(filepath.Join(t.TempDir(), "missing.json")).History(context.Background(), "USD", date("2025-03-03"), date("2025-03-04")); err == nil {
+		t.Error("History succeeded without a fixture file")
+	}
+}
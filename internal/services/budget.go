@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/email"
+	"worklio-api/internal/money"
+	"worklio-api/internal/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// Project budgets
+const (
+	BudgetHours  = "hours"
+	BudgetAmount = "amount"
+)
+
+// NotificationBudgetAlert is the type of the notification created when a
+// project's budget crosses one of its alert thresholds
+const NotificationBudgetAlert = "budget_alert"
+
+// DefaultBudgetAlertThresholds are the percentages of a project's budgets at
+// which the user is alerted unless the project sets its own
+var DefaultBudgetAlertThresholds = []int32{75, 90, 100}
+
+// BudgetMailer emails budget alerts. It is satisfied by *email.Service.
+type BudgetMailer interface {
+	SendBudgetAlert(ctx context.Context, recipientEmail, recipientName string, data email.BudgetAlertData) error
+}
+
+// BudgetDay is the time recorded against a project on one day
+type BudgetDay struct {
+	Date   time.Time
+	Hours  decimal.Decimal
+	Amount decimal.Decimal
+}
+
+// BudgetReport is how much of its budgets a project has used, day by day.
+// Hours count all time recorded against the project, amounts only billable
+// time, in the client's currency at the rate each entry was recorded at.
+type BudgetReport struct {
+	Project  db.Project
+	Client   db.GetClientByIDRow
+	Currency string
+	// BudgetHours and BudgetAmount are nil when the project has no such
+	// budget
+	BudgetHours  *decimal.Decimal
+	BudgetAmount *decimal.Decimal
+	UsedHours    decimal.Decimal
+	UsedAmount   decimal.Decimal
+	Days         []BudgetDay
+}
+
+// BudgetService reports on project budgets and alerts the user when time
+// recorded against a project crosses one of its alert thresholds
+type BudgetService struct {
+	queries *db.Queries
+	// mailer is nil when email is not configured, in which case alerts are
+	// only shown in the app
+	mailer BudgetMailer
+}
+
+// NewBudgetService creates a new budget service. mailer may be nil.
+func NewBudgetService(queries *db.Queries, mailer BudgetMailer) *BudgetService {
+	return &BudgetService{queries: queries, mailer: mailer}
+}
+
+// Report returns how much of its budgets the user's project has used, or
+// ErrProjectNotFound
+func (s *BudgetService) Report(ctx context.Context, userID, projectID int32) (BudgetReport, error) {
+	project, err := s.queries.GetProjectByID(ctx, db.GetProjectByIDParams{
+		ID:     projectID,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows {
+		return BudgetReport{}, ErrProjectNotFound
+	}
+	if err != nil {
+		return BudgetReport{}, fmt.Errorf("failed to fetch project: %w", err)
+	}
+	return s.report(ctx, project)
+}
+
+func (s *BudgetService) report(ctx context.Context, project db.Project) (BudgetReport, error) {
+	client, err := s.queries.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     project.ClientID,
+		UserID: project.UserID,
+	})
+	if err != nil {
+		return BudgetReport{}, fmt.Errorf("failed to fetch client: %w", err)
+	}
+
+	usage, err := s.queries.GetProjectDailyUsage(ctx, sql.NullInt32{Int32: project.ID, Valid: true})
+	if err != nil {
+		return BudgetReport{}, fmt.Errorf("failed to fetch project usage: %w", err)
+	}
+
+	report := BudgetReport{
+		Project:      project,
+		Client:       client,
+		Currency:     client.Currency,
+		BudgetHours:  nullDecimal(project.BudgetHours),
+		BudgetAmount: nullDecimal(project.BudgetAmount),
+		Days:         make([]BudgetDay, len(usage)),
+	}
+	for i, day := range usage {
+		report.Days[i] = BudgetDay{
+			Date:   day.Date,
+			Hours:  money.Parse(day.Hours),
+			Amount: money.Round(money.Parse(day.Amount), client.Currency),
+		}
+		report.UsedHours = report.UsedHours.Add(report.Days[i].Hours)
+		report.UsedAmount = report.UsedAmount.Add(report.Days[i].Amount)
+	}
+	return report, nil
+}
+
+// UsedPercent returns the percentage of budget that used represents, or nil
+// when there is no budget
+func UsedPercent(used decimal.Decimal, budget *decimal.Decimal) *decimal.Decimal {
+	if budget == nil || !budget.IsPositive() {
+		return nil
+	}
+	percent := used.Mul(decimal.NewFromInt(100)).Div(*budget)
+	return &percent
+}
+
+// CheckProject alerts the user, in the app and by email, when the project's
+// budgets have crossed an alert threshold since they were last checked. It is
+// called after time recorded against the project changes, or its budgets do,
+// and does nothing when projectID is null. Only the highest threshold newly
+// crossed is alerted; the ones below it are recorded as alerted too.
+func (s *BudgetService) CheckProject(ctx context.Context, userID int32, projectID sql.NullInt32) error {
+	if !projectID.Valid {
+		return nil
+	}
+
+	project, err := s.queries.GetProjectByID(ctx, db.GetProjectByIDParams{
+		ID:     projectID.Int32,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch project: %w", err)
+	}
+
+	report, err := s.report(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkBudget(ctx, report, BudgetHours, report.UsedHours, report.BudgetHours); err != nil {
+		return err
+	}
+	return s.checkBudget(ctx, report, BudgetAmount, report.UsedAmount, report.BudgetAmount)
+}
+
+// checkBudget records the thresholds one of the project's budgets has
+// crossed, clears the ones it no longer reaches and alerts the highest newly
+// crossed one
+func (s *BudgetService) checkBudget(ctx context.Context, report BudgetReport, budget string, used decimal.Decimal, limit *decimal.Decimal) error {
+	// Without a budget no threshold is reached
+	var reached int32
+	percent := UsedPercent(used, limit)
+	if percent != nil {
+		reached = math.MaxInt32
+		if floor := percent.Floor(); floor.LessThan(decimal.NewFromInt(math.MaxInt32)) {
+			reached = int32(floor.IntPart())
+		}
+	}
+
+	err := s.queries.DeleteProjectBudgetAlertsAbove(ctx, db.DeleteProjectBudgetAlertsAboveParams{
+		ProjectID: report.Project.ID,
+		Budget:    budget,
+		Threshold: reached,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear budget alerts: %w", err)
+	}
+
+	var crossed int32
+	for _, threshold := range report.Project.BudgetAlertThresholds {
+		if threshold > reached {
+			continue
+		}
+		_, err := s.queries.CreateProjectBudgetAlert(ctx, db.CreateProjectBudgetAlertParams{
+			ProjectID: report.Project.ID,
+			Budget:    budget,
+			Threshold: threshold,
+		})
+		if err == sql.ErrNoRows {
+			// Already alerted
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record budget alert: %w", err)
+		}
+		if threshold > crossed {
+			crossed = threshold
+		}
+	}
+	if crossed == 0 {
+		return nil
+	}
+
+	return s.alert(ctx, report, budget, crossed)
+}
+
+// alert notifies the user in the app and, when email is configured, by email
+// that one of the project's budgets crossed threshold
+func (s *BudgetService) alert(ctx context.Context, report BudgetReport, budget string, threshold int32) error {
+	var used, limit string
+	if budget == BudgetHours {
+		used = report.UsedHours.StringFixed(2) + " hours"
+		limit = report.BudgetHours.StringFixed(2) + " hours"
+	} else {
+		used = utils.FormatCurrency(report.UsedAmount, report.Currency)
+		limit = utils.FormatCurrency(*report.BudgetAmount, report.Currency)
+	}
+
+	_, err := s.queries.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID:    report.Project.UserID,
+		Type:      NotificationBudgetAlert,
+		Title:     fmt.Sprintf("%s has used %d%% of its budget", report.Project.Name, threshold),
+		Message:   fmt.Sprintf("%s used of the %s budget of %s for %s.", used, limit, report.Project.Name, report.Client.Name),
+		ProjectID: sql.NullInt32{Int32: report.Project.ID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	if s.mailer == nil {
+		return nil
+	}
+
+	user, err := s.queries.GetUserByID(ctx, report.Project.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	// The alert was already shown in the app, so a failed email is only logged
+	err = s.mailer.SendBudgetAlert(ctx, user.Email, user.Name, email.BudgetAlertData{
+		ProjectID:   report.Project.ID,
+		ProjectName: report.Project.Name,
+		ClientName:  report.Client.Name,
+		Threshold:   threshold,
+		Used:        used,
+		Budget:      limit,
+	})
+	if err != nil {
+		log.Printf("Failed to email budget alert for project %d: %v", report.Project.ID, err)
+	}
+	return nil
+}
+
+// nullDecimal parses an optional decimal column, or returns nil if it is null
+func nullDecimal(s sql.NullString) *decimal.Decimal {
+	if !s.Valid {
+		return nil
+	}
+	d := money.Parse(s.String)
+	return &d
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrInvoiceNotFound is returned when a credit note refers to an invoice
+	// the user does not own
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	// ErrInvoiceNotIssued is returned when crediting a draft invoice, which
+	// can still be edited instead
+	ErrInvoiceNotIssued = errors.New("draft invoices cannot be credited")
+	// ErrNothingToCredit is returned when a credit note would have no positive
+	// total
+	ErrNothingToCredit = errors.New("credit note total must be greater than zero")
+	// ErrCreditExceedsInvoice is returned when a credit note is worth more
+	// than what is left of the invoice after earlier credit notes
+	ErrCreditExceedsInvoice = errors.New("credit note exceeds the invoice total not yet credited")
+	// ErrCreditNoteNumberTaken is returned when a credit note is given a
+	// number another of the user's credit notes already has
+	ErrCreditNoteNumberTaken = errors.New("credit note number already exists")
+)
+
+// NewCreditNote describes a credit note to issue against an invoice. Full
+// credits everything the invoice bills; otherwise only LineItems are
+// credited. When CreditNoteNumber is empty the next number is allocated from
+// the user's credit note numbering pattern.
+type NewCreditNote struct {
+	CreditNoteNumber string
+	IssueDate        time.Time
+	Reason           string
+	Full             bool
+	LineItems        []models.InvoiceLineItemRequest
+}
+
+// CreditNoteService issues credit notes that cancel all or part of an issued
+// invoice. Credit notes cannot be changed once issued; a mistake is corrected
+// by issuing another document.
+type CreditNoteService struct {
+	queries  *db.Queries
+	invoices *InvoiceService
+}
+
+// NewCreditNoteService creates a new credit note service
+func NewCreditNoteService(queries *db.Queries, invoices *InvoiceService) *CreditNoteService {
+	return &CreditNoteService{queries: queries, invoices: invoices}
+}
+
+// CreateCreditNote issues a credit note against one of the user's invoices.
+// The credited amounts are taxed like the invoice, and the invoice status is
+// updated in the same transaction since the credit note lowers what is owed.
+// The invoice is locked like for payments, so that concurrent credit notes
+// cannot together credit more than the invoice total.
+func (s *CreditNoteService) CreateCreditNote(ctx context.Context, userID, invoiceID int32, creditNote NewCreditNote) (db.CreditNote, error) {
+	var created db.CreditNote
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		invoice, err := lockInvoice(ctx, q, userID, invoiceID)
+		if err != nil {
+			return err
+		}
+		if invoice.Status == "draft" {
+			return ErrInvoiceNotIssued
+		}
+
+		contents, err := billing.LoadContents(ctx, q, invoice.ID)
+		if err != nil {
+			return err
+		}
+
+		requests := creditNote.LineItems
+		if creditNote.Full {
+			requests = invoiceLineItemRequests(contents)
+		}
+		lineItems := creditNoteLineItems(requests)
+
+		totals := billing.Summarize(billing.CreditNoteLines(lineItems), contents.Taxes, invoice.ReverseCharge, contents.Currency)
+		if !totals.Total.IsPositive() {
+			return ErrNothingToCredit
+		}
+		invoiceTotals := billing.IssuedTotals(invoice, contents)
+		if totals.Total.GreaterThan(invoiceTotals.Net()) {
+			return ErrCreditExceedsInvoice
+		}
+
+		number := creditNote.CreditNoteNumber
+		if number == "" {
+			number, err = allocateNumber(ctx, q, documentCreditNote, userID, invoice.ClientID, creditNote.IssueDate)
+			if err != nil {
+				return fmt.Errorf("failed to allocate credit note number: %w", err)
+			}
+		}
+
+		created, err = q.CreateCreditNote(ctx, db.CreateCreditNoteParams{
+			UserID:            userID,
+			InvoiceID:         invoice.ID,
+			ClientID:          invoice.ClientID,
+			CreditNoteNumber:  number,
+			IssueDate:         creditNote.IssueDate,
+			Reason:            sql.NullString{String: creditNote.Reason, Valid: creditNote.Reason != ""},
+			Subtotal:          money.Format(totals.Subtotal, contents.Currency),
+			TaxAmount:         money.Format(totals.Tax, contents.Currency),
+			WithholdingAmount: money.Format(totals.Withholding, contents.Currency),
+			Total:             money.Format(totals.Total, contents.Currency),
+		})
+		if err != nil {
+			if isCreditNoteNumberTaken(err) {
+				return ErrCreditNoteNumberTaken
+			}
+			return err
+		}
+
+		for _, item := range lineItems {
+			_, err := q.CreateCreditNoteLineItem(ctx, db.CreateCreditNoteLineItemParams{
+				CreditNoteID: created.ID,
+				Description:  item.Description,
+				Quantity:     item.Quantity,
+				Unit:         item.Unit,
+				UnitPrice:    item.UnitPrice,
+				TaxRate:      item.TaxRate,
+				Position:     item.Position,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return billing.SyncInvoiceStatus(ctx, q, invoice)
+	})
+	return created, err
+}
+
+// PreviewCreditNoteNumber returns the number the next credit note issued on
+// issueDate to the client would be given, without allocating it
+func (s *CreditNoteService) PreviewCreditNoteNumber(ctx context.Context, userID, clientID int32, issueDate time.Time) (string, error) {
+	return previewNumber(ctx, s.queries, documentCreditNote, userID, clientID, issueDate)
+}
+
+// isCreditNoteNumberTaken reports whether err is the database rejecting a
+// credit note number that another of the user's credit notes already has
+func isCreditNoteNumberTaken(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Constraint == "credit_notes_user_credit_note_number_unique"
+}
+
+// invoiceLineItemRequests returns everything an invoice bills as line items:
+// its time entries as hours at the captured rate, followed by its line items
+func invoiceLineItemRequests(contents billing.Contents) []models.InvoiceLineItemRequest {
+	requests := make([]models.InvoiceLineItemRequest, 0, len(contents.TimeEntries)+len(contents.LineItems))
+	for _, entry := range contents.TimeEntries {
+		description := entry.Description.String
+		if description == "" {
+			description = "No description"
+		}
+
+		requests = append(requests, models.InvoiceLineItemRequest{
+			Description: fmt.Sprintf("%s (%s)", description, entry.Date.Format("2006-01-02")),
+			Quantity:    money.Parse(entry.Hours),
+			Unit:        "hour",
+			UnitPrice:   money.ParseNull(entry.HourlyRate),
+		})
+	}
+
+	for _, item := range contents.LineItems {
+		requests = append(requests, models.InvoiceLineItemRequest{
+			Description: item.Description,
+			Quantity:    money.Parse(item.Quantity),
+			Unit:        item.Unit,
+			UnitPrice:   money.Parse(item.UnitPrice),
+			TaxRate:     money.Parse(item.TaxRate),
+		})
+	}
+	return requests
+}
+
+// creditNoteLineItems converts requested line items into the rows stored for
+// a credit note, rounded as they will be stored so totals match the lines
+func creditNoteLineItems(requests []models.InvoiceLineItemRequest) []db.CreditNoteLineItem {
+	lineItems := make([]db.CreditNoteLineItem, len(requests))
+	for i, item := range requests {
+		unit := item.Unit
+		if unit == "" {
+			unit = "unit"
+		}
+
+		lineItems[i] = db.CreditNoteLineItem{
+			Description: strings.TrimSpace(item.Description),
+			Quantity:    item.Quantity.StringFixed(2),
+			Unit:        unit,
+			UnitPrice:   billing.FormatPrice(item.UnitPrice),
+			TaxRate:     item.TaxRate.StringFixed(2),
+			Position:    int32(i),
+		}
+	}
+	return lineItems
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/email"
+	"worklio-api/internal/pdf"
+	"worklio-api/internal/utils"
+)
+
+var (
+	// ErrInvoiceCancelled is returned when sending a cancelled invoice
+	ErrInvoiceCancelled = errors.New("cancelled invoices cannot be sent")
+	// ErrNoClientEmail is returned when the invoice's client has no email
+	// address to send it to
+	ErrNoClientEmail = errors.New("client has no email address")
+)
+
+// SendOptions customizes an invoice email
+type SendOptions struct {
+	Cc []string
+	// Message replaces the default email text when set
+	Message string
+}
+
+// DeliveryService emails invoices to their clients
+type DeliveryService struct {
+	queries  *db.Queries
+	invoices *InvoiceService
+	mailer   Mailer
+}
+
+// NewDeliveryService creates a new delivery service
+func NewDeliveryService(queries *db.Queries, invoices *InvoiceService, mailer Mailer) *DeliveryService {
+	return &DeliveryService{queries: queries, invoices: invoices, mailer: mailer}
+}
+
+// SendInvoice emails the invoice PDF to the client, moves a draft invoice to
+// sent and records the delivery. A draft is issued before it is rendered, so
+// that the email carries its number, in a transaction that is rolled back if
+// the email cannot be sent; the invoice then stays a draft and its number is
+// released. It returns sql.ErrNoRows when the user has no such invoice.
+func (s *DeliveryService) SendInvoice(ctx context.Context, userID, invoiceID int32, options SendOptions) (db.InvoiceDelivery, error) {
+	var delivery db.InvoiceDelivery
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		doc, err := billing.LoadDocument(ctx, q, userID, invoiceID)
+		if err != nil {
+			return err
+		}
+
+		if doc.Invoice.Status == "cancelled" {
+			return ErrInvoiceCancelled
+		}
+		if doc.Client.Email == "" {
+			return ErrNoClientEmail
+		}
+
+		if doc.Invoice.Status == "draft" {
+			if _, err := s.invoices.updateInvoiceStatus(ctx, q, userID, invoiceID, "sent"); err != nil {
+				return fmt.Errorf("failed to issue invoice: %w", err)
+			}
+			doc, err = billing.LoadDocument(ctx, q, userID, invoiceID)
+			if err != nil {
+				return err
+			}
+		}
+
+		pdfData, err := renderInvoicePDF(doc)
+		if err != nil {
+			return err
+		}
+
+		msg := email.InvoiceEmail(doc.Client.Email, email.InvoiceData{
+			ClientName:    doc.Client.Name,
+			SellerName:    doc.SellerName(),
+			InvoiceNumber: doc.Invoice.InvoiceNumber,
+			AmountDue:     utils.FormatCurrency(billing.IssuedTotals(doc.Invoice, doc.Contents).Balance, doc.Currency()),
+			DueDate:       doc.Invoice.DueDate.Format("Jan 2, 2006"),
+			Message:       options.Message,
+		})
+		msg.Cc = options.Cc
+		msg.ReplyTo = doc.Seller.Email
+		msg.MessageID = s.mailer.NewMessageID()
+		msg.Attachments = []email.Attachment{invoiceAttachment(doc, pdfData)}
+
+		// The delivery is recorded before sending so that nothing can fail
+		// between the email leaving and the transaction committing
+		message := strings.TrimSpace(options.Message)
+		delivery, err = q.CreateInvoiceDelivery(ctx, db.CreateInvoiceDeliveryParams{
+			InvoiceID: doc.Invoice.ID,
+			Recipient: msg.To,
+			Cc:        sql.NullString{String: strings.Join(options.Cc, ","), Valid: len(options.Cc) > 0},
+			Subject:   msg.Subject,
+			Message:   sql.NullString{String: message, Valid: message != ""},
+			MessageID: msg.MessageID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record invoice delivery: %w", err)
+		}
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			return fmt.Errorf("failed to send invoice email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.InvoiceDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// renderInvoicePDF renders the invoice PDF into memory
+func renderInvoicePDF(doc *billing.Document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pdf.RenderInvoice(doc).Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render invoice PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// invoiceAttachment returns the invoice PDF as an email attachment
+func invoiceAttachment(doc *billing.Document, pdfData []byte) email.Attachment {
+	return email.Attachment{
+		Filename:    doc.Invoice.InvoiceNumber + ".pdf",
+		ContentType: "application/pdf",
+		Data:        pdfData,
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/email"
+	"worklio-api/internal/utils"
+)
+
+// Mailer sends email messages. It is satisfied by *email.Service.
+type Mailer interface {
+	Send(ctx context.Context, msg email.Message) error
+	NewMessageID() string
+}
+
+// DunningService emails payment reminders for unpaid invoices according to
+// each user's reminder rules
+type DunningService struct {
+	queries *db.Queries
+	mailer  Mailer
+	now     func() time.Time
+}
+
+// NewDunningService creates a new dunning service
+func NewDunningService(queries *db.Queries, mailer Mailer) *DunningService {
+	return &DunningService{queries: queries, mailer: mailer, now: time.Now}
+}
+
+// SendDueReminders emails the reminders that have fallen due: for each unpaid
+// invoice, the latest enabled rule whose offset from the due date has been
+// reached, unless the client opted out or that reminder or a later one was
+// already sent. Reminders that failed or were missed are retried on the next
+// run. Each attempt is recorded in the invoice's reminder log. It returns the
+// number of reminders sent.
+func (s *DunningService) SendDueReminders(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	due, err := s.queries.GetDueReminders(ctx, today)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch due reminders: %w", err)
+	}
+
+	sent := 0
+	for _, reminder := range due {
+		if err := s.sendReminder(ctx, reminder); err != nil {
+			log.Printf("Failed to send reminder for invoice %d: %v", reminder.InvoiceID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// sendReminder emails a single reminder with the invoice PDF attached and
+// records the outcome
+func (s *DunningService) sendReminder(ctx context.Context, reminder db.GetDueRemindersRow) error {
+	doc, err := billing.LoadDocument(ctx, s.queries, reminder.UserID, reminder.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	pdfData, err := renderInvoicePDF(doc)
+	if err != nil {
+		return err
+	}
+
+	msg := email.PaymentReminder(doc.Client.Email, email.ReminderData{
+		ClientName:    doc.Client.Name,
+		SellerName:    doc.SellerName(),
+		InvoiceNumber: doc.Invoice.InvoiceNumber,
+		AmountDue:     utils.FormatCurrency(billing.IssuedTotals(doc.Invoice, doc.Contents).Balance, doc.Currency()),
+		DueDate:       doc.Invoice.DueDate.Format("Jan 2, 2006"),
+		OffsetDays:    int(reminder.OffsetDays),
+		Subject:       reminder.Subject.String,
+		Message:       reminder.Message.String,
+	})
+	msg.ReplyTo = doc.Seller.Email
+	msg.Attachments = []email.Attachment{invoiceAttachment(doc, pdfData)}
+
+	sendErr := s.mailer.Send(ctx, msg)
+
+	status := "sent"
+	var errorMessage sql.NullString
+	if sendErr != nil {
+		status = "failed"
+		errorMessage = sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+
+	_, err = s.queries.CreateInvoiceReminder(ctx, db.CreateInvoiceReminderParams{
+		InvoiceID:      reminder.InvoiceID,
+		ReminderRuleID: sql.NullInt32{Int32: reminder.ReminderRuleID, Valid: true},
+		OffsetDays:     reminder.OffsetDays,
+		Recipient:      msg.To,
+		Subject:        msg.Subject,
+		Status:         status,
+		Error:          errorMessage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record reminder: %w", err)
+	}
+	return sendErr
+}
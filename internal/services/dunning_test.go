@@ -0,0 +1,391 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"worklio-api/internal/db"
+	"worklio-api/internal/email"
+)
+
+// fakeDB answers sqlc queries by name with canned rows, and records the
+// arguments of every query it runs. Rows are sqlc row structs, whose fields
+// are in the order the queries select them.
+type fakeDB struct {
+	mu      sync.Mutex
+	results map[string]func(args []driver.NamedValue) []any
+	calls   map[string][][]driver.NamedValue
+}
+
+func (f *fakeDB) query(name string, args []driver.NamedValue) []any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[name] = append(f.calls[name], args)
+	if result, ok := f.results[name]; ok {
+		return result(args)
+	}
+	return nil
+}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("fakedb", fakeDriver{})
+}
+
+// openFakeDB returns queries backed by a fake database answering with results
+func openFakeDB(t *testing.T, results map[string]func(args []driver.NamedValue) []any) (*db.Queries, *fakeDB) {
+	t.Helper()
+	database, fake := openFakeSQL(t, results)
+	return db.New(database), fake
+}
+
+// openFakeSQL returns a fake database answering with results, for services
+// that run their queries in transactions
+func openFakeSQL(t *testing.T, results map[string]func(args []driver.NamedValue) []any) (*sql.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{results: results, calls: map[string][][]driver.NamedValue{}}
+	fakeDBsMu.Lock()
+	fakeDBs[t.Name()] = fake
+	fakeDBsMu.Unlock()
+
+	database, err := sql.Open("fakedb", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database, fake
+}
+
+// rows returns a result holding the given rows
+func rows(values ...any) func([]driver.NamedValue) []any {
+	return func([]driver.NamedValue) []any { return values }
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+	return fakeConn{fakeDBs[name]}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare is not supported")
+}
+
+func (c fakeConn) Close() error { return nil }
+
+// Begin starts a transaction that only pretends to isolate its queries
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	name := strings.Fields(strings.TrimPrefix(query, "-- name: "))[0]
+	return newFakeRows(c.db.query(name, args)), nil
+}
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	name := strings.Fields(strings.TrimPrefix(query, "-- name: "))[0]
+	c.db.query(name, args)
+	return driver.RowsAffected(1), nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+// newFakeRows flattens row structs into driver values, one column per field
+func newFakeRows(structs []any) *fakeRows {
+	r := &fakeRows{}
+	for _, s := range structs {
+		v := reflect.ValueOf(s)
+		row := make([]driver.Value, v.NumField())
+		for i := range row {
+			row[i] = driverValue(v.Field(i).Interface())
+		}
+		r.values = append(r.values, row)
+	}
+	if len(structs) > 0 {
+		for i := 0; i < reflect.TypeOf(structs[0]).NumField(); i++ {
+			r.columns = append(r.columns, reflect.TypeOf(structs[0]).Field(i).Name)
+		}
+	}
+	return r
+}
+
+func driverValue(field any) driver.Value {
+	switch v := field.(type) {
+	case driver.Valuer:
+		value, _ := v.Value()
+		return value
+	case int32:
+		return int64(v)
+	default:
+		return v
+	}
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// smtpServer is a fake SMTP server accepting every message, except for the
+// recipients it is told to reject
+type smtpServer struct {
+	listener net.Listener
+	reject   string
+
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func startSMTPServer(t *testing.T, reject string) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener, reject: reject}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) port() string {
+	return fmt.Sprint(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 localhost ESMTP")
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg = smtpMessage{from: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			to := strings.Trim(line[len("RCPT TO:"):], "<> ")
+			if to == s.reject {
+				reply("550 No such user")
+				continue
+			}
+			msg.to = append(msg.to, to)
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			msg.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpServer) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+// dunningFixtures returns the rows for one invoice, 7 days overdue, with a
+// reminder rule for 7 days after the due date
+func dunningFixtures(clientEmail string) map[string]func([]driver.NamedValue) []any {
+	issued := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	return map[string]func([]driver.NamedValue) []any{
+		"GetDueReminders": rows(db.GetDueRemindersRow{
+			InvoiceID:      42,
+			UserID:         1,
+			ReminderRuleID: 3,
+			OffsetDays:     7,
+			Subject:        sql.NullString{String: "Invoice {invoice_number} is overdue", Valid: true},
+		}),
+		"GetInvoiceByID": rows(db.Invoice{
+			ID:            42,
+			UserID:        1,
+			ClientID:      5,
+			InvoiceNumber: "INV-0042",
+			IssueDate:     issued,
+			DueDate:       issued.AddDate(0, 0, 30),
+			Status:        "overdue",
+			Currency:      sql.NullString{String: "EUR", Valid: true},
+			Total:         sql.NullString{String: "1200.00", Valid: true},
+		}),
+		"GetUserBillingProfile": rows(db.GetUserBillingProfileRow{
+			ID:    1,
+			Email: "jane@example.com",
+			Name:  "Jane Doe",
+		}),
+		"GetClientByID": rows(db.GetClientByIDRow{
+			ID:       5,
+			UserID:   1,
+			Name:     "Acme",
+			Email:    clientEmail,
+			Currency: "EUR",
+		}),
+		"GetInvoiceCurrency": rows(struct{ Currency string }{"EUR"}),
+		"GetInvoiceLineItems": rows(db.InvoiceLineItem{
+			ID:          1,
+			InvoiceID:   42,
+			Description: "Consulting",
+			Quantity:    "10",
+			Unit:        "hour",
+			UnitPrice:   "120",
+			TaxRate:     "0",
+		}),
+		"CreateInvoiceReminder": rows(db.InvoiceReminder{ID: 1}),
+	}
+}
+
+func TestSendDueReminders(t *testing.T) {
+	tests := []struct {
+		name       string
+		reject     string
+		wantSent   int
+		wantStatus string
+	}{
+		{name: "sent", wantSent: 1, wantStatus: "sent"},
+		{name: "rejected by the server", reject: "billing@acme.test", wantSent: 0, wantStatus: "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startSMTPServer(t, tt.reject)
+			mailer, err := email.NewService("127.0.0.1", server.port(), "", "", email.SecurityNone, "noreply@facturme.test", "FacturMe", "http://localhost")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			queries, fake := openFakeDB(t, dunningFixtures("billing@acme.test"))
+			service := NewDunningService(queries, mailer)
+			service.now = func() time.Time { return time.Date(2025, 4, 7, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60)) }
+
+			sent, err := service.SendDueReminders(context.Background())
+			if err != nil {
+				t.Fatalf("SendDueReminders: %v", err)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("sent %d reminders, want %d", sent, tt.wantSent)
+			}
+
+			// Reminders are due by the current date in UTC
+			due := fake.calls["GetDueReminders"]
+			if len(due) != 1 {
+				t.Fatalf("GetDueReminders called %d times", len(due))
+			}
+			if today := due[0][0].Value.(time.Time); !today.Equal(time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("reminders fetched for %s, want 2025-04-07", today)
+			}
+
+			messages := server.received()
+			if tt.wantSent == 1 {
+				if len(messages) != 1 {
+					t.Fatalf("server received %d messages, want 1", len(messages))
+				}
+				msg := messages[0]
+				if msg.from != "noreply@facturme.test" || len(msg.to) != 1 || msg.to[0] != "billing@acme.test" {
+					t.Errorf("message sent from %s to %v", msg.from, msg.to)
+				}
+				for _, want := range []string{"Subject: Invoice INV-0042 is overdue", "Reply-To: jane@example.com", "filename=INV-0042.pdf"} {
+					if !strings.Contains(msg.data, want) {
+						t.Errorf("message lacks %s", want)
+					}
+				}
+			} else if len(messages) != 0 {
+				t.Errorf("server received %d messages, want none", len(messages))
+			}
+
+			// Every attempt is recorded in the reminder log
+			recorded := fake.calls["CreateInvoiceReminder"]
+			if len(recorded) != 1 {
+				t.Fatalf("recorded %d reminders, want 1", len(recorded))
+			}
+			args := recorded[0]
+			if args[0].Value != int64(42) || args[1].Value != int64(3) || args[2].Value != int64(7) {
+				t.Errorf("recorded invoice, rule and offset %v, %v, %v", args[0].Value, args[1].Value, args[2].Value)
+			}
+			if args[3].Value != "billing@acme.test" || args[4].Value != "Invoice INV-0042 is overdue" {
+				t.Errorf("recorded recipient %v and subject %v", args[3].Value, args[4].Value)
+			}
+			if args[5].Value != tt.wantStatus {
+				t.Errorf("recorded status %v, want %s", args[5].Value, tt.wantStatus)
+			}
+			if failed := args[6].Value != nil; failed != (tt.wantStatus == "failed") {
+				t.Errorf("recorded error %v", args[6].Value)
+			}
+		})
+	}
+}
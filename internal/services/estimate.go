@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrEstimateNumberTaken is returned when an estimate is given a number
+	// another of the user's estimates already has
+	ErrEstimateNumberTaken = errors.New("estimate number already exists")
+	// ErrEstimateConverted is returned when changing an estimate that has
+	// already been converted into an invoice
+	ErrEstimateConverted = errors.New("estimate has already been converted into an invoice")
+	// ErrEstimateNotConvertible is returned when converting a declined or
+	// expired estimate
+	ErrEstimateNotConvertible = errors.New("declined and expired estimates cannot be converted")
+)
+
+// NewEstimate describes an estimate to create or the new contents of an
+// existing one. When EstimateNumber is empty the next number is allocated
+// from the user's estimate numbering pattern.
+type NewEstimate struct {
+	ClientID       int32
+	EstimateNumber string
+	IssueDate      time.Time
+	ValidUntil     time.Time
+	Status         string
+	Notes          string
+	LineItems      []models.InvoiceLineItemRequest
+}
+
+// EstimateConversion describes the invoice an estimate is converted into
+type EstimateConversion struct {
+	IssueDate time.Time
+	DueDate   time.Time
+	// TaxRateIDs selects the tax rates to apply; nil applies the user's
+	// default tax rates
+	TaxRateIDs []int32
+}
+
+// EstimateService manages estimates and converts accepted ones into
+// invoices through InvoiceService
+type EstimateService struct {
+	queries  *db.Queries
+	invoices *InvoiceService
+	now      func() time.Time
+}
+
+// NewEstimateService creates a new estimate service
+func NewEstimateService(queries *db.Queries, invoices *InvoiceService) *EstimateService {
+	return &EstimateService{queries: queries, invoices: invoices, now: time.Now}
+}
+
+// CreateEstimate creates an estimate with its line items in a single
+// transaction
+func (s *EstimateService) CreateEstimate(ctx context.Context, userID int32, estimate NewEstimate) (db.Estimate, error) {
+	var created db.Estimate
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		number := estimate.EstimateNumber
+		if number == "" {
+			var err error
+			number, err = allocateNumber(ctx, q, documentEstimate, userID, estimate.ClientID, estimate.IssueDate)
+			if err != nil {
+				return fmt.Errorf("failed to allocate estimate number: %w", err)
+			}
+		} else if err := checkClient(ctx, q, userID, estimate.ClientID); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = q.CreateEstimate(ctx, db.CreateEstimateParams{
+			UserID:         userID,
+			ClientID:       estimate.ClientID,
+			EstimateNumber: number,
+			IssueDate:      estimate.IssueDate,
+			ValidUntil:     estimate.ValidUntil,
+			Status:         estimate.Status,
+			Notes:          sql.NullString{String: estimate.Notes, Valid: estimate.Notes != ""},
+		})
+		if err != nil {
+			if isEstimateNumberTaken(err) {
+				return ErrEstimateNumberTaken
+			}
+			return err
+		}
+		return saveEstimateLineItems(ctx, q, created.ID, estimate.LineItems)
+	})
+	return created, err
+}
+
+// UpdateEstimate replaces the contents of an estimate that has not been
+// converted yet. It returns sql.ErrNoRows when the user has no such estimate.
+func (s *EstimateService) UpdateEstimate(ctx context.Context, userID, id int32, estimate NewEstimate) (db.Estimate, error) {
+	var updated db.Estimate
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		existing, err := q.GetEstimateByID(ctx, db.GetEstimateByIDParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if existing.InvoiceID.Valid {
+			return ErrEstimateConverted
+		}
+		if err := checkClient(ctx, q, userID, estimate.ClientID); err != nil {
+			return err
+		}
+
+		number := estimate.EstimateNumber
+		if number == "" {
+			number = existing.EstimateNumber
+		}
+
+		updated, err = q.UpdateEstimate(ctx, db.UpdateEstimateParams{
+			ID:             id,
+			UserID:         userID,
+			ClientID:       estimate.ClientID,
+			EstimateNumber: number,
+			IssueDate:      estimate.IssueDate,
+			ValidUntil:     estimate.ValidUntil,
+			Status:         estimate.Status,
+			Notes:          sql.NullString{String: estimate.Notes, Valid: estimate.Notes != ""},
+		})
+		if err != nil {
+			if isEstimateNumberTaken(err) {
+				return ErrEstimateNumberTaken
+			}
+			return err
+		}
+
+		if err := q.DeleteEstimateLineItems(ctx, id); err != nil {
+			return err
+		}
+		return saveEstimateLineItems(ctx, q, id, estimate.LineItems)
+	})
+	return updated, err
+}
+
+// UpdateEstimateStatus sets the status of an estimate that has not been
+// converted yet. It returns sql.ErrNoRows when the user has no such estimate.
+func (s *EstimateService) UpdateEstimateStatus(ctx context.Context, userID, id int32, status string) (db.Estimate, error) {
+	existing, err := s.queries.GetEstimateByID(ctx, db.GetEstimateByIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return db.Estimate{}, err
+	}
+	if existing.InvoiceID.Valid {
+		return db.Estimate{}, ErrEstimateConverted
+	}
+
+	return s.queries.UpdateEstimateStatus(ctx, db.UpdateEstimateStatusParams{
+		ID:     id,
+		UserID: userID,
+		Status: status,
+	})
+}
+
+// PreviewEstimateNumber returns the number the next estimate issued on
+// issueDate to the client would be given, without allocating it
+func (s *EstimateService) PreviewEstimateNumber(ctx context.Context, userID, clientID int32, issueDate time.Time) (string, error) {
+	return previewNumber(ctx, s.queries, documentEstimate, userID, clientID, issueDate)
+}
+
+// ConvertEstimate creates a draft invoice from the line items of an estimate
+// and marks the estimate accepted, linking it to the invoice. It returns
+// sql.ErrNoRows when the user has no such estimate.
+func (s *EstimateService) ConvertEstimate(ctx context.Context, userID, id int32, conversion EstimateConversion) (db.Invoice, error) {
+	var invoice db.Invoice
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		estimate, err := q.GetEstimateByID(ctx, db.GetEstimateByIDParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if estimate.InvoiceID.Valid {
+			return ErrEstimateConverted
+		}
+		if estimate.Status == "declined" || estimate.Status == "expired" {
+			return ErrEstimateNotConvertible
+		}
+
+		lineItems, err := q.GetEstimateLineItems(ctx, estimate.ID)
+		if err != nil {
+			return err
+		}
+
+		invoice, err = s.invoices.createInvoice(ctx, q, userID, NewInvoice{
+			ClientID:   estimate.ClientID,
+			IssueDate:  conversion.IssueDate,
+			DueDate:    conversion.DueDate,
+			Status:     "draft",
+			Notes:      estimate.Notes.String,
+			LineItems:  estimateLineItemRequests(lineItems),
+			TaxRateIDs: conversion.TaxRateIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.MarkEstimateConverted(ctx, db.MarkEstimateConvertedParams{
+			ID:        estimate.ID,
+			UserID:    userID,
+			InvoiceID: sql.NullInt32{Int32: invoice.ID, Valid: true},
+		})
+		return err
+	})
+	return invoice, err
+}
+
+// ExpireEstimates moves sent estimates whose validity date is before today
+// to expired and returns them. An estimate valid until today has not
+// expired yet.
+func (s *EstimateService) ExpireEstimates(ctx context.Context) ([]db.Estimate, error) {
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	estimates, err := s.queries.ExpireEstimates(ctx, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire estimates: %w", err)
+	}
+
+	for _, estimate := range estimates {
+		log.Printf("Estimate %s (user %d) expired on %s", estimate.EstimateNumber, estimate.UserID, estimate.ValidUntil.Format("2006-01-02"))
+	}
+	return estimates, nil
+}
+
+// isEstimateNumberTaken reports whether err is the database rejecting an
+// estimate number that another of the user's estimates already has
+func isEstimateNumberTaken(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Constraint == "estimates_user_estimate_number_unique"
+}
+
+// checkClient returns ErrClientNotFound unless the user owns the client
+func checkClient(ctx context.Context, q *db.Queries, userID, clientID int32) error {
+	_, err := q.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     clientID,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows {
+		return ErrClientNotFound
+	}
+	return err
+}
+
+func saveEstimateLineItems(ctx context.Context, q *db.Queries, estimateID int32, lineItems []models.InvoiceLineItemRequest) error {
+	for i, item := range lineItems {
+		unit := item.Unit
+		if unit == "" {
+			unit = "unit"
+		}
+
+		_, err := q.CreateEstimateLineItem(ctx, db.CreateEstimateLineItemParams{
+			EstimateID:  estimateID,
+			Description: strings.TrimSpace(item.Description),
+			Quantity:    item.Quantity.StringFixed(2),
+			Unit:        unit,
+			UnitPrice:   billing.FormatPrice(item.UnitPrice),
+			TaxRate:     item.TaxRate.StringFixed(2),
+			Position:    int32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimateLineItemRequests converts the line items of an estimate into the
+// line items of the invoice it is converted into
+func estimateLineItemRequests(lineItems []db.EstimateLineItem) []models.InvoiceLineItemRequest {
+	requests := make([]models.InvoiceLineItemRequest, len(lineItems))
+	for i, item := range lineItems {
+		requests[i] = models.InvoiceLineItemRequest{
+			Description: item.Description,
+			Quantity:    money.Parse(item.Quantity),
+			Unit:        item.Unit,
+			UnitPrice:   money.Parse(item.UnitPrice),
+			TaxRate:     money.Parse(item.TaxRate),
+		}
+	}
+	return requests
+}
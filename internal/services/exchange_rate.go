@@ -3,43 +3,89 @@ package services
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"strconv"
+	"time"
 	"worklio-api/internal/db"
+	"worklio-api/internal/iso4217"
+	"worklio-api/internal/money"
+	"worklio-api/internal/rates"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sources of the rates used to convert amounts
+const (
+	// RateSourceOverride is a rate the user fixed for the currency pair
+	RateSourceOverride = "override"
+	// RateSourceMarket is a rate published by an exchange rate provider
+	RateSourceMarket = "market"
 )
 
-// Supported currencies list
-var SupportedCurrencies = []string{
-	"USD", "EUR", "GBP", "JPY", "AUD",
-	"CAD", "CHF", "CNY", "SEK", "NZD",
-	"IDR", "SGD", "INR",
+// ErrRateUnavailable is returned when no rate is stored for a currency pair
+var ErrRateUnavailable = errors.New("exchange rate not available")
+
+// Rate is an exchange rate and where it came from, one of the RateSource
+// constants. Market rates record when they were last updated, or for a past
+// date the day of the rate in effect, and are stale when that is longer
+// before the date asked for than the service's maximum rate age.
+type Rate struct {
+	Value     float64
+	Source    string
+	UpdatedAt time.Time
+	Stale     bool
+}
+
+// RatesStatus describes how current the stored market rates are
+type RatesStatus struct {
+	// UpdatedAt is the time of the least recent update among the currencies
+	// in use
+	UpdatedAt time.Time
+	// Missing lists the currencies in use without a stored rate
+	Missing []string
+	// Stale is set when a rate is missing or older than the maximum age
+	Stale bool
+}
+
+// RateProvider fetches exchange rates from an external source
+type RateProvider interface {
+	Name() string
+	Latest(ctx context.Context, base string) (rates.Rates, error)
+	History(ctx context.Context, base string, from, to time.Time) ([]rates.Rates, error)
 }
 
 // ExchangeRateService handles currency exchange rate operations
 type ExchangeRateService struct {
-	queries *db.Queries
+	queries  *db.Queries
+	provider RateProvider
+	maxAge   time.Duration
 }
 
-// NewExchangeRateService creates a new exchange rate service
-func NewExchangeRateService(queries *db.Queries) *ExchangeRateService {
-	return &ExchangeRateService{queries: queries}
+// NewExchangeRateService creates a new exchange rate service that fetches
+// rates from provider. Market rates not updated within maxAge are reported
+// as stale; a maxAge of 0 never considers them stale.
+func NewExchangeRateService(queries *db.Queries, provider RateProvider, maxAge time.Duration) *ExchangeRateService {
+	return &ExchangeRateService{queries: queries, provider: provider, maxAge: maxAge}
 }
 
-// ExchangeAPIResponse represents the response from exchangerate-api.com
-type ExchangeAPIResponse struct {
-	Result           string             `json:"result"`
-	BaseCode         string             `json:"base_code"`
-	ConversionRates  map[string]float64 `json:"conversion_rates"`
-	TimeLastUpdateUTC string            `json:"time_last_update_utc"`
+// MaxAge returns the age after which market rates are considered stale
+func (s *ExchangeRateService) MaxAge() time.Duration {
+	return s.maxAge
 }
 
 // GetExchangeRate gets a single exchange rate from database or API
 func (s *ExchangeRateService) GetExchangeRate(ctx context.Context, baseCurrency, targetCurrency string) (float64, error) {
+	rate, _, err := s.latestRate(ctx, baseCurrency, targetCurrency)
+	return rate, err
+}
+
+// latestRate returns the stored rate of a currency pair and when it was last
+// updated. The update time is zero when both currencies are the same.
+func (s *ExchangeRateService) latestRate(ctx context.Context, baseCurrency, targetCurrency string) (float64, time.Time, error) {
 	if baseCurrency == targetCurrency {
-		return 1.0, nil
+		return 1.0, time.Time{}, nil
 	}
 
 	// Try to get from database using sqlc
@@ -52,58 +98,88 @@ func (s *ExchangeRateService) GetExchangeRate(ctx context.Context, baseCurrency,
 		// Parse the rate string to float64
 		rate, parseErr := strconv.ParseFloat(rateRow.Rate, 64)
 		if parseErr != nil {
-			return 0, fmt.Errorf("failed to parse exchange rate: %w", parseErr)
+			return 0, time.Time{}, fmt.Errorf("failed to parse exchange rate: %w", parseErr)
 		}
-		return rate, nil
+		return rate, rateRow.UpdatedAt.Time, nil
 	}
 
 	if err == sql.ErrNoRows {
 		// Not in database
 		log.Printf("Exchange rate not found for %s -> %s, needs to be updated", baseCurrency, targetCurrency)
-		return 0, fmt.Errorf("exchange rate not available, please run update job")
+		return 0, time.Time{}, fmt.Errorf("%w for %s -> %s, please run update job", ErrRateUnavailable, baseCurrency, targetCurrency)
 	}
 
-	return 0, fmt.Errorf("failed to query exchange rate: %w", err)
+	return 0, time.Time{}, fmt.Errorf("failed to query exchange rate: %w", err)
 }
 
-// UpdateAllRates fetches and updates all exchange rates from the API
-func (s *ExchangeRateService) UpdateAllRates(ctx context.Context) error {
-	baseCurrency := "USD"
-
-	log.Printf("Updating exchange rates for base currency: %s", baseCurrency)
+// isStale reports whether a market rate last updated at updatedAt is older
+// than the maximum rate age
+func (s *ExchangeRateService) isStale(updatedAt time.Time) bool {
+	return s.isStaleAt(updatedAt, time.Now())
+}
 
-	// Fetch rates from API (using free frankfurter.app - no API key needed)
-	url := fmt.Sprintf("https://api.frankfurter.app/latest?from=%s", baseCurrency)
+// isStaleAt reports whether a market rate last updated at updatedAt was
+// older than the maximum rate age on date
+func (s *ExchangeRateService) isStaleAt(updatedAt, date time.Time) bool {
+	return s.maxAge > 0 && !updatedAt.IsZero() && date.Sub(updatedAt) > s.maxAge
+}
 
-	resp, err := http.Get(url)
+// Status reports whether a rate is stored for every currency used by a client
+// or user and whether the oldest of them is stale. Currencies nobody uses are
+// not checked, since most providers only publish a subset of ISO 4217.
+func (s *ExchangeRateService) Status(ctx context.Context) (RatesStatus, error) {
+	rows, err := s.queries.GetAllExchangeRates(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to fetch exchange rates: %w", err)
+		return RatesStatus{}, fmt.Errorf("failed to query exchange rates: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	inUse, err := s.queries.GetCurrenciesInUse(ctx)
+	if err != nil {
+		return RatesStatus{}, fmt.Errorf("failed to query currencies in use: %w", err)
 	}
 
-	var apiResp struct {
-		Base  string             `json:"base"`
-		Date  string             `json:"date"`
-		Rates map[string]float64 `json:"rates"`
+	updated := make(map[string]time.Time)
+	for _, row := range rows {
+		if row.BaseCurrency == "USD" {
+			updated[row.TargetCurrency] = row.UpdatedAt.Time
+		}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return fmt.Errorf("failed to decode API response: %w", err)
+	var status RatesStatus
+	for _, currency := range inUse {
+		if currency == "USD" {
+			continue
+		}
+		updatedAt, ok := updated[currency]
+		if !ok {
+			status.Missing = append(status.Missing, currency)
+			continue
+		}
+		if status.UpdatedAt.IsZero() || updatedAt.Before(status.UpdatedAt) {
+			status.UpdatedAt = updatedAt
+		}
 	}
+	status.Stale = len(status.Missing) > 0 || s.isStale(status.UpdatedAt)
+	return status, nil
+}
 
-	// Add USD rate (base currency)
-	apiResp.Rates["USD"] = 1.0
+// UpdateAllRates fetches and updates all exchange rates from the API
+func (s *ExchangeRateService) UpdateAllRates(ctx context.Context) error {
+	baseCurrency := "USD"
+
+	log.Printf("Updating exchange rates for base currency: %s from %s", baseCurrency, s.provider.Name())
+
+	latest, err := s.provider.Latest(ctx, baseCurrency)
+	if err != nil {
+		return fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
 
-	// Update rates for supported currencies only using sqlc
-	updatedCount := 0
-	for _, targetCurrency := range SupportedCurrencies {
-		rate, exists := apiResp.Rates[targetCurrency]
+	// Update rates for ISO 4217 currencies only using sqlc. Providers
+	// publish a subset of them, so missing rates are only counted.
+	updatedCount, missingCount := 0, 0
+	for _, targetCurrency := range iso4217.Codes() {
+		rate, exists := latest.Rates[targetCurrency]
 		if !exists {
-			log.Printf("Warning: Rate not available for %s", targetCurrency)
+			missingCount++
 			continue
 		}
 
@@ -117,32 +193,302 @@ func (s *ExchangeRateService) UpdateAllRates(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("failed to update rate for %s: %w", targetCurrency, err)
 		}
+
+		// Keep the rate in the history for conversions at past dates
+		err = s.queries.UpsertExchangeRateHistory(ctx, db.UpsertExchangeRateHistoryParams{
+			BaseCurrency:   baseCurrency,
+			TargetCurrency: targetCurrency,
+			Rate:           fmt.Sprintf("%.10f", rate),
+			RateDate:       latest.Date,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record rate history for %s: %w", targetCurrency, err)
+		}
 		updatedCount++
 	}
 
-	log.Printf("Successfully updated %d exchange rates", updatedCount)
+	log.Printf("Successfully updated %d exchange rates, %d currencies not published by %s", updatedCount, missingCount, s.provider.Name())
 	return nil
 }
 
-// ConvertAmount converts an amount from one currency to another
-func (s *ExchangeRateService) ConvertAmount(ctx context.Context, amount float64, fromCurrency, toCurrency string) (float64, error) {
+// ConvertAmount converts an amount from one currency to another for the
+// user, at the user's override rate when one applies today and otherwise at
+// the market rate. A userID of 0 always uses the market rate.
+func (s *ExchangeRateService) ConvertAmount(ctx context.Context, userID int32, amount decimal.Decimal, fromCurrency, toCurrency string) (decimal.Decimal, error) {
+	rate, err := s.GetRate(ctx, userID, fromCurrency, toCurrency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return money.Convert(amount, rate.Value), nil
+}
+
+// GetRate returns the rate converting fromCurrency into toCurrency for the
+// user today and where it came from. A userID of 0 skips overrides.
+func (s *ExchangeRateService) GetRate(ctx context.Context, userID int32, fromCurrency, toCurrency string) (Rate, error) {
+	if fromCurrency == toCurrency {
+		return Rate{Value: 1.0, Source: RateSourceMarket}, nil
+	}
+
+	if rate, ok, err := s.override(ctx, userID, fromCurrency, toCurrency, time.Now()); err != nil || ok {
+		return rate, err
+	}
+
+	rate, updatedAt, err := s.latestRate(ctx, "USD", toCurrency)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	fromRate, fromUpdatedAt, err := s.latestRate(ctx, "USD", fromCurrency)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	// The cross rate is as old as the older of the two
+	if updatedAt.IsZero() || (!fromUpdatedAt.IsZero() && fromUpdatedAt.Before(updatedAt)) {
+		updatedAt = fromUpdatedAt
+	}
+
+	// Convert: fromCurrency -> USD -> toCurrency
+	return Rate{
+		Value:     rate / fromRate,
+		Source:    RateSourceMarket,
+		UpdatedAt: updatedAt,
+		Stale:     s.isStale(updatedAt),
+	}, nil
+}
+
+// override returns the user's override rate for the currency pair valid on
+// date, if there is one
+func (s *ExchangeRateService) override(ctx context.Context, userID int32, fromCurrency, toCurrency string, date time.Time) (Rate, bool, error) {
+	if userID == 0 {
+		return Rate{}, false, nil
+	}
+
+	override, err := s.queries.GetApplicableExchangeRateOverride(ctx, db.GetApplicableExchangeRateOverrideParams{
+		UserID:         userID,
+		BaseCurrency:   fromCurrency,
+		TargetCurrency: toCurrency,
+		OnDate:         date,
+	})
+	if err == sql.ErrNoRows {
+		return Rate{}, false, nil
+	}
+	if err != nil {
+		return Rate{}, false, fmt.Errorf("failed to query exchange rate override: %w", err)
+	}
+
+	value, err := strconv.ParseFloat(override.Rate, 64)
+	if err != nil || value == 0 {
+		return Rate{}, false, fmt.Errorf("invalid exchange rate override %q", override.Rate)
+	}
+	// Overrides apply in both directions
+	if override.BaseCurrency != fromCurrency {
+		value = 1 / value
+	}
+	return Rate{Value: value, Source: RateSourceOverride}, true, nil
+}
+
+// HistoricalRate is the exchange rate between two currencies on one day
+type HistoricalRate struct {
+	Date time.Time
+	Rate float64
+}
+
+// maxBackfillRange is the longest date range requested from the API at once
+const maxBackfillRange = 365 * 24 * time.Hour
+
+// BackfillRates fetches the daily rates of the supported currencies between
+// from and to, inclusive, into the rate history. Days without published
+// rates, such as weekends, are skipped. It returns the number of days stored.
+func (s *ExchangeRateService) BackfillRates(ctx context.Context, from, to time.Time) (int, error) {
+	if to.Before(from) {
+		return 0, fmt.Errorf("backfill range ends before it starts")
+	}
+
+	days := 0
+	for start := from; !start.After(to); start = start.Add(maxBackfillRange) {
+		end := start.Add(maxBackfillRange - 24*time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		stored, err := s.backfillRange(ctx, start, end)
+		if err != nil {
+			return days, err
+		}
+		days += stored
+	}
+
+	log.Printf("Backfilled exchange rates for %d days from %s to %s", days, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	return days, nil
+}
+
+// backfillRange fetches the daily rates between from and to with a single
+// provider request
+func (s *ExchangeRateService) backfillRange(ctx context.Context, from, to time.Time) (int, error) {
+	baseCurrency := "USD"
+
+	history, err := s.provider.History(ctx, baseCurrency, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch historical exchange rates: %w", err)
+	}
+
+	for _, day := range history {
+		for _, targetCurrency := range iso4217.Codes() {
+			rate, exists := day.Rates[targetCurrency]
+			if !exists {
+				continue
+			}
+			err := s.queries.UpsertExchangeRateHistory(ctx, db.UpsertExchangeRateHistoryParams{
+				BaseCurrency:   baseCurrency,
+				TargetCurrency: targetCurrency,
+				Rate:           fmt.Sprintf("%.10f", rate),
+				RateDate:       day.Date,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to record rate history for %s on %s: %w", targetCurrency, day.Date.Format("2006-01-02"), err)
+			}
+		}
+	}
+	return len(history), nil
+}
+
+// GetExchangeRateAt gets the exchange rate in effect on date, which is the
+// latest rate recorded on or before it
+func (s *ExchangeRateService) GetExchangeRateAt(ctx context.Context, baseCurrency, targetCurrency string, date time.Time) (float64, error) {
+	rate, _, err := s.rateAt(ctx, baseCurrency, targetCurrency, date)
+	return rate, err
+}
+
+// rateAt returns the rate of a currency pair in effect on date and the day it
+// was recorded. The day is zero when both currencies are the same.
+func (s *ExchangeRateService) rateAt(ctx context.Context, baseCurrency, targetCurrency string, date time.Time) (float64, time.Time, error) {
+	if baseCurrency == targetCurrency {
+		return 1.0, time.Time{}, nil
+	}
+
+	rateRow, err := s.queries.GetExchangeRateAt(ctx, db.GetExchangeRateAtParams{
+		BaseCurrency:   baseCurrency,
+		TargetCurrency: targetCurrency,
+		RateDate:       date,
+	})
+	if err == sql.ErrNoRows {
+		return 0, time.Time{}, fmt.Errorf("%w from %s to %s on or before %s", ErrRateUnavailable, baseCurrency, targetCurrency, date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to query exchange rate: %w", err)
+	}
+
+	rate, err := strconv.ParseFloat(rateRow.Rate, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to parse exchange rate: %w", err)
+	}
+	return rate, rateRow.RateDate, nil
+}
+
+// ConvertAmountAt converts an amount from one currency to another for the
+// user at the rates in effect on date: the user's override valid on date, or
+// the market rate of that day. A userID of 0 always uses the market rate.
+func (s *ExchangeRateService) ConvertAmountAt(ctx context.Context, userID int32, amount decimal.Decimal, fromCurrency, toCurrency string, date time.Time) (decimal.Decimal, error) {
+	rate, err := s.GetRateAt(ctx, userID, fromCurrency, toCurrency, date)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return money.Convert(amount, rate.Value), nil
+}
+
+// GetRateAt returns the rate converting fromCurrency into toCurrency for the
+// user on date and where it came from. A market rate is stale when the
+// latest rate recorded on or before date is older than the maximum rate age
+// on that date. A userID of 0 skips overrides.
+func (s *ExchangeRateService) GetRateAt(ctx context.Context, userID int32, fromCurrency, toCurrency string, date time.Time) (Rate, error) {
 	if fromCurrency == toCurrency {
-		return amount, nil
+		return Rate{Value: 1.0, Source: RateSourceMarket}, nil
 	}
 
-	rate, err := s.GetExchangeRate(ctx, "USD", toCurrency)
+	if rate, ok, err := s.override(ctx, userID, fromCurrency, toCurrency, date); err != nil || ok {
+		return rate, err
+	}
+
+	rate, rateDate, err := s.rateAt(ctx, "USD", toCurrency, date)
 	if err != nil {
-		return 0, err
+		return Rate{}, err
 	}
 
-	fromRate, err := s.GetExchangeRate(ctx, "USD", fromCurrency)
+	fromRate, fromRateDate, err := s.rateAt(ctx, "USD", fromCurrency, date)
 	if err != nil {
-		return 0, err
+		return Rate{}, err
 	}
 
-	// Convert: amount in fromCurrency -> USD -> toCurrency
-	usdAmount := amount / fromRate
-	convertedAmount := usdAmount * rate
+	// The cross rate is as old as the older of the two
+	if rateDate.IsZero() || (!fromRateDate.IsZero() && fromRateDate.Before(rateDate)) {
+		rateDate = fromRateDate
+	}
+
+	// Convert: fromCurrency -> USD -> toCurrency
+	return Rate{
+		Value:     rate / fromRate,
+		Source:    RateSourceMarket,
+		UpdatedAt: rateDate,
+		Stale:     s.isStaleAt(rateDate, date),
+	}, nil
+}
 
-	return convertedAmount, nil
+// GetRateHistory returns the daily rates from baseCurrency to
+// targetCurrency between from and to, inclusive, oldest first. Only days
+// with recorded rates for both currencies are included.
+func (s *ExchangeRateService) GetRateHistory(ctx context.Context, baseCurrency, targetCurrency string, from, to time.Time) ([]HistoricalRate, error) {
+	// Rates are stored against USD, so the pair is derived from both
+	// currencies' USD rates on each day
+	baseRates, err := s.usdRates(ctx, baseCurrency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	targetRates, err := s.queries.GetExchangeRateHistory(ctx, db.GetExchangeRateHistoryParams{
+		BaseCurrency:   "USD",
+		TargetCurrency: targetCurrency,
+		FromDate:       from,
+		ToDate:         to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange rate history: %w", err)
+	}
+
+	history := make([]HistoricalRate, 0, len(targetRates))
+	for _, row := range targetRates {
+		day := row.RateDate.Format("2006-01-02")
+		baseRate, ok := baseRates[day]
+		if !ok || baseRate == 0 {
+			continue
+		}
+		rate, err := strconv.ParseFloat(row.Rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse exchange rate: %w", err)
+		}
+		history = append(history, HistoricalRate{Date: row.RateDate, Rate: rate / baseRate})
+	}
+	return history, nil
+}
+
+// usdRates returns the USD rates of currency between from and to, keyed by
+// day
+func (s *ExchangeRateService) usdRates(ctx context.Context, currency string, from, to time.Time) (map[string]float64, error) {
+	rows, err := s.queries.GetExchangeRateHistory(ctx, db.GetExchangeRateHistoryParams{
+		BaseCurrency:   "USD",
+		TargetCurrency: currency,
+		FromDate:       from,
+		ToDate:         to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange rate history: %w", err)
+	}
+
+	byDay := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		rate, err := strconv.ParseFloat(row.Rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse exchange rate: %w", err)
+		}
+		byDay[row.RateDate.Format("2006-01-02")] = rate
+	}
+	return byDay, nil
 }
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"worklio-api/internal/db"
+)
+
+func TestGetRateAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		eurDate   time.Time
+		gbpDate   time.Time
+		wantDate  time.Time
+		wantStale bool
+	}{
+		{name: "recorded that day", eurDate: day(10), gbpDate: day(10), wantDate: day(10)},
+		{name: "recorded over the weekend", eurDate: day(8), gbpDate: day(10), wantDate: day(8)},
+		{name: "history stopped", eurDate: day(10), gbpDate: day(1), wantDate: day(1), wantStale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database, _ := openFakeSQL(t, map[string]func([]driver.NamedValue) []any{
+				"GetExchangeRateAt": func(args []driver.NamedValue) []any {
+					if args[1].Value == "EUR" {
+						return []any{db.ExchangeRateHistory{BaseCurrency: "USD", TargetCurrency: "EUR", Rate: "0.8", RateDate: tt.eurDate}}
+					}
+					return []any{db.ExchangeRateHistory{BaseCurrency: "USD", TargetCurrency: "GBP", Rate: "0.75", RateDate: tt.gbpDate}}
+				},
+			})
+			service := NewExchangeRateService(db.New(database), nil, 48*time.Hour)
+
+			rate, err := service.GetRateAt(context.Background(), 0, "EUR", "GBP", day(10))
+			if err != nil {
+				t.Fatalf("GetRateAt: %v", err)
+			}
+			if rate.Value != 0.75/0.8 {
+				t.Errorf("rate = %v, want %v", rate.Value, 0.75/0.8)
+			}
+			if !rate.UpdatedAt.Equal(tt.wantDate) {
+				t.Errorf("rate dated %s, want %s", rate.UpdatedAt, tt.wantDate)
+			}
+			if rate.Stale != tt.wantStale {
+				t.Errorf("stale = %v, want %v", rate.Stale, tt.wantStale)
+			}
+		})
+	}
+}
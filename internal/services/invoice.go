@@ -0,0 +1,476 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+)
+
+var (
+	// ErrClientNotFound is returned when an invoice refers to a client the
+	// user does not own
+	ErrClientNotFound = errors.New("client not found")
+	// ErrTaxRateNotFound is returned when an invoice refers to a tax rate the
+	// user does not own
+	ErrTaxRateNotFound = errors.New("tax rate not found")
+	// ErrTimeEntryNotFound is returned when an invoice refers to a time entry
+	// the user does not own
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	// ErrTimeEntryNotBillable is returned when an invoice refers to a time
+	// entry that is not billable
+	ErrTimeEntryNotBillable = errors.New("time entry is not billable")
+	// ErrInvoiceLocked is returned when editing an invoice that has left
+	// draft, which can only be revised
+	ErrInvoiceLocked = errors.New("issued invoices can no longer be edited")
+	// ErrInvoiceIssued is returned when moving an invoice that has left
+	// draft back to draft, which would make it editable again
+	ErrInvoiceIssued = errors.New("issued invoices cannot be moved back to draft")
+)
+
+// NewInvoice describes an invoice to create. When InvoiceNumber is empty the
+// next number is allocated from the user's numbering pattern once the invoice
+// leaves draft.
+type NewInvoice struct {
+	ClientID      int32
+	InvoiceNumber string
+	IssueDate     time.Time
+	DueDate       time.Time
+	Status        string
+	Notes         string
+	TimeEntryIDs  []int32
+	LineItems     []models.InvoiceLineItemRequest
+	// TaxRateIDs selects the tax rates to apply; nil applies the user's
+	// default tax rates
+	TaxRateIDs []int32
+}
+
+// InvoiceUpdate describes the changes to a draft invoice. LineItems and
+// TaxRateIDs replace the invoice's line items and taxes when not nil.
+type InvoiceUpdate struct {
+	ClientID      int32
+	InvoiceNumber string
+	IssueDate     time.Time
+	DueDate       time.Time
+	Status        string
+	Notes         string
+	LineItems     *[]models.InvoiceLineItemRequest
+	TaxRateIDs    *[]int32
+}
+
+// InvoiceService creates and updates invoices together with their time
+// entries, line items and taxes. Both the invoices API and the recurring
+// invoice job create invoices through it. When an invoice leaves draft its
+// totals and exchange rate are frozen on the invoice.
+type InvoiceService struct {
+	database      *sql.DB
+	queries       *db.Queries
+	exchangeRates *ExchangeRateService
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(database *sql.DB, queries *db.Queries, exchangeRates *ExchangeRateService) *InvoiceService {
+	return &InvoiceService{database: database, queries: queries, exchangeRates: exchangeRates}
+}
+
+// CreateInvoice creates an invoice for the user in a single transaction
+func (s *InvoiceService) CreateInvoice(ctx context.Context, userID int32, invoice NewInvoice) (db.Invoice, error) {
+	var created db.Invoice
+	err := s.withTx(ctx, func(q *db.Queries) error {
+		var err error
+		created, err = s.createInvoice(ctx, q, userID, invoice)
+		return err
+	})
+	return created, err
+}
+
+// UpdateInvoice applies update to a draft invoice in a single transaction,
+// freezing its totals if the update issues it. It returns ErrInvoiceLocked
+// when the invoice has left draft, also when it does so concurrently, and
+// sql.ErrNoRows when the user has no such invoice.
+func (s *InvoiceService) UpdateInvoice(ctx context.Context, userID, invoiceID int32, update InvoiceUpdate) (db.Invoice, error) {
+	var updated db.Invoice
+	err := s.withTx(ctx, func(q *db.Queries) error {
+		invoice, err := q.GetInvoiceByID(ctx, db.GetInvoiceByIDParams{
+			ID:     invoiceID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if invoice.Status != "draft" {
+			return ErrInvoiceLocked
+		}
+
+		var taxRates []db.TaxRate
+		if update.TaxRateIDs != nil {
+			taxRates, err = resolveTaxRates(ctx, q, userID, *update.TaxRateIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The client may have changed, so reverse charge is re-evaluated
+		reverseCharge, err := isReverseCharge(ctx, q, userID, update.ClientID)
+		if err != nil {
+			return err
+		}
+
+		invoice, err = q.UpdateInvoice(ctx, db.UpdateInvoiceParams{
+			ID:            invoice.ID,
+			UserID:        userID,
+			ClientID:      update.ClientID,
+			InvoiceNumber: update.InvoiceNumber,
+			IssueDate:     update.IssueDate,
+			DueDate:       update.DueDate,
+			Status:        update.Status,
+			Notes:         sql.NullString{String: update.Notes, Valid: update.Notes != ""},
+			ReverseCharge: reverseCharge,
+		})
+		if err != nil {
+			// The invoice was issued since it was read
+			if err == sql.ErrNoRows {
+				return ErrInvoiceLocked
+			}
+			if IsInvoiceNumberTaken(err) {
+				return ErrInvoiceNumberTaken
+			}
+			return err
+		}
+
+		if update.LineItems != nil {
+			if err := q.DeleteInvoiceLineItems(ctx, invoice.ID); err != nil {
+				return err
+			}
+			if err := saveLineItems(ctx, q, invoice.ID, *update.LineItems); err != nil {
+				return err
+			}
+		}
+		if update.TaxRateIDs != nil {
+			if err := q.DeleteInvoiceTaxes(ctx, invoice.ID); err != nil {
+				return err
+			}
+			if err := saveInvoiceTaxes(ctx, q, invoice.ID, taxRates); err != nil {
+				return err
+			}
+		}
+
+		updated, err = s.issue(ctx, q, invoice)
+		return err
+	})
+	return updated, err
+}
+
+// UpdateInvoiceStatus changes the status of an invoice. When the invoice
+// leaves draft its totals are recorded, together with the exchange rate to the
+// user's currency, in the same transaction. It returns sql.ErrNoRows when the
+// user has no such invoice.
+func (s *InvoiceService) UpdateInvoiceStatus(ctx context.Context, userID, invoiceID int32, status string) (db.Invoice, error) {
+	var updated db.Invoice
+	err := s.withTx(ctx, func(q *db.Queries) error {
+		var err error
+		updated, err = s.updateInvoiceStatus(ctx, q, userID, invoiceID, status)
+		return err
+	})
+	return updated, err
+}
+
+// withTx runs fn in a transaction, committing it when fn succeeds
+func (s *InvoiceService) withTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateInvoiceStatus changes the status of an invoice, locking it first, and
+// issues it when it leaves draft
+func (s *InvoiceService) updateInvoiceStatus(ctx context.Context, q *db.Queries, userID, invoiceID int32, status string) (db.Invoice, error) {
+	invoice, err := q.LockInvoice(ctx, db.LockInvoiceParams{
+		ID:     invoiceID,
+		UserID: userID,
+	})
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	if status == "draft" && invoice.Status != "draft" {
+		return db.Invoice{}, ErrInvoiceIssued
+	}
+
+	invoice, err = q.UpdateInvoiceStatus(ctx, db.UpdateInvoiceStatusParams{
+		ID:     invoice.ID,
+		UserID: userID,
+		Status: status,
+	})
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	return s.issue(ctx, q, invoice)
+}
+
+func (s *InvoiceService) createInvoice(ctx context.Context, q *db.Queries, userID int32, invoice NewInvoice) (db.Invoice, error) {
+	taxRates, err := resolveTaxRates(ctx, q, userID, invoice.TaxRateIDs)
+	if err != nil {
+		return db.Invoice{}, err
+	}
+
+	reverseCharge, err := isReverseCharge(ctx, q, userID, invoice.ClientID)
+	if err != nil {
+		return db.Invoice{}, err
+	}
+
+	created, err := q.CreateInvoice(ctx, db.CreateInvoiceParams{
+		UserID:        userID,
+		ClientID:      invoice.ClientID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		IssueDate:     invoice.IssueDate,
+		DueDate:       invoice.DueDate,
+		Status:        invoice.Status,
+		Notes:         sql.NullString{String: invoice.Notes, Valid: invoice.Notes != ""},
+		ReverseCharge: reverseCharge,
+	})
+	if err != nil {
+		if IsInvoiceNumberTaken(err) {
+			return db.Invoice{}, ErrInvoiceNumberTaken
+		}
+		return db.Invoice{}, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	for _, timeEntryID := range invoice.TimeEntryIDs {
+		entry, err := q.GetTimeEntryByID(ctx, db.GetTimeEntryByIDParams{
+			ID:     timeEntryID,
+			UserID: userID,
+		})
+		if err == sql.ErrNoRows {
+			return db.Invoice{}, ErrTimeEntryNotFound
+		}
+		if err != nil {
+			return db.Invoice{}, fmt.Errorf("failed to fetch time entry: %w", err)
+		}
+		if !entry.Billable {
+			return db.Invoice{}, ErrTimeEntryNotBillable
+		}
+
+		err = q.AddTimeEntryToInvoice(ctx, db.AddTimeEntryToInvoiceParams{
+			InvoiceID:   created.ID,
+			TimeEntryID: timeEntryID,
+		})
+		if err != nil {
+			return db.Invoice{}, fmt.Errorf("failed to add time entries to invoice: %w", err)
+		}
+	}
+
+	if err := saveLineItems(ctx, q, created.ID, invoice.LineItems); err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to add line items to invoice: %w", err)
+	}
+
+	if err := saveInvoiceTaxes(ctx, q, created.ID, taxRates); err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to apply taxes to invoice: %w", err)
+	}
+
+	return s.issue(ctx, q, created)
+}
+
+// issue numbers an invoice that has left draft without a number and freezes
+// its totals if they have not been frozen yet. Numbering in the transaction
+// that issues the invoice keeps the sequence free of gaps: drafts, which can
+// be deleted, never hold a number from it.
+func (s *InvoiceService) issue(ctx context.Context, q *db.Queries, invoice db.Invoice) (db.Invoice, error) {
+	if invoice.Status == "draft" {
+		return invoice, nil
+	}
+
+	if invoice.InvoiceNumber == "" {
+		number, err := allocateInvoiceNumber(ctx, q, invoice.UserID, invoice.ClientID, invoice.IssueDate)
+		if err != nil {
+			return db.Invoice{}, fmt.Errorf("failed to allocate invoice number: %w", err)
+		}
+		invoice, err = q.SetInvoiceNumber(ctx, db.SetInvoiceNumberParams{
+			ID:            invoice.ID,
+			UserID:        invoice.UserID,
+			InvoiceNumber: number,
+		})
+		if err != nil {
+			return db.Invoice{}, err
+		}
+	}
+
+	if invoice.Total.Valid {
+		return invoice, nil
+	}
+	return s.saveTotals(ctx, q, invoice)
+}
+
+// saveTotals records the invoice's current totals. The exchange rate frozen
+// when the invoice was issued is kept unless the invoice's currency has
+// changed since; otherwise the user's rate in effect on the issue date is
+// used. When no rate is available the totals are still recorded, without a
+// rate.
+func (s *InvoiceService) saveTotals(ctx context.Context, q *db.Queries, invoice db.Invoice) (db.Invoice, error) {
+	contents, err := billing.LoadContents(ctx, q, invoice.ID)
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	totals := contents.Totals(invoice.ReverseCharge)
+
+	client, err := q.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     invoice.ClientID,
+		UserID: invoice.UserID,
+	})
+	if err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to fetch client: %w", err)
+	}
+
+	user, err := q.GetUserByID(ctx, invoice.UserID)
+	if err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	reportingCurrency := "USD"
+	if user.Currency.Valid {
+		reportingCurrency = user.Currency.String
+	}
+
+	exchangeRate, rateSource := invoice.ExchangeRate, invoice.ExchangeRateSource
+	if invoice.Currency.String != client.Currency || invoice.ReportingCurrency.String != reportingCurrency || !exchangeRate.Valid {
+		exchangeRate, rateSource = sql.NullString{}, sql.NullString{}
+		rate, err := s.exchangeRates.GetRateAt(ctx, invoice.UserID, client.Currency, reportingCurrency, invoice.IssueDate)
+		if err != nil {
+			// The history may not reach back to the issue date yet
+			rate, err = s.exchangeRates.GetRate(ctx, invoice.UserID, client.Currency, reportingCurrency)
+		}
+		if err != nil {
+			log.Printf("Invoice %s issued without an exchange rate from %s to %s: %v", invoice.InvoiceNumber, client.Currency, reportingCurrency, err)
+		} else {
+			if rate.Stale {
+				log.Printf("Invoice %s issued at a stale exchange rate from %s to %s, last updated %s", invoice.InvoiceNumber, client.Currency, reportingCurrency, rate.UpdatedAt.Format("2006-01-02"))
+			}
+			exchangeRate = sql.NullString{String: fmt.Sprintf("%.10f", rate.Value), Valid: true}
+			rateSource = sql.NullString{String: rate.Source, Valid: true}
+		}
+	}
+
+	return q.SetInvoiceTotals(ctx, db.SetInvoiceTotalsParams{
+		ID:                 invoice.ID,
+		UserID:             invoice.UserID,
+		Currency:           sql.NullString{String: client.Currency, Valid: true},
+		Subtotal:           sql.NullString{String: money.Format(totals.Subtotal, client.Currency), Valid: true},
+		TaxAmount:          sql.NullString{String: money.Format(totals.Tax, client.Currency), Valid: true},
+		WithholdingAmount:  sql.NullString{String: money.Format(totals.Withholding, client.Currency), Valid: true},
+		Total:              sql.NullString{String: money.Format(totals.Total, client.Currency), Valid: true},
+		ReportingCurrency:  sql.NullString{String: reportingCurrency, Valid: true},
+		ExchangeRate:       exchangeRate,
+		ExchangeRateSource: rateSource,
+	})
+}
+
+func resolveTaxRates(ctx context.Context, q *db.Queries, userID int32, ids []int32) ([]db.TaxRate, error) {
+	var taxRates []db.TaxRate
+	if ids == nil {
+		defaults, err := q.GetDefaultTaxRatesByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		taxRates = defaults
+	} else {
+		for _, id := range ids {
+			taxRate, err := q.GetTaxRateByID(ctx, db.GetTaxRateByIDParams{
+				ID:     id,
+				UserID: userID,
+			})
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return nil, ErrTaxRateNotFound
+				}
+				return nil, err
+			}
+			taxRates = append(taxRates, taxRate)
+		}
+	}
+
+	sort.SliceStable(taxRates, func(i, j int) bool {
+		return !taxRates[i].Compound && taxRates[j].Compound
+	})
+	return taxRates, nil
+}
+
+func isReverseCharge(ctx context.Context, q *db.Queries, userID, clientID int32) (bool, error) {
+	seller, err := q.GetUserBillingProfile(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	client, err := q.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     clientID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, ErrClientNotFound
+		}
+		return false, err
+	}
+
+	return billing.IsReverseCharge(
+		seller.CountryCode.String,
+		seller.TaxID.String,
+		client.CountryCode.String,
+		client.TaxID.String,
+	), nil
+}
+
+// saveInvoiceTaxes copies taxRates onto the invoice
+func saveInvoiceTaxes(ctx context.Context, q *db.Queries, invoiceID int32, taxRates []db.TaxRate) error {
+	for i, taxRate := range taxRates {
+		_, err := q.CreateInvoiceTax(ctx, db.CreateInvoiceTaxParams{
+			InvoiceID:   invoiceID,
+			TaxRateID:   sql.NullInt32{Int32: taxRate.ID, Valid: true},
+			Name:        taxRate.Name,
+			Rate:        taxRate.Rate,
+			Compound:    taxRate.Compound,
+			Withholding: taxRate.Withholding,
+			Position:    int32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveLineItems(ctx context.Context, q *db.Queries, invoiceID int32, lineItems []models.InvoiceLineItemRequest) error {
+	for i, item := range lineItems {
+		unit := item.Unit
+		if unit == "" {
+			unit = "unit"
+		}
+
+		_, err := q.CreateInvoiceLineItem(ctx, db.CreateInvoiceLineItemParams{
+			InvoiceID:   invoiceID,
+			Description: strings.TrimSpace(item.Description),
+			Quantity:    item.Quantity.StringFixed(2),
+			Unit:        unit,
+			UnitPrice:   billing.FormatPrice(item.UnitPrice),
+			TaxRate:     item.TaxRate.StringFixed(2),
+			Position:    int32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+)
+
+var (
+	// ErrInvoiceDraft is returned when revising a draft invoice, which can
+	// still be edited directly
+	ErrInvoiceDraft = errors.New("draft invoices are edited directly")
+	// ErrRevisionNotFound is returned when diffing a revision the invoice
+	// does not have
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrRevisionBelowSettled is returned when a revision would lower the
+	// invoice total below what has already been paid and credited on it
+	ErrRevisionBelowSettled = errors.New("revised total is below the amount already paid and credited")
+)
+
+// InvoiceChanges describes a revision of an issued invoice. The status is
+// kept; LineItems and TaxRateIDs replace the invoice's line items and taxes
+// when not nil.
+type InvoiceChanges struct {
+	Reason        string
+	ClientID      int32
+	InvoiceNumber string
+	IssueDate     time.Time
+	DueDate       time.Time
+	Notes         string
+	LineItems     *[]models.InvoiceLineItemRequest
+	TaxRateIDs    *[]int32
+}
+
+// InvoiceDiff is the difference between two revisions of an invoice
+type InvoiceDiff struct {
+	From    int32
+	To      int32
+	Changes []billing.Change
+}
+
+// ReviseInvoice snapshots an issued invoice into its revision history and
+// then applies changes to it, in a single transaction. The invoice is locked
+// against concurrent payments and credit notes, the revision is rejected when
+// its total would fall below what has been paid and credited, and the
+// invoice status follows the revised total. It returns the diff between the
+// snapshotted revision and the revised invoice, and sql.ErrNoRows when the
+// user has no such invoice.
+func (s *InvoiceService) ReviseInvoice(ctx context.Context, userID, invoiceID int32, changes InvoiceChanges) (InvoiceDiff, error) {
+	var diff InvoiceDiff
+	err := s.withTx(ctx, func(q *db.Queries) error {
+		invoice, err := q.LockInvoice(ctx, db.LockInvoiceParams{
+			ID:     invoiceID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if invoice.Status == "draft" {
+			return ErrInvoiceDraft
+		}
+
+		before, err := loadSnapshot(ctx, q, invoice)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(before)
+		if err != nil {
+			return fmt.Errorf("failed to encode invoice snapshot: %w", err)
+		}
+
+		latest, err := q.GetLatestInvoiceRevision(ctx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		revision, err := q.CreateInvoiceRevision(ctx, db.CreateInvoiceRevisionParams{
+			InvoiceID: invoice.ID,
+			Revision:  latest + 1,
+			Reason:    sql.NullString{String: changes.Reason, Valid: changes.Reason != ""},
+			Snapshot:  data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save invoice revision: %w", err)
+		}
+
+		var taxRates []db.TaxRate
+		if changes.TaxRateIDs != nil {
+			taxRates, err = resolveTaxRates(ctx, q, userID, *changes.TaxRateIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The client may have changed, so reverse charge is re-evaluated
+		reverseCharge, err := isReverseCharge(ctx, q, userID, changes.ClientID)
+		if err != nil {
+			return err
+		}
+
+		invoice, err = q.UpdateIssuedInvoice(ctx, db.UpdateIssuedInvoiceParams{
+			ID:            invoice.ID,
+			UserID:        userID,
+			ClientID:      changes.ClientID,
+			InvoiceNumber: changes.InvoiceNumber,
+			IssueDate:     changes.IssueDate,
+			DueDate:       changes.DueDate,
+			Notes:         sql.NullString{String: changes.Notes, Valid: changes.Notes != ""},
+			ReverseCharge: reverseCharge,
+		})
+		if err != nil {
+			if IsInvoiceNumberTaken(err) {
+				return ErrInvoiceNumberTaken
+			}
+			return err
+		}
+
+		if changes.LineItems != nil {
+			if err := q.DeleteInvoiceLineItems(ctx, invoice.ID); err != nil {
+				return err
+			}
+			if err := saveLineItems(ctx, q, invoice.ID, *changes.LineItems); err != nil {
+				return err
+			}
+		}
+		if changes.TaxRateIDs != nil {
+			if err := q.DeleteInvoiceTaxes(ctx, invoice.ID); err != nil {
+				return err
+			}
+			if err := saveInvoiceTaxes(ctx, q, invoice.ID, taxRates); err != nil {
+				return err
+			}
+		}
+
+		// The frozen totals follow the revision; the exchange rate is kept
+		// unless the client's currency changed
+		invoice, err = s.saveTotals(ctx, q, invoice)
+		if err != nil {
+			return err
+		}
+
+		contents, err := billing.LoadContents(ctx, q, invoice.ID)
+		if err != nil {
+			return err
+		}
+		totals := billing.IssuedTotals(invoice, contents)
+		if totals.Net().LessThan(totals.Paid) {
+			return ErrRevisionBelowSettled
+		}
+		if err := billing.SyncInvoiceStatus(ctx, q, invoice); err != nil {
+			return fmt.Errorf("failed to update invoice status: %w", err)
+		}
+
+		after, err := loadSnapshot(ctx, q, invoice)
+		if err != nil {
+			return err
+		}
+
+		diff = InvoiceDiff{
+			From:    revision.Revision,
+			To:      revision.Revision + 1,
+			Changes: billing.Diff(before, after),
+		}
+		return nil
+	})
+	return diff, err
+}
+
+// DiffRevisions compares two revisions of an invoice. Revisions are numbered
+// from 1, and the invoice as it is now is the revision after the latest
+// snapshot; to = 0 compares against it. It returns sql.ErrNoRows when the
+// user has no such invoice.
+func (s *InvoiceService) DiffRevisions(ctx context.Context, userID, invoiceID, from, to int32) (InvoiceDiff, error) {
+	invoice, err := s.queries.GetInvoiceByID(ctx, db.GetInvoiceByIDParams{
+		ID:     invoiceID,
+		UserID: userID,
+	})
+	if err != nil {
+		return InvoiceDiff{}, err
+	}
+
+	latest, err := s.queries.GetLatestInvoiceRevision(ctx, invoice.ID)
+	if err != nil {
+		return InvoiceDiff{}, err
+	}
+	current := latest + 1
+	if to == 0 {
+		to = current
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 || from > current || to < 1 || to > current {
+		return InvoiceDiff{}, ErrRevisionNotFound
+	}
+
+	snapshot := func(revision int32) (billing.Snapshot, error) {
+		if revision == current {
+			return loadSnapshot(ctx, s.queries, invoice)
+		}
+		stored, err := s.queries.GetInvoiceRevision(ctx, db.GetInvoiceRevisionParams{
+			InvoiceID: invoice.ID,
+			Revision:  revision,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return billing.Snapshot{}, ErrRevisionNotFound
+			}
+			return billing.Snapshot{}, err
+		}
+		var snapshot billing.Snapshot
+		if err := json.Unmarshal(stored.Snapshot, &snapshot); err != nil {
+			return billing.Snapshot{}, fmt.Errorf("failed to decode invoice snapshot: %w", err)
+		}
+		return snapshot, nil
+	}
+
+	before, err := snapshot(from)
+	if err != nil {
+		return InvoiceDiff{}, err
+	}
+	after, err := snapshot(to)
+	if err != nil {
+		return InvoiceDiff{}, err
+	}
+
+	return InvoiceDiff{From: from, To: to, Changes: billing.Diff(before, after)}, nil
+}
+
+func loadSnapshot(ctx context.Context, q *db.Queries, invoice db.Invoice) (billing.Snapshot, error) {
+	contents, err := billing.LoadContents(ctx, q, invoice.ID)
+	if err != nil {
+		return billing.Snapshot{}, err
+	}
+	return billing.NewSnapshot(invoice, contents), nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+
+	"github.com/lib/pq"
+)
+
+// Document types numbered from their own sequences
+const (
+	documentInvoice    = "invoice"
+	documentCreditNote = "credit_note"
+	documentEstimate   = "estimate"
+)
+
+// ErrInvoiceNumberTaken is returned when an invoice is given a number another
+// of the user's invoices already has
+var ErrInvoiceNumberTaken = errors.New("invoice number already exists")
+
+// IsInvoiceNumberTaken reports whether err is the database rejecting an
+// invoice number that another of the user's invoices already has
+func IsInvoiceNumberTaken(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Constraint == "invoices_user_invoice_number_unique"
+}
+
+// PreviewInvoiceNumber returns the number the next invoice issued on
+// issueDate to the client would be given, without allocating it
+func (s *InvoiceService) PreviewInvoiceNumber(ctx context.Context, userID, clientID int32, issueDate time.Time) (string, error) {
+	return previewNumber(ctx, s.queries, documentInvoice, userID, clientID, issueDate)
+}
+
+// allocateInvoiceNumber takes the next number in the sequence for an invoice
+// issued on issueDate to the client. q must be in the transaction moving the
+// invoice out of draft: the sequence stays locked until it ends, and rolling
+// it back releases the number so no gaps are left. Numbers already given to
+// invoices by hand are skipped.
+func allocateInvoiceNumber(ctx context.Context, q *db.Queries, userID, clientID int32, issueDate time.Time) (string, error) {
+	return allocateNumber(ctx, q, documentInvoice, userID, clientID, issueDate)
+}
+
+// previewNumber returns the next number of the documentType sequence without
+// allocating it
+func previewNumber(ctx context.Context, q *db.Queries, documentType string, userID, clientID int32, issueDate time.Time) (string, error) {
+	pattern, prefix, err := documentNumbering(ctx, q, documentType, userID, clientID)
+	if err != nil {
+		return "", err
+	}
+
+	last, err := q.GetInvoiceNumberSequence(ctx, db.GetInvoiceNumberSequenceParams{
+		UserID:       userID,
+		DocumentType: documentType,
+		Scope:        pattern.Scope(issueDate, prefix),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+
+	for seq := int(last) + 1; ; seq++ {
+		number := pattern.Format(issueDate, prefix, seq)
+		taken, err := numberTaken(ctx, q, documentType, userID, number)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return number, nil
+		}
+	}
+}
+
+// allocateNumber takes the next number of the documentType sequence, skipping
+// numbers already in use
+func allocateNumber(ctx context.Context, q *db.Queries, documentType string, userID, clientID int32, issueDate time.Time) (string, error) {
+	pattern, prefix, err := documentNumbering(ctx, q, documentType, userID, clientID)
+	if err != nil {
+		return "", err
+	}
+
+	scope := pattern.Scope(issueDate, prefix)
+	for {
+		seq, err := q.IncrementInvoiceNumberSequence(ctx, db.IncrementInvoiceNumberSequenceParams{
+			UserID:       userID,
+			DocumentType: documentType,
+			Scope:        scope,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		number := pattern.Format(issueDate, prefix, int(seq))
+		taken, err := numberTaken(ctx, q, documentType, userID, number)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return number, nil
+		}
+	}
+}
+
+// numberTaken reports whether one of the user's documents of documentType
+// already has number
+func numberTaken(ctx context.Context, q *db.Queries, documentType string, userID int32, number string) (bool, error) {
+	switch documentType {
+	case documentCreditNote:
+		return q.CreditNoteNumberExists(ctx, db.CreditNoteNumberExistsParams{
+			UserID:           userID,
+			CreditNoteNumber: number,
+		})
+	case documentEstimate:
+		return q.EstimateNumberExists(ctx, db.EstimateNumberExistsParams{
+			UserID:         userID,
+			EstimateNumber: number,
+		})
+	}
+	return q.InvoiceNumberExists(ctx, db.InvoiceNumberExistsParams{
+		UserID:        userID,
+		InvoiceNumber: number,
+	})
+}
+
+// documentNumbering returns the user's number pattern for documentType and
+// the client's invoice prefix
+func documentNumbering(ctx context.Context, q *db.Queries, documentType string, userID, clientID int32) (billing.NumberPattern, string, error) {
+	patterns, err := q.GetUserNumberPatterns(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	client, err := q.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     clientID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", ErrClientNotFound
+		}
+		return "", "", err
+	}
+
+	pattern := patterns.InvoiceNumberPattern
+	switch documentType {
+	case documentCreditNote:
+		pattern = patterns.CreditNoteNumberPattern
+	case documentEstimate:
+		pattern = patterns.EstimateNumberPattern
+	}
+	return billing.NumberPattern(pattern), client.InvoicePrefix.String, nil
+}
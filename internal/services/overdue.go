@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"worklio-api/internal/db"
+)
+
+// OverdueStore is the subset of db.Queries used by OverdueService
+type OverdueStore interface {
+	MarkInvoicesOverdue(ctx context.Context, dueDate time.Time) ([]db.Invoice, error)
+}
+
+// OverdueService moves unpaid invoices past their due date to overdue
+type OverdueService struct {
+	store OverdueStore
+	now   func() time.Time
+}
+
+// NewOverdueService creates a new overdue service that tells which day it is
+// with now, usually time.Now
+func NewOverdueService(store OverdueStore, now func() time.Time) *OverdueService {
+	return &OverdueService{store: store, now: now}
+}
+
+// MarkOverdueInvoices transitions sent and partially paid invoices whose due
+// date is before today to overdue, recording when it happened, and returns
+// the invoices that were updated. An invoice due today is not overdue yet.
+func (s *OverdueService) MarkOverdueInvoices(ctx context.Context) ([]db.Invoice, error) {
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	invoices, err := s.store.MarkInvoicesOverdue(ctx, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark overdue invoices: %w", err)
+	}
+
+	for _, invoice := range invoices {
+		log.Printf("Invoice %s (user %d) is overdue since %s", invoice.InvoiceNumber, invoice.UserID, invoice.DueDate.Format("2006-01-02"))
+	}
+	return invoices, nil
+}
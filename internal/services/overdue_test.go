@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"worklio-api/internal/db"
+)
+
+// overdueStore holds invoices and marks them overdue like the
+// MarkInvoicesOverdue query: sent and partially paid invoices due before the
+// given date
+type overdueStore struct {
+	invoices []db.Invoice
+	dueDates []time.Time
+}
+
+func (s *overdueStore) MarkInvoicesOverdue(ctx context.Context, dueDate time.Time) ([]db.Invoice, error) {
+	s.dueDates = append(s.dueDates, dueDate)
+	var marked []db.Invoice
+	for i, invoice := range s.invoices {
+		if (invoice.Status == "sent" || invoice.Status == "partially_paid") && invoice.DueDate.Before(dueDate) {
+			s.invoices[i].Status = "overdue"
+			marked = append(marked, s.invoices[i])
+		}
+	}
+	return marked, nil
+}
+
+func TestMarkOverdueInvoices(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		status string
+		due    time.Time
+		want   bool
+	}{
+		{name: "due yesterday", now: day(10).Add(9 * time.Hour), status: "sent", due: day(9), want: true},
+		{name: "due today", now: day(10).Add(23 * time.Hour), status: "sent", due: day(10)},
+		{name: "due tomorrow", now: day(10), status: "sent", due: day(11)},
+		{name: "just after midnight UTC", now: day(10), status: "sent", due: day(9), want: true},
+		{name: "just before midnight UTC", now: day(10).Add(-time.Nanosecond), status: "sent", due: day(9)},
+		// Still the 9th in New York, but already the 10th in UTC
+		{name: "clock in another timezone", now: day(10).Add(2 * time.Hour).In(time.FixedZone("EDT", -4*3600)), status: "sent", due: day(9), want: true},
+		{name: "partially paid", now: day(10), status: "partially_paid", due: day(1), want: true},
+		{name: "draft", now: day(10), status: "draft", due: day(1)},
+		{name: "paid", now: day(10), status: "paid", due: day(1)},
+		{name: "already overdue", now: day(10), status: "overdue", due: day(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &overdueStore{invoices: []db.Invoice{{ID: 1, Status: tt.status, DueDate: tt.due}}}
+			service := NewOverdueService(store, func() time.Time { return tt.now })
+
+			marked, err := service.MarkOverdueInvoices(context.Background())
+			if err != nil {
+				t.Fatalf("MarkOverdueInvoices: %v", err)
+			}
+
+			// Invoices are overdue from the day after their due date, in UTC
+			today := tt.now.UTC().Truncate(24 * time.Hour)
+			if len(store.dueDates) != 1 || !store.dueDates[0].Equal(today) {
+				t.Errorf("marked invoices due before %v, want %s", store.dueDates, today)
+			}
+			if got := len(marked) == 1; got != tt.want {
+				t.Errorf("marked %d invoices overdue, want overdue: %v", len(marked), tt.want)
+			}
+		})
+	}
+}
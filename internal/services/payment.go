@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrPaymentNotFound is returned when changing a payment the invoice
+	// does not have
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrInvoiceNotPayable is returned when recording a payment on a draft
+	// invoice, which is not owed yet
+	ErrInvoiceNotPayable = errors.New("payments cannot be recorded on a draft invoice")
+	// ErrPaymentTooSmall is returned when a payment amount rounds to zero in
+	// the invoice currency, e.g. 0.001 USD
+	ErrPaymentTooSmall = errors.New("payment amount rounds to zero in the invoice currency")
+)
+
+// PaymentCurrencyError is returned when a payment is in another currency than
+// its invoice. Balances are kept in the invoice currency, so payments received
+// in another currency must be recorded at their converted amount.
+type PaymentCurrencyError struct {
+	InvoiceCurrency string
+}
+
+func (e *PaymentCurrencyError) Error() string {
+	return fmt.Sprintf("payment currency must match the invoice currency (%s)", e.InvoiceCurrency)
+}
+
+// PaymentBalanceError is returned when a payment is worth more than what is
+// still owed on its invoice
+type PaymentBalanceError struct {
+	Balance  decimal.Decimal
+	Currency string
+}
+
+func (e *PaymentBalanceError) Error() string {
+	return fmt.Sprintf("payment exceeds the outstanding balance of %s %s", money.Format(e.Balance, e.Currency), e.Currency)
+}
+
+// NewPayment describes a payment received against an invoice. Currency may be
+// empty, meaning the invoice currency.
+type NewPayment struct {
+	Amount      decimal.Decimal
+	Currency    string
+	PaymentDate time.Time
+	Method      string
+	Reference   string
+}
+
+// PaymentService records the payments received against invoices. Each change
+// locks the invoice, so that payments, credit notes and revisions of the same
+// invoice are checked against its balance one at a time, and updates the
+// invoice status in the same transaction.
+type PaymentService struct {
+	invoices *InvoiceService
+}
+
+// NewPaymentService creates a new payment service
+func NewPaymentService(invoices *InvoiceService) *PaymentService {
+	return &PaymentService{invoices: invoices}
+}
+
+// CreatePayment records a payment against one of the user's issued invoices.
+// It returns ErrInvoiceNotFound when the user has no such invoice.
+func (s *PaymentService) CreatePayment(ctx context.Context, userID, invoiceID int32, payment NewPayment) (db.Payment, error) {
+	var created db.Payment
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		invoice, contents, err := lockPayableInvoice(ctx, q, userID, invoiceID, payment.Currency)
+		if err != nil {
+			return err
+		}
+
+		totals := billing.IssuedTotals(invoice, contents)
+		if err := checkPaymentAmount(payment.Amount, totals.Balance, contents.Currency); err != nil {
+			return err
+		}
+
+		created, err = q.CreatePayment(ctx, db.CreatePaymentParams{
+			InvoiceID:   invoice.ID,
+			Amount:      money.Format(payment.Amount, contents.Currency),
+			Currency:    contents.Currency,
+			PaymentDate: payment.PaymentDate,
+			Method:      payment.Method,
+			Reference:   paymentReference(payment.Reference),
+		})
+		if err != nil {
+			return err
+		}
+
+		return billing.SyncInvoiceStatus(ctx, q, invoice)
+	})
+	return created, err
+}
+
+// UpdatePayment replaces a payment recorded against one of the user's
+// invoices. It returns ErrInvoiceNotFound when the user has no such invoice
+// and ErrPaymentNotFound when the invoice has no such payment.
+func (s *PaymentService) UpdatePayment(ctx context.Context, userID, invoiceID, paymentID int32, payment NewPayment) (db.Payment, error) {
+	var updated db.Payment
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		invoice, contents, err := lockPayableInvoice(ctx, q, userID, invoiceID, payment.Currency)
+		if err != nil {
+			return err
+		}
+
+		existing, err := q.GetPaymentByID(ctx, db.GetPaymentByIDParams{
+			ID:        paymentID,
+			InvoiceID: invoice.ID,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrPaymentNotFound
+			}
+			return err
+		}
+
+		// The payment being replaced no longer counts against the balance
+		totals := billing.IssuedTotals(invoice, contents)
+		balance := totals.Balance.Add(money.Parse(existing.Amount))
+		if err := checkPaymentAmount(payment.Amount, balance, contents.Currency); err != nil {
+			return err
+		}
+
+		updated, err = q.UpdatePayment(ctx, db.UpdatePaymentParams{
+			ID:          existing.ID,
+			InvoiceID:   invoice.ID,
+			Amount:      money.Format(payment.Amount, contents.Currency),
+			Currency:    contents.Currency,
+			PaymentDate: payment.PaymentDate,
+			Method:      payment.Method,
+			Reference:   paymentReference(payment.Reference),
+		})
+		if err != nil {
+			return err
+		}
+
+		return billing.SyncInvoiceStatus(ctx, q, invoice)
+	})
+	return updated, err
+}
+
+// DeletePayment removes a payment recorded against one of the user's
+// invoices. It returns ErrInvoiceNotFound when the user has no such invoice
+// and ErrPaymentNotFound when the invoice has no such payment.
+func (s *PaymentService) DeletePayment(ctx context.Context, userID, invoiceID, paymentID int32) error {
+	return s.invoices.withTx(ctx, func(q *db.Queries) error {
+		invoice, err := lockInvoice(ctx, q, userID, invoiceID)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := q.DeletePayment(ctx, db.DeletePaymentParams{
+			ID:        paymentID,
+			InvoiceID: invoice.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrPaymentNotFound
+		}
+
+		return billing.SyncInvoiceStatus(ctx, q, invoice)
+	})
+}
+
+// lockInvoice locks one of the user's invoices until the end of the
+// transaction of q. Payments and credit notes take this lock before checking
+// the invoice balance, so that concurrent changes cannot overpay or
+// over-credit it.
+func lockInvoice(ctx context.Context, q *db.Queries, userID, invoiceID int32) (db.Invoice, error) {
+	invoice, err := q.LockInvoice(ctx, db.LockInvoiceParams{
+		ID:     invoiceID,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows {
+		return db.Invoice{}, ErrInvoiceNotFound
+	}
+	return invoice, err
+}
+
+// lockPayableInvoice locks one of the user's invoices for a payment in
+// currency, checking that it can be paid, and returns it with its contents
+func lockPayableInvoice(ctx context.Context, q *db.Queries, userID, invoiceID int32, currency string) (db.Invoice, billing.Contents, error) {
+	invoice, err := lockInvoice(ctx, q, userID, invoiceID)
+	if err != nil {
+		return db.Invoice{}, billing.Contents{}, err
+	}
+	if invoice.Status == "draft" {
+		return db.Invoice{}, billing.Contents{}, ErrInvoiceNotPayable
+	}
+
+	contents, err := billing.LoadContents(ctx, q, invoice.ID)
+	if err != nil {
+		return db.Invoice{}, billing.Contents{}, err
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency != "" && currency != contents.Currency {
+		return db.Invoice{}, billing.Contents{}, &PaymentCurrencyError{InvoiceCurrency: contents.Currency}
+	}
+	return invoice, contents, nil
+}
+
+// checkPaymentAmount returns ErrPaymentTooSmall when amount is less than a
+// minor unit of currency, and a PaymentBalanceError when it exceeds balance by
+// a minor unit or more
+func checkPaymentAmount(amount, balance decimal.Decimal, currency string) error {
+	if !money.Round(amount, currency).IsPositive() {
+		return ErrPaymentTooSmall
+	}
+	if money.Round(amount.Sub(balance), currency).IsPositive() {
+		return &PaymentBalanceError{Balance: balance, Currency: currency}
+	}
+	return nil
+}
+
+// paymentReference returns the trimmed reference, null when empty
+func paymentReference(reference string) sql.NullString {
+	reference = strings.TrimSpace(reference)
+	return sql.NullString{String: reference, Valid: reference != ""}
+}
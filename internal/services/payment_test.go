@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"worklio-api/internal/db"
+
+	"github.com/shopspring/decimal"
+)
+
+// paymentFixtures returns the rows for an invoice on which 40.00 USD has been
+// paid. Payments it is asked to record are returned with the earlier one.
+func paymentFixtures(invoice db.Invoice) map[string]func([]driver.NamedValue) []any {
+	payments := []any{db.Payment{ID: 1, InvoiceID: invoice.ID, Amount: "40.00", Currency: "USD"}}
+	return map[string]func([]driver.NamedValue) []any{
+		"LockInvoice":        rows(invoice),
+		"GetInvoiceCurrency": rows(struct{ Currency string }{"USD"}),
+		"GetPaymentsByInvoiceID": func([]driver.NamedValue) []any {
+			return payments
+		},
+		"CreatePayment": func(args []driver.NamedValue) []any {
+			payment := db.Payment{ID: 2, InvoiceID: invoice.ID, Amount: args[1].Value.(string), Currency: "USD"}
+			payments = append(payments, payment)
+			return []any{payment}
+		},
+		"UpdateInvoiceStatus": rows(invoice),
+	}
+}
+
+func TestCreatePayment(t *testing.T) {
+	invoice := db.Invoice{
+		ID:       7,
+		UserID:   3,
+		ClientID: 5,
+		Status:   "sent",
+		Currency: sql.NullString{String: "USD", Valid: true},
+		Total:    sql.NullString{String: "100.00", Valid: true},
+	}
+
+	tests := []struct {
+		name       string
+		invoice    db.Invoice
+		amount     string
+		currency   string
+		wantErr    error
+		wantAmount string
+		wantStatus string
+	}{
+		{name: "settles the balance", invoice: invoice, amount: "60.00", wantAmount: "60.00", wantStatus: "paid"},
+		{name: "pays part of the balance", invoice: invoice, amount: "10.00", wantAmount: "10.00", wantStatus: "partially_paid"},
+		{name: "within a minor unit of the balance", invoice: invoice, amount: "60.004", wantAmount: "60.00", wantStatus: "paid"},
+		{name: "rounds to zero", invoice: invoice, amount: "0.004", wantErr: ErrPaymentTooSmall},
+		{name: "exceeds the balance", invoice: invoice, amount: "60.01", wantErr: &PaymentBalanceError{}},
+		{name: "in another currency", invoice: invoice, amount: "10.00", currency: "EUR", wantErr: &PaymentCurrencyError{}},
+		{name: "on a draft", invoice: db.Invoice{ID: 7, UserID: 3, Status: "draft"}, amount: "10.00", wantErr: ErrInvoiceNotPayable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database, fake := openFakeSQL(t, paymentFixtures(tt.invoice))
+			service := NewPaymentService(NewInvoiceService(database, db.New(database), nil))
+
+			_, err := service.CreatePayment(context.Background(), 3, 7, NewPayment{
+				Amount:      decimal.RequireFromString(tt.amount),
+				Currency:    tt.currency,
+				PaymentDate: time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+				Method:      "bank_transfer",
+			})
+
+			if len(fake.calls["LockInvoice"]) != 1 {
+				t.Errorf("invoice locked %d times, want 1", len(fake.calls["LockInvoice"]))
+			}
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("CreatePayment: %v", err)
+				}
+			case *PaymentBalanceError:
+				if !errors.As(err, &want) {
+					t.Fatalf("CreatePayment error = %v, want a PaymentBalanceError", err)
+				}
+			case *PaymentCurrencyError:
+				if !errors.As(err, &want) {
+					t.Fatalf("CreatePayment error = %v, want a PaymentCurrencyError", err)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Fatalf("CreatePayment error = %v, want %v", err, want)
+				}
+			}
+
+			if tt.wantErr != nil {
+				if len(fake.calls["CreatePayment"]) != 0 {
+					t.Error("payment recorded despite the error")
+				}
+				return
+			}
+			if got := fake.calls["CreatePayment"][0][1].Value; got != tt.wantAmount {
+				t.Errorf("recorded amount = %v, want %s", got, tt.wantAmount)
+			}
+			updates := fake.calls["UpdateInvoiceStatus"]
+			if len(updates) != 1 {
+				t.Fatalf("invoice status updated %d times, want 1", len(updates))
+			}
+			if got := updates[0][2].Value; got != tt.wantStatus {
+				t.Errorf("invoice status = %v, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"worklio-api/internal/db"
+)
+
+// Project statuses
+const (
+	ProjectActive    = "active"
+	ProjectCompleted = "completed"
+	ProjectArchived  = "archived"
+)
+
+var (
+	// ErrProjectNotFound is returned when time is recorded against a project
+	// the user does not own or that belongs to another client
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrTaskNotFound is returned when time is recorded against a task that
+	// does not belong to the project
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrProjectInactive is returned when time is recorded against a project
+	// that is completed or archived
+	ErrProjectInactive = errors.New("project is not active")
+)
+
+// Work is what time is recorded against: a client and, optionally, one of
+// the client's projects and one of the project's tasks
+type Work struct {
+	ClientID  int32
+	ProjectID sql.NullInt32
+	TaskID    sql.NullInt32
+}
+
+// WorkTerms are the terms time recorded against work is captured with
+type WorkTerms struct {
+	HourlyRate sql.NullString
+	// Billable is whether the time is billable unless the entry says
+	// otherwise
+	Billable bool
+}
+
+// ResolveWork checks that the user can record time against work and returns
+// the terms it is recorded with. The hourly rate is the task's when it has
+// one, otherwise the project's, otherwise the client's. Time is billable when
+// the project is, or the client when the project does not say. Time can only
+// be recorded against active projects.
+func ResolveWork(ctx context.Context, q *db.Queries, userID int32, work Work) (WorkTerms, error) {
+	client, err := q.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     work.ClientID,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows {
+		return WorkTerms{}, ErrClientNotFound
+	}
+	if err != nil {
+		return WorkTerms{}, fmt.Errorf("failed to fetch client: %w", err)
+	}
+
+	terms := WorkTerms{HourlyRate: client.HourlyRate, Billable: client.Billable}
+	if !work.ProjectID.Valid {
+		if work.TaskID.Valid {
+			return WorkTerms{}, ErrTaskNotFound
+		}
+		return terms, nil
+	}
+
+	project, err := q.GetProjectByID(ctx, db.GetProjectByIDParams{
+		ID:     work.ProjectID.Int32,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows || (err == nil && project.ClientID != work.ClientID) {
+		return WorkTerms{}, ErrProjectNotFound
+	}
+	if err != nil {
+		return WorkTerms{}, fmt.Errorf("failed to fetch project: %w", err)
+	}
+	if project.Status != ProjectActive {
+		return WorkTerms{}, ErrProjectInactive
+	}
+
+	if project.HourlyRate.Valid {
+		terms.HourlyRate = project.HourlyRate
+	}
+	if project.Billable.Valid {
+		terms.Billable = project.Billable.Bool
+	}
+	if !work.TaskID.Valid {
+		return terms, nil
+	}
+
+	task, err := q.GetTaskByID(ctx, db.GetTaskByIDParams{
+		ID:        work.TaskID.Int32,
+		ProjectID: project.ID,
+	})
+	if err == sql.ErrNoRows {
+		return WorkTerms{}, ErrTaskNotFound
+	}
+	if err != nil {
+		return WorkTerms{}, fmt.Errorf("failed to fetch task: %w", err)
+	}
+	if task.HourlyRate.Valid {
+		terms.HourlyRate = task.HourlyRate
+	}
+	return terms, nil
+}
+
+// IsValidProjectStatus reports whether status is a project status
+func IsValidProjectStatus(status string) bool {
+	switch status {
+	case ProjectActive, ProjectCompleted, ProjectArchived:
+		return true
+	}
+	return false
+}
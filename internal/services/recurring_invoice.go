@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"worklio-api/internal/billing"
+	"worklio-api/internal/db"
+	"worklio-api/internal/models"
+	"worklio-api/internal/money"
+)
+
+var (
+	// ErrRecurringInvoiceNotActive is returned when pausing a recurring
+	// invoice that is not active
+	ErrRecurringInvoiceNotActive = errors.New("recurring invoice is not active")
+	// ErrRecurringInvoiceNotPaused is returned when resuming a recurring
+	// invoice that is not paused
+	ErrRecurringInvoiceNotPaused = errors.New("recurring invoice is not paused")
+)
+
+// RecurringTemplate describes the invoices a recurring invoice issues and
+// their schedule
+type RecurringTemplate struct {
+	ClientID  int32
+	Name      string
+	Frequency string
+	AnchorDay int32
+	StartDate time.Time
+	// EndDate is the last day an invoice may be issued; zero means never
+	EndDate          time.Time
+	PaymentTermsDays int32
+	Notes            string
+	AutoSend         bool
+	LineItems        []models.InvoiceLineItemRequest
+}
+
+// Occurrence is an upcoming invoice of a recurring invoice
+type Occurrence struct {
+	IssueDate time.Time
+	DueDate   time.Time
+}
+
+// RecurringInvoiceService manages recurring invoices and issues their
+// invoices through InvoiceService, optionally emailing them
+type RecurringInvoiceService struct {
+	queries  *db.Queries
+	invoices *InvoiceService
+	delivery *DeliveryService
+	now      func() time.Time
+}
+
+// NewRecurringInvoiceService creates a new recurring invoice service.
+// delivery may be nil when email is not configured, in which case invoices
+// that should be sent automatically are left as drafts.
+func NewRecurringInvoiceService(queries *db.Queries, invoices *InvoiceService, delivery *DeliveryService) *RecurringInvoiceService {
+	return &RecurringInvoiceService{queries: queries, invoices: invoices, delivery: delivery, now: time.Now}
+}
+
+// CreateRecurringInvoice creates an active recurring invoice whose first
+// invoice is issued on the first occurrence from today
+func (s *RecurringInvoiceService) CreateRecurringInvoice(ctx context.Context, userID int32, template RecurringTemplate) (db.RecurringInvoice, error) {
+	if err := s.checkClient(ctx, userID, template.ClientID); err != nil {
+		return db.RecurringInvoice{}, err
+	}
+
+	status, nextRun := nextRunStatus(scheduleOf(template), s.today(), sql.NullTime{})
+
+	var created db.RecurringInvoice
+	err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+		var err error
+		created, err = q.CreateRecurringInvoice(ctx, db.CreateRecurringInvoiceParams{
+			UserID:           userID,
+			ClientID:         template.ClientID,
+			Name:             template.Name,
+			Frequency:        template.Frequency,
+			AnchorDay:        template.AnchorDay,
+			StartDate:        template.StartDate,
+			EndDate:          sql.NullTime{Time: template.EndDate, Valid: !template.EndDate.IsZero()},
+			PaymentTermsDays: template.PaymentTermsDays,
+			Notes:            sql.NullString{String: template.Notes, Valid: template.Notes != ""},
+			AutoSend:         template.AutoSend,
+			Status:           status,
+			NextRunDate:      nextRun,
+		})
+		if err != nil {
+			return err
+		}
+		return saveRecurringLineItems(ctx, q, created.ID, template.LineItems)
+	})
+	return created, err
+}
+
+// UpdateRecurringInvoice replaces the template and schedule of a recurring
+// invoice. The next invoice is issued on the first occurrence from today that
+// has not been invoiced yet. It returns sql.ErrNoRows when the user has no
+// such recurring invoice.
+func (s *RecurringInvoiceService) UpdateRecurringInvoice(ctx context.Context, userID, id int32, template RecurringTemplate) (db.RecurringInvoice, error) {
+	existing, err := s.queries.GetRecurringInvoiceByID(ctx, db.GetRecurringInvoiceByIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return db.RecurringInvoice{}, err
+	}
+
+	if err := s.checkClient(ctx, userID, template.ClientID); err != nil {
+		return db.RecurringInvoice{}, err
+	}
+
+	status, nextRun := nextRunStatus(scheduleOf(template), s.today(), existing.LastRunDate)
+	if existing.Status == "paused" && status == "active" {
+		status = "paused"
+	}
+
+	var updated db.RecurringInvoice
+	err = s.invoices.withTx(ctx, func(q *db.Queries) error {
+		var err error
+		updated, err = q.UpdateRecurringInvoice(ctx, db.UpdateRecurringInvoiceParams{
+			ID:               id,
+			UserID:           userID,
+			ClientID:         template.ClientID,
+			Name:             template.Name,
+			Frequency:        template.Frequency,
+			AnchorDay:        template.AnchorDay,
+			StartDate:        template.StartDate,
+			EndDate:          sql.NullTime{Time: template.EndDate, Valid: !template.EndDate.IsZero()},
+			PaymentTermsDays: template.PaymentTermsDays,
+			Notes:            sql.NullString{String: template.Notes, Valid: template.Notes != ""},
+			AutoSend:         template.AutoSend,
+			Status:           status,
+			NextRunDate:      nextRun,
+		})
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteRecurringInvoiceLineItems(ctx, id); err != nil {
+			return err
+		}
+		return saveRecurringLineItems(ctx, q, id, template.LineItems)
+	})
+	return updated, err
+}
+
+// PauseRecurringInvoice stops a recurring invoice from issuing invoices until
+// it is resumed
+func (s *RecurringInvoiceService) PauseRecurringInvoice(ctx context.Context, userID, id int32) (db.RecurringInvoice, error) {
+	rec, err := s.queries.GetRecurringInvoiceByID(ctx, db.GetRecurringInvoiceByIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return db.RecurringInvoice{}, err
+	}
+	if rec.Status != "active" {
+		return db.RecurringInvoice{}, ErrRecurringInvoiceNotActive
+	}
+
+	return s.queries.UpdateRecurringInvoiceSchedule(ctx, db.UpdateRecurringInvoiceScheduleParams{
+		ID:          id,
+		UserID:      userID,
+		Status:      "paused",
+		NextRunDate: rec.NextRunDate,
+	})
+}
+
+// ResumeRecurringInvoice reactivates a paused recurring invoice. Occurrences
+// missed while it was paused are skipped, not issued.
+func (s *RecurringInvoiceService) ResumeRecurringInvoice(ctx context.Context, userID, id int32) (db.RecurringInvoice, error) {
+	rec, err := s.queries.GetRecurringInvoiceByID(ctx, db.GetRecurringInvoiceByIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return db.RecurringInvoice{}, err
+	}
+	if rec.Status != "paused" {
+		return db.RecurringInvoice{}, ErrRecurringInvoiceNotPaused
+	}
+
+	status, nextRun := nextRunStatus(billing.RecurringSchedule(rec), s.today(), rec.LastRunDate)
+	return s.queries.UpdateRecurringInvoiceSchedule(ctx, db.UpdateRecurringInvoiceScheduleParams{
+		ID:          id,
+		UserID:      userID,
+		Status:      status,
+		NextRunDate: nextRun,
+	})
+}
+
+// PreviewOccurrences returns the next n invoices the recurring invoice will
+// issue. A paused recurring invoice is previewed as if it were resumed today.
+func (s *RecurringInvoiceService) PreviewOccurrences(rec db.RecurringInvoice, n int) []Occurrence {
+	if rec.Status == "completed" {
+		return []Occurrence{}
+	}
+
+	from := s.today()
+	if rec.Status == "active" && rec.NextRunDate.Valid {
+		from = rec.NextRunDate.Time
+	} else if rec.LastRunDate.Valid && !rec.LastRunDate.Time.Before(from) {
+		from = rec.LastRunDate.Time.AddDate(0, 0, 1)
+	}
+
+	dates := billing.RecurringSchedule(rec).Occurrences(from, n)
+	occurrences := make([]Occurrence, len(dates))
+	for i, date := range dates {
+		occurrences[i] = Occurrence{
+			IssueDate: date,
+			DueDate:   date.AddDate(0, 0, int(rec.PaymentTermsDays)),
+		}
+	}
+	return occurrences
+}
+
+// IssueDueInvoices issues an invoice for every occurrence of an active
+// recurring invoice up to today, including occurrences missed while the job
+// was not running, and returns the number of invoices issued
+func (s *RecurringInvoiceService) IssueDueInvoices(ctx context.Context) (int, error) {
+	today := s.today()
+
+	due, err := s.queries.GetDueRecurringInvoices(ctx, today)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch due recurring invoices: %w", err)
+	}
+
+	issued := 0
+	for _, rec := range due {
+		n, err := s.issueInvoices(ctx, rec, today)
+		issued += n
+		if err != nil {
+			log.Printf("Failed to issue invoice for recurring invoice %d: %v", rec.ID, err)
+		}
+	}
+	return issued, nil
+}
+
+// issueInvoices issues the invoices of rec that are due by today
+func (s *RecurringInvoiceService) issueInvoices(ctx context.Context, rec db.RecurringInvoice, today time.Time) (int, error) {
+	lineItems, err := s.queries.GetRecurringInvoiceLineItems(ctx, rec.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	schedule := billing.RecurringSchedule(rec)
+	issued := 0
+	for rec.Status == "active" && rec.NextRunDate.Valid && !rec.NextRunDate.Time.After(today) {
+		issueDate := rec.NextRunDate.Time
+		status, nextRun := nextRunStatus(schedule, issueDate, sql.NullTime{Time: issueDate, Valid: true})
+
+		var invoice db.Invoice
+		err := s.invoices.withTx(ctx, func(q *db.Queries) error {
+			var err error
+			invoice, err = s.invoices.createInvoice(ctx, q, rec.UserID, NewInvoice{
+				ClientID:  rec.ClientID,
+				IssueDate: issueDate,
+				DueDate:   issueDate.AddDate(0, 0, int(rec.PaymentTermsDays)),
+				Status:    "draft",
+				Notes:     rec.Notes.String,
+				LineItems: lineItemRequests(lineItems),
+			})
+			if err != nil {
+				return err
+			}
+			return q.AdvanceRecurringInvoice(ctx, db.AdvanceRecurringInvoiceParams{
+				ID:          rec.ID,
+				Status:      status,
+				NextRunDate: nextRun,
+				LastRunDate: sql.NullTime{Time: issueDate, Valid: true},
+			})
+		})
+		if err != nil {
+			return issued, err
+		}
+		issued++
+		rec.Status = status
+		rec.NextRunDate = nextRun
+		log.Printf("Created draft invoice %d from recurring invoice %d (user %d)", invoice.ID, rec.ID, rec.UserID)
+
+		if rec.AutoSend {
+			s.sendInvoice(ctx, rec, invoice)
+		}
+	}
+	return issued, nil
+}
+
+// sendInvoice emails an invoice issued by a recurring invoice. Failures are
+// logged and leave the invoice as a draft for the user to send.
+func (s *RecurringInvoiceService) sendInvoice(ctx context.Context, rec db.RecurringInvoice, invoice db.Invoice) {
+	if s.delivery == nil {
+		log.Printf("Email service not configured. Invoice %d left as draft", invoice.ID)
+		return
+	}
+	if _, err := s.delivery.SendInvoice(ctx, rec.UserID, invoice.ID, SendOptions{}); err != nil {
+		log.Printf("Failed to send invoice %d: %v", invoice.ID, err)
+	}
+}
+
+func (s *RecurringInvoiceService) checkClient(ctx context.Context, userID, clientID int32) error {
+	_, err := s.queries.GetClientByID(ctx, db.GetClientByIDParams{
+		ID:     clientID,
+		UserID: userID,
+	})
+	if err == sql.ErrNoRows {
+		return ErrClientNotFound
+	}
+	return err
+}
+
+func (s *RecurringInvoiceService) today() time.Time {
+	now := s.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextRunStatus returns the status and next run date of a schedule whose next
+// invoice is due on or after today and after lastRun. A schedule with no
+// further occurrences is completed.
+func nextRunStatus(schedule billing.Schedule, today time.Time, lastRun sql.NullTime) (string, sql.NullTime) {
+	from := today
+	if lastRun.Valid && !lastRun.Time.Before(from) {
+		from = lastRun.Time.AddDate(0, 0, 1)
+	}
+
+	next, ok := schedule.Next(from)
+	if !ok {
+		return "completed", sql.NullTime{}
+	}
+	return "active", sql.NullTime{Time: next, Valid: true}
+}
+
+func scheduleOf(template RecurringTemplate) billing.Schedule {
+	return billing.Schedule{
+		Frequency: template.Frequency,
+		AnchorDay: int(template.AnchorDay),
+		StartDate: template.StartDate,
+		EndDate:   template.EndDate,
+	}
+}
+
+func saveRecurringLineItems(ctx context.Context, q *db.Queries, recurringInvoiceID int32, lineItems []models.InvoiceLineItemRequest) error {
+	for i, item := range lineItems {
+		unit := item.Unit
+		if unit == "" {
+			unit = "unit"
+		}
+
+		_, err := q.CreateRecurringInvoiceLineItem(ctx, db.CreateRecurringInvoiceLineItemParams{
+			RecurringInvoiceID: recurringInvoiceID,
+			Description:        strings.TrimSpace(item.Description),
+			Quantity:           item.Quantity.StringFixed(2),
+			Unit:               unit,
+			UnitPrice:          billing.FormatPrice(item.UnitPrice),
+			TaxRate:            item.TaxRate.StringFixed(2),
+			Position:           int32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineItemRequests converts the line items of a recurring invoice into the
+// line items of an invoice it issues
+func lineItemRequests(lineItems []db.RecurringInvoiceLineItem) []models.InvoiceLineItemRequest {
+	requests := make([]models.InvoiceLineItemRequest, len(lineItems))
+	for i, item := range lineItems {
+		requests[i] = models.InvoiceLineItemRequest{
+			Description: item.Description,
+			Quantity:    money.Parse(item.Quantity),
+			Unit:        item.Unit,
+			UnitPrice:   money.Parse(item.UnitPrice),
+			TaxRate:     money.Parse(item.TaxRate),
+		}
+	}
+	return requests
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"worklio-api/internal/db"
+)
+
+// MaxTimeEntryPeriod is the longest period a single time entry can span
+const MaxTimeEntryPeriod = 24 * time.Hour
+
+// OverlapError is returned when the period of a time entry overlaps other
+// time entries of the user
+type OverlapError struct {
+	Overlaps []db.TimeEntry
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("time entry overlaps %d other time entries", len(e.Overlaps))
+}
+
+// FindOverlaps returns the user's time entries, other than excludeID, whose
+// period overlaps the one from startedAt to endedAt. Entries recorded without
+// a period overlap nothing.
+func FindOverlaps(ctx context.Context, q *db.Queries, userID, excludeID int32, startedAt, endedAt sql.NullTime) ([]db.TimeEntry, error) {
+	if !startedAt.Valid {
+		return nil, nil
+	}
+	return q.GetOverlappingTimeEntries(ctx, db.GetOverlappingTimeEntriesParams{
+		UserID:    userID,
+		ExcludeID: excludeID,
+		EndedAt:   endedAt,
+		StartedAt: startedAt,
+	})
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"worklio-api/internal/db"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// Timer statuses
+const (
+	TimerRunning = "running"
+	TimerPaused  = "paused"
+	TimerStopped = "stopped"
+)
+
+var (
+	// ErrTimerActive is returned when starting a timer while the user already
+	// has one running or paused
+	ErrTimerActive = errors.New("a timer is already running")
+	// ErrNoActiveTimer is returned when the user has no running or paused
+	// timer
+	ErrNoActiveTimer = errors.New("no timer is running")
+	// ErrTimerNotRunning is returned when pausing a timer that is paused
+	ErrTimerNotRunning = errors.New("timer is not running")
+	// ErrTimerNotPaused is returned when resuming a timer that is running
+	ErrTimerNotPaused = errors.New("timer is not paused")
+	// ErrTimerTooShort is returned when stopping a timer that has run for
+	// less than the smallest number of hours a time entry can record
+	ErrTimerTooShort = errors.New("timer has not run long enough to record")
+	// ErrTimerTooLong is returned when stopping a timer started longer ago
+	// than a time entry can span
+	ErrTimerTooLong = errors.New("timer was started more than 24 hours ago")
+)
+
+// TimerService runs live timers and converts stopped timers into time
+// entries. Timers are kept in the database, so they keep counting while the
+// server restarts.
+type TimerService struct {
+	database *sql.DB
+	queries  *db.Queries
+	budgets  *BudgetService
+	now      func() time.Time
+}
+
+// NewTimerService creates a new timer service. Stopped timers are checked
+// against the budgets of their project with budgets.
+func NewTimerService(database *sql.DB, queries *db.Queries, budgets *BudgetService) *TimerService {
+	return &TimerService{database: database, queries: queries, budgets: budgets, now: time.Now}
+}
+
+// Current returns the user's running or paused timer, or ErrNoActiveTimer
+func (s *TimerService) Current(ctx context.Context, userID int32) (db.Timer, error) {
+	timer, err := s.queries.GetActiveTimer(ctx, userID)
+	if err == sql.ErrNoRows {
+		return db.Timer{}, ErrNoActiveTimer
+	}
+	return timer, err
+}
+
+// Start starts a timer for work on a client, project or task. A user can only
+// have one timer running or paused at a time.
+func (s *TimerService) Start(ctx context.Context, userID int32, work Work, description string) (db.Timer, error) {
+	if _, err := ResolveWork(ctx, s.queries, userID, work); err != nil {
+		return db.Timer{}, err
+	}
+
+	timer, err := s.queries.CreateTimer(ctx, db.CreateTimerParams{
+		UserID:      userID,
+		ClientID:    work.ClientID,
+		ProjectID:   work.ProjectID,
+		TaskID:      work.TaskID,
+		Description: sql.NullString{String: description, Valid: description != ""},
+		StartedAt:   s.now().UTC(),
+	})
+	if err != nil && isTimerActive(err) {
+		return db.Timer{}, ErrTimerActive
+	}
+	return timer, err
+}
+
+// Pause pauses the user's running timer. The time it has run so far is kept
+// and counting continues when it is resumed.
+func (s *TimerService) Pause(ctx context.Context, userID int32) (db.Timer, error) {
+	timer, err := s.Current(ctx, userID)
+	if err != nil {
+		return db.Timer{}, err
+	}
+	if timer.Status != TimerRunning {
+		return db.Timer{}, ErrTimerNotRunning
+	}
+
+	paused, err := s.queries.PauseTimer(ctx, db.PauseTimerParams{
+		ID:             timer.ID,
+		UserID:         userID,
+		ElapsedSeconds: s.Elapsed(timer),
+	})
+	if err == sql.ErrNoRows {
+		// Paused or stopped by another request in the meantime
+		return db.Timer{}, ErrTimerNotRunning
+	}
+	return paused, err
+}
+
+// Resume resumes the user's paused timer
+func (s *TimerService) Resume(ctx context.Context, userID int32) (db.Timer, error) {
+	timer, err := s.Current(ctx, userID)
+	if err != nil {
+		return db.Timer{}, err
+	}
+	if timer.Status != TimerPaused {
+		return db.Timer{}, ErrTimerNotPaused
+	}
+
+	resumed, err := s.queries.ResumeTimer(ctx, db.ResumeTimerParams{
+		ID:        timer.ID,
+		UserID:    userID,
+		ResumedAt: sql.NullTime{Time: s.now().UTC(), Valid: true},
+	})
+	if err == sql.ErrNoRows {
+		return db.Timer{}, ErrTimerNotPaused
+	}
+	return resumed, err
+}
+
+// Stop stops the user's running or paused timer and records the time it ran
+// as a time entry, dated the day it was started in the user's timezone and
+// billed at the current hourly rate of its task, project or client, in a
+// single transaction. The entry is billable when its project or client is.
+// Its period runs from the start to the stop of the timer, and the time the
+// timer was paused is recorded on it and left out of its hours. Like any
+// other time entry, it is rejected with an OverlapError when its period
+// overlaps other entries of the user, unless allowOverlap is set, and cannot
+// span more than MaxTimeEntryPeriod: a timer started longer ago returns
+// ErrTimerTooLong and must be discarded.
+func (s *TimerService) Stop(ctx context.Context, userID int32, allowOverlap bool) (db.Timer, db.CreateTimeEntryRow, error) {
+	var stopped db.Timer
+	var entry db.CreateTimeEntryRow
+	err := s.withTx(ctx, func(q *db.Queries) error {
+		timer, err := q.GetActiveTimer(ctx, userID)
+		if err == sql.ErrNoRows {
+			return ErrNoActiveTimer
+		}
+		if err != nil {
+			return err
+		}
+
+		now := s.now().UTC()
+		if now.Sub(timer.StartedAt) > MaxTimeEntryPeriod {
+			return ErrTimerTooLong
+		}
+		seconds := elapsedAt(timer, now)
+		hours := ElapsedHours(seconds)
+		if !hours.IsPositive() {
+			return ErrTimerTooShort
+		}
+
+		terms, err := ResolveWork(ctx, q, userID, Work{
+			ClientID:  timer.ClientID,
+			ProjectID: timer.ProjectID,
+			TaskID:    timer.TaskID,
+		})
+		if err != nil {
+			return err
+		}
+
+		startedAt := sql.NullTime{Time: timer.StartedAt, Valid: true}
+		endedAt := sql.NullTime{Time: now, Valid: true}
+		overlaps, err := FindOverlaps(ctx, q, userID, 0, startedAt, endedAt)
+		if err != nil {
+			return fmt.Errorf("failed to check for overlapping time entries: %w", err)
+		}
+		if len(overlaps) > 0 && !allowOverlap {
+			return &OverlapError{Overlaps: overlaps}
+		}
+
+		location, err := userLocation(ctx, q, userID)
+		if err != nil {
+			return err
+		}
+		started := timer.StartedAt.In(location)
+
+		paused := int32(now.Sub(timer.StartedAt)/time.Second) - seconds
+		if paused < 0 {
+			paused = 0
+		}
+
+		entry, err = q.CreateTimeEntry(ctx, db.CreateTimeEntryParams{
+			UserID:        userID,
+			ClientID:      timer.ClientID,
+			ProjectID:     timer.ProjectID,
+			TaskID:        timer.TaskID,
+			Date:          time.Date(started.Year(), started.Month(), started.Day(), 0, 0, 0, 0, time.UTC),
+			Hours:         hours.StringFixed(2),
+			Description:   timer.Description,
+			HourlyRate:    terms.HourlyRate,
+			Billable:      terms.Billable,
+			StartedAt:     startedAt,
+			EndedAt:       endedAt,
+			PausedSeconds: paused,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create time entry: %w", err)
+		}
+
+		stopped, err = q.StopTimer(ctx, db.StopTimerParams{
+			ID:             timer.ID,
+			UserID:         userID,
+			ElapsedSeconds: seconds,
+			StoppedAt:      sql.NullTime{Time: now, Valid: true},
+			TimeEntryID:    sql.NullInt32{Int32: entry.ID, Valid: true},
+		})
+		if err == sql.ErrNoRows {
+			// Stopped or discarded by another request in the meantime
+			return ErrNoActiveTimer
+		}
+		return err
+	})
+	if err != nil {
+		return stopped, entry, err
+	}
+
+	// The time entry is recorded either way, so a failed check is only logged
+	if err := s.budgets.CheckProject(ctx, userID, entry.ProjectID); err != nil {
+		log.Printf("Failed to check budget of project %d: %v", entry.ProjectID.Int32, err)
+	}
+	return stopped, entry, nil
+}
+
+// Discard deletes the user's running or paused timer without recording the
+// time it ran
+func (s *TimerService) Discard(ctx context.Context, userID int32) error {
+	_, err := s.queries.DeleteActiveTimer(ctx, userID)
+	if err == sql.ErrNoRows {
+		return ErrNoActiveTimer
+	}
+	return err
+}
+
+// Elapsed returns how many seconds the timer has run, including the current
+// run when it is running
+func (s *TimerService) Elapsed(timer db.Timer) int32 {
+	return elapsedAt(timer, s.now())
+}
+
+// ElapsedHours converts seconds of work into the hours recorded on a time
+// entry, to the nearest hundredth of an hour
+func ElapsedHours(seconds int32) decimal.Decimal {
+	return decimal.NewFromInt(int64(seconds)).Div(decimal.NewFromInt(3600)).Round(2)
+}
+
+func elapsedAt(timer db.Timer, now time.Time) int32 {
+	seconds := timer.ElapsedSeconds
+	if timer.Status == TimerRunning && timer.ResumedAt.Valid {
+		if run := now.Sub(timer.ResumedAt.Time); run > 0 {
+			seconds += int32(run / time.Second)
+		}
+	}
+	return seconds
+}
+
+// userLocation returns the timezone the user works in. A timezone the runtime
+// does not know falls back to UTC.
+func userLocation(ctx context.Context, q *db.Queries, userID int32) (*time.Location, error) {
+	timezone, err := q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timezone: %w", err)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("Unknown timezone %q of user %d, using UTC", timezone, userID)
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
+// withTx runs fn in a transaction, committing it when fn succeeds
+func (s *TimerService) withTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(s.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isTimerActive reports whether err is the database rejecting a second
+// running or paused timer for the user
+func isTimerActive(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Constraint == "timers_user_active_unique"
+}
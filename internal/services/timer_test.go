@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"worklio-api/internal/db"
+)
+
+// timerFixtures returns the rows for a user working in timezone, with a timer
+// on a client billed at 90 an hour
+func timerFixtures(timer db.Timer, timezone string) map[string]func([]driver.NamedValue) []any {
+	return map[string]func([]driver.NamedValue) []any{
+		"GetActiveTimer":  rows(timer),
+		"GetUserTimezone": rows(struct{ Timezone string }{timezone}),
+		"GetClientByID": rows(db.GetClientByIDRow{
+			ID:         timer.ClientID,
+			UserID:     timer.UserID,
+			Name:       "Acme",
+			Currency:   "EUR",
+			HourlyRate: sql.NullString{String: "90", Valid: true},
+			Billable:   true,
+		}),
+		"CreateTimeEntry": rows(db.CreateTimeEntryRow{ID: 9, UserID: timer.UserID, ClientID: timer.ClientID}),
+		"StopTimer":       rows(db.Timer{ID: timer.ID, UserID: timer.UserID, ClientID: timer.ClientID, Status: TimerStopped}),
+	}
+}
+
+func TestTimerStop(t *testing.T) {
+	// Started at half past midnight in Paris, the previous day in UTC
+	started := time.Date(2025, 4, 6, 22, 30, 0, 0, time.UTC)
+	stopped := started.Add(90 * time.Minute)
+
+	tests := []struct {
+		name       string
+		timer      db.Timer
+		timezone   string
+		wantDate   time.Time
+		wantHours  string
+		wantPaused int64
+	}{
+		{
+			name: "running without pauses",
+			timer: db.Timer{
+				Status:    TimerRunning,
+				StartedAt: started,
+				ResumedAt: sql.NullTime{Time: started, Valid: true},
+			},
+			timezone:  "Europe/Paris",
+			wantDate:  time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC),
+			wantHours: "1.50",
+		},
+		{
+			name: "resumed after a pause",
+			timer: db.Timer{
+				Status:         TimerRunning,
+				StartedAt:      started,
+				ResumedAt:      sql.NullTime{Time: stopped.Add(-30 * time.Minute), Valid: true},
+				ElapsedSeconds: 1800,
+			},
+			timezone:   "Europe/Paris",
+			wantDate:   time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC),
+			wantHours:  "1.00",
+			wantPaused: 1800,
+		},
+		{
+			name: "paused",
+			timer: db.Timer{
+				Status:         TimerPaused,
+				StartedAt:      started,
+				ElapsedSeconds: 2700,
+			},
+			timezone:   "America/New_York",
+			wantDate:   time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC),
+			wantHours:  "0.75",
+			wantPaused: 2700,
+		},
+		{
+			name: "unknown timezone",
+			timer: db.Timer{
+				Status:    TimerRunning,
+				StartedAt: started,
+				ResumedAt: sql.NullTime{Time: started, Valid: true},
+			},
+			timezone:  "Mars/Olympus_Mons",
+			wantDate:  time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC),
+			wantHours: "1.50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.timer.ID, tt.timer.UserID, tt.timer.ClientID = 3, 1, 5
+			database, fake := openFakeSQL(t, timerFixtures(tt.timer, tt.timezone))
+			queries := db.New(database)
+			service := NewTimerService(database, queries, NewBudgetService(queries, nil))
+			service.now = func() time.Time { return stopped }
+
+			_, entry, err := service.Stop(context.Background(), 1, false)
+			if err != nil {
+				t.Fatalf("Stop: %v", err)
+			}
+			if entry.ID != 9 {
+				t.Errorf("returned time entry %d, want 9", entry.ID)
+			}
+
+			created := fake.calls["CreateTimeEntry"]
+			if len(created) != 1 {
+				t.Fatalf("created %d time entries, want 1", len(created))
+			}
+			args := created[0]
+			if date := args[4].Value.(time.Time); !date.Equal(tt.wantDate) {
+				t.Errorf("dated %s, want %s", date, tt.wantDate)
+			}
+			if args[5].Value != tt.wantHours {
+				t.Errorf("recorded %v hours, want %s", args[5].Value, tt.wantHours)
+			}
+			if args[8].Value != true || args[7].Value != "90" {
+				t.Errorf("recorded billable %v at %v", args[8].Value, args[7].Value)
+			}
+
+			// The period less the paused time is the time the timer ran
+			start, end := args[9].Value.(time.Time), args[10].Value.(time.Time)
+			if !start.Equal(started) || !end.Equal(stopped) {
+				t.Errorf("recorded period %s to %s", start, end)
+			}
+			if args[11].Value != tt.wantPaused {
+				t.Errorf("recorded %v paused seconds, want %d", args[11].Value, tt.wantPaused)
+			}
+
+			stops := fake.calls["StopTimer"]
+			if len(stops) != 1 || stops[0][4].Value != int64(9) {
+				t.Errorf("timer not stopped with its time entry: %v", stops)
+			}
+		})
+	}
+}
+
+func TestTimerStopOverlap(t *testing.T) {
+	started := time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC)
+	stopped := started.Add(2 * time.Hour)
+	timer := db.Timer{
+		ID:        3,
+		UserID:    1,
+		ClientID:  5,
+		Status:    TimerRunning,
+		StartedAt: started,
+		ResumedAt: sql.NullTime{Time: started, Valid: true},
+	}
+	overlapping := db.TimeEntry{
+		ID:        7,
+		UserID:    1,
+		ClientID:  5,
+		Date:      time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC),
+		Hours:     "1.00",
+		StartedAt: sql.NullTime{Time: started.Add(30 * time.Minute), Valid: true},
+		EndedAt:   sql.NullTime{Time: started.Add(90 * time.Minute), Valid: true},
+	}
+
+	for name, allowOverlap := range map[string]bool{"rejected": false, "allowed": true} {
+		t.Run(name, func(t *testing.T) {
+			fixtures := timerFixtures(timer, "UTC")
+			fixtures["GetOverlappingTimeEntries"] = rows(overlapping)
+			database, fake := openFakeSQL(t, fixtures)
+			queries := db.New(database)
+			service := NewTimerService(database, queries, NewBudgetService(queries, nil))
+			service.now = func() time.Time { return stopped }
+
+			_, _, err := service.Stop(context.Background(), 1, allowOverlap)
+
+			// The period of the timer is checked like any other time entry's
+			checks := fake.calls["GetOverlappingTimeEntries"]
+			if len(checks) != 1 {
+				t.Fatalf("checked for overlaps %d times, want 1", len(checks))
+			}
+			args := checks[0]
+			if args[0].Value != int64(1) || args[1].Value != int64(0) {
+				t.Errorf("checked overlaps for user %v excluding %v", args[0].Value, args[1].Value)
+			}
+			if end, start := args[2].Value.(time.Time), args[3].Value.(time.Time); !start.Equal(started) || !end.Equal(stopped) {
+				t.Errorf("checked overlaps from %s to %s", start, end)
+			}
+
+			created := len(fake.calls["CreateTimeEntry"])
+			stops := len(fake.calls["StopTimer"])
+			if allowOverlap {
+				if err != nil {
+					t.Fatalf("Stop with allowOverlap: %v", err)
+				}
+				if created != 1 || stops != 1 {
+					t.Errorf("created %d time entries and stopped %d timers, want 1 each", created, stops)
+				}
+				return
+			}
+
+			var overlapErr *OverlapError
+			if !errors.As(err, &overlapErr) {
+				t.Fatalf("Stop returned %v, want an OverlapError", err)
+			}
+			if len(overlapErr.Overlaps) != 1 || overlapErr.Overlaps[0].ID != 7 {
+				t.Errorf("Stop reported overlaps %v, want time entry 7", overlapErr.Overlaps)
+			}
+			if created != 0 || stops != 0 {
+				t.Errorf("created %d time entries and stopped %d timers, want none", created, stops)
+			}
+		})
+	}
+}
+
+func TestTimerStopTooLong(t *testing.T) {
+	started := time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC)
+	timer := db.Timer{
+		ID:             3,
+		UserID:         1,
+		ClientID:       5,
+		Status:         TimerPaused,
+		StartedAt:      started,
+		ElapsedSeconds: 3600,
+	}
+
+	tests := []struct {
+		name    string
+		stopped time.Time
+		wantErr error
+	}{
+		{name: "exactly a day", stopped: started.Add(MaxTimeEntryPeriod)},
+		{name: "over a day", stopped: started.Add(MaxTimeEntryPeriod + time.Second), wantErr: ErrTimerTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database, fake := openFakeSQL(t, timerFixtures(timer, "UTC"))
+			queries := db.New(database)
+			service := NewTimerService(database, queries, NewBudgetService(queries, nil))
+			service.now = func() time.Time { return tt.stopped }
+
+			_, _, err := service.Stop(context.Background(), 1, false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Stop returned %v, want %v", err, tt.wantErr)
+			}
+			if created := len(fake.calls["CreateTimeEntry"]); (created == 1) != (tt.wantErr == nil) {
+				t.Errorf("created %d time entries", created)
+			}
+		})
+	}
+}
@@ -2,66 +2,73 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"worklio-api/internal/iso4217"
+	"worklio-api/internal/money"
+
+	"github.com/shopspring/decimal"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
 
-var currencySymbols = map[string]string{
-	"USD": "$",
-	"EUR": "€",
-	"GBP": "£",
-	"JPY": "¥",
-	"AUD": "A$",
-	"CAD": "C$",
-	"CHF": "CHF",
-	"CNY": "¥",
-	"SEK": "kr",
-	"NZD": "NZ$",
-	"IDR": "Rp",
-	"SGD": "S$",
-	"INR": "₹",
-}
-
-// GetCurrencySymbol returns the symbol for a given currency code
+// GetCurrencySymbol returns the symbol for a given currency code, or the
+// code itself for currencies without one
 func GetCurrencySymbol(currency string) string {
-	if symbol, ok := currencySymbols[currency]; ok {
-		return symbol
-	}
-	return "$" // Default to USD
+	return iso4217.Symbol(currency)
 }
 
-// FormatCurrency formats an amount with currency symbol and thousand separators
-func FormatCurrency(amount float64, currency string) string {
+// FormatCurrency formats an amount with currency symbol and thousand separators,
+// using the currency's number of minor units
+func FormatCurrency(amount decimal.Decimal, currency string) string {
 	symbol := GetCurrencySymbol(currency)
-	p := message.NewPrinter(language.English)
-	return fmt.Sprintf("%s%s", symbol, p.Sprintf("%.2f", amount))
+	return fmt.Sprintf("%s%s", symbol, FormatNumber(amount, iso4217.MinorUnits(currency)))
 }
 
-// FormatCurrencyRate formats an hourly rate with currency symbol
-func FormatCurrencyRate(rate float64, currency string) string {
+// FormatCurrencyRate formats an hourly rate with currency symbol. Whole rates
+// are shown without decimals.
+func FormatCurrencyRate(rate decimal.Decimal, currency string) string {
 	symbol := GetCurrencySymbol(currency)
-	p := message.NewPrinter(language.English)
-	return fmt.Sprintf("%s%s", symbol, p.Sprintf("%.0f", rate))
+	return fmt.Sprintf("%s%s", symbol, FormatNumber(rate, rateDecimals(rate, currency)))
 }
 
-// FormatNumber formats a number with thousand separators
-func FormatNumber(num float64, decimals int) string {
+// FormatNumber formats a number rounded to decimals with thousand separators
+func FormatNumber(num decimal.Decimal, decimals int) string {
+	formatted := money.FormatTo(num, int32(decimals))
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(formatted, ".")
+
 	p := message.NewPrinter(language.English)
-	format := fmt.Sprintf("%%.%df", decimals)
-	return p.Sprintf(format, num)
+	wholeNum, _ := strconv.ParseInt(whole, 10, 64)
+	grouped := p.Sprintf("%d", wholeNum)
+	if hasFraction {
+		return sign + grouped + "." + fraction
+	}
+	return sign + grouped
 }
 
 // FormatCurrencyForPDF formats an amount with ASCII-safe currency code for PDF generation
 // Uses currency codes (EUR, GBP, USD) instead of symbols to avoid UTF-8 issues in PDFs
-func FormatCurrencyForPDF(amount float64, currency string) string {
-	p := message.NewPrinter(language.English)
-	formattedAmount := p.Sprintf("%.2f", amount)
+func FormatCurrencyForPDF(amount decimal.Decimal, currency string) string {
+	formattedAmount := FormatNumber(amount, iso4217.MinorUnits(currency))
 	return fmt.Sprintf("%s %s", currency, formattedAmount)
 }
 
 // FormatCurrencyRateForPDF formats an hourly rate with ASCII-safe currency code for PDF generation
-func FormatCurrencyRateForPDF(rate float64, currency string) string {
-	p := message.NewPrinter(language.English)
-	formattedRate := p.Sprintf("%.0f", rate)
+func FormatCurrencyRateForPDF(rate decimal.Decimal, currency string) string {
+	formattedRate := FormatNumber(rate, rateDecimals(rate, currency))
 	return fmt.Sprintf("%s %s", currency, formattedRate)
 }
+
+// rateDecimals returns the decimals a rate is shown with: none for whole
+// rates, the currency's minor units otherwise
+func rateDecimals(rate decimal.Decimal, currency string) int {
+	if money.Round(rate, currency).IsInteger() {
+		return 0
+	}
+	return iso4217.MinorUnits(currency)
+}
@@ -23,11 +23,16 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"strings"
 	"time"
+	// Timezone data for users' timezones, which the runtime image lacks
+	_ "time/tzdata"
 	"worklio-api/internal/db"
 	"worklio-api/internal/email"
 	"worklio-api/internal/handlers"
 	appMiddleware "worklio-api/internal/middleware"
+	"worklio-api/internal/money"
+	"worklio-api/internal/rates"
 	"worklio-api/internal/services"
 	"worklio-api/pkg/config"
 
@@ -47,6 +52,13 @@ func main() {
 		log.Fatal("Failed to load configuration:", err)
 	}
 
+	// Set how money amounts are rounded before anything is computed
+	rounding, err := money.ParseRounding(cfg.MoneyRounding)
+	if err != nil {
+		log.Fatal("Invalid MONEY_ROUNDING:", err)
+	}
+	money.SetRounding(rounding)
+
 	// Connect to database
 	database, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
@@ -68,13 +80,15 @@ func main() {
 
 	// Initialize email service
 	var emailService *email.Service
-	if cfg.SMTPHost != "" && cfg.SMTPUsername != "" && cfg.SMTPPassword != "" {
+	// Credentials are optional with SMTP_SECURITY=none so a local SMTP stand-in can be used
+	if cfg.SMTPHost != "" && ((cfg.SMTPUsername != "" && cfg.SMTPPassword != "") || cfg.SMTPSecurity == email.SecurityNone) {
 		var err error
 		emailService, err = email.NewService(
 			cfg.SMTPHost,
 			cfg.SMTPPort,
 			cfg.SMTPUsername,
 			cfg.SMTPPassword,
+			cfg.SMTPSecurity,
 			cfg.SenderEmail,
 			cfg.SenderName,
 			cfg.AppURL,
@@ -90,9 +104,40 @@ func main() {
 	}
 
 	// Initialize exchange rate service
-	exchangeRateService := services.NewExchangeRateService(queries)
+	rateProvider, err := rates.New(strings.Split(cfg.RateProviders, ","), rates.Options{
+		OpenExchangeRatesAppID: cfg.OpenExchangeAppID,
+		FixturePath:            cfg.RateFixturePath,
+	})
+	if err != nil {
+		log.Fatal("Failed to configure exchange rate providers:", err)
+	}
+	log.Printf("Exchange rates provided by %s", rateProvider.Name())
+	rateMaxAge, err := time.ParseDuration(cfg.RateMaxAge)
+	if err != nil {
+		log.Fatal("Invalid EXCHANGE_RATE_MAX_AGE:", err)
+	}
+	exchangeRateService := services.NewExchangeRateService(queries, rateProvider, rateMaxAge)
+
+	// Initialize invoice services. Invoices can only be emailed when email is configured.
+	invoiceService := services.NewInvoiceService(database, queries, exchangeRateService)
+	var deliveryService *services.DeliveryService
+	if emailService != nil {
+		deliveryService = services.NewDeliveryService(queries, invoiceService, emailService)
+	}
+	recurringInvoiceService := services.NewRecurringInvoiceService(queries, invoiceService, deliveryService)
+	// Budget alerts are only shown in the app when email is not configured
+	var budgetService *services.BudgetService
+	if emailService != nil {
+		budgetService = services.NewBudgetService(queries, emailService)
+	} else {
+		budgetService = services.NewBudgetService(queries, nil)
+	}
+	timerService := services.NewTimerService(database, queries, budgetService)
+	creditNoteService := services.NewCreditNoteService(queries, invoiceService)
+	paymentService := services.NewPaymentService(invoiceService)
+	estimateService := services.NewEstimateService(queries, invoiceService)
 
-	// Initialize and start cron scheduler for exchange rates
+	// Initialize and start cron scheduler for background jobs
 	scheduler, err := gocron.NewScheduler()
 	if err != nil {
 		log.Fatal("Failed to create scheduler:", err)
@@ -117,9 +162,94 @@ func main() {
 		log.Fatal("Failed to schedule exchange rate job:", err)
 	}
 
+	// Schedule overdue detection daily at 1 AM, after invoices fall due at midnight
+	overdueService := services.NewOverdueService(queries, time.Now)
+	_, err = scheduler.NewJob(
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(1, 0, 0))),
+		gocron.NewTask(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			invoices, err := overdueService.MarkOverdueInvoices(ctx)
+			if err != nil {
+				log.Printf("Error marking overdue invoices: %v", err)
+			} else {
+				log.Printf("Marked %d invoices as overdue", len(invoices))
+			}
+		}),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		log.Fatal("Failed to schedule overdue invoice job:", err)
+	}
+
+	// Schedule estimate expiry daily at 1 AM, after estimates stop being valid at midnight
+	_, err = scheduler.NewJob(
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(1, 0, 0))),
+		gocron.NewTask(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			estimates, err := estimateService.ExpireEstimates(ctx)
+			if err != nil {
+				log.Printf("Error expiring estimates: %v", err)
+			} else {
+				log.Printf("Expired %d estimates", len(estimates))
+			}
+		}),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		log.Fatal("Failed to schedule estimate expiry job:", err)
+	}
+
+	// Schedule recurring invoices daily at 6 AM, catching up on missed days at startup
+	_, err = scheduler.NewJob(
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(6, 0, 0))),
+		gocron.NewTask(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+			defer cancel()
+
+			issued, err := recurringInvoiceService.IssueDueInvoices(ctx)
+			if err != nil {
+				log.Printf("Error issuing recurring invoices: %v", err)
+			} else {
+				log.Printf("Issued %d recurring invoices", issued)
+			}
+		}),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		log.Fatal("Failed to schedule recurring invoice job:", err)
+	}
+
+	// Schedule payment reminders daily at 9 AM, once overdue invoices are marked
+	if emailService != nil {
+		dunningService := services.NewDunningService(queries, emailService)
+		_, err = scheduler.NewJob(
+			gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(9, 0, 0))),
+			gocron.NewTask(func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+				defer cancel()
+
+				sent, err := dunningService.SendDueReminders(ctx)
+				if err != nil {
+					log.Printf("Error sending payment reminders: %v", err)
+				} else {
+					log.Printf("Sent %d payment reminders", sent)
+				}
+			}),
+		)
+		if err != nil {
+			log.Fatal("Failed to schedule payment reminder job:", err)
+		}
+	} else {
+		log.Println("Email service not configured. Payment reminders disabled.")
+	}
+
 	// Start the scheduler
 	scheduler.Start()
-	log.Println("Exchange rate scheduler started (runs daily at 2 AM)")
+	log.Println("Scheduler started (exchange rates daily at 2 AM, overdue invoices and expired estimates daily at 1 AM, recurring invoices daily at 6 AM, payment reminders daily at 9 AM)")
 
 	// Run initial update on startup
 	go func() {
@@ -132,6 +262,18 @@ func main() {
 		} else {
 			log.Println("Initial exchange rates loaded successfully")
 		}
+
+		if cfg.RateBackfillFrom == "" {
+			return
+		}
+		from, err := time.Parse("2006-01-02", cfg.RateBackfillFrom)
+		if err != nil {
+			log.Printf("Warning: Invalid EXCHANGE_RATE_BACKFILL_FROM %q: %v", cfg.RateBackfillFrom, err)
+			return
+		}
+		if _, err := exchangeRateService.BackfillRates(ctx, from, time.Now()); err != nil {
+			log.Printf("Warning: Exchange rate backfill failed: %v", err)
+		}
 	}()
 
 	// Create Echo instance
@@ -149,11 +291,23 @@ func main() {
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(queries, cfg.JWTSecret, emailService)
 	clientHandler := handlers.NewClientHandler(queries)
-	timeEntryHandler := handlers.NewTimeEntryHandler(queries, exchangeRateService)
-	invoiceHandler := handlers.NewInvoiceHandler(queries)
+	projectHandler := handlers.NewProjectHandler(queries, budgetService)
+	timeEntryHandler := handlers.NewTimeEntryHandler(queries, exchangeRateService, budgetService)
+	timerHandler := handlers.NewTimerHandler(timerService)
+	invoiceHandler := handlers.NewInvoiceHandler(queries, invoiceService)
+	invoiceDeliveryHandler := handlers.NewInvoiceDeliveryHandler(queries, deliveryService)
+	taxRateHandler := handlers.NewTaxRateHandler(queries)
+	exchangeRateOverrideHandler := handlers.NewExchangeRateOverrideHandler(queries)
+	paymentHandler := handlers.NewPaymentHandler(queries, paymentService)
+	reminderHandler := handlers.NewReminderHandler(queries)
+	notificationHandler := handlers.NewNotificationHandler(queries)
+	recurringInvoiceHandler := handlers.NewRecurringInvoiceHandler(queries, recurringInvoiceService)
+	creditNoteHandler := handlers.NewCreditNoteHandler(queries, creditNoteService)
+	estimateHandler := handlers.NewEstimateHandler(queries, estimateService)
 	demoHandler := handlers.NewDemoHandler(queries)
 	currencyHandler := handlers.NewCurrencyHandler(exchangeRateService)
 	statsHandler := handlers.NewStatsHandler(queries, exchangeRateService)
+	healthHandler := handlers.NewHealthHandler(exchangeRateService)
 
 	// Routes
 	api := e.Group("/api")
@@ -161,6 +315,7 @@ func main() {
 	// Public routes
 	api.GET("/supported-currencies", currencyHandler.GetSupportedCurrencies)
 	api.GET("/convert-currency", currencyHandler.ConvertCurrency)
+	api.GET("/exchange-rates/history", currencyHandler.GetExchangeRateHistory)
 
 	// Auth routes (public)
 	auth := api.Group("/auth")
@@ -182,6 +337,10 @@ func main() {
 		protected.POST("/users/complete-tour", authHandler.CompleteTour)
 		protected.POST("/users/change-password", authHandler.ChangePassword)
 		protected.POST("/users/currency", authHandler.UpdateCurrency)
+		protected.GET("/users/billing-profile", authHandler.GetBillingProfile)
+		protected.PUT("/users/billing-profile", authHandler.UpdateBillingProfile)
+		protected.GET("/users/timezone", authHandler.GetTimezone)
+		protected.PUT("/users/timezone", authHandler.UpdateTimezone)
 
 		// Auth routes (protected)
 		protected.POST("/auth/resend-verification", authHandler.ResendVerificationEmail)
@@ -193,6 +352,18 @@ func main() {
 		protected.PUT("/clients/:id", clientHandler.UpdateClient)
 		protected.DELETE("/clients/:id", clientHandler.DeleteClient)
 
+		// Project routes
+		protected.POST("/projects", projectHandler.CreateProject)
+		protected.GET("/projects", projectHandler.GetProjects)
+		protected.GET("/projects/:id", projectHandler.GetProject)
+		protected.GET("/projects/:id/budget", projectHandler.GetProjectBudget)
+		protected.PUT("/projects/:id", projectHandler.UpdateProject)
+		protected.DELETE("/projects/:id", projectHandler.DeleteProject)
+		protected.POST("/projects/:id/tasks", projectHandler.CreateTask)
+		protected.GET("/projects/:id/tasks", projectHandler.GetTasks)
+		protected.PUT("/projects/:id/tasks/:taskId", projectHandler.UpdateTask)
+		protected.DELETE("/projects/:id/tasks/:taskId", projectHandler.DeleteTask)
+
 		// Time entry routes
 		protected.POST("/time-entries", timeEntryHandler.CreateTimeEntry)
 		protected.GET("/time-entries", timeEntryHandler.GetTimeEntries)
@@ -202,15 +373,93 @@ func main() {
 		protected.PUT("/time-entries/:id", timeEntryHandler.UpdateTimeEntry)
 		protected.DELETE("/time-entries/:id", timeEntryHandler.DeleteTimeEntry)
 
+		// Timer routes
+		protected.GET("/timers/current", timerHandler.GetCurrentTimer)
+		protected.POST("/timers/start", timerHandler.StartTimer)
+		protected.POST("/timers/pause", timerHandler.PauseTimer)
+		protected.POST("/timers/resume", timerHandler.ResumeTimer)
+		protected.POST("/timers/stop", timerHandler.StopTimer)
+		protected.DELETE("/timers/current", timerHandler.DiscardTimer)
+
 		// Invoice routes
 		protected.POST("/invoices", invoiceHandler.CreateInvoice)
 		protected.GET("/invoices", invoiceHandler.GetInvoices)
 		protected.GET("/invoices/available-time-entries", invoiceHandler.GetAvailableTimeEntries)
+		protected.GET("/invoices/next-number", invoiceHandler.GetNextInvoiceNumber)
 		protected.GET("/invoices/:id", invoiceHandler.GetInvoice)
 		protected.GET("/invoices/:id/pdf", invoiceHandler.DownloadInvoicePDF)
+		protected.GET("/invoices/:id/ubl", invoiceHandler.DownloadInvoiceUBL)
 		protected.PUT("/invoices/:id", invoiceHandler.UpdateInvoice)
 		protected.PATCH("/invoices/:id/status", invoiceHandler.UpdateInvoiceStatus)
+		protected.POST("/invoices/:id/revise", invoiceHandler.ReviseInvoice)
+		protected.GET("/invoices/:id/revisions", invoiceHandler.GetInvoiceRevisions)
+		protected.GET("/invoices/:id/revisions/diff", invoiceHandler.GetInvoiceRevisionDiff)
 		protected.DELETE("/invoices/:id", invoiceHandler.DeleteInvoice)
+		protected.POST("/invoices/:id/send", invoiceDeliveryHandler.SendInvoice)
+		protected.GET("/invoices/:id/deliveries", invoiceDeliveryHandler.GetInvoiceDeliveries)
+
+		// Invoice numbering routes
+		protected.GET("/invoice-numbering", invoiceHandler.GetInvoiceNumbering)
+		protected.PUT("/invoice-numbering", invoiceHandler.UpdateInvoiceNumbering)
+
+		// Credit note routes
+		protected.POST("/invoices/:id/credit-notes", creditNoteHandler.CreateCreditNote)
+		protected.GET("/invoices/:id/credit-notes", creditNoteHandler.GetInvoiceCreditNotes)
+		protected.GET("/credit-notes", creditNoteHandler.GetCreditNotes)
+		protected.GET("/credit-notes/next-number", creditNoteHandler.GetNextCreditNoteNumber)
+		protected.GET("/credit-notes/:id", creditNoteHandler.GetCreditNote)
+		protected.GET("/credit-notes/:id/pdf", creditNoteHandler.DownloadCreditNotePDF)
+
+		// Estimate routes
+		protected.POST("/estimates", estimateHandler.CreateEstimate)
+		protected.GET("/estimates", estimateHandler.GetEstimates)
+		protected.GET("/estimates/next-number", estimateHandler.GetNextEstimateNumber)
+		protected.GET("/estimates/:id", estimateHandler.GetEstimate)
+		protected.PUT("/estimates/:id", estimateHandler.UpdateEstimate)
+		protected.PATCH("/estimates/:id/status", estimateHandler.UpdateEstimateStatus)
+		protected.DELETE("/estimates/:id", estimateHandler.DeleteEstimate)
+		protected.GET("/estimates/:id/pdf", estimateHandler.DownloadEstimatePDF)
+		protected.POST("/estimates/:id/convert", estimateHandler.ConvertEstimate)
+
+		// Recurring invoice routes
+		protected.POST("/recurring-invoices", recurringInvoiceHandler.CreateRecurringInvoice)
+		protected.GET("/recurring-invoices", recurringInvoiceHandler.GetRecurringInvoices)
+		protected.GET("/recurring-invoices/:id", recurringInvoiceHandler.GetRecurringInvoice)
+		protected.GET("/recurring-invoices/:id/preview", recurringInvoiceHandler.PreviewRecurringInvoice)
+		protected.PUT("/recurring-invoices/:id", recurringInvoiceHandler.UpdateRecurringInvoice)
+		protected.POST("/recurring-invoices/:id/pause", recurringInvoiceHandler.PauseRecurringInvoice)
+		protected.POST("/recurring-invoices/:id/resume", recurringInvoiceHandler.ResumeRecurringInvoice)
+		protected.DELETE("/recurring-invoices/:id", recurringInvoiceHandler.DeleteRecurringInvoice)
+
+		// Payment routes
+		protected.POST("/invoices/:id/payments", paymentHandler.CreatePayment)
+		protected.GET("/invoices/:id/payments", paymentHandler.GetPayments)
+		protected.PUT("/invoices/:id/payments/:paymentId", paymentHandler.UpdatePayment)
+		protected.DELETE("/invoices/:id/payments/:paymentId", paymentHandler.DeletePayment)
+
+		// Payment reminder routes
+		protected.POST("/reminder-rules", reminderHandler.CreateReminderRule)
+		protected.GET("/reminder-rules", reminderHandler.GetReminderRules)
+		protected.PUT("/reminder-rules/:id", reminderHandler.UpdateReminderRule)
+		protected.DELETE("/reminder-rules/:id", reminderHandler.DeleteReminderRule)
+		protected.GET("/invoices/:id/reminders", reminderHandler.GetInvoiceReminders)
+
+		// Notification routes
+		protected.GET("/notifications", notificationHandler.GetNotifications)
+		protected.POST("/notifications/read-all", notificationHandler.MarkAllNotificationsRead)
+		protected.POST("/notifications/:id/read", notificationHandler.MarkNotificationRead)
+
+		// Tax rate routes
+		protected.POST("/tax-rates", taxRateHandler.CreateTaxRate)
+		protected.GET("/tax-rates", taxRateHandler.GetTaxRates)
+		protected.PUT("/tax-rates/:id", taxRateHandler.UpdateTaxRate)
+		protected.DELETE("/tax-rates/:id", taxRateHandler.DeleteTaxRate)
+
+		// Exchange rate override routes
+		protected.POST("/exchange-rate-overrides", exchangeRateOverrideHandler.CreateExchangeRateOverride)
+		protected.GET("/exchange-rate-overrides", exchangeRateOverrideHandler.GetExchangeRateOverrides)
+		protected.PUT("/exchange-rate-overrides/:id", exchangeRateOverrideHandler.UpdateExchangeRateOverride)
+		protected.DELETE("/exchange-rate-overrides/:id", exchangeRateOverrideHandler.DeleteExchangeRateOverride)
 
 		// Demo routes
 		protected.POST("/demo/generate", demoHandler.GenerateDemoData)
@@ -221,12 +470,11 @@ func main() {
 		protected.GET("/stats/recent-time-entries", statsHandler.GetRecentTimeEntries)
 		protected.GET("/stats/recent-invoices", statsHandler.GetRecentInvoices)
 		protected.GET("/stats/invoices", statsHandler.GetInvoiceStats)
+		protected.GET("/stats/tax", statsHandler.GetTaxReport)
 	}
 
 	// Health check
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{"status": "ok"})
-	})
+	e.GET("/health", healthHandler.GetHealth)
 
 	// Swagger documentation
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -16,9 +16,16 @@ type Config struct {
 	SMTPPort           string
 	SMTPUsername       string
 	SMTPPassword       string
+	SMTPSecurity       string
 	SenderEmail        string
 	SenderName         string
 	AppURL             string
+	RateBackfillFrom   string
+	RateProviders      string
+	OpenExchangeAppID  string
+	RateFixturePath    string
+	RateMaxAge         string
+	MoneyRounding      string
 }
 
 func Load() (*Config, error) {
@@ -33,9 +40,16 @@ func Load() (*Config, error) {
 		SMTPPort:            getEnv("SMTP_PORT", "465"),
 		SMTPUsername:        getEnv("SMTP_USERNAME", ""),
 		SMTPPassword:        getEnv("SMTP_PASSWORD", ""),
+		SMTPSecurity:        getEnv("SMTP_SECURITY", "tls"),
 		SenderEmail:         getEnv("SENDER_EMAIL", "noreply@yourdomain.com"),
 		SenderName:          getEnv("SENDER_NAME", "FacturMe"),
 		AppURL:              getEnv("APP_URL", "http://localhost:5173"),
+		RateBackfillFrom:    getEnv("EXCHANGE_RATE_BACKFILL_FROM", ""),
+		RateProviders:       getEnv("EXCHANGE_RATE_PROVIDERS", "frankfurter,ecb"),
+		OpenExchangeAppID:   getEnv("OPENEXCHANGERATES_APP_ID", ""),
+		RateFixturePath:     getEnv("EXCHANGE_RATE_FIXTURE", ""),
+		RateMaxAge:          getEnv("EXCHANGE_RATE_MAX_AGE", "48h"),
+		MoneyRounding:       getEnv("MONEY_ROUNDING", "half_up"),
 	}
 
 	return config, nil